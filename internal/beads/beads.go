@@ -595,6 +595,12 @@ func (b *Beads) Update(id string, opts UpdateOptions) error {
 	return err
 }
 
+// AddComment adds a comment to an issue.
+func (b *Beads) AddComment(id, text string) error {
+	_, err := b.run("comment", id, text)
+	return err
+}
+
 // Close closes one or more issues.
 // If a runtime session ID is set in the environment, it is passed to bd close
 // for work attribution tracking (see decision 009-session-events-architecture.md).
@@ -2448,3 +2448,48 @@ func TestCloseAndClearAgentBead_ReasonVariations(t *testing.T) {
 		})
 	}
 }
+
+func TestStepFieldsRoundTrip(t *testing.T) {
+	issue := &Issue{Description: `Run the tests.
+
+instantiated_from: gt-mol
+step: test
+max_attempts: 2
+on_failure: escalate
+attempts: 1
+output.coverage: 81%`}
+
+	fields := ParseStepFields(issue)
+	if fields == nil {
+		t.Fatal("ParseStepFields returned nil")
+	}
+	if fields.Ref != "test" || fields.MaxAttempts != 2 || fields.OnFailure != "escalate" || fields.Attempts != 1 {
+		t.Errorf("fields = %+v", fields)
+	}
+	if fields.Outputs["coverage"] != "81%" {
+		t.Errorf("Outputs = %v", fields.Outputs)
+	}
+
+	fields.Attempts = 2
+	fields.Outcome = StepOutcomePassed
+	fields.Outputs["bumped"] = "true"
+	got := SetStepFields(issue, fields)
+	want := `Run the tests.
+
+instantiated_from: gt-mol
+
+step: test
+max_attempts: 2
+on_failure: escalate
+attempts: 2
+outcome: passed
+output.bumped: true
+output.coverage: 81%`
+	if got != want {
+		t.Errorf("SetStepFields() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if ParseStepFields(&Issue{Description: "Just prose."}) != nil {
+		t.Error("ParseStepFields should return nil without step fields")
+	}
+}
@@ -3,6 +3,7 @@ package beads
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -512,6 +513,257 @@ func FormatSynthesisFields(fields *SynthesisFields) string {
 	return strings.Join(lines, "\n")
 }
 
+// Step outcomes recorded in StepFields.Outcome.
+const (
+	StepOutcomePassed   = "passed"
+	StepOutcomeFailed   = "failed"
+	StepOutcomeTimedOut = "timed_out"
+	StepOutcomeSkipped  = "skipped"
+)
+
+// StepFields holds the execution policy and state of a molecule step.
+// These are stored as "key: value" lines in the step's description and
+// enforced by gt mol step done.
+type StepFields struct {
+	Ref         string // Step reference within the molecule (e.g., "bump")
+	Timeout     string // Max duration of one attempt (e.g., "30m")
+	MaxAttempts int    // Attempts before the on_failure policy applies
+	OnFailure   string // retry, skip, escalate, or abort
+	When        string // Condition; the step is skipped when it is false
+
+	Attempts  int    // Attempts that have finished
+	StartedAt string // ISO 8601 start of the current attempt
+	Outcome   string // passed, failed, timed_out, or skipped
+	LastError string // Why the last attempt failed
+
+	// Outputs are recorded with gt mol step done --output key=value and
+	// stored as "output.<key>: value" lines.
+	Outputs map[string]string
+}
+
+// stepOutputPrefix prefixes output keys in step descriptions.
+const stepOutputPrefix = "output."
+
+// ParseStepFields extracts step fields from an issue's description.
+// Returns nil if no step fields are found.
+func ParseStepFields(issue *Issue) *StepFields {
+	if issue == nil || issue.Description == "" {
+		return nil
+	}
+
+	fields := &StepFields{}
+	hasFields := false
+
+	for _, line := range strings.Split(issue.Description, "\n") {
+		line = strings.TrimSpace(line)
+		colonIdx := strings.Index(line, ":")
+		if colonIdx == -1 {
+			continue
+		}
+
+		key := strings.TrimSpace(line[:colonIdx])
+		value := strings.TrimSpace(line[colonIdx+1:])
+		if value == "" {
+			continue
+		}
+
+		if name, ok := strings.CutPrefix(key, stepOutputPrefix); ok && name != "" {
+			if fields.Outputs == nil {
+				fields.Outputs = make(map[string]string)
+			}
+			fields.Outputs[name] = value
+			hasFields = true
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "step":
+			fields.Ref = value
+		case "timeout":
+			fields.Timeout = value
+		case "max_attempts", "max-attempts", "maxattempts":
+			n, err := parseIntField(value)
+			if err != nil {
+				continue
+			}
+			fields.MaxAttempts = n
+		case "on_failure", "on-failure", "onfailure":
+			fields.OnFailure = strings.ToLower(value)
+		case "when":
+			fields.When = value
+		case "attempts":
+			n, err := parseIntField(value)
+			if err != nil {
+				continue
+			}
+			fields.Attempts = n
+		case "started_at", "started-at", "startedat":
+			fields.StartedAt = value
+		case "outcome":
+			fields.Outcome = value
+		case "last_error", "last-error", "lasterror":
+			fields.LastError = value
+		default:
+			continue
+		}
+		hasFields = true
+	}
+
+	if !hasFields {
+		return nil
+	}
+	return fields
+}
+
+// FormatStepFields formats StepFields as a string suitable for an issue description.
+// Only non-empty fields are included; outputs are sorted by key.
+func FormatStepFields(fields *StepFields) string {
+	if fields == nil {
+		return ""
+	}
+
+	var lines []string
+
+	if fields.Ref != "" {
+		lines = append(lines, "step: "+fields.Ref)
+	}
+	if fields.Timeout != "" {
+		lines = append(lines, "timeout: "+fields.Timeout)
+	}
+	if fields.MaxAttempts > 0 {
+		lines = append(lines, fmt.Sprintf("max_attempts: %d", fields.MaxAttempts))
+	}
+	if fields.OnFailure != "" {
+		lines = append(lines, "on_failure: "+fields.OnFailure)
+	}
+	if fields.When != "" {
+		lines = append(lines, "when: "+fields.When)
+	}
+	if fields.Attempts > 0 {
+		lines = append(lines, fmt.Sprintf("attempts: %d", fields.Attempts))
+	}
+	if fields.StartedAt != "" {
+		lines = append(lines, "started_at: "+fields.StartedAt)
+	}
+	if fields.Outcome != "" {
+		lines = append(lines, "outcome: "+fields.Outcome)
+	}
+	if fields.LastError != "" {
+		lines = append(lines, "last_error: "+fields.LastError)
+	}
+
+	keys := make([]string, 0, len(fields.Outputs))
+	for k := range fields.Outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		lines = append(lines, stepOutputPrefix+k+": "+fields.Outputs[k])
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SetStepFields updates an issue's description with the given step fields.
+// Existing step field lines are replaced; other content is preserved.
+// Unlike the other Set*Fields helpers, the fields go after the content,
+// since a step's instructions are what the agent reads first.
+// Returns the new description string.
+func SetStepFields(issue *Issue, fields *StepFields) string {
+	stepKeys := map[string]bool{
+		"step":         true,
+		"timeout":      true,
+		"max_attempts": true,
+		"max-attempts": true,
+		"maxattempts":  true,
+		"on_failure":   true,
+		"on-failure":   true,
+		"onfailure":    true,
+		"when":         true,
+		"attempts":     true,
+		"started_at":   true,
+		"started-at":   true,
+		"startedat":    true,
+		"outcome":      true,
+		"last_error":   true,
+		"last-error":   true,
+		"lasterror":    true,
+	}
+
+	// Collect non-step lines from existing description
+	var otherLines []string
+	if issue != nil && issue.Description != "" {
+		for _, line := range strings.Split(issue.Description, "\n") {
+			trimmed := strings.TrimSpace(line)
+			colonIdx := strings.Index(trimmed, ":")
+			if colonIdx == -1 {
+				otherLines = append(otherLines, line)
+				continue
+			}
+
+			key := strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))
+			if !stepKeys[key] && !strings.HasPrefix(key, stepOutputPrefix) {
+				otherLines = append(otherLines, line)
+			}
+			// Skip step field lines - they'll be replaced
+		}
+	}
+
+	formatted := FormatStepFields(fields)
+
+	// Trim trailing blank lines from other content
+	for len(otherLines) > 0 && strings.TrimSpace(otherLines[len(otherLines)-1]) == "" {
+		otherLines = otherLines[:len(otherLines)-1]
+	}
+
+	if formatted == "" {
+		return strings.Join(otherLines, "\n")
+	}
+	if len(otherLines) == 0 {
+		return formatted
+	}
+
+	return strings.Join(otherLines, "\n") + "\n\n" + formatted
+}
+
+// moleculeVarPrefix prefixes formula var lines in molecule descriptions.
+const moleculeVarPrefix = "vars."
+
+// FormatMoleculeVars formats the vars a molecule was run with as
+// "vars.<name>: value" lines, sorted by name, for the molecule's
+// description. Step when conditions read them as vars.<name>.
+func FormatMoleculeVars(vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, moleculeVarPrefix+name+": "+vars[name])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseMoleculeVars extracts the vars written by FormatMoleculeVars.
+func ParseMoleculeVars(issue *Issue) map[string]string {
+	vars := make(map[string]string)
+	if issue == nil {
+		return vars
+	}
+	for _, line := range strings.Split(issue.Description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		if name, ok := strings.CutPrefix(strings.TrimSpace(key), moleculeVarPrefix); ok && name != "" {
+			vars[name] = strings.TrimSpace(value)
+		}
+	}
+	return vars
+}
+
 // RoleConfig holds structured lifecycle configuration for role beads.
 // These fields are stored as "key: value" lines in the role bead description.
 // This enables agents to self-register their lifecycle configuration,
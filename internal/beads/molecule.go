@@ -18,6 +18,10 @@ type MoleculeStep struct {
 	Tier         string         // Optional tier hint: haiku, sonnet, opus
 	Type         string         // Step type: "task" (default), "wait", etc.
 	Backoff      *BackoffConfig // Backoff configuration for wait-type steps
+	Timeout      string         // Max duration of one attempt (e.g., "30m")
+	MaxAttempts  int            // Attempts before OnFailure applies
+	OnFailure    string         // retry, skip, escalate, or abort
+	When         string         // Condition; the step is skipped when false
 }
 
 // BackoffConfig defines exponential backoff parameters for wait-type steps.
@@ -49,6 +53,18 @@ var typeLineRegex = regexp.MustCompile(`(?i)^Type:\s*(\w+)\s*$`)
 // Parses backoff configuration for wait-type steps.
 var backoffLineRegex = regexp.MustCompile(`(?i)^Backoff:\s*(.+)$`)
 
+// timeoutLineRegex matches "Timeout: 30m" lines.
+var timeoutLineRegex = regexp.MustCompile(`(?i)^Timeout:\s*(\S+)\s*$`)
+
+// maxAttemptsLineRegex matches "MaxAttempts: 2" lines.
+var maxAttemptsLineRegex = regexp.MustCompile(`(?i)^Max_?Attempts:\s*(\d+)\s*$`)
+
+// onFailureLineRegex matches "OnFailure: retry|skip|escalate|abort" lines.
+var onFailureLineRegex = regexp.MustCompile(`(?i)^On_?Failure:\s*(retry|skip|escalate|abort)\s*$`)
+
+// whenLineRegex matches "When: <condition>" lines.
+var whenLineRegex = regexp.MustCompile(`(?i)^When:\s*(.+)$`)
+
 // templateVarRegex matches {{variable}} placeholders.
 var templateVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
 
@@ -62,6 +78,10 @@ var templateVarRegex = regexp.MustCompile(`\{\{(\w+)\}\}`)
 //	Tier: haiku|sonnet|opus  # optional
 //	Type: task|wait  # optional, default is "task"
 //	Backoff: base=30s, multiplier=2, max=10m  # optional, for wait-type steps
+//	Timeout: 30m  # optional
+//	MaxAttempts: 2  # optional
+//	OnFailure: retry|skip|escalate|abort  # optional, default is abort
+//	When: <condition>  # optional, see formula.Condition
 //
 // Returns an empty slice if no steps are found.
 func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
@@ -127,6 +147,24 @@ func ParseMoleculeSteps(description string) ([]MoleculeStep, error) {
 				continue
 			}
 
+			// Check for execution policy lines
+			if matches := timeoutLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.Timeout = matches[1]
+				continue
+			}
+			if matches := maxAttemptsLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.MaxAttempts, _ = strconv.Atoi(matches[1])
+				continue
+			}
+			if matches := onFailureLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.OnFailure = strings.ToLower(matches[1])
+				continue
+			}
+			if matches := whenLineRegex.FindStringSubmatch(trimmed); matches != nil {
+				currentStep.When = strings.TrimSpace(matches[1])
+				continue
+			}
+
 			// Regular instruction line
 			instructionLines = append(instructionLines, line)
 		}
@@ -406,6 +444,14 @@ func (b *Beads) instantiateFromMarkdown(mol *Issue, parent *Issue, opts Instanti
 		if step.Tier != "" {
 			description += fmt.Sprintf("\ntier: %s", step.Tier)
 		}
+		if policy := FormatStepFields(&StepFields{
+			Timeout:     step.Timeout,
+			MaxAttempts: step.MaxAttempts,
+			OnFailure:   step.OnFailure,
+			When:        step.When,
+		}); policy != "" {
+			description += "\n" + policy
+		}
 
 		// Create the child issue
 		childOpts := CreateOptions{
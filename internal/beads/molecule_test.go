@@ -787,3 +787,35 @@ Needs: step1`
 		t.Errorf("step[1].Type = %q, want task", steps[1].Type)
 	}
 }
+
+func TestParseMoleculeSteps_WithPolicy(t *testing.T) {
+	desc := `## Step: test
+Run the test suite.
+Timeout: 30m
+MaxAttempts: 2
+OnFailure: Escalate
+
+## Step: publish
+Publish the release.
+Needs: test
+When: steps.test.outputs.bumped == "true"`
+
+	steps, err := ParseMoleculeSteps(desc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(steps))
+	}
+
+	test := steps[0]
+	if test.Timeout != "30m" || test.MaxAttempts != 2 || test.OnFailure != "escalate" {
+		t.Errorf("test policy = %q/%d/%q, want 30m/2/escalate", test.Timeout, test.MaxAttempts, test.OnFailure)
+	}
+	if test.Instructions != "Run the test suite." {
+		t.Errorf("test.Instructions = %q, policy lines should be stripped", test.Instructions)
+	}
+	if steps[1].When != `steps.test.outputs.bumped == "true"` {
+		t.Errorf("publish.When = %q", steps[1].When)
+	}
+}
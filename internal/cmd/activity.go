@@ -31,7 +31,8 @@ var activityCmd = &cobra.Command{
 	Short:   "Emit and view activity events",
 	Long: `Emit and view activity events for the Gas Town activity feed.
 
-Events are written to ~/gt/.events.jsonl and can be viewed with 'gt feed'.
+Events are written to ~/gt/.events.jsonl and can be viewed with 'gt feed'
+or searched with 'gt events query'.
 
 Subcommands:
   emit    Emit an activity event`,
@@ -403,36 +403,15 @@ func formatTownlogSummary(e townlog.Event) string {
 func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, error) {
 	var entries []AuditEntry
 
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	file, err := os.Open(eventsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil // No events file yet
-		}
-		return nil, err
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		var e events.Event
-		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
-			continue // Skip malformed lines
-		}
-
+	err := events.Each(townRoot, since, func(e events.Event) bool {
 		// Apply actor filter
 		if actor != "" && !matchesActor(e.Actor, actor) {
-			continue
+			return true
 		}
 
-		// Parse timestamp
+		// Parse timestamp (Each has already applied the since filter)
 		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
 
-		// Apply since filter
-		if !since.IsZero() && ts.Before(since) {
-			continue
-		}
-
 		entries = append(entries, AuditEntry{
 			Timestamp: ts,
 			Source:    "events",
@@ -440,9 +419,10 @@ func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, e
 			Actor:     e.Actor,
 			Summary:   formatFeedSummary(e),
 		})
-	}
+		return true
+	})
 
-	return entries, nil
+	return entries, err
 }
 
 // formatFeedSummary creates a readable summary from a feed event.
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -54,8 +56,65 @@ var daemonStatusCmd = &cobra.Command{
 var daemonLogsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "View daemon logs",
-	Long:  `View the daemon log file.`,
-	RunE:  runDaemonLogs,
+	Long: `View the daemon log file.
+
+With the daemon running, -f streams lines over its control socket as they
+are logged; otherwise the log file is tailed.`,
+	RunE: runDaemonLogs,
+}
+
+var daemonHeartbeatCmd = &cobra.Command{
+	Use:   "heartbeat",
+	Short: "Run a daemon heartbeat now",
+	Long: `Ask the running daemon to run a heartbeat now instead of waiting for
+the next interval (3 minutes).
+
+Requests made while a heartbeat is already pending share it. With --wait,
+the command returns once the heartbeat has completed.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonHeartbeat,
+}
+
+var daemonPauseCmd = &cobra.Command{
+	Use:   "pause <patrol>...",
+	Short: "Pause heartbeat patrols",
+	Long: `Stop the running daemon from running patrols in its heartbeat.
+
+Patrols: ` + strings.Join(daemon.Patrols, ", ") + `
+
+Pauses last until 'gt daemon resume' or a daemon restart. To disable a
+patrol permanently, set it in mayor/daemon.json and run 'gt daemon reload'.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDaemonPause,
+}
+
+var daemonResumeCmd = &cobra.Command{
+	Use:   "resume [patrol...]",
+	Short: "Resume paused patrols",
+	Long:  `Resume patrols paused with 'gt daemon pause'. With --all, resume every paused patrol.`,
+	RunE:  runDaemonResume,
+}
+
+var daemonRestartSessionCmd = &cobra.Command{
+	Use:   "restart-session <agent>",
+	Short: "Restart an agent's session via the daemon",
+	Long: `Have the running daemon kill an agent's session and start a fresh one,
+as a lifecycle restart request would.
+
+The agent is given as a lifecycle identity: mayor, deacon, <rig>-witness,
+<rig>-refinery, <rig>-crew-<name> or <rig>/polecats/<name>.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDaemonRestartSession,
+}
+
+var daemonReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload the daemon's patrol config",
+	Long: `Make the running daemon re-read mayor/daemon.json.
+
+Dolt server settings still take effect only when the daemon restarts.`,
+	Args: cobra.NoArgs,
+	RunE: runDaemonReload,
 }
 
 var daemonRunCmd = &cobra.Command{
@@ -66,8 +125,11 @@ var daemonRunCmd = &cobra.Command{
 }
 
 var (
-	daemonLogLines int
-	daemonLogFollow bool
+	daemonLogLines      int
+	daemonLogFollow     bool
+	daemonStatusJSON    bool
+	daemonHeartbeatWait bool
+	daemonResumeAll     bool
 )
 
 func init() {
@@ -76,9 +138,17 @@ func init() {
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonCmd.AddCommand(daemonLogsCmd)
 	daemonCmd.AddCommand(daemonRunCmd)
+	daemonCmd.AddCommand(daemonHeartbeatCmd)
+	daemonCmd.AddCommand(daemonPauseCmd)
+	daemonCmd.AddCommand(daemonResumeCmd)
+	daemonCmd.AddCommand(daemonRestartSessionCmd)
+	daemonCmd.AddCommand(daemonReloadCmd)
 
 	daemonLogsCmd.Flags().IntVarP(&daemonLogLines, "lines", "n", 50, "Number of lines to show")
 	daemonLogsCmd.Flags().BoolVarP(&daemonLogFollow, "follow", "f", false, "Follow log output")
+	daemonStatusCmd.Flags().BoolVar(&daemonStatusJSON, "json", false, "Output as JSON")
+	daemonHeartbeatCmd.Flags().BoolVar(&daemonHeartbeatWait, "wait", false, "Wait for the heartbeat to complete")
+	daemonResumeCmd.Flags().BoolVar(&daemonResumeAll, "all", false, "Resume all paused patrols")
 
 	rootCmd.AddCommand(daemonCmd)
 }
@@ -175,6 +245,26 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("checking daemon status: %w", err)
 	}
 
+	// A running daemon answers on its control socket; state.json covers
+	// daemons that predate it
+	var live *daemon.StatusReply
+	if running {
+		live, _ = daemon.NewControlClient(townRoot).Status()
+	}
+
+	if daemonStatusJSON {
+		out := struct {
+			Running bool                `json:"running"`
+			PID     int                 `json:"pid,omitempty"`
+			Live    *daemon.StatusReply `json:"live,omitempty"`
+			State   *daemon.State       `json:"state,omitempty"`
+		}{Running: running, PID: pid, Live: live}
+		if running && live == nil {
+			out.State, _ = daemon.LoadState(townRoot)
+		}
+		return outputJSON(out)
+	}
+
 	if running {
 		fmt.Printf("%s Daemon is %s (PID %d)\n",
 			style.Bold.Render("●"),
@@ -183,6 +273,16 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 
 		// Load state for more details
 		state, err := daemon.LoadState(townRoot)
+		if live != nil {
+			state = &daemon.State{
+				Running:        true,
+				PID:            live.PID,
+				StartedAt:      live.StartedAt,
+				LastHeartbeat:  live.LastHeartbeat,
+				HeartbeatCount: live.HeartbeatCount,
+			}
+			err = nil
+		}
 		if err == nil && !state.StartedAt.IsZero() {
 			fmt.Printf("  Started: %s\n", state.StartedAt.Format("2006-01-02 15:04:05"))
 			if !state.LastHeartbeat.IsZero() {
@@ -190,6 +290,9 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 					state.LastHeartbeat.Format("15:04:05"),
 					state.HeartbeatCount)
 			}
+			if live != nil {
+				printLiveDaemonStatus(live)
+			}
 
 			// Check if binary is newer than process
 			if binaryModTime, err := getBinaryModTime(); err == nil {
@@ -211,6 +314,30 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printLiveDaemonStatus prints what only a running daemon knows: the
+// heartbeat schedule and paused patrols.
+func printLiveDaemonStatus(live *daemon.StatusReply) {
+	if live.HeartbeatRunning {
+		fmt.Printf("  Heartbeat: %s\n", style.Bold.Render("running now"))
+	} else if !live.NextHeartbeat.IsZero() {
+		fmt.Printf("  Next heartbeat: %s (in %s)\n",
+			live.NextHeartbeat.Format("15:04:05"),
+			formatDuration(time.Until(live.NextHeartbeat).Round(time.Second)))
+	}
+	if live.SessionBackend != "" {
+		fmt.Printf("  Sessions: %s\n", live.SessionBackend)
+	}
+	if len(live.Paused) > 0 {
+		fmt.Printf("  %s Paused: %s %s\n",
+			style.Bold.Render("⏸"),
+			strings.Join(live.Paused, ", "),
+			style.Dim.Render("(gt daemon resume --all)"))
+	}
+	if len(live.Disabled) > 0 {
+		fmt.Printf("  Disabled in config: %s\n", strings.Join(live.Disabled, ", "))
+	}
+}
+
 // getBinaryModTime returns the modification time of the current executable
 func getBinaryModTime() (time.Time, error) {
 	exePath, err := os.Executable()
@@ -230,6 +357,17 @@ func runDaemonLogs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// Follow over the control socket when the daemon is up, so lines
+	// arrive as they are logged rather than as tail polls for them
+	if daemonLogFollow {
+		if running, _, _ := daemon.IsRunning(townRoot); running {
+			err := daemon.NewControlClient(townRoot).FollowLog(daemonLogLines, os.Stdout)
+			if !errors.Is(err, daemon.ErrControlUnavailable) {
+				return err
+			}
+		}
+	}
+
 	logFile := filepath.Join(townRoot, "daemon", "daemon.log")
 
 	if _, err := os.Stat(logFile); os.IsNotExist(err) {
@@ -238,7 +376,7 @@ func runDaemonLogs(cmd *cobra.Command, args []string) error {
 
 	if daemonLogFollow {
 		// Use tail -f for following
-		tailCmd := exec.Command("tail", "-f", logFile)
+		tailCmd := exec.Command("tail", "-n", fmt.Sprintf("%d", daemonLogLines), "-f", logFile)
 		tailCmd.Stdout = os.Stdout
 		tailCmd.Stderr = os.Stderr
 		return tailCmd.Run()
@@ -251,6 +389,115 @@ func runDaemonLogs(cmd *cobra.Command, args []string) error {
 	return tailCmd.Run()
 }
 
+// daemonControl returns a control client for the town's running daemon.
+func daemonControl() (*daemon.ControlClient, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	running, _, err := daemon.IsRunning(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("checking daemon status: %w", err)
+	}
+	if !running {
+		return nil, fmt.Errorf("daemon is not running (start with 'gt daemon start')")
+	}
+	return daemon.NewControlClient(townRoot), nil
+}
+
+func runDaemonHeartbeat(cmd *cobra.Command, args []string) error {
+	client, err := daemonControl()
+	if err != nil {
+		return err
+	}
+	if daemonHeartbeatWait {
+		fmt.Printf("%s Waiting for heartbeat...\n", style.Dim.Render("○"))
+	}
+	reply, err := client.Heartbeat(daemonHeartbeatWait)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case daemonHeartbeatWait:
+		fmt.Printf("%s Heartbeat complete (#%d)\n", style.Bold.Render("✓"), reply.Count)
+	case reply.Queued:
+		fmt.Printf("%s Heartbeat requested\n", style.Bold.Render("✓"))
+	default:
+		fmt.Printf("%s Heartbeat already pending\n", style.Bold.Render("●"))
+	}
+	return nil
+}
+
+func runDaemonPause(cmd *cobra.Command, args []string) error {
+	client, err := daemonControl()
+	if err != nil {
+		return err
+	}
+	paused, err := client.Pause(args...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Paused: %s\n", style.Bold.Render("✓"), strings.Join(paused, ", "))
+	return nil
+}
+
+func runDaemonResume(cmd *cobra.Command, args []string) error {
+	if daemonResumeAll {
+		args = daemon.Patrols
+	} else if len(args) == 0 {
+		return fmt.Errorf("name the patrols to resume, or use --all")
+	}
+	client, err := daemonControl()
+	if err != nil {
+		return err
+	}
+	paused, err := client.Resume(args...)
+	if err != nil {
+		return err
+	}
+	if len(paused) == 0 {
+		fmt.Printf("%s No patrols paused\n", style.Bold.Render("✓"))
+		return nil
+	}
+	fmt.Printf("%s Still paused: %s\n", style.Bold.Render("✓"), strings.Join(paused, ", "))
+	return nil
+}
+
+func runDaemonRestartSession(cmd *cobra.Command, args []string) error {
+	client, err := daemonControl()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Restarting %s...\n", style.Dim.Render("○"), args[0])
+	sessionName, err := client.Restart(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Restarted session %s\n", style.Bold.Render("✓"), sessionName)
+	return nil
+}
+
+func runDaemonReload(cmd *cobra.Command, args []string) error {
+	client, err := daemonControl()
+	if err != nil {
+		return err
+	}
+	reply, err := client.Reload()
+	if err != nil {
+		return err
+	}
+	if !reply.Found {
+		fmt.Printf("%s No %s; all patrols enabled\n", style.Bold.Render("✓"), reply.ConfigFile)
+		return nil
+	}
+	fmt.Printf("%s Reloaded %s\n", style.Bold.Render("✓"), reply.ConfigFile)
+	if len(reply.Disabled) > 0 {
+		fmt.Printf("  Disabled: %s\n", strings.Join(reply.Disabled, ", "))
+	}
+	return nil
+}
+
 func runDaemonRun(cmd *cobra.Command, args []string) error {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -27,7 +29,25 @@ The dashboard shows real-time convoy status with:
 - Convoy list with status indicators
 - Progress tracking for each convoy
 - Last activity indicator (green/yellow/red)
-- Auto-refresh every 30 seconds via htmx
+- Live panel updates pushed over Server-Sent Events (/events) as the
+  town's event log changes, with a full refresh every 60 seconds
+
+Access control is configured in settings/dashboard.json. Without it the
+dashboard is open to anyone who can reach the port. Tokens authenticate
+with "Authorization: Bearer <token>" (browsers can open the dashboard
+once with ?token=<token>); users authenticate with HTTP basic auth.
+Each has a scope: "read-only" (view and run safe commands) or "operator"
+(also send mail and run action commands):
+
+  {
+    "type": "dashboard",
+    "version": 1,
+    "tokens": [{"name": "ci", "secret_env": "GT_DASHBOARD_CI_TOKEN"}],
+    "users": [{"name": "alice", "secret_env": "GT_DASHBOARD_ALICE", "scope": "operator"}]
+  }
+
+Every change made through the dashboard is recorded in the town's events
+log as a dashboard_request audit event.
 
 Example:
   gt dashboard              # Start on default port 8080
@@ -47,7 +67,8 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	var handler http.Handler
 	var err error
 
-	if _, wsErr := workspace.FindFromCwdOrError(); wsErr != nil {
+	authStatus := ""
+	if townRoot, wsErr := workspace.FindFromCwdOrError(); wsErr != nil {
 		// No workspace - run in setup mode
 		handler, err = web.NewSetupMux()
 		if err != nil {
@@ -64,6 +85,12 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("creating dashboard handler: %w", err)
 		}
+
+		// NewDashboardMux has validated the config; load it again to report.
+		dashboardConfig, _ := config.LoadOrCreateDashboardConfig(config.DashboardConfigPath(townRoot))
+		if auth, authErr := web.NewAuth(dashboardConfig); authErr == nil {
+			authStatus = auth.Describe()
+		}
 	}
 
 	// Build the URL
@@ -77,6 +104,11 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	// Start the server with timeouts
 	fmt.Printf("🚚 Gas Town Control Center starting at %s\n", url)
 	fmt.Printf("   API available at %s/api/\n", url)
+	if authStatus == "disabled" {
+		style.PrintWarning("authentication disabled; anyone who can reach port %d can run commands (see settings/dashboard.json)", dashboardPort)
+	} else if authStatus != "" {
+		fmt.Printf("   Auth: %s\n", authStatus)
+	}
 	fmt.Printf("   Press Ctrl+C to stop\n")
 
 	server := &http.Server{
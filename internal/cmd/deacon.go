@@ -16,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
@@ -287,6 +288,25 @@ Examples:
 	RunE: runDeaconZombieScan,
 }
 
+var deaconOverdueMailCmd = &cobra.Command{
+	Use:   "overdue-mail",
+	Short: "Escalate task mail past its reply-by deadline",
+	Long: `Escalate task messages whose reply-by deadline passed without a reply.
+
+Messages sent with 'gt mail send --reply-by' carry a deadline. Once it
+passes and the recipient has not replied (gt mail reply or --reply-to),
+this command raises an escalation through the normal escalation routing
+and marks the message so it is escalated only once.
+
+Urgent and high priority messages escalate at high severity, others at
+medium.
+
+Examples:
+  gt deacon overdue-mail             # Escalate overdue replies
+  gt deacon overdue-mail --dry-run   # List them without escalating`,
+	RunE: runDeaconOverdueMail,
+}
+
 var (
 	triggerTimeout time.Duration
 
@@ -308,6 +328,9 @@ var (
 
 	// Zombie scan flags
 	zombieScanDryRun bool
+
+	// Overdue mail flags
+	overdueMailDryRun bool
 )
 
 func init() {
@@ -326,6 +349,7 @@ func init() {
 	deaconCmd.AddCommand(deaconResumeCmd)
 	deaconCmd.AddCommand(deaconCleanupOrphansCmd)
 	deaconCmd.AddCommand(deaconZombieScanCmd)
+	deaconCmd.AddCommand(deaconOverdueMailCmd)
 
 	// Flags for trigger-pending
 	deaconTriggerPendingCmd.Flags().DurationVar(&triggerTimeout, "timeout", 2*time.Second,
@@ -359,6 +383,10 @@ func init() {
 	deaconZombieScanCmd.Flags().BoolVar(&zombieScanDryRun, "dry-run", false,
 		"List zombies without killing them")
 
+	// Flags for overdue-mail
+	deaconOverdueMailCmd.Flags().BoolVar(&overdueMailDryRun, "dry-run", false,
+		"List overdue messages without escalating them")
+
 	deaconStartCmd.Flags().StringVar(&deaconAgentOverride, "agent", "", "Agent alias to run the Deacon with (overrides town default)")
 	deaconAttachCmd.Flags().StringVar(&deaconAgentOverride, "agent", "", "Agent alias to run the Deacon with (overrides town default)")
 	deaconRestartCmd.Flags().StringVar(&deaconAgentOverride, "agent", "", "Agent alias to run the Deacon with (overrides town default)")
@@ -1271,3 +1299,68 @@ func runDeaconZombieScan(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runDeaconOverdueMail escalates task mail whose reply-by deadline passed.
+func runDeaconOverdueMail(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	now := time.Now()
+	router := mail.NewRouter(townRoot)
+	overdue, err := router.OverdueReplies(now)
+	if err != nil {
+		return fmt.Errorf("checking reply deadlines: %w", err)
+	}
+	if len(overdue) == 0 {
+		fmt.Printf("%s No overdue task mail\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	var escalated int
+	for _, msg := range overdue {
+		late := now.Sub(*msg.ReplyBy).Round(time.Minute)
+		if overdueMailDryRun {
+			fmt.Printf("  %s %s: %s → %s (overdue %s)\n",
+				style.Bold.Render("?"), msg.ID, msg.From, msg.To, late)
+			continue
+		}
+		if err := escalateOverdueMail(msg, late); err != nil {
+			style.PrintWarning("could not escalate %s: %v", msg.ID, err)
+			continue
+		}
+		if err := router.MarkReplyEscalated(msg.ID); err != nil {
+			style.PrintWarning("%v", err)
+		}
+		escalated++
+		fmt.Printf("  %s %s: %s → %s (overdue %s)\n",
+			style.Bold.Render("✓"), msg.ID, msg.From, msg.To, late)
+	}
+
+	if overdueMailDryRun {
+		fmt.Printf("\n%s Dry run - %d overdue message(s), none escalated.\n",
+			style.Dim.Render("ℹ"), len(overdue))
+	} else {
+		fmt.Printf("%s Escalated %d overdue message(s)\n", style.Bold.Render("✓"), escalated)
+	}
+	return nil
+}
+
+// escalateOverdueMail raises an escalation for an unanswered task message.
+func escalateOverdueMail(msg *mail.Message, late time.Duration) error {
+	severity := "medium"
+	if msg.Priority == mail.PriorityUrgent || msg.Priority == mail.PriorityHigh {
+		severity = "high"
+	}
+	reason := fmt.Sprintf("%s has not replied to task mail %s from %s; reply was due %s (%s ago)",
+		msg.To, msg.ID, msg.From, msg.ReplyBy.Local().Format("2006-01-02 15:04"), late)
+	escalateCmd := exec.Command("gt", "escalate", "Overdue reply: "+msg.Subject,
+		"--severity", severity,
+		"--reason", reason,
+		"--source", "patrol:deacon",
+		"--related", msg.ID)
+	escalateCmd.Stdout = os.Stdout
+	escalateCmd.Stderr = os.Stderr
+	return escalateCmd.Run()
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
+	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -472,11 +473,25 @@ notifyWitness:
 	}
 	bodyLines = append(bodyLines, fmt.Sprintf("Branch: %s", branch))
 
+	// The Witness reads the envelope; the text lines are for humans and
+	// older Witnesses that only parse the body text.
+	doneBody := strings.Join(bodyLines, "\n")
+	if enveloped, err := mail.EncodeEnvelope(witness.EnvelopePolecatDone, witness.PolecatDonePayload{
+		PolecatName: polecatName,
+		Exit:        exitType,
+		IssueID:     issueID,
+		MRID:        mrID,
+		Branch:      branch,
+		Gate:        doneGate,
+	}, doneBody); err == nil {
+		doneBody = enveloped
+	}
+
 	doneNotification := &mail.Message{
 		To:      witnessAddr,
 		From:    sender,
 		Subject: fmt.Sprintf("POLECAT_DONE %s", polecatName),
-		Body:    strings.Join(bodyLines, "\n"),
+		Body:    doneBody,
 	}
 
 	fmt.Printf("\nNotifying Witness...\n")
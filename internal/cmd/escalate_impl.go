@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -115,7 +117,16 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Process external notification actions (email:, sms:, slack)
-	executeExternalActions(actions, escalationConfig, issue.ID, severity, description)
+	executeExternalActions(bd, actions, escalationConfig, &notify.Notification{
+		ID:       issue.ID,
+		Severity: severity,
+		Subject:  description,
+		Body:     formatEscalationMailBody(issue.ID, severity, escalateReason, agentID, escalateRelatedBead),
+		From:     agentID,
+		Source:   escalateSource,
+		Related:  escalateRelatedBead,
+		Time:     time.Now(),
+	})
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -379,6 +390,16 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 				}
 			}
 
+			// Deliver external notifications for the new severity
+			executeExternalActions(bd, actions, escalationConfig, &notify.Notification{
+				ID:       result.ID,
+				Severity: result.NewSeverity,
+				Subject:  fmt.Sprintf("Re-escalated: %s", result.Title),
+				Body:     formatReescalationMailBody(result, reescalatedBy),
+				From:     reescalatedBy,
+				Time:     time.Now(),
+			})
+
 			// Log to activity feed
 			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
@@ -538,41 +559,48 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack).
-// For now, this logs warnings if contacts aren't configured - actual sending is future work.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, _, _, _ string) {
+// executeExternalActions delivers external notification actions (email:,
+// sms:, slack, discord, webhook:) and records the outcome on the escalation bead.
+func executeExternalActions(bd *beads.Beads, actions []string, cfg *config.EscalationConfig, n *notify.Notification) {
 	for _, action := range actions {
-		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual email sending
-				fmt.Printf("  📧 Would send email to %s (not yet implemented)\n", cfg.Contacts.HumanEmail)
-			}
-
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				// TODO: Implement actual SMS sending
-				fmt.Printf("  📱 Would send SMS to %s (not yet implemented)\n", cfg.Contacts.HumanSMS)
-			}
-
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				// TODO: Implement actual Slack webhook posting
-				fmt.Printf("  💬 Would post to Slack (not yet implemented)\n")
-			}
-
-		case action == "log":
+		if action == "log" {
 			// Log action always succeeds - writes to escalation log file
 			// TODO: Implement actual log file writing
 			fmt.Printf("  📝 Logged to escalation log\n")
 		}
 	}
+
+	deliveries := notify.NewDispatcher(cfg).Dispatch(context.Background(), actions, n)
+	if len(deliveries) == 0 {
+		return
+	}
+
+	for _, d := range deliveries {
+		switch {
+		case d.Skipped:
+			style.PrintWarning("%s action skipped: %s in settings/escalation.json", d.Action, d.Error)
+		case !d.Success:
+			style.PrintWarning("%s delivery to %s failed after %d attempt(s): %s", d.Action, d.Target, d.Attempts, d.Error)
+		default:
+			fmt.Printf("  %s Delivered %s to %s\n", deliveryEmoji(d.Action), d.Action, d.Target)
+		}
+	}
+
+	if err := bd.AddComment(n.ID, notify.FormatDeliveries(deliveries)); err != nil {
+		style.PrintWarning("failed to record deliveries on %s: %v", n.ID, err)
+	}
+}
+
+// deliveryEmoji returns the display emoji for an external action.
+func deliveryEmoji(action string) string {
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		return "📧"
+	case strings.HasPrefix(action, "sms:"):
+		return "📱"
+	default:
+		return "💬"
+	}
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Events query flags
+var (
+	eventsQueryTypes   []string
+	eventsQueryActors  []string
+	eventsQueryRig     string
+	eventsQueryBead    string
+	eventsQuerySince   string
+	eventsQueryUntil   string
+	eventsQueryGroupBy []string
+	eventsQueryLimit   int
+	eventsQueryFormat  string
+	eventsQueryJSON    bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Query the event history",
+	Long: `Query the raw event history (~/gt/.events.jsonl and its rotated segments).
+
+Everything KRC has not yet pruned is searchable, so 'gt events query' is the
+place to start a postmortem. Use 'gt feed' for the live, curated view.
+
+Subcommands:
+  query    Filter, group and count events`,
+	RunE: requireSubcommand,
+}
+
+var eventsQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Filter, group and count events",
+	Long: `Filter events by type, actor, rig, bead and time, then list them or
+count them by field.
+
+Filters (repeat or comma-separate --type and --actor to match any):
+  --type     Event type glob (session_death, merge_*)
+  --actor    Actor, glob, or prefix (gastown matches gastown/witness)
+  --rig      Rig, from the payload or the agent/actor address
+  --bead     Bead ID from the payload
+  --since    Start: an age (30m, 24h, 7d), a date (2006-01-02) or RFC3339
+  --until    End, in the same formats (exclusive)
+
+Grouping (--group-by) counts events per distinct value of one or more of:
+  type, actor, source, visibility, rig, bead, day, hour, payload.<field>
+
+Examples:
+  gt events query --type session_death --since 7d --group-by rig
+  gt events query --type 'merge_*' --rig gastown --since 2026-01-01
+  gt events query --bead gt-abc12                 # Everything that touched a bead
+  gt events query --actor gastown/witness -n 20   # Last 20 witness events
+  gt events query --since 30d --group-by day,type --format csv > daily.csv`,
+	Args: cobra.NoArgs,
+	RunE: runEventsQuery,
+}
+
+func init() {
+	f := eventsQueryCmd.Flags()
+	f.StringSliceVar(&eventsQueryTypes, "type", nil, "Event type glob(s)")
+	f.StringSliceVar(&eventsQueryActors, "actor", nil, "Actor(s), glob or prefix")
+	f.StringVar(&eventsQueryRig, "rig", "", "Only events about this rig")
+	f.StringVar(&eventsQueryBead, "bead", "", "Only events about this bead")
+	f.StringVar(&eventsQuerySince, "since", "", "Start time: age (7d, 24h), date, or RFC3339")
+	f.StringVar(&eventsQueryUntil, "until", "", "End time (exclusive): age, date, or RFC3339")
+	f.StringSliceVar(&eventsQueryGroupBy, "group-by", nil, "Count events by field(s)")
+	f.IntVarP(&eventsQueryLimit, "limit", "n", 100, "Maximum rows: most recent events, or top groups (0 = all)")
+	f.StringVar(&eventsQueryFormat, "format", "table", "Output format: table, json, or csv")
+	f.BoolVar(&eventsQueryJSON, "json", false, "Output as JSON (same as --format json)")
+
+	eventsCmd.AddCommand(eventsQueryCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsQuery(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	format := eventsQueryFormat
+	if eventsQueryJSON {
+		format = "json"
+	}
+	switch format {
+	case "table", "json", "csv":
+	default:
+		return fmt.Errorf("invalid --format %q: use table, json, or csv", format)
+	}
+	for _, field := range eventsQueryGroupBy {
+		if !events.ValidField(field) {
+			return fmt.Errorf("invalid --group-by field %q (valid: %s, payload.<field>)",
+				field, strings.Join(events.Fields, ", "))
+		}
+	}
+
+	filter, err := buildEventsFilter(time.Now())
+	if err != nil {
+		return err
+	}
+	evs, err := events.Query(townRoot, filter)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	if len(eventsQueryGroupBy) > 0 {
+		groups := events.Count(evs, eventsQueryGroupBy)
+		if eventsQueryLimit > 0 && len(groups) > eventsQueryLimit {
+			groups = groups[:eventsQueryLimit]
+		}
+		return outputEventGroups(format, eventsQueryGroupBy, groups, len(evs))
+	}
+
+	total := len(evs)
+	if eventsQueryLimit > 0 && len(evs) > eventsQueryLimit {
+		evs = evs[len(evs)-eventsQueryLimit:]
+	}
+	return outputEventRows(format, evs, total)
+}
+
+// buildEventsFilter turns the query flags into a filter.
+func buildEventsFilter(now time.Time) (events.Filter, error) {
+	filter := events.Filter{
+		Types:  eventsQueryTypes,
+		Actors: eventsQueryActors,
+		Rig:    eventsQueryRig,
+		Bead:   eventsQueryBead,
+	}
+	var err error
+	if eventsQuerySince != "" {
+		if filter.Since, err = parseEventsTime(eventsQuerySince, now); err != nil {
+			return filter, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if eventsQueryUntil != "" {
+		if filter.Until, err = parseEventsTime(eventsQueryUntil, now); err != nil {
+			return filter, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return filter, fmt.Errorf("--since must be before --until")
+	}
+	return filter, nil
+}
+
+// parseEventsTime parses an age (7d, 24h, 30m), a local date, or RFC3339.
+func parseEventsTime(s string, now time.Time) (time.Time, error) {
+	if d, err := parseDuration(s); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("%q: age must be positive", s)
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q: use an age like 7d, a date like 2006-01-02, or RFC3339", s)
+}
+
+// eventRow is one event as listed by gt events query.
+type eventRow struct {
+	Time    string
+	Type    string
+	Actor   string
+	Rig     string
+	Bead    string
+	Details string
+}
+
+func newEventRow(e events.Event) eventRow {
+	when := e.Timestamp
+	if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		when = ts.Local().Format("2006-01-02 15:04:05")
+	}
+	return eventRow{
+		Time:    when,
+		Type:    e.Type,
+		Actor:   e.Actor,
+		Rig:     e.Field("rig"),
+		Bead:    e.Field("bead"),
+		Details: formatEventPayload(e.Payload),
+	}
+}
+
+// formatEventPayload renders a payload as sorted key=value pairs.
+func formatEventPayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := payload[k]
+		if s, ok := v.(string); ok {
+			if strings.ContainsAny(s, " \t") {
+				v = strconv.Quote(s)
+			}
+		} else if _, ok := v.([]interface{}); ok {
+			data, _ := json.Marshal(v)
+			v = string(data)
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(parts, " ")
+}
+
+func outputEventRows(format string, evs []events.Event, total int) error {
+	switch format {
+	case "json":
+		if evs == nil {
+			evs = []events.Event{}
+		}
+		return outputJSON(evs)
+
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write([]string{"ts", "type", "actor", "rig", "bead", "visibility", "payload"})
+		for _, e := range evs {
+			payload := ""
+			if len(e.Payload) > 0 {
+				data, _ := json.Marshal(e.Payload)
+				payload = string(data)
+			}
+			_ = w.Write([]string{e.Timestamp, e.Type, e.Actor, e.Field("rig"), e.Field("bead"), e.Visibility, payload})
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(evs) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No matching events."))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTYPE\tACTOR\tRIG\tBEAD\tDETAILS")
+	for _, e := range evs {
+		r := newEventRow(e)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Time, r.Type, r.Actor, r.Rig, r.Bead, truncateEventDetails(r.Details, 80))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if total > len(evs) {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("(showing the last %d of %d events; use -n 0 for all)", len(evs), total)))
+	}
+	return nil
+}
+
+func truncateEventDetails(s string, max int) string {
+	if len([]rune(s)) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-1]) + "…"
+}
+
+func outputEventGroups(format string, fields []string, groups []events.GroupCount, total int) error {
+	switch format {
+	case "json":
+		out := make([]map[string]interface{}, 0, len(groups))
+		for _, g := range groups {
+			row := map[string]interface{}{"count": g.Count}
+			for i, f := range fields {
+				row[f] = g.Values[i]
+			}
+			out = append(out, row)
+		}
+		return outputJSON(out)
+
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		_ = w.Write(append(append([]string{}, fields...), "count"))
+		for _, g := range groups {
+			_ = w.Write(append(append([]string{}, g.Values...), strconv.Itoa(g.Count)))
+		}
+		w.Flush()
+		return w.Error()
+	}
+
+	if len(groups) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No matching events."))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(fields, "\t"))+"\tCOUNT")
+	for _, g := range groups {
+		values := make([]string, len(g.Values))
+		for i, v := range g.Values {
+			values[i] = v
+			if v == "" {
+				values[i] = "-"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%d\n", strings.Join(values, "\t"), g.Count)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("%d event(s) in %d group(s)", total, len(groups))))
+	return nil
+}
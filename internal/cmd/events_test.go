@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEventsTime(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7d", now.Add(-7 * 24 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2026-01-03", time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"2026-01-03T08:00:00Z", time.Date(2026, 1, 3, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseEventsTime(tt.in, now)
+		if err != nil {
+			t.Errorf("parseEventsTime(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseEventsTime(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"soon", "-2h", "2026-13-01"} {
+		if _, err := parseEventsTime(bad, now); err == nil {
+			t.Errorf("parseEventsTime(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestFormatEventPayload(t *testing.T) {
+	got := formatEventPayload(map[string]interface{}{
+		"reason":   "zombie cleanup",
+		"bead":     "gt-1",
+		"sessions": []interface{}{"a", "b"},
+		"count":    float64(2),
+	})
+	want := `bead=gt-1 count=2 reason="zombie cleanup" sessions=["a","b"]`
+	if got != want {
+		t.Errorf("formatEventPayload = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	feedRulesFile      string
+	feedRulesEvents    string
+	feedRulesSince     string
+	feedRulesLast      int
+	feedRulesShowDrops bool
+	feedRulesJSON      bool
+)
+
+func init() {
+	feedCmd.AddCommand(feedRulesCmd)
+	feedRulesCmd.AddCommand(feedRulesTestCmd)
+
+	feedRulesCmd.PersistentFlags().StringVar(&feedRulesFile, "rules", "", "Rules file (default: <town>/settings/feed-rules.json)")
+
+	feedRulesTestCmd.Flags().StringVar(&feedRulesEvents, "events", "", "Events file or .jsonl.gz segment to replay (default: the town event log)")
+	feedRulesTestCmd.Flags().StringVar(&feedRulesSince, "since", "", "Only replay events from the last duration (e.g., 1h, 30m)")
+	feedRulesTestCmd.Flags().IntVarP(&feedRulesLast, "last", "n", 50, "Show only the last N results (0 = all)")
+	feedRulesTestCmd.Flags().BoolVar(&feedRulesShowDrops, "dropped", false, "Also show dropped events and why")
+	feedRulesTestCmd.Flags().BoolVar(&feedRulesJSON, "json", false, "Output decisions as JSON")
+}
+
+var feedRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Show the feed curation rules",
+	Long: `Show the rules the feed curator applies to raw events.
+
+Rules live in <town>/settings/feed-rules.json. They are checked in order and
+the first rule matching an event decides what happens to it; events no rule
+matches are kept with the built-in summary. Without a rules file the built-in
+rules apply (dedupe done signals, aggregate sling bursts, flag failures).
+
+Example settings/feed-rules.json:
+
+  {
+    "type": "feed-rules",
+    "version": 1,
+    "rules": [
+      {"name": "quiet-patrols", "match": {"types": ["patrol_*"]}, "action": "drop"},
+      {"name": "dedupe-done", "match": {"types": ["done"]},
+       "dedupe": {"key": "{{.Actor}}/{{.Payload.bead}}", "window": "1m"}},
+      {"name": "mail-bursts", "match": {"types": ["mail"]},
+       "aggregate": {"window": "1m", "min_count": 5,
+                     "summary": "{{.Actor}} sent {{.Count}} messages"}},
+      {"name": "merges", "match": {"types": ["merged"]},
+       "summary": "✓ {{.Payload.branch}} merged"},
+      {"name": "failures", "match": {"types": ["merge_failed", "session_death"]},
+       "severity": "warning"}
+    ]
+  }
+
+Match fields (types, actors, sources, payload) take glob patterns. Templates
+see {{.Type}}, {{.Actor}}, {{.Source}}, {{.Payload.<field>}}, {{.Summary}}
+(the built-in summary) and, in aggregate summaries, {{.Count}}.
+
+The daemon's curator reloads the file when it changes. Use 'gt feed rules
+test' to see what a rules file does to the events already recorded.`,
+	RunE: runFeedRules,
+}
+
+var feedRulesTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Replay recorded events through the feed rules",
+	Long: `Replay the event log through the feed rules and show the resulting feed.
+
+Dedupe and aggregation windows are measured from each event's timestamp, so
+the output is what the curator would have written had these rules been in
+effect. Nothing is written to .feed.jsonl.
+
+Examples:
+  gt feed rules test                          # Last 50 feed entries
+  gt feed rules test --since 1h --dropped     # Include drops and their rule
+  gt feed rules test --rules /tmp/rules.json  # Try a draft rules file
+  gt feed rules test -n 0 --json              # Every decision as JSON`,
+	RunE: runFeedRulesTest,
+}
+
+// loadFeedRulesConfig loads --rules or the town rules file, returning the
+// config and where it came from.
+func loadFeedRulesConfig(townRoot string) (*config.FeedRulesConfig, string, error) {
+	if feedRulesFile != "" {
+		cfg, err := config.LoadFeedRulesConfig(feedRulesFile)
+		return cfg, feedRulesFile, err
+	}
+	path := config.FeedRulesConfigPath(townRoot)
+	cfg, err := config.LoadFeedRulesConfig(path)
+	if err == nil {
+		return cfg, path, nil
+	}
+	if !errors.Is(err, config.ErrNotFound) {
+		return nil, path, err
+	}
+	return config.NewFeedRulesConfig(), "built-in rules", nil
+}
+
+func runFeedRules(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, source, err := loadFeedRulesConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if _, err := feed.CompileRules(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Feed rules from %s\n\n", style.Bold.Render("📰"), source)
+	if len(cfg.Rules) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(no rules: every feed-visible event is kept)"))
+		return nil
+	}
+	for i, r := range cfg.Rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rules[%d]", i)
+		}
+		fmt.Printf("  %d. %s  %s\n", i+1, style.Bold.Render(name), style.Dim.Render("match "+describeFeedMatch(r.Match)))
+		fmt.Printf("     %s\n", describeFeedRule(r))
+	}
+	return nil
+}
+
+// describeFeedMatch renders a rule's match for display.
+func describeFeedMatch(m config.FeedRuleMatch) string {
+	var parts []string
+	if len(m.Types) > 0 {
+		parts = append(parts, "type="+strings.Join(m.Types, "|"))
+	}
+	if len(m.Actors) > 0 {
+		parts = append(parts, "actor="+strings.Join(m.Actors, "|"))
+	}
+	if len(m.Sources) > 0 {
+		parts = append(parts, "source="+strings.Join(m.Sources, "|"))
+	}
+	fields := make([]string, 0, len(m.Payload))
+	for f := range m.Payload {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	for _, f := range fields {
+		parts = append(parts, "payload."+f+"="+m.Payload[f])
+	}
+	if len(parts) == 0 {
+		return "all events"
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeFeedRule renders what a rule does for display.
+func describeFeedRule(r config.FeedRule) string {
+	if r.Action == config.FeedActionDrop {
+		return "drop"
+	}
+	parts := []string{"keep"}
+	if r.Severity != "" {
+		parts = append(parts, "severity "+r.Severity)
+	}
+	if r.Summary != "" {
+		parts = append(parts, fmt.Sprintf("summary %q", r.Summary))
+	}
+	if d := r.Dedupe; d != nil {
+		parts = append(parts, fmt.Sprintf("dedupe by %q within %s", orDefaultKey(d.Key), d.Window))
+	}
+	if a := r.Aggregate; a != nil {
+		parts = append(parts, fmt.Sprintf("aggregate by %q within %s", orDefaultKey(a.Key), a.Window))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orDefaultKey(key string) string {
+	if key == "" {
+		return "{{.Actor}}"
+	}
+	return key
+}
+
+func runFeedRulesTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, source, err := loadFeedRulesConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	rules, err := feed.CompileRules(cfg)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if feedRulesSince != "" {
+		d, err := time.ParseDuration(feedRulesSince)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid --since %q: use a duration like 1h", feedRulesSince)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	var evs []events.Event
+	if feedRulesEvents != "" {
+		if evs, err = events.ReadFile(feedRulesEvents); err != nil {
+			return err
+		}
+		evs = eventsSince(evs, since)
+	} else {
+		err = events.Each(townRoot, since, func(e events.Event) bool {
+			evs = append(evs, e)
+			return true
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	decisions := rules.Replay(evs)
+
+	var kept int
+	dropped := make(map[string]int)
+	var shown []feed.Decision
+	for _, d := range decisions {
+		if d.Feed != nil {
+			kept++
+		} else {
+			dropped[dropLabel(d)]++
+		}
+		if d.Feed != nil || feedRulesShowDrops || feedRulesJSON {
+			shown = append(shown, d)
+		}
+	}
+	if feedRulesLast > 0 && len(shown) > feedRulesLast {
+		shown = shown[len(shown)-feedRulesLast:]
+	}
+
+	if feedRulesJSON {
+		if shown == nil {
+			shown = []feed.Decision{}
+		}
+		return outputJSON(shown)
+	}
+
+	fmt.Printf("%s Replayed %d event(s) through %s: %d in feed, %d dropped\n",
+		style.Bold.Render("📰"), len(decisions), source, kept, len(decisions)-kept)
+	if len(dropped) > 0 {
+		labels := make([]string, 0, len(dropped))
+		for label := range dropped {
+			labels = append(labels, label)
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			if dropped[labels[i]] != dropped[labels[j]] {
+				return dropped[labels[i]] > dropped[labels[j]]
+			}
+			return labels[i] < labels[j]
+		})
+		var parts []string
+		for _, label := range labels {
+			parts = append(parts, fmt.Sprintf("%s %d", label, dropped[label]))
+		}
+		fmt.Printf("   %s\n", style.Dim.Render("dropped: "+strings.Join(parts, ", ")))
+	}
+	fmt.Println()
+
+	if len(shown) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("(feed would be empty)"))
+		return nil
+	}
+	for _, d := range shown {
+		fmt.Println(formatFeedDecision(d))
+	}
+	return nil
+}
+
+// eventsSince returns the events at or after cutoff; a zero cutoff keeps all.
+func eventsSince(evs []events.Event, cutoff time.Time) []events.Event {
+	if cutoff.IsZero() {
+		return evs
+	}
+	var result []events.Event
+	for _, e := range evs {
+		if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil && !ts.Before(cutoff) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// dropLabel says why an event was dropped, naming the rule if one applied.
+func dropLabel(d feed.Decision) string {
+	if d.Rule == "" {
+		return d.Reason
+	}
+	return d.Reason + " (" + d.Rule + ")"
+}
+
+// formatFeedDecision renders one replayed decision as a feed line.
+func formatFeedDecision(d feed.Decision) string {
+	when := d.Event.Timestamp
+	if ts, err := time.Parse(time.RFC3339, when); err == nil {
+		when = ts.Local().Format("01-02 15:04:05")
+	}
+
+	if d.Feed == nil {
+		return style.Dim.Render(fmt.Sprintf("  %s  ✗ %-16s %s — %s", when, d.Event.Type, d.Event.Actor, dropLabel(d)))
+	}
+
+	line := fmt.Sprintf("  %s  %-18s %s", when, d.Feed.Type, d.Feed.Summary)
+	if d.Feed.Count > 0 {
+		line += style.Dim.Render(fmt.Sprintf(" (×%d)", d.Feed.Count))
+	}
+	switch d.Feed.Severity {
+	case config.FeedSeverityCritical:
+		line += " " + style.Bold.Render("[critical]")
+	case config.FeedSeverityWarning:
+		line += " " + style.Bold.Render("[warning]")
+	}
+	if d.Rule != "" && feedRulesShowDrops {
+		line += style.Dim.Render(" · " + d.Rule)
+	}
+	return line
+}
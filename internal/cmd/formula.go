@@ -12,9 +12,9 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
-	"github.com/steveyegge/gastown/internal/beads"
-	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 	"golang.org/x/text/cases"
@@ -23,12 +23,15 @@ import (
 
 // Formula command flags
 var (
-	formulaListJSON   bool
-	formulaShowJSON   bool
-	formulaRunPR      int
-	formulaRunRig     string
-	formulaRunDryRun  bool
-	formulaCreateType string
+	formulaListJSON     bool
+	formulaShowJSON     bool
+	formulaShowResolved bool
+	formulaRunPR        int
+	formulaRunRig       string
+	formulaRunDryRun    bool
+	formulaRunVars      []string
+	formulaRunTargets   []string
+	formulaCreateType   string
 )
 
 var formulaCmd = &cobra.Command{
@@ -88,9 +91,14 @@ Shows:
   - Steps with dependencies
   - Composition rules (extends, aspects)
 
+With --resolved, prints the final formula after extends and [[include]]
+are merged, as TOML (or JSON with --json). Steps override inherited steps
+with the same id, or are inserted with before = "<id>" / after = "<id>".
+
 Examples:
   gt formula show shiny
-  gt formula show rule-of-five --json`,
+  gt formula show rule-of-five --json
+  gt formula show shiny-secure --resolved`,
 	Args: cobra.ExactArgs(1),
 	RunE: runFormulaShow,
 }
@@ -98,28 +106,42 @@ Examples:
 var formulaRunCmd = &cobra.Command{
 	Use:   "run [name]",
 	Short: "Execute a formula",
-	Long: `Execute a formula by pouring it and dispatching work.
+	Long: `Execute a formula by creating its work and dispatching it.
 
-This command:
-  1. Looks up the formula by name (or uses default from rig config)
-  2. Pours it to create a molecule (or uses existing proto)
-  3. Dispatches the molecule to available workers
+How a formula runs depends on its type:
+  convoy     Each leg becomes a bead tracked by a convoy, plus a synthesis
+             bead blocked on every leg
+  aspect     Each aspect fans out like a convoy leg
+  workflow   Steps become a molecule, created in dependency order; the
+             first ready steps are dispatched
+  expansion  Templates are instantiated once per --target, then run as a
+             workflow molecule
 
-For PR-based workflows, use --pr to specify the GitHub PR number.
+All dispatched beads are slung to the target rig in one gt sling command,
+so each gets its own polecat.
 
 If no formula name is provided, uses the default formula configured in
 the rig's settings/config.json under workflow.default_formula.
 
+Inputs and vars are checked against their declared type, pattern, range
+and choices before anything is created, and names the formula does not
+declare are rejected. In a terminal, missing required inputs are prompted
+for; otherwise the run fails listing every missing input.
+
 Options:
-  --pr=N      Run formula on GitHub PR #N
-  --rig=NAME  Target specific rig (default: current or gastown)
-  --dry-run   Show what would happen without executing
+  --pr=N          Run formula on GitHub PR #N (convoy formulas)
+  --rig=NAME      Target specific rig (default: current or gastown)
+  --var=KEY=VAL   Formula input or variable; vars replace {{KEY}} in steps (repeatable)
+  --target=ID     Expansion input, a bead ID or free text (repeatable)
+  --dry-run       Show what would happen without executing
 
 Examples:
   gt formula run shiny                    # Run formula in current rig
   gt formula run                          # Run default formula from rig config
-  gt formula run shiny --pr=123           # Run on PR #123
+  gt formula run code-review --pr=123     # Review PR #123
   gt formula run security-audit --rig=beads  # Run in specific rig
+  gt formula run beads-release --var version=0.9.0
+  gt formula run rule-of-five --target=gt-abc --target=gt-def
   gt formula run release --dry-run        # Preview execution`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runFormulaRun,
@@ -152,11 +174,14 @@ func init() {
 
 	// Show flags
 	formulaShowCmd.Flags().BoolVar(&formulaShowJSON, "json", false, "Output as JSON")
+	formulaShowCmd.Flags().BoolVar(&formulaShowResolved, "resolved", false, "Show the formula with extends and includes merged")
 
 	// Run flags
 	formulaRunCmd.Flags().IntVar(&formulaRunPR, "pr", 0, "GitHub PR number to run formula on")
 	formulaRunCmd.Flags().StringVar(&formulaRunRig, "rig", "", "Target rig (default: current or gastown)")
 	formulaRunCmd.Flags().BoolVar(&formulaRunDryRun, "dry-run", false, "Preview execution without running")
+	formulaRunCmd.Flags().StringArrayVar(&formulaRunVars, "var", nil, "Formula variable (key=value), can be repeated")
+	formulaRunCmd.Flags().StringArrayVar(&formulaRunTargets, "target", nil, "Expansion target (bead ID or text), can be repeated")
 
 	// Create flags
 	formulaCreateCmd.Flags().StringVar(&formulaCreateType, "type", "task", "Formula type: task, workflow, or patrol")
@@ -186,6 +211,9 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 // runFormulaShow delegates to bd formula show
 func runFormulaShow(cmd *cobra.Command, args []string) error {
 	formulaName := args[0]
+	if formulaShowResolved {
+		return runFormulaShowResolved(formulaName)
+	}
 	bdArgs := []string{"formula", "show", formulaName}
 	if formulaShowJSON {
 		bdArgs = append(bdArgs, "--json")
@@ -197,469 +225,20 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 	return bdCmd.Run()
 }
 
-// runFormulaRun executes a formula by spawning a convoy of polecats.
-// For convoy-type formulas, it creates a convoy bead, creates leg beads,
-// and slings each leg to a separate polecat with leg-specific prompts.
-func runFormulaRun(cmd *cobra.Command, args []string) error {
-	// Determine target rig first (needed for default formula lookup)
-	targetRig := formulaRunRig
-	var rigPath string
-	if targetRig == "" {
-		// Try to detect from current directory
-		townRoot, err := workspace.FindFromCwd()
-		if err == nil && townRoot != "" {
-			rigName, r, rigErr := findCurrentRig(townRoot)
-			if rigErr == nil && rigName != "" {
-				targetRig = rigName
-				if r != nil {
-					rigPath = r.Path
-				}
-			}
-			// If we still don't have a target rig but have townRoot, use gastown
-			if targetRig == "" {
-				targetRig = "gastown"
-				rigPath = filepath.Join(townRoot, "gastown")
-			}
-		} else {
-			// No town root found, fall back to gastown without rigPath
-			targetRig = "gastown"
-		}
-	} else {
-		// If rig specified, construct path
-		townRoot, err := workspace.FindFromCwd()
-		if err == nil && townRoot != "" {
-			rigPath = filepath.Join(townRoot, targetRig)
-		}
-	}
-
-	// Get formula name from args or default
-	var formulaName string
-	if len(args) > 0 {
-		formulaName = args[0]
-	} else {
-		// Try to get default formula from rig config
-		if rigPath != "" {
-			formulaName = config.GetDefaultFormula(rigPath)
-		}
-		if formulaName == "" {
-			return fmt.Errorf("no formula specified and no default formula configured\n\nTo set a default formula, add to your rig's settings/config.json:\n  \"workflow\": {\n    \"default_formula\": \"<formula-name>\"\n  }")
-		}
-		fmt.Printf("%s Using default formula: %s\n", style.Dim.Render("Note:"), formulaName)
-	}
-
-	// Find the formula file
-	formulaPath, err := findFormulaFile(formulaName)
-	if err != nil {
-		return fmt.Errorf("finding formula: %w", err)
-	}
-
-	// Parse the formula
-	f, err := parseFormulaFile(formulaPath)
-	if err != nil {
-		return fmt.Errorf("parsing formula: %w", err)
-	}
-
-	// Handle dry-run mode
-	if formulaRunDryRun {
-		return dryRunFormula(f, formulaName, targetRig)
-	}
-
-	// Currently only convoy formulas are supported for execution
-	if f.Type != "convoy" {
-		fmt.Printf("%s Formula type '%s' not yet supported for execution.\n",
-			style.Dim.Render("Note:"), f.Type)
-		fmt.Printf("Currently only 'convoy' formulas can be run.\n")
-		fmt.Printf("\nTo run '%s' manually:\n", formulaName)
-		fmt.Printf("  1. View formula:   gt formula show %s\n", formulaName)
-		fmt.Printf("  2. Cook to proto:  bd cook %s\n", formulaName)
-		fmt.Printf("  3. Pour molecule:  bd pour %s\n", formulaName)
-		fmt.Printf("  4. Sling to rig:   gt sling <mol-id> %s\n", targetRig)
-		return nil
-	}
-
-	// Execute convoy formula
-	return executeConvoyFormula(f, formulaName, targetRig)
-}
-
-// dryRunFormula shows what would happen without executing
-func dryRunFormula(f *formulaData, formulaName, targetRig string) error {
-	fmt.Printf("%s Would execute formula:\n", style.Dim.Render("[dry-run]"))
-	fmt.Printf("  Formula: %s\n", style.Bold.Render(formulaName))
-	fmt.Printf("  Type:    %s\n", f.Type)
-	fmt.Printf("  Rig:     %s\n", targetRig)
-	if formulaRunPR > 0 {
-		fmt.Printf("  PR:      #%d\n", formulaRunPR)
-	}
-
-	if f.Type == "convoy" && len(f.Legs) > 0 {
-		// Generate review ID for dry-run display
-		reviewID := generateFormulaShortID()
-
-		// Build target description
-		var targetDescription string
-		if formulaRunPR > 0 {
-			targetDescription = fmt.Sprintf("PR #%d", formulaRunPR)
-		} else {
-			targetDescription = "local files"
-		}
-
-		// Fetch PR info if --pr flag is set
-		var prTitle string
-		var changedFiles []map[string]interface{}
-		if formulaRunPR > 0 {
-			prTitle, changedFiles = fetchPRInfo(formulaRunPR)
-			if prTitle != "" {
-				fmt.Printf("  PR Title: %s\n", prTitle)
-			}
-			if len(changedFiles) > 0 {
-				fmt.Printf("  Changed files: %d\n", len(changedFiles))
-			}
-		}
-
-		// Show output directory if configured
-		var outputDir string
-		if f.Output != nil && f.Output.Directory != "" {
-			dirCtx := map[string]interface{}{
-				"review_id":    reviewID,
-				"formula_name": formulaName,
-			}
-			outputDir = renderTemplateOrDefault(f.Output.Directory, dirCtx, ".reviews/"+reviewID)
-			fmt.Printf("\n  Output directory: %s\n", outputDir)
-		}
-
-		fmt.Printf("\n  Legs (%d parallel):\n", len(f.Legs))
-		for _, leg := range f.Legs {
-			// Show rendered output path for each leg
-			if f.Output != nil && outputDir != "" {
-				legCtx := map[string]interface{}{
-					"formula_name":       formulaName,
-					"target_description": targetDescription,
-					"review_id":          reviewID,
-					"pr_number":          formulaRunPR,
-					"pr_title":           prTitle,
-					"leg": map[string]interface{}{
-						"id":          leg.ID,
-						"title":       leg.Title,
-						"focus":       leg.Focus,
-						"description": leg.Description,
-					},
-					"changed_files": changedFiles,
-				}
-				legPattern := renderTemplateOrDefault(f.Output.LegPattern, legCtx, leg.ID+"-findings.md")
-				outputPath := filepath.Join(outputDir, legPattern)
-				fmt.Printf("    • %s: %s\n      → %s\n", leg.ID, leg.Title, outputPath)
-			} else {
-				fmt.Printf("    • %s: %s\n", leg.ID, leg.Title)
-			}
-		}
-		if f.Synthesis != nil {
-			fmt.Printf("\n  Synthesis:\n")
-			if f.Output != nil && outputDir != "" {
-				synthPath := filepath.Join(outputDir, f.Output.Synthesis)
-				fmt.Printf("    • %s\n      → %s\n", f.Synthesis.Title, synthPath)
-			} else {
-				fmt.Printf("    • %s\n", f.Synthesis.Title)
-			}
-		}
-	}
-
-	return nil
-}
-
-// executeConvoyFormula spawns a convoy of polecats to execute a convoy formula
-func executeConvoyFormula(f *formulaData, formulaName, targetRig string) error {
-	fmt.Printf("%s Executing convoy formula: %s\n\n",
-		style.Bold.Render("🚚"), formulaName)
-
-	// Get town beads directory for convoy creation
-	townRoot, err := workspace.FindFromCwd()
+// runFormulaShowResolved prints a formula with its composition merged.
+func runFormulaShowResolved(name string) error {
+	f, err := loadFormula(name)
 	if err != nil {
-		return fmt.Errorf("finding town root: %w", err)
+		return err
 	}
-	townBeads := filepath.Join(townRoot, ".beads")
-
-	// Step 1: Create convoy bead
-	convoyID := fmt.Sprintf("hq-cv-%s", generateFormulaShortID())
-	convoyTitle := fmt.Sprintf("%s: %s", formulaName, f.Description)
-	if len(convoyTitle) > 80 {
-		convoyTitle = convoyTitle[:77] + "..."
-	}
-
-	// Build description with formula context
-	description := fmt.Sprintf("Formula convoy: %s\n\nLegs: %d\nRig: %s",
-		formulaName, len(f.Legs), targetRig)
-	if formulaRunPR > 0 {
-		description += fmt.Sprintf("\nPR: #%d", formulaRunPR)
-	}
-
-	createArgs := []string{
-		"create",
-		"--type=convoy",
-		"--id=" + convoyID,
-		"--title=" + convoyTitle,
-		"--description=" + description,
-	}
-	if beads.NeedsForceForID(convoyID) {
-		createArgs = append(createArgs, "--force")
-	}
-
-	createCmd := exec.Command("bd", createArgs...)
-	createCmd.Dir = townBeads
-	createCmd.Stderr = os.Stderr
-	if err := createCmd.Run(); err != nil {
-		return fmt.Errorf("creating convoy bead: %w", err)
-	}
-
-	fmt.Printf("%s Created convoy: %s\n", style.Bold.Render("✓"), convoyID)
-
-	// Generate a unique review ID for this convoy run
-	reviewID := generateFormulaShortID()
-
-	// Build target description
-	var targetDescription string
-	if formulaRunPR > 0 {
-		targetDescription = fmt.Sprintf("PR #%d", formulaRunPR)
-	} else {
-		targetDescription = "local files"
-	}
-
-	// Fetch PR info if --pr flag is set
-	var prTitle string
-	var changedFiles []map[string]interface{}
-	if formulaRunPR > 0 {
-		prTitle, changedFiles = fetchPRInfo(formulaRunPR)
-	}
-
-	// Create output directory if configured
-	var outputDir string
-	if f.Output != nil && f.Output.Directory != "" {
-		// Build minimal context for directory rendering
-		dirCtx := map[string]interface{}{
-			"review_id":    reviewID,
-			"formula_name": formulaName,
-		}
-		outputDir = renderTemplateOrDefault(f.Output.Directory, dirCtx, ".reviews/"+reviewID)
-
-		// Create the directory
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			fmt.Printf("%s Failed to create output directory %s: %v\n",
-				style.Dim.Render("Warning:"), outputDir, err)
-		} else {
-			fmt.Printf("  %s Output directory: %s\n", style.Dim.Render("📁"), outputDir)
-		}
-	}
-
-	// Step 2: Create leg beads and track them
-	legBeads := make(map[string]string) // leg.ID -> bead ID
-	for _, leg := range f.Legs {
-		legBeadID := fmt.Sprintf("hq-leg-%s", generateFormulaShortID())
-
-		// Build leg description with prompt if available
-		legDesc := leg.Description
-		if f.Prompts != nil {
-			if basePrompt, ok := f.Prompts["base"]; ok {
-				// Build template context for this leg
-				legCtx := map[string]interface{}{
-					"formula_name":       formulaName,
-					"target_description": targetDescription,
-					"review_id":          reviewID,
-					"pr_number":          formulaRunPR,
-					"pr_title":           prTitle,
-					"leg": map[string]interface{}{
-						"id":          leg.ID,
-						"title":       leg.Title,
-						"focus":       leg.Focus,
-						"description": leg.Description,
-					},
-					"changed_files": changedFiles,
-					"files":         []string{}, // TODO: support --files flag
-				}
-
-				// Compute output path for this leg
-				if f.Output != nil {
-					legPattern := renderTemplateOrDefault(f.Output.LegPattern, legCtx, leg.ID+"-findings.md")
-					outputPath := filepath.Join(outputDir, legPattern)
-					legCtx["output_path"] = outputPath
-					legCtx["output"] = map[string]interface{}{
-						"directory": outputDir,
-						"synthesis": f.Output.Synthesis,
-					}
-				}
-
-				// Render the base prompt with template context
-				renderedPrompt, err := renderTemplate(basePrompt, legCtx)
-				if err != nil {
-					fmt.Printf("%s Failed to render template for %s: %v\n",
-						style.Dim.Render("Warning:"), leg.ID, err)
-					renderedPrompt = basePrompt // Fall back to raw template
-				}
-				legDesc = fmt.Sprintf("%s\n\n---\nBase Prompt:\n%s", leg.Description, renderedPrompt)
-			}
-		}
-
-		legArgs := []string{
-			"create",
-			"--type=task",
-			"--id=" + legBeadID,
-			"--title=" + leg.Title,
-			"--description=" + legDesc,
-		}
-		if beads.NeedsForceForID(legBeadID) {
-			legArgs = append(legArgs, "--force")
-		}
-
-		legCmd := exec.Command("bd", legArgs...)
-		legCmd.Dir = townBeads
-		legCmd.Stderr = os.Stderr
-		if err := legCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to create leg bead for %s: %v\n",
-				style.Dim.Render("Warning:"), leg.ID, err)
-			continue
-		}
-
-		// Track the leg with the convoy
-		trackArgs := []string{"dep", "add", convoyID, legBeadID, "--type=tracks"}
-		trackCmd := exec.Command("bd", trackArgs...)
-		trackCmd.Dir = townBeads
-		if err := trackCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to track leg %s: %v\n",
-				style.Dim.Render("Warning:"), leg.ID, err)
-		}
-
-		legBeads[leg.ID] = legBeadID
-		fmt.Printf("  %s Created leg: %s (%s)\n", style.Dim.Render("○"), leg.ID, legBeadID)
-	}
-
-	// Step 3: Create synthesis bead if defined
-	var synthesisBeadID string
-	if f.Synthesis != nil {
-		synthesisBeadID = fmt.Sprintf("hq-syn-%s", generateFormulaShortID())
-
-		synDesc := f.Synthesis.Description
-		if synDesc == "" {
-			synDesc = "Synthesize findings from all legs into unified output"
-		}
-
-		synArgs := []string{
-			"create",
-			"--type=task",
-			"--id=" + synthesisBeadID,
-			"--title=" + f.Synthesis.Title,
-			"--description=" + synDesc,
-		}
-		if beads.NeedsForceForID(synthesisBeadID) {
-			synArgs = append(synArgs, "--force")
-		}
-
-		synCmd := exec.Command("bd", synArgs...)
-		synCmd.Dir = townBeads
-		synCmd.Stderr = os.Stderr
-		if err := synCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to create synthesis bead: %v\n",
-				style.Dim.Render("Warning:"), err)
-		} else {
-			// Track synthesis with convoy
-			trackArgs := []string{"dep", "add", convoyID, synthesisBeadID, "--type=tracks"}
-			trackCmd := exec.Command("bd", trackArgs...)
-			trackCmd.Dir = townBeads
-			_ = trackCmd.Run()
-
-			// Add dependencies: synthesis depends on all legs
-			for _, legBeadID := range legBeads {
-				depArgs := []string{"dep", "add", synthesisBeadID, legBeadID}
-				depCmd := exec.Command("bd", depArgs...)
-				depCmd.Dir = townBeads
-				_ = depCmd.Run()
-			}
-
-			fmt.Printf("  %s Created synthesis: %s\n", style.Dim.Render("★"), synthesisBeadID)
-		}
-	}
-
-	// Step 4: Sling each leg to a polecat
-	fmt.Printf("\n%s Dispatching legs to polecats...\n\n", style.Bold.Render("→"))
-
-	slingCount := 0
-	for _, leg := range f.Legs {
-		legBeadID, ok := legBeads[leg.ID]
-		if !ok {
-			continue
-		}
-
-		// Build context message for the polecat
-		contextMsg := fmt.Sprintf("Convoy leg: %s\nFocus: %s", leg.Title, leg.Focus)
-
-		// Use gt sling with args for leg-specific context
-		slingArgs := []string{
-			"sling", legBeadID, targetRig,
-			"-a", leg.Description,
-			"-s", leg.Title,
-		}
-
-		slingCmd := exec.Command("gt", slingArgs...)
-		slingCmd.Stdout = os.Stdout
-		slingCmd.Stderr = os.Stderr
-
-		if err := slingCmd.Run(); err != nil {
-			fmt.Printf("%s Failed to sling leg %s: %v\n",
-				style.Dim.Render("Warning:"), leg.ID, err)
-			// Add comment to bead about failure
-			commentArgs := []string{"comment", legBeadID, fmt.Sprintf("Failed to sling: %v", err)}
-			commentCmd := exec.Command("bd", commentArgs...)
-			commentCmd.Dir = townBeads
-			_ = commentCmd.Run()
-			continue
-		}
-
-		slingCount++
-		_ = contextMsg // Used in future for richer context
-	}
-
-	// Summary
-	fmt.Printf("\n%s Convoy dispatched!\n", style.Bold.Render("✓"))
-	fmt.Printf("  Convoy:  %s\n", convoyID)
-	fmt.Printf("  Legs:    %d dispatched\n", slingCount)
-	if synthesisBeadID != "" {
-		fmt.Printf("  Synthesis: %s (blocked until legs complete)\n", synthesisBeadID)
+	if formulaShowJSON {
+		return outputJSON(f)
 	}
-	fmt.Printf("\n  Track progress: gt convoy status %s\n", convoyID)
-
-	return nil
-}
-
-// formulaData holds parsed formula information
-type formulaData struct {
-	Name        string
-	Description string
-	Type        string
-	Legs        []formulaLeg
-	Synthesis   *formulaSynthesis
-	Prompts     map[string]string
-	Output      *formulaOutput
+	return toml.NewEncoder(os.Stdout).Encode(f)
 }
 
-type formulaOutput struct {
-	Directory  string
-	LegPattern string
-	Synthesis  string
-}
-
-type formulaLeg struct {
-	ID          string
-	Title       string
-	Focus       string
-	Description string
-}
-
-type formulaSynthesis struct {
-	Title       string
-	Description string
-	DependsOn   []string
-}
-
-// findFormulaFile searches for a formula file by name
-func findFormulaFile(name string) (string, error) {
-	// Search paths in order
+// formulaSearchPaths returns the directories searched for formulas, in order.
+func formulaSearchPaths() []string {
 	searchPaths := []string{}
 
 	// 1. Project .beads/formulas/
@@ -677,9 +256,14 @@ func findFormulaFile(name string) (string, error) {
 		searchPaths = append(searchPaths, filepath.Join(home, ".beads", "formulas"))
 	}
 
+	return searchPaths
+}
+
+// findFormulaFile searches for a formula file by name
+func findFormulaFile(name string) (string, error) {
 	// Try each path with common extensions
 	extensions := []string{".formula.toml", ".formula.json"}
-	for _, basePath := range searchPaths {
+	for _, basePath := range formulaSearchPaths() {
 		for _, ext := range extensions {
 			path := filepath.Join(basePath, name+ext)
 			if _, err := os.Stat(path); err == nil {
@@ -691,207 +275,18 @@ func findFormulaFile(name string) (string, error) {
 	return "", fmt.Errorf("formula '%s' not found in search paths", name)
 }
 
-// parseFormulaFile parses a formula file into formulaData
-func parseFormulaFile(path string) (*formulaData, error) {
-	data, err := os.ReadFile(path)
+// loadFormula finds, parses and resolves a formula by name. Formulas it
+// extends or includes are looked up in the same search paths.
+func loadFormula(name string) (*formula.Formula, error) {
+	path, err := findFormulaFile(name)
 	if err != nil {
-		return nil, err
-	}
-
-	// Use simple TOML parsing for the fields we need
-	// (avoids importing the full formula package which might cause cycles)
-	f := &formulaData{
-		Prompts: make(map[string]string),
-	}
-
-	content := string(data)
-
-	// Parse formula name
-	if match := extractTOMLValue(content, "formula"); match != "" {
-		f.Name = match
-	}
-
-	// Parse description
-	if match := extractTOMLMultiline(content, "description"); match != "" {
-		f.Description = match
-	}
-
-	// Parse type
-	if match := extractTOMLValue(content, "type"); match != "" {
-		f.Type = match
-	}
-
-	// Parse legs (convoy formulas)
-	f.Legs = extractLegs(content)
-
-	// Parse synthesis
-	f.Synthesis = extractSynthesis(content)
-
-	// Parse prompts
-	f.Prompts = extractPrompts(content)
-
-	// Parse output config
-	f.Output = extractOutput(content)
-
-	return f, nil
-}
-
-// extractTOMLValue extracts a simple quoted value from TOML
-func extractTOMLValue(content, key string) string {
-	// Match: key = "value" or key = 'value'
-	for _, line := range strings.Split(content, "\n") {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, key+" =") || strings.HasPrefix(line, key+"=") {
-			parts := strings.SplitN(line, "=", 2)
-			if len(parts) == 2 {
-				val := strings.TrimSpace(parts[1])
-				// Remove quotes
-				if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') {
-					return val[1 : len(val)-1]
-				}
-				return val
-			}
-		}
-	}
-	return ""
-}
-
-// extractTOMLMultiline extracts a multiline string (""" ... """)
-func extractTOMLMultiline(content, key string) string {
-	// Look for key = """
-	keyPattern := key + ` = """`
-	idx := strings.Index(content, keyPattern)
-	if idx == -1 {
-		// Try single-line
-		return extractTOMLValue(content, key)
-	}
-
-	start := idx + len(keyPattern)
-	end := strings.Index(content[start:], `"""`)
-	if end == -1 {
-		return ""
-	}
-
-	return strings.TrimSpace(content[start : start+end])
-}
-
-// extractLegs parses [[legs]] sections from TOML
-func extractLegs(content string) []formulaLeg {
-	var legs []formulaLeg
-
-	// Split by [[legs]]
-	sections := strings.Split(content, "[[legs]]")
-	for i, section := range sections {
-		if i == 0 {
-			continue // Skip content before first [[legs]]
-		}
-
-		// Find where this section ends (next [[ or EOF)
-		endIdx := strings.Index(section, "[[")
-		if endIdx == -1 {
-			endIdx = len(section)
-		}
-		section = section[:endIdx]
-
-		leg := formulaLeg{
-			ID:          extractTOMLValue(section, "id"),
-			Title:       extractTOMLValue(section, "title"),
-			Focus:       extractTOMLValue(section, "focus"),
-			Description: extractTOMLMultiline(section, "description"),
-		}
-
-		if leg.ID != "" {
-			legs = append(legs, leg)
-		}
-	}
-
-	return legs
-}
-
-// extractSynthesis parses [synthesis] section from TOML
-func extractSynthesis(content string) *formulaSynthesis {
-	idx := strings.Index(content, "[synthesis]")
-	if idx == -1 {
-		return nil
-	}
-
-	section := content[idx:]
-	// Find where section ends
-	if endIdx := strings.Index(section[1:], "\n["); endIdx != -1 {
-		section = section[:endIdx+1]
-	}
-
-	syn := &formulaSynthesis{
-		Title:       extractTOMLValue(section, "title"),
-		Description: extractTOMLMultiline(section, "description"),
+		return nil, fmt.Errorf("finding formula: %w", err)
 	}
-
-	// Parse depends_on array
-	if depsLine := extractTOMLValue(section, "depends_on"); depsLine != "" {
-		// Simple array parsing: ["a", "b", "c"]
-		depsLine = strings.Trim(depsLine, "[]")
-		for _, dep := range strings.Split(depsLine, ",") {
-			dep = strings.Trim(strings.TrimSpace(dep), `"'`)
-			if dep != "" {
-				syn.DependsOn = append(syn.DependsOn, dep)
-			}
-		}
-	}
-
-	if syn.Title == "" && syn.Description == "" {
-		return nil
-	}
-
-	return syn
-}
-
-// extractPrompts parses [prompts] section from TOML
-func extractPrompts(content string) map[string]string {
-	prompts := make(map[string]string)
-
-	idx := strings.Index(content, "[prompts]")
-	if idx == -1 {
-		return prompts
-	}
-
-	section := content[idx:]
-	// Find where section ends
-	if endIdx := strings.Index(section[1:], "\n["); endIdx != -1 {
-		section = section[:endIdx+1]
-	}
-
-	// Extract base prompt
-	if base := extractTOMLMultiline(section, "base"); base != "" {
-		prompts["base"] = base
-	}
-
-	return prompts
-}
-
-// extractOutput parses [output] section from TOML
-func extractOutput(content string) *formulaOutput {
-	idx := strings.Index(content, "[output]")
-	if idx == -1 {
-		return nil
-	}
-
-	section := content[idx:]
-	// Find where section ends (next [ that isn't part of output)
-	if endIdx := strings.Index(section[1:], "\n["); endIdx != -1 {
-		section = section[:endIdx+1]
-	}
-
-	out := &formulaOutput{
-		Directory:  extractTOMLValue(section, "directory"),
-		LegPattern: extractTOMLValue(section, "leg_pattern"),
-		Synthesis:  extractTOMLValue(section, "synthesis"),
-	}
-
-	if out.Directory == "" && out.LegPattern == "" && out.Synthesis == "" {
-		return nil
+	f, err := formula.ParseFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing formula: %w", err)
 	}
-
-	return out
+	return f.Resolve(formula.DirLoader(formulaSearchPaths()...))
 }
 
 // renderTemplate renders a Go text/template with the given context map
@@ -0,0 +1,806 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// formulaRunPlan is the work a formula run creates, worked out from the
+// parsed formula before any beads exist.
+type formulaRunPlan struct {
+	Formula *formula.Formula
+
+	// Inputs are the bound input and var values.
+	Inputs map[string]string
+
+	// Legs are the parallel units of convoy and aspect formulas.
+	Legs []formula.Leg
+
+	// Molecule holds the steps of workflow and expansion formulas, with
+	// variables applied and templates instantiated.
+	Molecule *formula.Formula
+
+	// Order is the molecule's steps in dependency order (TopologicalSort).
+	Order []string
+
+	// Waves groups the molecule's steps as ParallelReadySteps releases
+	// them. Waves[0] is dispatched when the run starts.
+	Waves [][]string
+
+	// Skipped are steps whose when condition is already false given the
+	// vars. They are created closed and left out of Waves.
+	Skipped map[string]bool
+}
+
+// planFormulaRun works out what running f creates and dispatches.
+// vars are the values bound by Formula.Bind and fill {{name}} placeholders
+// in workflow steps; targets are what an expansion formula is
+// instantiated for.
+func planFormulaRun(f *formula.Formula, vars map[string]string, targets []formula.Target) (*formulaRunPlan, error) {
+	plan := &formulaRunPlan{Formula: f, Inputs: vars}
+
+	mol := f
+	switch f.Type {
+	case formula.TypeConvoy:
+		plan.Legs = f.Legs
+		return plan, nil
+	case formula.TypeAspect:
+		// Aspects fan out exactly like convoy legs.
+		for _, a := range f.Aspects {
+			plan.Legs = append(plan.Legs, formula.Leg(a))
+		}
+		return plan, nil
+	case formula.TypeExpansion:
+		expanded, err := f.Expand(targets)
+		if err != nil {
+			return nil, err
+		}
+		mol = expanded
+	}
+
+	mol = applyFormulaVars(mol, vars)
+	order, err := mol.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	plan.Molecule = mol
+	plan.Order = order
+	plan.Skipped = skippedFormulaSteps(mol, order, vars)
+	plan.Waves = mol.WavesAfter(plan.Skipped)
+	return plan, nil
+}
+
+// skippedFormulaSteps returns the steps whose when condition can be
+// decided before the run starts and is false: conditions that only read
+// vars and the outcome of steps skipped the same way. The rest are
+// evaluated by gt mol step done as the molecule runs.
+func skippedFormulaSteps(mol *formula.Formula, order []string, vars map[string]string) map[string]bool {
+	skipped := make(map[string]bool)
+	for _, id := range order {
+		step := mol.GetStep(id)
+		if step.When == "" {
+			continue
+		}
+		cond, err := formula.ParseCondition(step.When)
+		if err != nil {
+			continue // Rejected by Validate
+		}
+		decidable := true
+		for _, ref := range cond.Refs() {
+			if dep, _, ok := formula.StepRef(ref); ok && !skipped[dep] {
+				decidable = false
+			}
+		}
+		if !decidable {
+			continue
+		}
+		met := cond.Eval(func(ref string) string {
+			if name, ok := strings.CutPrefix(ref, "vars."); ok {
+				return vars[name]
+			}
+			if _, field, _ := formula.StepRef(ref); field == "outcome" {
+				return beads.StepOutcomeSkipped
+			}
+			return ""
+		})
+		if !met {
+			skipped[id] = true
+		}
+	}
+	return skipped
+}
+
+// applyFormulaVars returns a copy of f with {{name}} placeholders in step
+// titles and descriptions replaced by the bound values.
+func applyFormulaVars(f *formula.Formula, vars map[string]string) *formula.Formula {
+	var pairs []string
+	for name, v := range vars {
+		pairs = append(pairs, "{{"+name+"}}", v)
+	}
+	r := strings.NewReplacer(pairs...)
+
+	out := *f
+	out.Steps = make([]formula.Step, len(f.Steps))
+	for i, step := range f.Steps {
+		step.Title = r.Replace(step.Title)
+		step.Description = r.Replace(step.Description)
+		out.Steps[i] = step
+	}
+	return &out
+}
+
+// parseFormulaVars parses --var key=value flags.
+func parseFormulaVars(args []string) (map[string]string, error) {
+	vars := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected key=value", arg)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// bindFormulaInputs validates --var values (and --pr) against the
+// formula's declared inputs. In a terminal, missing values are prompted
+// for; otherwise every missing input is reported at once.
+func bindFormulaInputs(f *formula.Formula, vars map[string]string) (map[string]string, error) {
+	if formulaRunPR > 0 {
+		if _, ok := f.Inputs["pr"]; ok {
+			if _, set := vars["pr"]; !set {
+				vars["pr"] = strconv.Itoa(formulaRunPR)
+			}
+		}
+	}
+
+	opts := formula.BindOptions{Check: checkFormulaInput}
+	if formulaRunInteractive() {
+		opts.Prompt = promptFormulaInput(bufio.NewReader(os.Stdin))
+	}
+	values, err := f.Bind(vars, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// --var pr=N is the same as --pr=N.
+	if pr, ok := values["pr"]; ok && formulaRunPR == 0 {
+		formulaRunPR, _ = strconv.Atoi(pr)
+	}
+	return values, nil
+}
+
+// formulaRunInteractive reports whether gt formula run can prompt.
+func formulaRunInteractive() bool {
+	return !formulaRunDryRun && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// promptFormulaInput returns a BindOptions.Prompt that reads from reader.
+func promptFormulaInput(reader *bufio.Reader) func(formula.Param, error) (string, error) {
+	return func(p formula.Param, problem error) (string, error) {
+		if problem != nil {
+			style.PrintWarning("%v", problem)
+		}
+		fmt.Printf("%s: ", p.Usage())
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return "", err
+		}
+		return strings.TrimSpace(line), nil
+	}
+}
+
+// checkFormulaInput checks that bead-id, rig and path inputs refer to
+// things that exist.
+func checkFormulaInput(p formula.Param, value string) error {
+	switch p.Type {
+	case formula.ParamBeadID:
+		if err := verifyBeadExists(value); err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+	case formula.ParamRig:
+		if _, ok := IsRigName(value); !ok {
+			return fmt.Errorf("%s: no rig named %q", p.Name, value)
+		}
+	case formula.ParamPath:
+		if _, err := os.Stat(value); err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// resolveFormulaTargets turns --target values into expansion targets.
+// Bead IDs take the bead's title and description; anything else is used
+// as free text.
+func resolveFormulaTargets(townRoot string, args []string) []formula.Target {
+	var b *beads.Beads
+	if townRoot != "" {
+		b = beads.New(townRoot)
+	}
+	targets := make([]formula.Target, 0, len(args))
+	for _, arg := range args {
+		if b != nil {
+			if issue, err := b.Show(arg); err == nil {
+				targets = append(targets, formula.Target{ID: issue.ID, Title: issue.Title, Description: issue.Description})
+				continue
+			}
+		}
+		targets = append(targets, formula.Target{ID: arg, Title: arg, Description: arg})
+	}
+	return targets
+}
+
+// formatStepPolicy summarizes a step's timeout, retry and when settings
+// for display, or returns "" if it has none.
+func formatStepPolicy(step *formula.Step) string {
+	var parts []string
+	if step.Timeout != "" {
+		parts = append(parts, "timeout "+step.Timeout)
+	}
+	if step.MaxAttempts > 0 {
+		parts = append(parts, fmt.Sprintf("%d attempts", step.MaxAttempts))
+	}
+	if step.OnFailure != "" {
+		parts = append(parts, "on failure "+step.OnFailure)
+	}
+	if step.When != "" {
+		parts = append(parts, "when "+step.When)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// formulaSlingArgs builds the single gt sling invocation that dispatches a
+// run's beads to a rig. With several beads, sling gives each one its own
+// polecat.
+func formulaSlingArgs(beadIDs []string, targetRig string, noConvoy bool) []string {
+	args := append([]string{"sling"}, beadIDs...)
+	args = append(args, targetRig)
+	if noConvoy {
+		args = append(args, "--no-convoy")
+	}
+	return args
+}
+
+// slingFormulaBeads dispatches beadIDs to targetRig with one gt sling.
+func slingFormulaBeads(beadIDs []string, targetRig string, noConvoy bool) error {
+	slingCmd := exec.Command("gt", formulaSlingArgs(beadIDs, targetRig, noConvoy)...)
+	slingCmd.Stdout = os.Stdout
+	slingCmd.Stderr = os.Stderr
+	return slingCmd.Run()
+}
+
+// runFormulaRun executes a formula: convoy and aspect formulas fan out into
+// legs tracked by a convoy, workflow and expansion formulas become a
+// molecule. Either way the ready work is slung to the rig in one command.
+func runFormulaRun(cmd *cobra.Command, args []string) error {
+	// Determine target rig first (needed for default formula lookup)
+	targetRig := formulaRunRig
+	var rigPath string
+	townRoot, _ := workspace.FindFromCwd()
+	if targetRig == "" {
+		// Try to detect from current directory
+		if townRoot != "" {
+			rigName, r, rigErr := findCurrentRig(townRoot)
+			if rigErr == nil && rigName != "" {
+				targetRig = rigName
+				if r != nil {
+					rigPath = r.Path
+				}
+			}
+			// If we still don't have a target rig but have townRoot, use gastown
+			if targetRig == "" {
+				targetRig = "gastown"
+				rigPath = filepath.Join(townRoot, "gastown")
+			}
+		} else {
+			// No town root found, fall back to gastown without rigPath
+			targetRig = "gastown"
+		}
+	} else if townRoot != "" {
+		rigPath = filepath.Join(townRoot, targetRig)
+	}
+
+	// Get formula name from args or default
+	var formulaName string
+	if len(args) > 0 {
+		formulaName = args[0]
+	} else {
+		// Try to get default formula from rig config
+		if rigPath != "" {
+			formulaName = config.GetDefaultFormula(rigPath)
+		}
+		if formulaName == "" {
+			return fmt.Errorf("no formula specified and no default formula configured\n\nTo set a default formula, add to your rig's settings/config.json:\n  \"workflow\": {\n    \"default_formula\": \"<formula-name>\"\n  }")
+		}
+		fmt.Printf("%s Using default formula: %s\n", style.Dim.Render("Note:"), formulaName)
+	}
+
+	f, err := loadFormula(formulaName)
+	if err != nil {
+		return err
+	}
+
+	vars, err := parseFormulaVars(formulaRunVars)
+	if err != nil {
+		return err
+	}
+	values, err := bindFormulaInputs(f, vars)
+	if err != nil {
+		return err
+	}
+	var targets []formula.Target
+	if f.Type == formula.TypeExpansion {
+		targets = resolveFormulaTargets(townRoot, formulaRunTargets)
+	}
+
+	plan, err := planFormulaRun(f, values, targets)
+	if err != nil {
+		return err
+	}
+
+	if formulaRunDryRun {
+		return dryRunFormula(plan, formulaName, targetRig)
+	}
+	if plan.Molecule != nil {
+		return executeMoleculeFormula(plan, formulaName, targetRig)
+	}
+	return executeConvoyFormula(plan, formulaName, targetRig)
+}
+
+// convoyRunContext is the template context shared by every leg of a
+// convoy run.
+type convoyRunContext struct {
+	formulaName       string
+	inputs            map[string]string
+	reviewID          string
+	targetDescription string
+	prTitle           string
+	changedFiles      []map[string]interface{}
+	outputDir         string
+}
+
+func newConvoyRunContext(plan *formulaRunPlan, formulaName string) *convoyRunContext {
+	f := plan.Formula
+	c := &convoyRunContext{
+		formulaName:       formulaName,
+		inputs:            plan.Inputs,
+		reviewID:          generateFormulaShortID(),
+		targetDescription: "local files",
+	}
+	if formulaRunPR > 0 {
+		c.targetDescription = fmt.Sprintf("PR #%d", formulaRunPR)
+		c.prTitle, c.changedFiles = fetchPRInfo(formulaRunPR)
+	}
+	if f.Output != nil && f.Output.Directory != "" {
+		dirCtx := map[string]interface{}{
+			"review_id":    c.reviewID,
+			"formula_name": formulaName,
+		}
+		c.outputDir = renderTemplateOrDefault(f.Output.Directory, dirCtx, ".reviews/"+c.reviewID)
+	}
+	return c
+}
+
+// legContext builds the template context for one leg. Bound inputs are
+// available by name; the built-in keys take precedence.
+func (c *convoyRunContext) legContext(f *formula.Formula, leg formula.Leg) map[string]interface{} {
+	ctx := make(map[string]interface{}, len(c.inputs)+10)
+	for name, v := range c.inputs {
+		ctx[name] = v
+	}
+	ctx["formula_name"] = c.formulaName
+	ctx["target_description"] = c.targetDescription
+	ctx["review_id"] = c.reviewID
+	ctx["pr_number"] = formulaRunPR
+	ctx["pr_title"] = c.prTitle
+	ctx["leg"] = map[string]interface{}{
+		"id":          leg.ID,
+		"title":       leg.Title,
+		"focus":       leg.Focus,
+		"description": leg.Description,
+	}
+	ctx["changed_files"] = c.changedFiles
+	ctx["files"] = []string{} // TODO: support --files flag
+	if f.Output != nil {
+		legPattern := renderTemplateOrDefault(f.Output.LegPattern, ctx, leg.ID+"-findings.md")
+		ctx["output_path"] = filepath.Join(c.outputDir, legPattern)
+		ctx["output"] = map[string]interface{}{
+			"directory": c.outputDir,
+			"synthesis": f.Output.Synthesis,
+		}
+	}
+	return ctx
+}
+
+// dryRunFormula shows what would happen without executing
+func dryRunFormula(plan *formulaRunPlan, formulaName, targetRig string) error {
+	f := plan.Formula
+	fmt.Printf("%s Would execute formula:\n", style.Dim.Render("[dry-run]"))
+	fmt.Printf("  Formula: %s\n", style.Bold.Render(formulaName))
+	fmt.Printf("  Type:    %s\n", f.Type)
+	fmt.Printf("  Rig:     %s\n", targetRig)
+	if formulaRunPR > 0 {
+		fmt.Printf("  PR:      #%d\n", formulaRunPR)
+	}
+	if len(plan.Inputs) > 0 {
+		names := make([]string, 0, len(plan.Inputs))
+		for name := range plan.Inputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("\n  Inputs:\n")
+		for _, name := range names {
+			fmt.Printf("    %s = %s\n", name, plan.Inputs[name])
+		}
+	}
+
+	if plan.Molecule != nil {
+		fmt.Printf("\n  Molecule steps (%d, in dependency order):\n", len(plan.Order))
+		for i, wave := range plan.Waves {
+			label := ""
+			if len(wave) > 1 {
+				label = style.Dim.Render(" (parallel)")
+			}
+			fmt.Printf("    Wave %d%s:\n", i+1, label)
+			for _, id := range wave {
+				step := plan.Molecule.GetStep(id)
+				fmt.Printf("      • %s: %s%s\n", id, step.Title, style.Dim.Render(formatStepPolicy(step)))
+			}
+		}
+		for _, id := range plan.Order {
+			if plan.Skipped[id] {
+				fmt.Printf("    Skipped: %s %s\n", id, style.Dim.Render("(when "+plan.Molecule.GetStep(id).When+")"))
+			}
+		}
+		if len(plan.Waves) > 0 {
+			fmt.Printf("\n  Would sling wave 1 (%d step bead(s)) to %s in one gt sling\n",
+				len(plan.Waves[0]), targetRig)
+		}
+		return nil
+	}
+
+	run := newConvoyRunContext(plan, formulaName)
+	if run.prTitle != "" {
+		fmt.Printf("  PR Title: %s\n", run.prTitle)
+	}
+	if len(run.changedFiles) > 0 {
+		fmt.Printf("  Changed files: %d\n", len(run.changedFiles))
+	}
+	if run.outputDir != "" {
+		fmt.Printf("\n  Output directory: %s\n", run.outputDir)
+	}
+
+	fmt.Printf("\n  Legs (%d parallel):\n", len(plan.Legs))
+	for _, leg := range plan.Legs {
+		if path, ok := run.legContext(f, leg)["output_path"]; ok && run.outputDir != "" {
+			fmt.Printf("    • %s: %s\n      → %s\n", leg.ID, leg.Title, path)
+		} else {
+			fmt.Printf("    • %s: %s\n", leg.ID, leg.Title)
+		}
+	}
+	if f.Synthesis != nil {
+		fmt.Printf("\n  Synthesis:\n")
+		if f.Output != nil && run.outputDir != "" {
+			synthPath := filepath.Join(run.outputDir, f.Output.Synthesis)
+			fmt.Printf("    • %s\n      → %s\n", f.Synthesis.Title, synthPath)
+		} else {
+			fmt.Printf("    • %s\n", f.Synthesis.Title)
+		}
+	}
+
+	return nil
+}
+
+// executeConvoyFormula spawns a convoy of polecats, one per leg of a convoy
+// or aspect formula.
+func executeConvoyFormula(plan *formulaRunPlan, formulaName, targetRig string) error {
+	f := plan.Formula
+	fmt.Printf("%s Executing %s formula: %s\n\n",
+		style.Bold.Render("🚚"), f.Type, formulaName)
+
+	// Get town beads directory for convoy creation
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+	townBeads := filepath.Join(townRoot, ".beads")
+
+	// Step 1: Create convoy bead
+	convoyID := fmt.Sprintf("hq-cv-%s", generateFormulaShortID())
+	convoyTitle := fmt.Sprintf("%s: %s", formulaName, f.Description)
+	if len(convoyTitle) > 80 {
+		convoyTitle = convoyTitle[:77] + "..."
+	}
+
+	// Build description with formula context
+	description := fmt.Sprintf("Formula convoy: %s\n\nLegs: %d\nRig: %s",
+		formulaName, len(plan.Legs), targetRig)
+	if formulaRunPR > 0 {
+		description += fmt.Sprintf("\nPR: #%d", formulaRunPR)
+	}
+
+	createArgs := []string{
+		"create",
+		"--type=convoy",
+		"--id=" + convoyID,
+		"--title=" + convoyTitle,
+		"--description=" + description,
+	}
+	if beads.NeedsForceForID(convoyID) {
+		createArgs = append(createArgs, "--force")
+	}
+
+	createCmd := exec.Command("bd", createArgs...)
+	createCmd.Dir = townBeads
+	createCmd.Stderr = os.Stderr
+	if err := createCmd.Run(); err != nil {
+		return fmt.Errorf("creating convoy bead: %w", err)
+	}
+
+	fmt.Printf("%s Created convoy: %s\n", style.Bold.Render("✓"), convoyID)
+
+	run := newConvoyRunContext(plan, formulaName)
+	if run.outputDir != "" {
+		if err := os.MkdirAll(run.outputDir, 0755); err != nil {
+			fmt.Printf("%s Failed to create output directory %s: %v\n",
+				style.Dim.Render("Warning:"), run.outputDir, err)
+		} else {
+			fmt.Printf("  %s Output directory: %s\n", style.Dim.Render("📁"), run.outputDir)
+		}
+	}
+
+	// Step 2: Create leg beads and track them
+	var legBeadIDs []string
+	for _, leg := range plan.Legs {
+		legBeadID := fmt.Sprintf("hq-leg-%s", generateFormulaShortID())
+
+		// Build leg description with prompt if available
+		legDesc := leg.Description
+		if basePrompt, ok := f.Prompts["base"]; ok {
+			renderedPrompt, err := renderTemplate(basePrompt, run.legContext(f, leg))
+			if err != nil {
+				fmt.Printf("%s Failed to render template for %s: %v\n",
+					style.Dim.Render("Warning:"), leg.ID, err)
+				renderedPrompt = basePrompt // Fall back to raw template
+			}
+			legDesc = fmt.Sprintf("%s\n\n---\nBase Prompt:\n%s", leg.Description, renderedPrompt)
+		}
+
+		legArgs := []string{
+			"create",
+			"--type=task",
+			"--id=" + legBeadID,
+			"--title=" + leg.Title,
+			"--description=" + legDesc,
+		}
+		if beads.NeedsForceForID(legBeadID) {
+			legArgs = append(legArgs, "--force")
+		}
+
+		legCmd := exec.Command("bd", legArgs...)
+		legCmd.Dir = townBeads
+		legCmd.Stderr = os.Stderr
+		if err := legCmd.Run(); err != nil {
+			fmt.Printf("%s Failed to create leg bead for %s: %v\n",
+				style.Dim.Render("Warning:"), leg.ID, err)
+			continue
+		}
+
+		// Track the leg with the convoy
+		trackArgs := []string{"dep", "add", convoyID, legBeadID, "--type=tracks"}
+		trackCmd := exec.Command("bd", trackArgs...)
+		trackCmd.Dir = townBeads
+		if err := trackCmd.Run(); err != nil {
+			fmt.Printf("%s Failed to track leg %s: %v\n",
+				style.Dim.Render("Warning:"), leg.ID, err)
+		}
+
+		legBeadIDs = append(legBeadIDs, legBeadID)
+		fmt.Printf("  %s Created leg: %s (%s)\n", style.Dim.Render("○"), leg.ID, legBeadID)
+	}
+
+	// Step 3: Create synthesis bead if defined
+	var synthesisBeadID string
+	if f.Synthesis != nil {
+		synthesisBeadID = fmt.Sprintf("hq-syn-%s", generateFormulaShortID())
+
+		synDesc := f.Synthesis.Description
+		if synDesc == "" {
+			synDesc = "Synthesize findings from all legs into unified output"
+		}
+
+		synArgs := []string{
+			"create",
+			"--type=task",
+			"--id=" + synthesisBeadID,
+			"--title=" + f.Synthesis.Title,
+			"--description=" + synDesc,
+		}
+		if beads.NeedsForceForID(synthesisBeadID) {
+			synArgs = append(synArgs, "--force")
+		}
+
+		synCmd := exec.Command("bd", synArgs...)
+		synCmd.Dir = townBeads
+		synCmd.Stderr = os.Stderr
+		if err := synCmd.Run(); err != nil {
+			fmt.Printf("%s Failed to create synthesis bead: %v\n",
+				style.Dim.Render("Warning:"), err)
+			synthesisBeadID = ""
+		} else {
+			// Track synthesis with convoy
+			trackArgs := []string{"dep", "add", convoyID, synthesisBeadID, "--type=tracks"}
+			trackCmd := exec.Command("bd", trackArgs...)
+			trackCmd.Dir = townBeads
+			_ = trackCmd.Run()
+
+			// Add dependencies: synthesis depends on all legs
+			for _, legBeadID := range legBeadIDs {
+				depArgs := []string{"dep", "add", synthesisBeadID, legBeadID}
+				depCmd := exec.Command("bd", depArgs...)
+				depCmd.Dir = townBeads
+				_ = depCmd.Run()
+			}
+
+			fmt.Printf("  %s Created synthesis: %s\n", style.Dim.Render("★"), synthesisBeadID)
+		}
+	}
+
+	if len(legBeadIDs) == 0 {
+		return fmt.Errorf("no leg beads created for convoy %s", convoyID)
+	}
+
+	// Step 4: Sling every leg in one command. The legs are already tracked
+	// by this convoy, so sling must not create its own.
+	fmt.Printf("\n%s Dispatching %d legs to %s...\n\n", style.Bold.Render("→"), len(legBeadIDs), targetRig)
+	if err := slingFormulaBeads(legBeadIDs, targetRig, true); err != nil {
+		fmt.Printf("\n  Retry: gt %s\n", strings.Join(formulaSlingArgs(legBeadIDs, targetRig, true), " "))
+		return fmt.Errorf("dispatching convoy %s: %w", convoyID, err)
+	}
+
+	// Summary
+	fmt.Printf("\n%s Convoy dispatched!\n", style.Bold.Render("✓"))
+	fmt.Printf("  Convoy:  %s\n", convoyID)
+	fmt.Printf("  Legs:    %d dispatched\n", len(legBeadIDs))
+	if synthesisBeadID != "" {
+		fmt.Printf("  Synthesis: %s (blocked until legs complete)\n", synthesisBeadID)
+	}
+	fmt.Printf("\n  Track progress: gt convoy status %s\n", convoyID)
+
+	return nil
+}
+
+// executeMoleculeFormula pours a workflow (or expanded) formula into a
+// molecule and slings its first ready steps. Steps are created as children
+// of the molecule in dependency order, so gt mol step done can walk them.
+func executeMoleculeFormula(plan *formulaRunPlan, formulaName, targetRig string) error {
+	mol := plan.Molecule
+	fmt.Printf("%s Executing %s formula: %s\n\n",
+		style.Bold.Render("🧪"), plan.Formula.Type, formulaName)
+
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+	b := beads.New(townRoot)
+
+	title := fmt.Sprintf("%s: %s", formulaName, mol.Description)
+	if len(title) > 80 {
+		title = title[:77] + "..."
+	}
+	description := fmt.Sprintf("Formula molecule: %s\n\nSteps: %d\nRig: %s", formulaName, len(plan.Order), targetRig)
+	if vars := beads.FormatMoleculeVars(plan.Inputs); vars != "" {
+		description += "\n\n" + vars
+	}
+	root, err := b.Create(beads.CreateOptions{
+		Title:       title,
+		Type:        "molecule",
+		Priority:    -1,
+		Description: description,
+	})
+	if err != nil {
+		return fmt.Errorf("creating molecule: %w", err)
+	}
+	fmt.Printf("%s Created molecule: %s\n", style.Bold.Render("✓"), root.ID)
+
+	dispatched := make(map[string]bool)
+	if len(plan.Waves) > 0 {
+		for _, id := range plan.Waves[0] {
+			dispatched[id] = true
+		}
+	}
+	startedAt := time.Now().Format(time.RFC3339)
+
+	stepBeads := make(map[string]string, len(plan.Order))
+	for _, id := range plan.Order {
+		step := mol.GetStep(id)
+		stepTitle := step.Title
+		if stepTitle == "" {
+			stepTitle = step.ID
+		}
+		// The policy fields let gt mol step done enforce timeouts, retries
+		// and conditions.
+		fields := &beads.StepFields{
+			Ref:         step.ID,
+			Timeout:     step.Timeout,
+			MaxAttempts: step.MaxAttempts,
+			OnFailure:   step.OnFailure,
+			When:        step.When,
+		}
+		if plan.Skipped[id] {
+			fields.Outcome = beads.StepOutcomeSkipped
+		} else if dispatched[id] && step.Timeout != "" {
+			fields.StartedAt = startedAt
+		}
+		issue, err := b.Create(beads.CreateOptions{
+			Title:       stepTitle,
+			Type:        "task",
+			Priority:    -1,
+			Description: beads.SetStepFields(&beads.Issue{Description: step.Description}, fields),
+			Parent:      root.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("creating step %s: %w", id, err)
+		}
+		stepBeads[id] = issue.ID
+
+		// Needs were created first (topological order).
+		for _, need := range step.Needs {
+			if err := b.AddDependency(issue.ID, stepBeads[need]); err != nil {
+				return fmt.Errorf("adding dependency %s -> %s: %w", id, need, err)
+			}
+		}
+
+		if plan.Skipped[id] {
+			if err := b.CloseWithReason("skipped: when "+step.When+" is false", issue.ID); err != nil {
+				return fmt.Errorf("skipping step %s: %w", id, err)
+			}
+			fmt.Printf("  %s Skipped step: %s (%s)\n", style.Dim.Render("–"), id, issue.ID)
+			continue
+		}
+		fmt.Printf("  %s Created step: %s (%s)\n", style.Dim.Render("○"), id, issue.ID)
+	}
+
+	var ready []string
+	for _, id := range plan.Order {
+		if dispatched[id] {
+			ready = append(ready, stepBeads[id])
+		}
+	}
+	if len(ready) == 0 {
+		fmt.Printf("\n%s Every step was skipped; nothing to dispatch\n", style.Dim.Render("ℹ"))
+		return nil
+	}
+
+	fmt.Printf("\n%s Dispatching %d ready step(s) to %s...\n\n", style.Bold.Render("→"), len(ready), targetRig)
+	if err := slingFormulaBeads(ready, targetRig, false); err != nil {
+		fmt.Printf("\n  Retry: gt %s\n", strings.Join(formulaSlingArgs(ready, targetRig, false), " "))
+		return fmt.Errorf("dispatching molecule %s: %w", root.ID, err)
+	}
+
+	fmt.Printf("\n%s Molecule dispatched!\n", style.Bold.Render("✓"))
+	fmt.Printf("  Molecule: %s\n", root.ID)
+	fmt.Printf("  Steps:    %d (%d dispatched, rest follow via gt mol step done)\n", len(plan.Order), len(ready))
+	fmt.Printf("\n  Track progress: gt mol dag %s\n", root.ID)
+
+	return nil
+}
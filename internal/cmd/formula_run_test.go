@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+func mustParseFormula(t *testing.T, data string) *formula.Formula {
+	t.Helper()
+	f, err := formula.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	return f
+}
+
+func TestPlanFormulaRun_Workflow(t *testing.T) {
+	f := mustParseFormula(t, `
+formula = "release"
+type = "workflow"
+version = 1
+[[steps]]
+id = "tag"
+title = "Tag v{{version}}"
+needs = ["bump"]
+[[steps]]
+id = "bump"
+title = "Bump to {{version}}"
+description = "Set {{version}} on {{branch}}"
+[vars.version]
+required = true
+[vars.branch]
+default = "main"
+`)
+
+	if _, err := bindFormulaInputs(f, map[string]string{}); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("expected missing version error, got %v", err)
+	}
+	if _, err := bindFormulaInputs(f, map[string]string{"version": "1.2.0", "brnach": "dev"}); err == nil || !strings.Contains(err.Error(), "brnach") {
+		t.Fatalf("expected unknown var error, got %v", err)
+	}
+
+	values, err := bindFormulaInputs(f, map[string]string{"version": "1.2.0"})
+	if err != nil {
+		t.Fatalf("bindFormulaInputs: %v", err)
+	}
+	plan, err := planFormulaRun(f, values, nil)
+	if err != nil {
+		t.Fatalf("planFormulaRun: %v", err)
+	}
+	if !reflect.DeepEqual(plan.Order, []string{"bump", "tag"}) {
+		t.Errorf("Order = %v, want [bump tag]", plan.Order)
+	}
+	if !reflect.DeepEqual(plan.Waves[0], []string{"bump"}) {
+		t.Errorf("Waves[0] = %v, want [bump]", plan.Waves[0])
+	}
+	bump := plan.Molecule.GetStep("bump")
+	if bump.Title != "Bump to 1.2.0" || bump.Description != "Set 1.2.0 on main" {
+		t.Errorf("bump = %+v", bump)
+	}
+	if f.GetStep("bump").Title != "Bump to {{version}}" {
+		t.Error("applying vars modified the parsed formula")
+	}
+}
+
+func TestPlanFormulaRun_FanOut(t *testing.T) {
+	aspect := mustParseFormula(t, `
+formula = "audit"
+type = "aspect"
+version = 1
+[[aspects]]
+id = "auth"
+title = "Authentication"
+focus = "login flows"
+[[aspects]]
+id = "input"
+title = "Input validation"
+`)
+	plan, err := planFormulaRun(aspect, nil, nil)
+	if err != nil {
+		t.Fatalf("planFormulaRun: %v", err)
+	}
+	if plan.Molecule != nil || len(plan.Legs) != 2 || plan.Legs[0].Focus != "login flows" {
+		t.Errorf("aspect plan = %+v", plan)
+	}
+
+	expansion := mustParseFormula(t, `
+formula = "refine"
+type = "expansion"
+version = 1
+[[template]]
+id = "{target}.draft"
+title = "Draft {target.title}"
+[[template]]
+id = "{target}.polish"
+title = "Polish"
+needs = ["{target}.draft"]
+`)
+	if _, err := planFormulaRun(expansion, nil, nil); err == nil {
+		t.Error("expected error for expansion without targets")
+	}
+	plan, err = planFormulaRun(expansion, nil, []formula.Target{{ID: "gt-a", Title: "A"}, {ID: "gt-b", Title: "B"}})
+	if err != nil {
+		t.Fatalf("planFormulaRun: %v", err)
+	}
+	if len(plan.Order) != 4 || !reflect.DeepEqual(plan.Waves[0], []string{"gt-a.draft", "gt-b.draft"}) {
+		t.Errorf("expansion order %v, waves %v", plan.Order, plan.Waves)
+	}
+}
+
+func TestFormulaSlingArgs(t *testing.T) {
+	got := formulaSlingArgs([]string{"hq-leg-a", "hq-leg-b"}, "gastown", true)
+	want := []string{"sling", "hq-leg-a", "hq-leg-b", "gastown", "--no-convoy"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("formulaSlingArgs = %v, want %v", got, want)
+	}
+}
+
+func TestParseFormulaVars(t *testing.T) {
+	vars, err := parseFormulaVars([]string{"version=1.0", "note=a=b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["version"] != "1.0" || vars["note"] != "a=b" {
+		t.Errorf("vars = %v", vars)
+	}
+	if _, err := parseFormulaVars([]string{"novalue"}); err == nil {
+		t.Error("expected error for missing '='")
+	}
+}
+
+func TestConvoyLegContext_Inputs(t *testing.T) {
+	f := mustParseFormula(t, `
+formula = "design"
+type = "convoy"
+[inputs.problem]
+required = true
+[inputs.review_id]
+[[legs]]
+id = "api"
+title = "API"
+`)
+	plan := &formulaRunPlan{Formula: f, Inputs: map[string]string{"problem": "rate limits", "review_id": "mine"}}
+	run := newConvoyRunContext(plan, "design")
+	ctx := run.legContext(f, f.Legs[0])
+	if ctx["problem"] != "rate limits" {
+		t.Errorf("problem = %v, want bound input", ctx["problem"])
+	}
+	if ctx["review_id"] != run.reviewID {
+		t.Errorf("review_id = %v, want built-in %s", ctx["review_id"], run.reviewID)
+	}
+}
@@ -54,8 +54,10 @@ var krcPruneCmd = &cobra.Command{
 	Short: "Remove expired events",
 	Long: `Prune events that have exceeded their TTL.
 
-Events are removed from both .events.jsonl and .feed.jsonl.
-The operation is atomic (uses temp files and rename).
+The event log is pruned by dropping whole rotated segments (.events/*.jsonl.gz)
+once every event in them has expired; events still in the active
+.events.jsonl are rotated out first, one segment per day. .feed.jsonl is
+rewritten without its expired events (atomically, via temp file and rename).
 
 Use --dry-run to preview what would be pruned without making changes.`,
 	RunE: runKrcPrune,
@@ -147,7 +149,7 @@ func runKrcStats(cmd *cobra.Command, args []string) error {
 
 	// File stats
 	fmt.Println(style.Bold.Render("Files:"))
-	fmt.Printf("  Events: %s (%d events)\n", formatBytes(stats.EventsFile.Size), stats.EventsFile.EventCount)
+	fmt.Printf("  Events: %s (%d events, %d rotated segments)\n", formatBytes(stats.EventsFile.Size), stats.EventsFile.EventCount, stats.Segments)
 	fmt.Printf("  Feed:   %s (%d events)\n", formatBytes(stats.FeedFile.Size), stats.FeedFile.EventCount)
 	fmt.Println()
 
@@ -245,7 +247,9 @@ func runKrcPrune(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %-20s %d events (TTL: %s)\n", t, info.Expired, krcFormatDuration(info.TTL))
 		}
 		fmt.Println()
-		fmt.Printf("Total: %d events would be pruned\n", totalExpired)
+		fmt.Printf("Total: %d events have expired\n", totalExpired)
+		fmt.Printf("Event log segments that would be dropped: %d of %d\n", stats.ExpiredSegments, stats.Segments)
+		fmt.Println(style.Dim.Render("(expired events are dropped once every event in their segment has expired)"))
 		fmt.Println()
 		fmt.Println("Run without --dry-run to prune.")
 		return nil
@@ -266,6 +270,7 @@ func runKrcPrune(cmd *cobra.Command, args []string) error {
 	fmt.Println(style.Bold.Render("Prune complete:"))
 	fmt.Printf("  Events processed: %d\n", result.EventsProcessed)
 	fmt.Printf("  Events pruned:    %d\n", result.EventsPruned)
+	fmt.Printf("  Segments dropped: %d\n", result.SegmentsPruned)
 	fmt.Printf("  Events retained:  %d\n", result.EventsRetained)
 	fmt.Printf("  Space saved:      %s\n", formatBytes(result.BytesBefore-result.BytesAfter))
 	fmt.Printf("  Duration:         %s\n", result.Duration.Round(time.Millisecond))
@@ -18,6 +18,10 @@ var (
 	mailNotify        bool
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
+	mailReplyBy       string
+	mailAt            string
+	mailIn            string
+	mailInterrupt     bool
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
@@ -29,12 +33,21 @@ var (
 	mailThreadJSON    bool
 	mailReplySubject  string
 	mailReplyMessage  string
+	mailStatusJSON    bool
+
+	// Scheduled flags
+	mailScheduledJSON bool
+	mailScheduledFrom string
+	mailScheduledAll  bool
 
 	// Search flags
 	mailSearchFrom    string
 	mailSearchSubject bool
 	mailSearchBody    bool
 	mailSearchArchive bool
+	mailSearchAll     bool
+	mailSearchLimit   int
+	mailSearchReindex bool
 	mailSearchJSON    bool
 
 	// Announces flags
@@ -130,7 +143,18 @@ Examples:
   gt mail send mayor/ -s "Re: Status" -m "Done" --reply-to msg-abc123
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
-  gt mail send list:oncall -s "Alert" -m "System down"`,
+  gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send gastown/witness -s "Review" -m "Check gt-abc" --reply-by 4h
+  gt mail send --self -s "Reminder" -m "Check CI on gt-abc" --in 2h
+  gt mail send gastown/refinery -s "Rebase" -m "Queue is quiet" --at 02:00
+
+A --reply-by deadline makes the message a task. The Deacon escalates task
+messages that are still unanswered after their deadline. Use
+'gt mail status <id>' to see who received, read and replied to a message.
+
+--at and --in defer delivery: the message is held as a scheduled bead and
+the daemon delivers it on the first heartbeat after it is due. Use
+'gt mail scheduled' to list or cancel pending messages.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMailSend,
 }
@@ -385,29 +409,54 @@ Examples:
 var mailSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search messages by content",
-	Long: `Search inbox for messages matching a pattern.
+	Long: `Search your mailbox using the town's mail index.
 
 SYNTAX:
   gt mail search <query> [flags]
 
-The query is a regular expression pattern. Search is case-insensitive by default.
+Results are ranked: matches in the subject count more than matches in the
+sender or recipient, which count more than matches in the body. Queries
+without free-text terms list matching messages newest first.
+
+QUERY LANGUAGE:
+  word              Message contains word (all words must match)
+  word*             Message contains a word starting with word
+  "some phrase"     Subject or body contains the phrase
+  -word             Message does not contain word
+  from:<addr>       Sender contains addr
+  to:<addr>         Recipient or CC contains addr
+  subject:<text>    Subject contains text (quote text with spaces)
+  body:<text>       Body contains text
+  thread:<id>       Message is in thread
+  label:<label>     Message bead has label
+  type:<type>       task, scavenge, notification or reply
+  priority:<p>      urgent, high, normal, low (or 0-3)
+  is:<state>        unread, read, archived, pinned, wisp or scheduled
+  after:<when>      Sent after 30m, 6h, 2d, 1w, today, 2006-01-02 or RFC3339
+  before:<when>     Sent before the given time
+Any field can be negated: -from:witness, -is:read.
+
+The index lives in <town>/.runtime/mail-index.json. Each search refreshes it
+with the messages that changed since the last search; --reindex rebuilds it.
 
 FLAGS:
-  --from <sender>   Filter by sender address (substring match)
-  --subject         Only search subject lines
-  --body            Only search message body
-  --archive         Include archived (closed) messages
+  --from <sender>   Filter by sender address (same as from:)
+  --subject         Match the query literally against subject lines
+  --body            Match the query literally against message bodies
+  --archive         Include archived messages
+  --all             Search all mail in the town, not just your mailbox
+  --limit <n>       Maximum number of results (default 50)
   --json            Output as JSON
 
-By default, searches both subject and body text.
-
 Examples:
-  gt mail search "urgent"                    # Find messages with "urgent"
-  gt mail search "status.*check" --subject   # Regex in subjects only
-  gt mail search "error" --from witness      # From witness, containing "error"
-  gt mail search "handoff" --archive         # Include archived messages
-  gt mail search "" --from mayor/            # All messages from mayor`,
-	Args: cobra.ExactArgs(1),
+  gt mail search urgent                               # Messages mentioning "urgent"
+  gt mail search 'from:witness subject:MERGED after:2d is:unread'
+  gt mail search '"merge queue" -flaky'               # Phrase, excluding a word
+  gt mail search "status check" --subject             # Literal subject match
+  gt mail search handoff --archive                    # Include archived messages
+  gt mail search "" --from mayor/                     # All messages from mayor
+  gt mail search 'type:task is:unread' --all --json   # Open tasks, town-wide`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runMailSearch,
 }
 
@@ -443,6 +492,54 @@ Examples:
 	RunE: runMailAnnounces,
 }
 
+var mailScheduledCmd = &cobra.Command{
+	Use:   "scheduled",
+	Short: "List scheduled messages",
+	Long: `List messages waiting for delivery, soonest first.
+
+Messages sent with --at or --in are held until they are due, then the
+daemon delivers them on its next heartbeat. By default only messages you
+sent are listed.
+
+Examples:
+  gt mail scheduled                    # Your scheduled messages
+  gt mail scheduled --all              # Everyone's scheduled messages
+  gt mail scheduled cancel hq-abc123   # Cancel before delivery`,
+	Args: cobra.NoArgs,
+	RunE: runMailScheduled,
+}
+
+var mailScheduledCancelCmd = &cobra.Command{
+	Use:   "cancel <message-id>...",
+	Short: "Cancel scheduled messages before delivery",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runMailScheduledCancel,
+}
+
+var mailStatusCmd = &cobra.Command{
+	Use:   "status <message-id>",
+	Short: "Show delivery, read and reply state per recipient",
+	Long: `Show who received, read and replied to a message.
+
+For list, group and multi-recipient sends, every copy of the fan-out is
+included. CC recipients are listed after the primary recipients.
+
+States:
+  pending    - not yet seen in the recipient's inbox
+  delivered  - listed in the recipient's inbox
+  read       - opened with gt mail read (or marked read)
+  replied    - the recipient replied with --reply-to
+
+Task messages sent with --reply-by are flagged OVERDUE once the deadline
+passes without a reply. The Deacon escalates those on patrol.
+
+Examples:
+  gt mail status hq-abc123
+  gt mail status hq-abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailStatus,
+}
+
 func init() {
 	// Send flags
 	mailSendCmd.Flags().StringVarP(&mailSubject, "subject", "s", "", "Message subject (required)")
@@ -458,6 +555,10 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().StringVar(&mailAt, "at", "", "Deliver at a time (15:04, 2006-01-02 15:04, or RFC3339)")
+	mailSendCmd.Flags().StringVar(&mailIn, "in", "", "Deliver after a delay (e.g., 2h, 30m)")
+	mailSendCmd.Flags().BoolVar(&mailInterrupt, "interrupt", false, "Inject the message into the recipient's session instead of a new-mail notice")
+	mailSendCmd.Flags().StringVar(&mailReplyBy, "reply-by", "", "Require a reply by a deadline (duration like 4h, or RFC3339 time); implies --type task")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -489,11 +590,23 @@ func init() {
 	mailSearchCmd.Flags().BoolVar(&mailSearchSubject, "subject", false, "Only search subject lines")
 	mailSearchCmd.Flags().BoolVar(&mailSearchBody, "body", false, "Only search message body")
 	mailSearchCmd.Flags().BoolVar(&mailSearchArchive, "archive", false, "Include archived messages")
+	mailSearchCmd.Flags().BoolVar(&mailSearchAll, "all", false, "Search all mail in the town, not just your mailbox")
+	mailSearchCmd.Flags().IntVarP(&mailSearchLimit, "limit", "n", 50, "Maximum number of results (0 = no limit)")
+	mailSearchCmd.Flags().BoolVar(&mailSearchReindex, "reindex", false, "Rebuild the search index before searching")
 	mailSearchCmd.Flags().BoolVar(&mailSearchJSON, "json", false, "Output as JSON")
 
 	// Announces flags
 	mailAnnouncesCmd.Flags().BoolVar(&mailAnnouncesJSON, "json", false, "Output as JSON")
 
+	// Status flags
+	mailStatusCmd.Flags().BoolVar(&mailStatusJSON, "json", false, "Output as JSON")
+
+	// Scheduled flags
+	mailScheduledCmd.Flags().BoolVar(&mailScheduledJSON, "json", false, "Output as JSON")
+	mailScheduledCmd.Flags().StringVar(&mailScheduledFrom, "from", "", "Only messages from this sender (default: you)")
+	mailScheduledCmd.Flags().BoolVarP(&mailScheduledAll, "all", "a", false, "List messages from all senders")
+	mailScheduledCmd.AddCommand(mailScheduledCancelCmd)
+
 	// Clear flags
 	mailClearCmd.Flags().BoolVar(&mailClearAll, "all", false, "Clear all messages (default behavior)")
 
@@ -518,6 +631,8 @@ func init() {
 	mailCmd.AddCommand(mailClearCmd)
 	mailCmd.AddCommand(mailSearchCmd)
 	mailCmd.AddCommand(mailAnnouncesCmd)
+	mailCmd.AddCommand(mailStatusCmd)
+	mailCmd.AddCommand(mailScheduledCmd)
 
 	rootCmd.AddCommand(mailCmd)
 }
@@ -65,6 +65,7 @@ func runMailCheck(cmd *cobra.Command, args []string) error {
 		if unread > 0 {
 			// Get subjects for context
 			messages, _ := mailbox.ListUnread()
+			_ = mailbox.MarkDelivered(messages)
 			var subjects []string
 			for _, msg := range messages {
 				subjects = append(subjects, fmt.Sprintf("- %s from %s: %s", msg.ID, msg.From, msg.Subject))
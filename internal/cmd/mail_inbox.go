@@ -66,6 +66,9 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("listing messages: %w", err)
 	}
 
+	// Delivery receipts are best-effort; they never block reading mail
+	_ = mailbox.MarkDelivered(messages)
+
 	// JSON output
 	if mailInboxJSON {
 		enc := json.NewEncoder(os.Stdout)
@@ -109,11 +112,23 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 			msg.From)
 		fmt.Printf("      %s\n",
 			style.Dim.Render(msg.Timestamp.Format("2006-01-02 15:04")))
+		if msg.ReplyBy != nil {
+			fmt.Printf("      %s\n", formatReplyBy(msg, time.Now()))
+		}
 	}
 
 	return nil
 }
 
+// formatReplyBy renders a message's reply deadline, flagging it when overdue.
+func formatReplyBy(msg *mail.Message, now time.Time) string {
+	deadline := "reply by " + msg.ReplyBy.Local().Format("2006-01-02 15:04")
+	if msg.ReplyOverdue(now) {
+		return style.Bold.Render(deadline + " (OVERDUE)")
+	}
+	return style.Dim.Render(deadline)
+}
+
 func runMailRead(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
 		return errors.New("message ID or index required")
@@ -148,6 +163,7 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("getting message: %w", err)
 	}
+	_ = mailbox.MarkDelivered([]*mail.Message{msg})
 
 	// Mark as read when viewed (adds "read" label, does not close/archive).
 	// Handoff messages are preserved via the hook mechanism, so marking
@@ -189,9 +205,12 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	if msg.ReplyTo != "" {
 		fmt.Printf("Reply-To: %s\n", style.Dim.Render(msg.ReplyTo))
 	}
+	if msg.ReplyBy != nil {
+		fmt.Printf("Reply-By: %s\n", formatReplyBy(msg, time.Now()))
+	}
 
 	if msg.Body != "" {
-		fmt.Printf("\n%s\n", msg.Body)
+		fmt.Printf("\n%s\n", mail.StripEnvelope(msg.Body))
 	}
 
 	return nil
@@ -229,7 +248,7 @@ func runMailPeek(cmd *cobra.Command, args []string) error {
 
 	// Body preview (truncate long bodies)
 	if msg.Body != "" {
-		body := msg.Body
+		body := mail.StripEnvelope(msg.Body)
 		// Truncate to ~500 chars for popup display
 		if len(body) > 500 {
 			body = body[:500] + "\n..."
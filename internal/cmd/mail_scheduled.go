@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+func runMailScheduled(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	router := mail.NewRouter(workDir)
+	scheduled, err := router.ListScheduled()
+	if err != nil {
+		return err
+	}
+
+	if !mailScheduledAll {
+		from := mailScheduledFrom
+		if from == "" {
+			from = detectSender()
+		}
+		var mine []*mail.Message
+		for _, msg := range scheduled {
+			if mail.AddressToIdentity(msg.From) == mail.AddressToIdentity(from) {
+				mine = append(mine, msg)
+			}
+		}
+		scheduled = mine
+	}
+
+	if mailScheduledJSON {
+		if scheduled == nil {
+			scheduled = []*mail.Message{}
+		}
+		return outputJSON(scheduled)
+	}
+
+	if len(scheduled) == 0 {
+		fmt.Printf("%s No scheduled messages\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	now := time.Now()
+	fmt.Printf("%s %d scheduled message(s)\n\n", style.Bold.Render("⏰"), len(scheduled))
+	for _, msg := range scheduled {
+		when := "due now"
+		if msg.DeliverAt != nil && msg.DeliverAt.After(now) {
+			when = fmt.Sprintf("%s (in %s)", msg.DeliverAt.Local().Format("2006-01-02 15:04"),
+				msg.DeliverAt.Sub(now).Round(time.Minute))
+		}
+		fmt.Printf("  %s %s\n", style.Bold.Render(msg.ID), msg.Subject)
+		fmt.Printf("      %s → %s, %s\n", msg.From, msg.To, when)
+	}
+	return nil
+}
+
+func runMailScheduledCancel(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	router := mail.NewRouter(workDir)
+	var failed int
+	for _, id := range args {
+		if err := router.CancelScheduled(id); err != nil {
+			style.PrintWarning("%s: %v", id, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s Cancelled %s\n", style.Bold.Render("✓"), id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d message(s) not cancelled", failed, len(args))
+	}
+	return nil
+}
+
+// parseDeliverAt parses the --at and --in flags into a delivery time, or
+// the zero time for immediate delivery. --at accepts a clock time (the next
+// occurrence of 15:04), a local date and time, or RFC3339.
+func parseDeliverAt(at, in string, now time.Time) (time.Time, error) {
+	switch {
+	case at != "" && in != "":
+		return time.Time{}, errors.New("--at and --in are mutually exclusive")
+	case in != "":
+		d, err := time.ParseDuration(in)
+		if err != nil || d <= 0 {
+			return time.Time{}, fmt.Errorf("invalid --in %q: use a positive duration like 2h or 30m", in)
+		}
+		return now.Add(d), nil
+	case at == "":
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, at); err == nil {
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("invalid --at %q: time is in the past", at)
+		}
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", at, now.Location()); err == nil {
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("invalid --at %q: time is in the past", at)
+		}
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("15:04", at, now.Location()); err == nil {
+		next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at %q: use 15:04, \"2006-01-02 15:04\" or RFC3339", at)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDeliverAt(t *testing.T) {
+	loc := time.FixedZone("test", 2*3600)
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, loc)
+
+	tests := []struct {
+		name    string
+		at, in  string
+		want    time.Time
+		wantErr string
+	}{
+		{"immediate", "", "", time.Time{}, ""},
+		{"in", "", "2h", now.Add(2 * time.Hour), ""},
+		{"clock later today", "14:30", "", time.Date(2026, 1, 2, 14, 30, 0, 0, loc), ""},
+		{"clock tomorrow", "02:00", "", time.Date(2026, 1, 3, 2, 0, 0, 0, loc), ""},
+		{"date and time", "2026-01-05 09:00", "", time.Date(2026, 1, 5, 9, 0, 0, 0, loc), ""},
+		{"rfc3339", "2026-01-05T09:00:00Z", "", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), ""},
+		{"both", "14:30", "2h", time.Time{}, "mutually exclusive"},
+		{"negative in", "", "-5m", time.Time{}, "positive duration"},
+		{"past", "2026-01-01 09:00", "", time.Time{}, "in the past"},
+		{"garbage", "soon", "", time.Time{}, "use 15:04"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDeliverAt(tt.at, tt.in, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseDeliverAt error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDeliverAt: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseDeliverAt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
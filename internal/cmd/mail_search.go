@@ -1,18 +1,17 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
-// runMailSearch searches for messages matching a pattern.
+// runMailSearch searches the mail index.
 func runMailSearch(cmd *cobra.Command, args []string) error {
-	query := args[0]
+	query := buildMailSearchQuery(strings.Join(args, " "), mailSearchFrom, mailSearchSubject, mailSearchBody)
 
 	// Determine which inbox to search
 	address := detectSender()
@@ -23,44 +22,40 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	// Get mailbox
-	router := mail.NewRouter(workDir)
-	mailbox, err := router.GetMailbox(address)
-	if err != nil {
-		return fmt.Errorf("getting mailbox: %w", err)
+	opts := mail.IndexSearchOptions{
+		IncludeArchived: mailSearchArchive,
+		Limit:           mailSearchLimit,
 	}
-
-	// Build search options
-	opts := mail.SearchOptions{
-		Query:       query,
-		FromFilter:  mailSearchFrom,
-		SubjectOnly: mailSearchSubject,
-		BodyOnly:    mailSearchBody,
+	scope := "all mail"
+	if !mailSearchAll {
+		opts.Identity = address
+		scope = address
 	}
 
-	// Execute search
-	messages, err := mailbox.Search(opts)
+	router := mail.NewRouter(workDir)
+	results, err := router.SearchMail(query, opts, mailSearchReindex)
 	if err != nil {
 		return fmt.Errorf("searching messages: %w", err)
 	}
 
 	// JSON output
 	if mailSearchJSON {
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		return enc.Encode(messages)
+		if results == nil {
+			results = []mail.SearchResult{}
+		}
+		return outputJSON(results)
 	}
 
 	// Human-readable output
 	fmt.Printf("%s Search results for %s: %d message(s)\n\n",
-		style.Bold.Render("🔍"), address, len(messages))
+		style.Bold.Render("🔍"), scope, len(results))
 
-	if len(messages) == 0 {
+	if len(results) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(no matches)"))
 		return nil
 	}
 
-	for _, msg := range messages {
+	for _, msg := range results {
 		readMarker := "●"
 		if msg.Read {
 			readMarker = "○"
@@ -77,14 +72,39 @@ func runMailSearch(cmd *cobra.Command, args []string) error {
 		if msg.Wisp {
 			wispMarker = " " + style.Dim.Render("(wisp)")
 		}
+		if msg.Archived {
+			wispMarker += " " + style.Dim.Render("(archived)")
+		}
 
 		fmt.Printf("  %s %s%s%s%s\n", readMarker, msg.Subject, typeMarker, priorityMarker, wispMarker)
-		fmt.Printf("    %s from %s\n",
-			style.Dim.Render(msg.ID),
-			msg.From)
+		if mailSearchAll {
+			fmt.Printf("    %s from %s to %s\n", style.Dim.Render(msg.ID), msg.From, msg.To)
+		} else {
+			fmt.Printf("    %s from %s\n", style.Dim.Render(msg.ID), msg.From)
+		}
 		fmt.Printf("    %s\n",
 			style.Dim.Render(msg.Timestamp.Format("2006-01-02 15:04")))
 	}
 
 	return nil
 }
+
+// buildMailSearchQuery folds the --from, --subject and --body flags into
+// the query. With --subject or --body the text is matched literally
+// rather than parsed as a query.
+func buildMailSearchQuery(text, from string, subjectOnly, bodyOnly bool) string {
+	var clauses []string
+	if literal := strings.TrimSpace(strings.ReplaceAll(text, `"`, "")); literal != "" && (subjectOnly || bodyOnly) {
+		field := "subject"
+		if bodyOnly {
+			field = "body"
+		}
+		clauses = append(clauses, field+`:"`+literal+`"`)
+	} else if !subjectOnly && !bodyOnly {
+		clauses = append(clauses, text)
+	}
+	if from = strings.TrimSpace(strings.ReplaceAll(from, `"`, "")); from != "" {
+		clauses = append(clauses, `from:"`+from+`"`)
+	}
+	return strings.TrimSpace(strings.Join(clauses, " "))
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import "testing"
+
+func TestBuildMailSearchQuery(t *testing.T) {
+	tests := []struct {
+		text, from    string
+		subject, body bool
+		want          string
+	}{
+		{"from:witness is:unread", "", false, false, "from:witness is:unread"},
+		{"error", "witness", false, false, `error from:"witness"`},
+		{"status check", "", true, false, `subject:"status check"`},
+		{`say "hi"`, "", false, true, `body:"say hi"`},
+		{"", "mayor/", false, false, `from:"mayor/"`},
+		{"", "", true, false, ""},
+	}
+	for _, tt := range tests {
+		got := buildMailSearchQuery(tt.text, tt.from, tt.subject, tt.body)
+		if got != tt.want {
+			t.Errorf("buildMailSearchQuery(%q, %q, %v, %v) = %q, want %q",
+				tt.text, tt.from, tt.subject, tt.body, got, tt.want)
+		}
+	}
+}
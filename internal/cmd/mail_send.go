@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -88,6 +89,38 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Set interrupt delivery: the recipient's session gets the message itself
+	if mailInterrupt {
+		msg.Delivery = mail.DeliveryInterrupt
+	}
+
+	// Set deferred delivery (--at/--in)
+	now := time.Now()
+	deliverAt, err := parseDeliverAt(mailAt, mailIn, now)
+	if err != nil {
+		return err
+	}
+	if !deliverAt.IsZero() {
+		msg.DeliverAt = &deliverAt
+	}
+
+	// Set reply deadline: a reply is expected, so the message is a task.
+	// Durations count from delivery, so scheduled mail gets the full window.
+	if mailReplyBy != "" {
+		base := now
+		if msg.DeliverAt != nil {
+			base = *msg.DeliverAt
+		}
+		replyBy, err := parseReplyBy(mailReplyBy, base)
+		if err != nil {
+			return err
+		}
+		msg.ReplyBy = &replyBy
+		if msg.Type == mail.TypeNotification {
+			msg.Type = mail.TypeTask
+		}
+	}
+
 	// Handle reply-to: auto-set type to reply and look up thread
 	if mailReplyTo != "" {
 		msg.ReplyTo = mailReplyTo
@@ -110,6 +143,20 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		msg.ThreadID = generateThreadID()
 	}
 
+	// Scheduled mail keeps the original address; groups and lists are
+	// resolved when the daemon releases it
+	if msg.DeliverAt != nil {
+		router := mail.NewRouter(workDir)
+		if err := router.Send(msg); err != nil {
+			return fmt.Errorf("scheduling message: %w", err)
+		}
+		fmt.Printf("%s Message to %s scheduled for %s\n", style.Bold.Render("⏰"), to,
+			msg.DeliverAt.Local().Format("2006-01-02 15:04"))
+		fmt.Printf("  Subject: %s\n", mailSubject)
+		fmt.Printf("  ID: %s (cancel with: gt mail scheduled cancel %s)\n", msg.ID, msg.ID)
+		return nil
+	}
+
 	// Use address resolver for new address types
 	townRoot, _ := workspace.FindFromCwd()
 	b := beads.New(townRoot)
@@ -132,6 +179,17 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	router := mail.NewRouter(workDir)
 	var recipientAddrs []string
 
+	// Link direct copies so gt mail status can report on all of them
+	direct := 0
+	for _, rec := range recipients {
+		if rec.Type != mail.RecipientQueue && rec.Type != mail.RecipientChannel {
+			direct++
+		}
+	}
+	if direct > 1 {
+		msg.FanoutID = mail.NewFanoutID()
+	}
+
 	for _, rec := range recipients {
 		switch rec.Type {
 		case mail.RecipientQueue:
@@ -178,6 +236,9 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	if msg.Type != mail.TypeNotification {
 		fmt.Printf("  Type: %s\n", msg.Type)
 	}
+	if msg.ReplyBy != nil {
+		fmt.Printf("  Reply by: %s\n", msg.ReplyBy.Local().Format("2006-01-02 15:04"))
+	}
 
 	return nil
 }
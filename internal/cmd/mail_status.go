@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+func runMailStatus(cmd *cobra.Command, args []string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	router := mail.NewRouter(workDir)
+	status, err := router.DeliveryStatus(args[0], time.Now())
+	if err != nil {
+		return fmt.Errorf("getting status of %s: %w", args[0], err)
+	}
+
+	if mailStatusJSON {
+		return outputJSON(status)
+	}
+
+	msg := status.Message
+	typeStr := ""
+	if msg.Type != "" && msg.Type != mail.TypeNotification {
+		typeStr = fmt.Sprintf(" [%s]", msg.Type)
+	}
+	fmt.Printf("%s %s%s\n", style.Bold.Render("📨"), msg.Subject, typeStr)
+	fmt.Printf("  From: %s\n", msg.From)
+	fmt.Printf("  Sent: %s\n", msg.Timestamp.Local().Format("2006-01-02 15:04"))
+	if msg.ReplyBy != nil {
+		fmt.Printf("  Reply by: %s\n", msg.ReplyBy.Local().Format("2006-01-02 15:04"))
+	}
+	if msg.FanoutID != "" {
+		fmt.Printf("  Fan-out: %s\n", style.Dim.Render(msg.FanoutID))
+	}
+	fmt.Println()
+
+	for _, r := range status.Recipients {
+		fmt.Println("  " + formatRecipientStatus(r))
+	}
+	return nil
+}
+
+// formatRecipientStatus renders one recipient's line of gt mail status.
+func formatRecipientStatus(r mail.RecipientStatus) string {
+	marker := style.Dim.Render("○")
+	switch r.State() {
+	case "replied":
+		marker = style.Bold.Render("✓")
+	case "read", "delivered":
+		marker = "●"
+	}
+
+	recipient := r.Recipient
+	if r.CC {
+		recipient = "cc " + recipient
+	}
+
+	var times []string
+	if r.DeliveredAt != nil {
+		times = append(times, "delivered "+r.DeliveredAt.Local().Format("01-02 15:04"))
+	}
+	if r.ReadAt != nil {
+		times = append(times, "read "+r.ReadAt.Local().Format("01-02 15:04"))
+	}
+	if r.RepliedAt != nil {
+		times = append(times, "replied "+r.RepliedAt.Local().Format("01-02 15:04"))
+	}
+
+	line := fmt.Sprintf("%s %-32s %-9s", marker, recipient, r.State())
+	if len(times) > 0 {
+		line += " " + style.Dim.Render(strings.Join(times, ", "))
+	}
+	if r.Overdue {
+		line += " " + style.Bold.Render("OVERDUE")
+	}
+	if r.MessageID != "" {
+		line += " " + style.Dim.Render(r.MessageID)
+	}
+	return line
+}
+
+// parseReplyBy parses a --reply-by deadline: a duration from now (4h,
+// 90m) or an RFC3339 time. Deadlines must be in the future.
+func parseReplyBy(s string, now time.Time) (time.Time, error) {
+	var deadline time.Time
+	if d, err := time.ParseDuration(s); err == nil {
+		deadline = now.Add(d)
+	} else if t, err := time.Parse(time.RFC3339, s); err == nil {
+		deadline = t
+	} else {
+		return time.Time{}, fmt.Errorf("invalid --reply-by %q: use a duration (4h) or RFC3339 time", s)
+	}
+	if !deadline.After(now) {
+		return time.Time{}, fmt.Errorf("invalid --reply-by %q: deadline is not in the future", s)
+	}
+	return deadline, nil
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReplyBy(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr string
+	}{
+		{"4h", now.Add(4 * time.Hour), ""},
+		{"90m", now.Add(90 * time.Minute), ""},
+		{"2026-01-03T09:00:00Z", time.Date(2026, 1, 3, 9, 0, 0, 0, time.UTC), ""},
+		{"tomorrow", time.Time{}, "use a duration"},
+		{"-1h", time.Time{}, "not in the future"},
+		{"2026-01-01T09:00:00Z", time.Time{}, "not in the future"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseReplyBy(tt.in, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseReplyBy(%q) error = %v, want containing %q", tt.in, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseReplyBy(%q): %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseReplyBy(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
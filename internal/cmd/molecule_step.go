@@ -7,9 +7,11 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -34,21 +36,39 @@ This command handles the step-to-step transition for polecats:
    - Sends POLECAT_DONE to witness
    - Exits the session
 
+Steps created from formulas carry an execution policy, enforced here:
+  timeout       A step finished after its timeout counts as a failed attempt
+  max_attempts  A failed attempt reopens the step until attempts run out
+  on_failure    Then: skip (close it and carry on), escalate (block it and
+                run gt escalate), or abort (block it and the molecule)
+  when          A ready step whose condition is false is closed as skipped
+
+Report a failed attempt with --failed. Record outputs for later steps' when
+conditions with --output key=value.
+
 IMPORTANT: This is the canonical way to complete molecule steps. Do NOT manually
 close steps with 'bd close' - it skips the auto-continuation logic.
 
 Example:
-  gt mol step done gt-abc.1    # Complete step 1 of molecule gt-abc`,
+  gt mol step done gt-abc.1    # Complete step 1 of molecule gt-abc
+  gt mol step done gt-abc.2 --output bumped=true
+  gt mol step done gt-abc.3 --failed --reason "2 tests failing"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMoleculeStepDone,
 }
 
 var (
-	moleculeStepDryRun bool
+	moleculeStepDryRun  bool
+	moleculeStepFailed  bool
+	moleculeStepReason  string
+	moleculeStepOutputs []string
 )
 
 func init() {
 	moleculeStepDoneCmd.Flags().BoolVarP(&moleculeStepDryRun, "dry-run", "n", false, "Show what would be done without executing")
+	moleculeStepDoneCmd.Flags().BoolVar(&moleculeStepFailed, "failed", false, "Report this attempt as failed (applies the step's retry policy)")
+	moleculeStepDoneCmd.Flags().StringVar(&moleculeStepReason, "reason", "", "Why the attempt failed (with --failed)")
+	moleculeStepDoneCmd.Flags().StringArrayVar(&moleculeStepOutputs, "output", nil, "Step output (key=value) for later when conditions, can be repeated")
 	moleculeStepDoneCmd.Flags().BoolVar(&moleculeJSON, "json", false, "Output as JSON")
 }
 
@@ -60,8 +80,11 @@ type StepDoneResult struct {
 	NextStepID    string   `json:"next_step_id,omitempty"`
 	NextStepTitle string   `json:"next_step_title,omitempty"`
 	ParallelSteps []string `json:"parallel_steps,omitempty"` // Multiple ready steps for fan-out
+	SkippedSteps  []string `json:"skipped_steps,omitempty"`  // Steps closed because their when condition is false
+	Outcome       string   `json:"outcome,omitempty"`        // passed, failed, or timed_out
+	Attempt       int      `json:"attempt,omitempty"`
 	Complete      bool     `json:"complete"`
-	Action        string   `json:"action"` // "continue", "parallel", "done", "no_more_ready"
+	Action        string   `json:"action"` // "continue", "parallel", "done", "no_more_ready", "escalated", "aborted"
 }
 
 func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
@@ -101,27 +124,137 @@ func runMoleculeStepDone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot extract molecule ID from step %s (expected format: gt-xxx.N)", stepID)
 	}
 
+	outputs, err := parseStepOutputs(moleculeStepOutputs)
+	if err != nil {
+		return err
+	}
+
 	result := StepDoneResult{
 		StepID:     stepID,
 		MoleculeID: moleculeID,
 	}
 
-	// Step 3: Close the step
-	if moleculeStepDryRun {
+	// Step 3: Record the attempt and close the step, or apply its failure
+	// policy.
+	fields := beads.ParseStepFields(step)
+	tracked := fields != nil || len(outputs) > 0 // Leave plain steps' descriptions alone
+	if fields == nil {
+		fields = &beads.StepFields{}
+	}
+	outcome, problem := stepAttemptOutcome(fields, moleculeStepFailed, time.Now())
+	if problem == "" {
+		problem = moleculeStepReason
+	}
+	fields.Attempts++
+	fields.Outcome = outcome
+	fields.StartedAt = ""
+	fields.LastError = problem
+	for k, v := range outputs {
+		if fields.Outputs == nil {
+			fields.Outputs = make(map[string]string)
+		}
+		fields.Outputs[k] = v
+	}
+	result.Outcome = outcome
+	result.Attempt = fields.Attempts
+
+	action := ""
+	if outcome != beads.StepOutcomePassed {
+		action = formula.FailureAction(fields.OnFailure, fields.MaxAttempts, fields.Attempts)
+		fmt.Printf("%s Step %s attempt %d %s%s\n", style.Bold.Render("✗"), stepID, fields.Attempts,
+			strings.ReplaceAll(outcome, "_", " "), formatProblem(problem))
+	}
+
+	switch {
+	case moleculeStepDryRun && action == "":
 		fmt.Printf("[dry-run] Would close step: %s\n", stepID)
 		result.StepClosed = true
-	} else {
+	case moleculeStepDryRun:
+		fmt.Printf("[dry-run] Would apply on_failure action: %s\n", action)
+		result.StepClosed = action == formula.OnFailureSkip
+	case action == "":
+		if tracked {
+			if err := updateStep(b, step, fields, ""); err != nil {
+				return err
+			}
+		}
 		if err := b.Close(stepID); err != nil {
 			return fmt.Errorf("closing step: %w", err)
 		}
 		result.StepClosed = true
 		fmt.Printf("%s Closed step %s: %s\n", style.Bold.Render("✓"), stepID, step.Title)
+	case action == formula.OnFailureRetry:
+		if err := updateStep(b, step, fields, "open"); err != nil {
+			return err
+		}
+		fmt.Printf("%s Reopened step %s for another attempt\n", style.Bold.Render("↻"), stepID)
+	case action == formula.OnFailureSkip:
+		if err := updateStep(b, step, fields, ""); err != nil {
+			return err
+		}
+		if err := b.CloseWithReason(fmt.Sprintf("%s, skipped by on_failure", outcome), stepID); err != nil {
+			return fmt.Errorf("closing step: %w", err)
+		}
+		result.StepClosed = true
+		fmt.Printf("%s Closed failed step %s (on_failure: skip)\n", style.Bold.Render("✓"), stepID)
+	default: // escalate or abort
+		if err := updateStep(b, step, fields, "blocked"); err != nil {
+			return err
+		}
+		if action == formula.OnFailureAbort {
+			blocked := "blocked"
+			if err := b.Update(moleculeID, beads.UpdateOptions{Status: &blocked}); err != nil {
+				style.PrintWarning("could not block molecule %s: %v", moleculeID, err)
+			}
+		}
 	}
 
-	// Step 4: Find all ready steps (supports fan-out pattern)
-	readySteps, allComplete, err := findAllReadySteps(b, moleculeID)
-	if err != nil {
-		return fmt.Errorf("finding next steps: %w", err)
+	// Escalated and aborted steps stay blocked; nothing continues from here.
+	if action == formula.OnFailureEscalate || action == formula.OnFailureAbort {
+		result.Action = "escalated"
+		if action == formula.OnFailureAbort {
+			result.Action = "aborted"
+		}
+		if moleculeJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(result)
+		}
+		if action == formula.OnFailureAbort {
+			fmt.Printf("\n%s Molecule %s aborted: step %s is blocked\n", style.Bold.Render("⛔"), moleculeID, stepID)
+			fmt.Printf("Fix the problem, then reopen the step with: bd update %s --status=open\n", stepID)
+			return nil
+		}
+		if moleculeStepDryRun {
+			fmt.Printf("[dry-run] Would escalate step %s\n", stepID)
+			return nil
+		}
+		fmt.Printf("\n%s Step %s is blocked pending escalation\n", style.Bold.Render("⚠"), stepID)
+		return escalateStepFailure(step, moleculeID, fields)
+	}
+
+	// Step 4: Find all ready steps (supports fan-out pattern), skipping
+	// those whose when condition is false. Skipping can make more steps
+	// ready, so repeat until nothing more is skipped.
+	var readySteps []*beads.Issue
+	var allComplete bool
+	for {
+		readySteps, allComplete, err = findAllReadySteps(b, moleculeID)
+		if err != nil {
+			return fmt.Errorf("finding next steps: %w", err)
+		}
+		var skipped []string
+		readySteps, skipped, err = skipUnmetSteps(b, moleculeID, readySteps, moleculeStepDryRun)
+		if err != nil {
+			return fmt.Errorf("evaluating step conditions: %w", err)
+		}
+		result.SkippedSteps = append(result.SkippedSteps, skipped...)
+		if len(skipped) == 0 || moleculeStepDryRun {
+			break
+		}
+	}
+	if !moleculeStepDryRun {
+		markStepsStarted(b, readySteps)
 	}
 
 	if allComplete {
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// stepAttemptOutcome classifies the attempt that gt mol step done is
+// finishing: failed if the agent said so, timed_out if it ran past the
+// step's timeout, otherwise passed.
+func stepAttemptOutcome(fields *beads.StepFields, failed bool, now time.Time) (outcome, problem string) {
+	if failed {
+		return beads.StepOutcomeFailed, ""
+	}
+	if fields.Timeout == "" || fields.StartedAt == "" {
+		return beads.StepOutcomePassed, ""
+	}
+	timeout, err := time.ParseDuration(fields.Timeout)
+	if err != nil {
+		return beads.StepOutcomePassed, ""
+	}
+	started, err := time.Parse(time.RFC3339, fields.StartedAt)
+	if err != nil {
+		return beads.StepOutcomePassed, ""
+	}
+	if elapsed := now.Sub(started); elapsed > timeout {
+		return beads.StepOutcomeTimedOut, fmt.Sprintf("timed out after %s (limit %s)",
+			elapsed.Round(time.Second), fields.Timeout)
+	}
+	return beads.StepOutcomePassed, ""
+}
+
+// formatProblem renders an attempt's failure reason for a status line.
+func formatProblem(problem string) string {
+	if problem == "" {
+		return ""
+	}
+	return ": " + problem
+}
+
+// parseStepOutputs parses --output key=value flags.
+func parseStepOutputs(args []string) (map[string]string, error) {
+	outputs := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" || strings.ContainsAny(key, ": \t") {
+			return nil, fmt.Errorf("invalid --output %q: expected key=value", arg)
+		}
+		outputs[key] = value
+	}
+	return outputs, nil
+}
+
+// updateStep writes fields to the step's description and optionally
+// changes its status.
+func updateStep(b *beads.Beads, step *beads.Issue, fields *beads.StepFields, status string) error {
+	desc := beads.SetStepFields(step, fields)
+	opts := beads.UpdateOptions{Description: &desc}
+	if status != "" {
+		opts.Status = &status
+	}
+	if err := b.Update(step.ID, opts); err != nil {
+		return fmt.Errorf("updating step %s: %w", step.ID, err)
+	}
+	step.Description = desc
+	return nil
+}
+
+// markStepsStarted records when the next attempt of each step with a
+// timeout starts, so that gt mol step done can tell if it overran.
+func markStepsStarted(b *beads.Beads, steps []*beads.Issue) {
+	now := time.Now().Format(time.RFC3339)
+	for _, step := range steps {
+		fields := beads.ParseStepFields(step)
+		if fields == nil || fields.Timeout == "" {
+			continue
+		}
+		fields.StartedAt = now
+		if err := updateStep(b, step, fields, ""); err != nil {
+			style.PrintWarning("could not record start of %s: %v", step.ID, err)
+		}
+	}
+}
+
+// stepConditionLookup resolves when-condition references for a molecule:
+// vars.<name> from the molecule bead, and steps.<ref>.outcome and
+// steps.<ref>.outputs.<key> from its step beads.
+func stepConditionLookup(b *beads.Beads, moleculeID string) (func(ref string) string, error) {
+	mol, err := b.Show(moleculeID)
+	if err != nil {
+		return nil, fmt.Errorf("loading molecule %s: %w", moleculeID, err)
+	}
+	vars := beads.ParseMoleculeVars(mol)
+
+	children, err := b.List(beads.ListOptions{
+		Parent:   moleculeID,
+		Status:   "all",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing molecule steps: %w", err)
+	}
+	steps := make(map[string]*beads.StepFields)
+	for _, child := range children {
+		if fields := beads.ParseStepFields(child); fields != nil && fields.Ref != "" {
+			steps[fields.Ref] = fields
+		}
+	}
+
+	return func(ref string) string {
+		if name, ok := strings.CutPrefix(ref, "vars."); ok {
+			return vars[name]
+		}
+		id, field, ok := formula.StepRef(ref)
+		if !ok || steps[id] == nil {
+			return ""
+		}
+		if field == "outcome" {
+			return steps[id].Outcome
+		}
+		return steps[id].Outputs[strings.TrimPrefix(field, "outputs.")]
+	}, nil
+}
+
+// skipUnmetSteps closes the ready steps whose when condition is false and
+// returns the rest. In dry-run mode the steps are only reported.
+func skipUnmetSteps(b *beads.Beads, moleculeID string, ready []*beads.Issue, dryRun bool) (remaining []*beads.Issue, skipped []string, err error) {
+	var lookup func(string) string
+	for _, step := range ready {
+		fields := beads.ParseStepFields(step)
+		if fields == nil || fields.When == "" {
+			remaining = append(remaining, step)
+			continue
+		}
+		cond, err := formula.ParseCondition(fields.When)
+		if err != nil {
+			style.PrintWarning("step %s: %v; running it anyway", step.ID, err)
+			remaining = append(remaining, step)
+			continue
+		}
+		if lookup == nil {
+			if lookup, err = stepConditionLookup(b, moleculeID); err != nil {
+				return nil, nil, err
+			}
+		}
+		if cond.Eval(lookup) {
+			remaining = append(remaining, step)
+			continue
+		}
+
+		skipped = append(skipped, step.ID)
+		if dryRun {
+			fmt.Printf("[dry-run] Would skip step %s: when %s is false\n", step.ID, fields.When)
+			continue
+		}
+		fields.Outcome = beads.StepOutcomeSkipped
+		if err := updateStep(b, step, fields, ""); err != nil {
+			return nil, nil, err
+		}
+		if err := b.CloseWithReason("skipped: when "+fields.When+" is false", step.ID); err != nil {
+			return nil, nil, fmt.Errorf("skipping step %s: %w", step.ID, err)
+		}
+		fmt.Printf("%s Skipped step %s: when %s is false\n", style.Dim.Render("–"), step.ID, fields.When)
+	}
+	return remaining, skipped, nil
+}
+
+// escalateStepFailure raises an escalation for a step that ran out of
+// attempts.
+func escalateStepFailure(step *beads.Issue, moleculeID string, fields *beads.StepFields) error {
+	reason := fmt.Sprintf("Step %s of molecule %s failed after %d attempt(s)", step.ID, moleculeID, fields.Attempts)
+	if fields.LastError != "" {
+		reason += ": " + fields.LastError
+	}
+	escalateCmd := exec.Command("gt", "escalate", "Molecule step failed: "+step.Title,
+		"--severity", "high",
+		"--reason", reason,
+		"--source", "molecule:"+moleculeID,
+		"--related", step.ID)
+	escalateCmd.Stdout = os.Stdout
+	escalateCmd.Stderr = os.Stderr
+	return escalateCmd.Run()
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestStepAttemptOutcome(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-45 * time.Minute).Format(time.RFC3339)
+
+	tests := []struct {
+		name    string
+		fields  beads.StepFields
+		failed  bool
+		want    string
+		problem string
+	}{
+		{"no policy", beads.StepFields{}, false, beads.StepOutcomePassed, ""},
+		{"reported failure", beads.StepFields{}, true, beads.StepOutcomeFailed, ""},
+		{"within timeout", beads.StepFields{Timeout: "1h", StartedAt: started}, false, beads.StepOutcomePassed, ""},
+		{"overran timeout", beads.StepFields{Timeout: "30m", StartedAt: started}, false, beads.StepOutcomeTimedOut, "timed out after 45m0s"},
+		{"timeout without start", beads.StepFields{Timeout: "30m"}, false, beads.StepOutcomePassed, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, problem := stepAttemptOutcome(&tt.fields, tt.failed, now)
+			if got != tt.want {
+				t.Errorf("outcome = %q, want %q", got, tt.want)
+			}
+			if !strings.Contains(problem, tt.problem) {
+				t.Errorf("problem = %q, want containing %q", problem, tt.problem)
+			}
+		})
+	}
+}
+
+func TestParseStepOutputs(t *testing.T) {
+	got, err := parseStepOutputs([]string{"bumped=true", "version=1.2.0"})
+	if err != nil {
+		t.Fatalf("parseStepOutputs: %v", err)
+	}
+	if got["bumped"] != "true" || got["version"] != "1.2.0" {
+		t.Errorf("outputs = %v", got)
+	}
+	for _, bad := range []string{"novalue", "=x", "a key=x", "a:b=x"} {
+		if _, err := parseStepOutputs([]string{bad}); err == nil {
+			t.Errorf("parseStepOutputs(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestPlanFormulaRun_SkipsFalseConditions(t *testing.T) {
+	f := mustParseFormula(t, `
+formula = "release"
+[vars.channel]
+default = "stable"
+[[steps]]
+id = "bump"
+[[steps]]
+id = "announce"
+needs = ["bump"]
+when = 'vars.channel == "beta"'
+[[steps]]
+id = "thank"
+needs = ["announce"]
+when = 'steps.announce.outcome != "skipped"'
+[[steps]]
+id = "publish"
+needs = ["bump"]
+when = 'steps.bump.outputs.bumped == "true"'
+`)
+	plan, err := planFormulaRun(f, map[string]string{"channel": "stable"}, nil)
+	if err != nil {
+		t.Fatalf("planFormulaRun: %v", err)
+	}
+	if !plan.Skipped["announce"] || !plan.Skipped["thank"] {
+		t.Errorf("Skipped = %v, want announce and thank", plan.Skipped)
+	}
+	if plan.Skipped["publish"] {
+		t.Error("publish depends on a step output and must be decided at run time")
+	}
+	for _, wave := range plan.Waves {
+		for _, id := range wave {
+			if plan.Skipped[id] {
+				t.Errorf("skipped step %s is in waves %v", id, plan.Waves)
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// MQ explain command flags
+var mqExplainJSON bool
+
+var mqExplainCmd = &cobra.Command{
+	Use:   "explain <rig> <mr-id>",
+	Short: "Explain how a merge request's priority score was computed",
+	Long: `Show the term-by-term breakdown of a merge request's priority score
+and its position in the ready queue.
+
+Scoring weights come from merge_queue.scoring in the rig's
+settings/config.json. Terms with a zero weight are omitted.
+
+Examples:
+  gt mq explain gastown gt-mr-abc123
+  gt mq explain gastown gt-mr-abc123 --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQExplain,
+}
+
+func init() {
+	mqExplainCmd.Flags().BoolVar(&mqExplainJSON, "json", false, "Output as JSON")
+
+	mqCmd.AddCommand(mqExplainCmd)
+}
+
+// MQExplainOutput is the JSON output structure for gt mq explain.
+type MQExplainOutput struct {
+	ID        string                  `json:"id"`
+	Title     string                  `json:"title"`
+	Score     refinery.ScoreBreakdown `json:"score"`
+	Position  int                     `json:"position,omitempty"` // 1-based; 0 if not in the ready queue
+	QueueSize int                     `json:"queue_size"`
+}
+
+func runMQExplain(cmd *cobra.Command, args []string) error {
+	rigName, mrID := args[0], args[1]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	b := beads.New(r.BeadsPath())
+	issue, err := b.Show(mrID)
+	if err != nil {
+		if err == beads.ErrNotFound {
+			return fmt.Errorf("merge request '%s' not found", mrID)
+		}
+		return fmt.Errorf("fetching merge request: %w", err)
+	}
+
+	scorer := refinery.NewScorer(r)
+	now := time.Now()
+	breakdown := scorer.Explain(issue, now)
+
+	// Rank against the other ready MRs
+	issues, err := b.List(beads.ListOptions{
+		Type:     "merge-request",
+		Status:   "open",
+		Priority: -1,
+	})
+	if err != nil {
+		return fmt.Errorf("querying merge queue: %w", err)
+	}
+	type scoredIssue struct {
+		id    string
+		score float64
+	}
+	var ready []scoredIssue
+	for _, other := range issues {
+		if other.Status != "open" || len(other.BlockedBy) > 0 || other.BlockedByCount > 0 {
+			continue
+		}
+		score := breakdown.Total
+		if other.ID != issue.ID {
+			score = scorer.Score(other, now)
+		}
+		ready = append(ready, scoredIssue{id: other.ID, score: score})
+	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		return ready[i].score > ready[j].score
+	})
+
+	out := MQExplainOutput{
+		ID:        issue.ID,
+		Title:     issue.Title,
+		Score:     breakdown,
+		QueueSize: len(ready),
+	}
+	for i, s := range ready {
+		if s.id == issue.ID {
+			out.Position = i + 1
+			break
+		}
+	}
+
+	if mqExplainJSON {
+		return outputJSON(out)
+	}
+
+	fmt.Printf("%s Score for %s: %s\n\n", style.Bold.Render("🧮"), issue.ID, issue.Title)
+	for _, term := range breakdown.Terms {
+		fmt.Printf("  %+9.1f  %-14s %s\n", term.Points, term.Name, style.Dim.Render(term.Detail))
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("---------"))
+	fmt.Printf("  %9.1f  %s\n\n", breakdown.Total, style.Bold.Render("total"))
+
+	if out.Position > 0 {
+		fmt.Printf("  Queue position: %d of %d ready\n", out.Position, out.QueueSize)
+	} else {
+		fmt.Printf("  Queue position: %s\n", style.Dim.Render("not ready (closed or blocked)"))
+	}
+	return nil
+}
@@ -61,7 +61,8 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Apply additional filters and calculate scores
+	// Apply additional filters and calculate scores using the rig's scoring policy
+	scorer := refinery.NewScorer(r)
 	now := time.Now()
 	type scoredIssue struct {
 		issue  *beads.Issue
@@ -114,7 +115,7 @@ func runMQList(cmd *cobra.Command, args []string) error {
 		}
 
 		// Calculate priority score
-		score := calculateMRScore(issue, fields, now)
+		score := scorer.Score(issue, now)
 		scored = append(scored, scoredIssue{issue: issue, fields: fields, score: score})
 	}
 
@@ -275,37 +276,3 @@ func outputJSON(data interface{}) error {
 	enc.SetIndent("", "  ")
 	return enc.Encode(data)
 }
-
-// calculateMRScore computes the priority score for an MR using the refinery scoring function.
-// Higher scores mean higher priority (process first).
-func calculateMRScore(issue *beads.Issue, fields *beads.MRFields, now time.Time) float64 {
-	// Parse MR creation time
-	mrCreatedAt, err := time.Parse(time.RFC3339, issue.CreatedAt)
-	if err != nil {
-		mrCreatedAt, err = time.Parse("2006-01-02T15:04:05Z", issue.CreatedAt)
-		if err != nil {
-			mrCreatedAt = now // Fallback to now if parsing fails
-		}
-	}
-
-	// Build score input
-	input := refinery.ScoreInput{
-		Priority:    issue.Priority,
-		MRCreatedAt: mrCreatedAt,
-		Now:         now,
-	}
-
-	// Add fields from MR metadata if available
-	if fields != nil {
-		input.RetryCount = fields.RetryCount
-
-		// Parse convoy created at if available
-		if fields.ConvoyCreatedAt != "" {
-			if convoyTime, err := time.Parse(time.RFC3339, fields.ConvoyCreatedAt); err == nil {
-				input.ConvoyCreatedAt = &convoyTime
-			}
-		}
-	}
-
-	return refinery.ScoreMRWithDefaults(input)
-}
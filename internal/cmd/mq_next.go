@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -28,6 +29,9 @@ The priority scoring function considers:
   - Retry count: MRs that fail repeatedly get deprioritized
   - MR age: FIFO tiebreaker for same priority/convoy
 
+Weights come from merge_queue.scoring in the rig's settings/config.json.
+Use 'gt mq explain' to see how an MR's score was computed.
+
 Use --strategy=fifo for first-in-first-out ordering instead.
 
 Examples:
@@ -91,6 +95,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	}
 
 	now := time.Now()
+	scorer := refinery.NewScorer(r)
 
 	// Sort based on strategy
 	if mqNextStrategy == "fifo" {
@@ -108,8 +113,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 		}
 		scored := make([]scoredIssue, len(ready))
 		for i, issue := range ready {
-			fields := beads.ParseMRFields(issue)
-			score := calculateMRScore(issue, fields, now)
+			score := scorer.Score(issue, now)
 			scored[i] = scoredIssue{issue: issue, score: score}
 		}
 
@@ -140,7 +144,7 @@ func runMQNext(cmd *cobra.Command, args []string) error {
 	// Human-readable output
 	fmt.Printf("%s Next MR to process:\n\n", style.Bold.Render("🎯"))
 
-	score := calculateMRScore(next, fields, now)
+	score := scorer.Score(next, now)
 
 	fmt.Printf("  ID:       %s\n", next.ID)
 	fmt.Printf("  Score:    %.1f\n", score)
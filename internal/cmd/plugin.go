@@ -18,6 +18,7 @@ import (
 // Plugin command flags
 var (
 	pluginListJSON    bool
+	pluginListDue     bool
 	pluginShowJSON    bool
 	pluginRunForce    bool
 	pluginRunDryRun   bool
@@ -62,8 +63,13 @@ Plugins are discovered from:
 
 When a plugin exists at both levels, the rig-level version takes precedence.
 
+Each plugin's gate is evaluated against its run history to show when it
+last ran and when it is next due. Condition gates are only checked with
+--due, since that runs their check commands.
+
 Examples:
   gt plugin list              # Human-readable output
+  gt plugin list --due        # Only plugins whose gate is open now
   gt plugin list --json       # JSON output for scripting`,
 	RunE: runPluginList,
 }
@@ -116,6 +122,7 @@ Examples:
 func init() {
 	// List subcommand flags
 	pluginListCmd.Flags().BoolVar(&pluginListJSON, "json", false, "Output as JSON")
+	pluginListCmd.Flags().BoolVar(&pluginListDue, "due", false, "Only show plugins that are due to run (evaluates condition gates)")
 
 	// Show subcommand flags
 	pluginShowCmd.Flags().BoolVar(&pluginShowJSON, "json", false, "Output as JSON")
@@ -178,17 +185,33 @@ func runPluginList(cmd *cobra.Command, args []string) error {
 		return plugins[i].Name < plugins[j].Name
 	})
 
+	evaluator := plugin.NewGateEvaluator(plugin.NewRecorder(townRoot))
+	evaluator.SkipConditions = !pluginListDue
+	statuses := make(map[string]plugin.GateStatus, len(plugins))
+	var shown []*plugin.Plugin
+	for _, p := range plugins {
+		status := evaluator.Evaluate(p)
+		if pluginListDue && !status.Open {
+			continue
+		}
+		statuses[p.Name] = status
+		shown = append(shown, p)
+	}
+
 	if pluginListJSON {
-		return outputPluginListJSON(plugins)
+		return outputPluginListJSON(shown, statuses)
 	}
 
-	return outputPluginListText(plugins, townRoot)
+	return outputPluginListText(shown, statuses, townRoot)
 }
 
-func outputPluginListJSON(plugins []*plugin.Plugin) error {
+func outputPluginListJSON(plugins []*plugin.Plugin, statuses map[string]plugin.GateStatus) error {
 	summaries := make([]plugin.PluginSummary, len(plugins))
 	for i, p := range plugins {
 		summaries[i] = p.Summary()
+		if status, ok := statuses[p.Name]; ok {
+			summaries[i].Status = &status
+		}
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -196,7 +219,11 @@ func outputPluginListJSON(plugins []*plugin.Plugin) error {
 	return enc.Encode(summaries)
 }
 
-func outputPluginListText(plugins []*plugin.Plugin, townRoot string) error {
+func outputPluginListText(plugins []*plugin.Plugin, statuses map[string]plugin.GateStatus, townRoot string) error {
+	if len(plugins) == 0 && pluginListDue {
+		fmt.Printf("%s No plugins due\n", style.Dim.Render("○"))
+		return nil
+	}
 	if len(plugins) == 0 {
 		fmt.Printf("%s No plugins discovered\n", style.Dim.Render("○"))
 		fmt.Printf("\n  Plugin directories:\n")
@@ -223,7 +250,7 @@ func outputPluginListText(plugins []*plugin.Plugin, townRoot string) error {
 	if len(townPlugins) > 0 {
 		fmt.Printf("  %s\n", style.Bold.Render("Town-level plugins:"))
 		for _, p := range townPlugins {
-			printPluginSummary(p)
+			printPluginSummary(p, statuses[p.Name])
 		}
 		fmt.Println()
 	}
@@ -238,7 +265,7 @@ func outputPluginListText(plugins []*plugin.Plugin, townRoot string) error {
 	for _, rigName := range rigNames {
 		fmt.Printf("  %s\n", style.Bold.Render(fmt.Sprintf("Rig %s:", rigName)))
 		for _, p := range rigPlugins[rigName] {
-			printPluginSummary(p)
+			printPluginSummary(p, statuses[p.Name])
 		}
 		fmt.Println()
 	}
@@ -246,7 +273,7 @@ func outputPluginListText(plugins []*plugin.Plugin, townRoot string) error {
 	return nil
 }
 
-func printPluginSummary(p *plugin.Plugin) {
+func printPluginSummary(p *plugin.Plugin, status plugin.GateStatus) {
 	gateType := "manual"
 	if p.Gate != nil && p.Gate.Type != "" {
 		gateType = string(p.Gate.Type)
@@ -261,6 +288,24 @@ func printPluginSummary(p *plugin.Plugin) {
 	if desc != "" {
 		fmt.Printf("      %s\n", style.Dim.Render(desc))
 	}
+	fmt.Printf("      %s\n", formatGateStatus(status))
+}
+
+// formatGateStatus renders a gate status as a one-line schedule summary.
+func formatGateStatus(status plugin.GateStatus) string {
+	var parts []string
+	if status.Open {
+		parts = append(parts, style.Success.Render("due now"))
+	} else if status.NextRun != nil {
+		parts = append(parts, fmt.Sprintf("next: %s", status.NextRun.Local().Format("2006-01-02 15:04 MST")))
+	}
+	if status.LastRun != nil {
+		parts = append(parts, fmt.Sprintf("last: %s", status.LastRun.Local().Format("2006-01-02 15:04 MST")))
+	}
+	if !status.Open && status.NextRun == nil {
+		parts = append(parts, status.Reason)
+	}
+	return style.Dim.Render(strings.Join(parts, " · "))
 }
 
 func runPluginShow(cmd *cobra.Command, args []string) error {
@@ -317,6 +362,9 @@ func outputPluginShowText(p *plugin.Plugin) error {
 		if p.Gate.Schedule != "" {
 			fmt.Printf("  Schedule: %s\n", p.Gate.Schedule)
 		}
+		if p.Gate.Timezone != "" {
+			fmt.Printf("  Timezone: %s\n", p.Gate.Timezone)
+		}
 		if p.Gate.Check != "" {
 			fmt.Printf("  Check: %s\n", p.Gate.Check)
 		}
@@ -383,22 +431,14 @@ func runPluginRun(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Check gate status for cooldown gates
+	// Check gate status
 	gateOpen := true
 	gateReason := ""
-	if p.Gate != nil && p.Gate.Type == plugin.GateCooldown && !pluginRunForce {
-		recorder := plugin.NewRecorder(townRoot)
-		duration := p.Gate.Duration
-		if duration == "" {
-			duration = "1h" // default
-		}
-		count, err := recorder.CountRunsSince(p.Name, duration)
-		if err != nil {
-			// Log warning but continue
-			fmt.Fprintf(os.Stderr, "Warning: checking gate status: %v\n", err)
-		} else if count > 0 {
-			gateOpen = false
-			gateReason = fmt.Sprintf("ran %d time(s) within %s cooldown", count, duration)
+	if p.Gate != nil && !pluginRunForce {
+		status := plugin.NewGateEvaluator(plugin.NewRecorder(townRoot)).Evaluate(p)
+		if p.Gate.Type != plugin.GateManual && p.Gate.Type != plugin.GateEvent {
+			gateOpen = status.Open
+			gateReason = status.Reason
 		}
 	}
 
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -252,43 +251,28 @@ func runSeanceTalk(sessionID, prompt string) error {
 	return nil
 }
 
-// discoverSessions reads session_start events from our event stream.
+// discoverSessions reads session_start events from our event stream,
+// including rotated segments.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-
-	file, err := os.Open(eventsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	defer file.Close()
-
 	var sessions []sessionEvent
-	scanner := bufio.NewScanner(file)
-
-	// Increase buffer for large lines
-	buf := make([]byte, 0, 64*1024)
-	scanner.Buffer(buf, 1024*1024)
-
-	for scanner.Scan() {
-		var event sessionEvent
-		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
-			continue
-		}
-
-		if event.Type == events.TypeSessionStart {
-			sessions = append(sessions, event)
+	err := events.Each(townRoot, time.Time{}, func(e events.Event) bool {
+		if e.Type == events.TypeSessionStart {
+			sessions = append(sessions, sessionEvent{
+				Timestamp: e.Timestamp,
+				Type:      e.Type,
+				Actor:     e.Actor,
+				Payload:   e.Payload,
+			})
 		}
-	}
+		return true
+	})
 
 	// Sort by timestamp descending (most recent first)
 	sort.Slice(sessions, func(i, j int) bool {
 		return sessions[i].Timestamp > sessions[j].Timestamp
 	})
 
-	return sessions, scanner.Err()
+	return sessions, err
 }
 
 func getPayloadString(payload map[string]interface{}, key string) string {
@@ -241,6 +241,20 @@ func validateMergeQueueConfig(c *MergeQueueConfig) error {
 		return fmt.Errorf("%w: max_concurrent must be non-negative", ErrMissingField)
 	}
 
+	if c.Scoring != nil {
+		for _, pattern := range c.Scoring.HotPaths {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid scoring.hot_paths pattern %q: %w", pattern, err)
+			}
+		}
+		if p := c.Scoring.MaxRetryPenalty; p != nil && *p < 0 {
+			return fmt.Errorf("%w: scoring.max_retry_penalty must be non-negative", ErrMissingField)
+		}
+		if p := c.Scoring.MaxDiffSizePenalty; p != nil && *p < 0 {
+			return fmt.Errorf("%w: scoring.max_diff_size_penalty must be non-negative", ErrMissingField)
+		}
+	}
+
 	return nil
 }
 
@@ -1682,7 +1696,8 @@ func SaveEscalationConfig(path string, config *EscalationConfig) error {
 		return fmt.Errorf("encoding escalation config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
+	// May contain SMTP/SMS credentials, so keep it private to the owner.
+	if err := os.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("writing escalation config: %w", err)
 	}
 
@@ -1722,6 +1737,55 @@ func validateEscalationConfig(c *EscalationConfig) error {
 		return fmt.Errorf("%w: max_reescalations must be non-negative", ErrMissingField)
 	}
 
+	if c.SMTP != nil {
+		switch c.SMTP.TLS {
+		case "", "starttls", "tls", "none":
+		default:
+			return fmt.Errorf("invalid smtp.tls '%s' (valid: starttls, tls, none)", c.SMTP.TLS)
+		}
+	}
+
+	if c.SMS != nil {
+		switch c.SMS.Provider {
+		case "twilio":
+		case "webhook":
+			if c.SMS.URL == "" {
+				return fmt.Errorf("%w: sms.url is required for the webhook provider", ErrMissingField)
+			}
+		default:
+			return fmt.Errorf("invalid sms.provider '%s' (valid: twilio, webhook)", c.SMS.Provider)
+		}
+	}
+
+	for name, wh := range c.Webhooks {
+		if wh == nil || wh.URL == "" {
+			return fmt.Errorf("%w: webhooks.%s.url", ErrMissingField, name)
+		}
+		switch wh.Format {
+		case "", "json", "slack", "discord":
+		default:
+			return fmt.Errorf("invalid webhooks.%s.format '%s' (valid: json, slack, discord)", name, wh.Format)
+		}
+	}
+
+	if d := c.Delivery; d != nil {
+		if d.MaxAttempts < 0 {
+			return fmt.Errorf("%w: delivery.max_attempts must be non-negative", ErrMissingField)
+		}
+		for field, v := range map[string]string{
+			"initial_backoff": d.InitialBackoff,
+			"max_backoff":     d.MaxBackoff,
+			"timeout":         d.Timeout,
+		} {
+			if v == "" {
+				continue
+			}
+			if _, err := time.ParseDuration(v); err != nil {
+				return fmt.Errorf("invalid delivery.%s: %w", field, err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -1756,3 +1820,185 @@ func (c *EscalationConfig) GetMaxReescalations() int {
 	}
 	return c.MaxReescalations
 }
+
+// DashboardConfigPath returns the standard path for dashboard access config in a town.
+func DashboardConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "dashboard.json")
+}
+
+// LoadDashboardConfig loads and validates a dashboard access configuration file.
+func LoadDashboardConfig(path string) (*DashboardConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading dashboard config: %w", err)
+	}
+
+	var config DashboardConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing dashboard config: %w", err)
+	}
+
+	if err := validateDashboardConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// LoadOrCreateDashboardConfig loads the dashboard config, returning an open
+// (no authentication) config if the file does not exist.
+func LoadOrCreateDashboardConfig(path string) (*DashboardConfig, error) {
+	config, err := LoadDashboardConfig(path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NewDashboardConfig(), nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+// validateDashboardConfig validates a DashboardConfig.
+func validateDashboardConfig(c *DashboardConfig) error {
+	if c.Type != "dashboard" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'dashboard', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentDashboardVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentDashboardVersion)
+	}
+
+	for kind, creds := range map[string][]DashboardCredential{"tokens": c.Tokens, "users": c.Users} {
+		seen := make(map[string]bool)
+		for i, cred := range creds {
+			if cred.Name == "" {
+				return fmt.Errorf("%w: %s[%d].name", ErrMissingField, kind, i)
+			}
+			if seen[cred.Name] {
+				return fmt.Errorf("duplicate %s name '%s'", kind, cred.Name)
+			}
+			seen[cred.Name] = true
+			if cred.Secret == "" && cred.SecretEnv == "" {
+				return fmt.Errorf("%w: %s.%s needs secret or secret_env", ErrMissingField, kind, cred.Name)
+			}
+			switch cred.Scope {
+			case "", DashboardScopeReadOnly, DashboardScopeOperator:
+			default:
+				return fmt.Errorf("invalid %s.%s.scope '%s' (valid: read-only, operator)", kind, cred.Name, cred.Scope)
+			}
+		}
+	}
+
+	return nil
+}
+
+// FeedRulesConfigPath returns the standard path for feed curation rules in a town.
+func FeedRulesConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "feed-rules.json")
+}
+
+// LoadFeedRulesConfig loads and validates a feed rules file.
+func LoadFeedRulesConfig(path string) (*FeedRulesConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally, not from user input
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		return nil, fmt.Errorf("reading feed rules: %w", err)
+	}
+
+	var config FeedRulesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing feed rules: %w", err)
+	}
+
+	if err := validateFeedRulesConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// LoadOrCreateFeedRulesConfig loads the feed rules, returning the built-in
+// rules if the file does not exist.
+func LoadOrCreateFeedRulesConfig(path string) (*FeedRulesConfig, error) {
+	config, err := LoadFeedRulesConfig(path)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return NewFeedRulesConfig(), nil
+		}
+		return nil, err
+	}
+	return config, nil
+}
+
+// validateFeedRulesConfig validates a FeedRulesConfig. Templates are
+// checked by the feed package when the rules are compiled.
+func validateFeedRulesConfig(c *FeedRulesConfig) error {
+	if c.Type != "feed-rules" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'feed-rules', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentFeedRulesVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentFeedRulesVersion)
+	}
+
+	for i, rule := range c.Rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rules[%d]", i)
+		}
+		switch rule.Action {
+		case "", FeedActionKeep, FeedActionDrop:
+		default:
+			return fmt.Errorf("invalid %s.action '%s' (valid: keep, drop)", name, rule.Action)
+		}
+		switch rule.Severity {
+		case "", FeedSeverityInfo, FeedSeverityWarning, FeedSeverityCritical:
+		default:
+			return fmt.Errorf("invalid %s.severity '%s' (valid: info, warning, critical)", name, rule.Severity)
+		}
+		for _, patterns := range [][]string{rule.Match.Types, rule.Match.Actors, rule.Match.Sources} {
+			for _, p := range patterns {
+				if _, err := filepath.Match(p, ""); err != nil {
+					return fmt.Errorf("invalid %s.match pattern '%s': %w", name, p, err)
+				}
+			}
+		}
+		for field, p := range rule.Match.Payload {
+			if _, err := filepath.Match(p, ""); err != nil {
+				return fmt.Errorf("invalid %s.match.payload.%s pattern '%s': %w", name, field, p, err)
+			}
+		}
+		if d := rule.Dedupe; d != nil {
+			if err := validateFeedWindow(name+".dedupe", d.Window); err != nil {
+				return err
+			}
+		}
+		if a := rule.Aggregate; a != nil {
+			if err := validateFeedWindow(name+".aggregate", a.Window); err != nil {
+				return err
+			}
+			if a.MinCount < 0 {
+				return fmt.Errorf("invalid %s.aggregate.min_count %d", name, a.MinCount)
+			}
+			if a.Summary == "" {
+				return fmt.Errorf("%w: %s.aggregate.summary", ErrMissingField, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateFeedWindow checks a dedupe or aggregation window.
+func validateFeedWindow(field, window string) error {
+	if window == "" {
+		return fmt.Errorf("%w: %s.window", ErrMissingField, field)
+	}
+	if d, err := time.ParseDuration(window); err != nil || d <= 0 {
+		return fmt.Errorf("invalid %s.window '%s': use a positive duration like 30s", field, window)
+	}
+	return nil
+}
@@ -3126,6 +3126,36 @@ func TestEscalationConfigValidation(t *testing.T) {
 			wantErr: true,
 			errMsg:  "max_reescalations must be non-negative",
 		},
+		{
+			name: "sms webhook without url",
+			config: &EscalationConfig{
+				Type:    "escalation",
+				Version: 1,
+				SMS:     &EscalationSMSConfig{Provider: "webhook"},
+			},
+			wantErr: true,
+			errMsg:  "sms.url is required",
+		},
+		{
+			name: "invalid webhook format",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Webhooks: map[string]*EscalationWebhookConfig{"ops": {URL: "https://example.com", Format: "teams"}},
+			},
+			wantErr: true,
+			errMsg:  "invalid webhooks.ops.format",
+		},
+		{
+			name: "invalid delivery backoff",
+			config: &EscalationConfig{
+				Type:     "escalation",
+				Version:  1,
+				Delivery: &EscalationDeliveryConfig{InitialBackoff: "soon"},
+			},
+			wantErr: true,
+			errMsg:  "invalid delivery.initial_backoff",
+		},
 	}
 
 	for _, tt := range tests {
@@ -3515,3 +3545,125 @@ func TestBuildStartupCommandWithAgentOverride_NoGTAgentWhenNoOverride(t *testing
 		t.Errorf("expected no GT_AGENT in command when no override, got: %q", cmd)
 	}
 }
+
+func TestDashboardConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config *DashboardConfig
+		errMsg string
+	}{
+		{"open", NewDashboardConfig(), ""},
+		{"valid", &DashboardConfig{
+			Tokens: []DashboardCredential{{Name: "ci", SecretEnv: "CI_TOKEN"}},
+			Users:  []DashboardCredential{{Name: "alice", Secret: "pw", Scope: DashboardScopeOperator}},
+		}, ""},
+		{"invalid type", &DashboardConfig{Type: "wrong"}, "invalid config type"},
+		{"missing name", &DashboardConfig{Tokens: []DashboardCredential{{Secret: "x"}}}, "tokens[0].name"},
+		{"missing secret", &DashboardConfig{Users: []DashboardCredential{{Name: "bob"}}}, "needs secret"},
+		{"duplicate", &DashboardConfig{Tokens: []DashboardCredential{{Name: "a", Secret: "x"}, {Name: "a", Secret: "y"}}}, "duplicate"},
+		{"bad scope", &DashboardConfig{Tokens: []DashboardCredential{{Name: "a", Secret: "x", Scope: "admin"}}}, "invalid tokens.a.scope"},
+	}
+	for _, tt := range tests {
+		err := validateDashboardConfig(tt.config)
+		if tt.errMsg == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+			t.Errorf("%s: error = %v, want containing %q", tt.name, err, tt.errMsg)
+		}
+	}
+}
+
+func TestLoadOrCreateDashboardConfig_Missing(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadOrCreateDashboardConfig(DashboardConfigPath(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Tokens) != 0 || len(cfg.Users) != 0 {
+		t.Errorf("missing config should have no credentials: %+v", cfg)
+	}
+}
+
+func TestFeedRulesConfigValidation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		config  *FeedRulesConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid default config",
+			config:  NewFeedRulesConfig(),
+			wantErr: false,
+		},
+		{
+			name: "wrong type",
+			config: &FeedRulesConfig{
+				Type:    "wrong",
+				Version: 1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown action",
+			config: &FeedRulesConfig{
+				Type:    "feed-rules",
+				Version: 1,
+				Rules:   []FeedRule{{Action: "mute"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown severity",
+			config: &FeedRulesConfig{
+				Type:    "feed-rules",
+				Version: 1,
+				Rules:   []FeedRule{{Severity: "loud"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad match pattern",
+			config: &FeedRulesConfig{
+				Type:    "feed-rules",
+				Version: 1,
+				Rules:   []FeedRule{{Match: FeedRuleMatch{Types: []string{"[done"}}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "dedupe without window",
+			config: &FeedRulesConfig{
+				Type:    "feed-rules",
+				Version: 1,
+				Rules:   []FeedRule{{Dedupe: &FeedDedupe{Key: "{{.Actor}}"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "aggregate without summary",
+			config: &FeedRulesConfig{
+				Type:    "feed-rules",
+				Version: 1,
+				Rules:   []FeedRule{{Aggregate: &FeedAggregate{Window: "30s"}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFeedRulesConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFeedRulesConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -68,6 +68,12 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// SessionBackend selects what agent sessions run under.
+	// Values: "tmux" (default), or "headless" for PTYs supervised by the
+	// daemon, for hosts without tmux (containers, CI).
+	// Can be overridden by GT_SESSION_BACKEND environment variable.
+	SessionBackend string `json:"session_backend,omitempty"`
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -716,6 +722,56 @@ type MergeQueueConfig struct {
 
 	// MaxConcurrent is the maximum number of concurrent merges.
 	MaxConcurrent int `json:"max_concurrent"`
+
+	// Scoring tunes merge request priority scoring. Unset fields keep the
+	// refinery's built-in defaults.
+	Scoring *MergeQueueScoringConfig `json:"scoring,omitempty"`
+}
+
+// MergeQueueScoringConfig overrides the weights used to order the merge queue.
+// Higher scores are processed first. Pointer fields distinguish "unset"
+// (use default) from an explicit zero.
+type MergeQueueScoringConfig struct {
+	// BaseScore is the starting score before applying factors.
+	BaseScore *float64 `json:"base_score,omitempty"`
+
+	// ConvoyAgeWeight is points added per hour of convoy age.
+	ConvoyAgeWeight *float64 `json:"convoy_age_weight,omitempty"`
+
+	// PriorityWeight is multiplied by (4 - priority) so P0 gets most points.
+	PriorityWeight *float64 `json:"priority_weight,omitempty"`
+
+	// RetryPenalty is subtracted per retry attempt.
+	RetryPenalty *float64 `json:"retry_penalty,omitempty"`
+
+	// MaxRetryPenalty caps the total retry penalty.
+	MaxRetryPenalty *float64 `json:"max_retry_penalty,omitempty"`
+
+	// MRAgeWeight is points added per hour since MR submission.
+	MRAgeWeight *float64 `json:"mr_age_weight,omitempty"`
+
+	// DiffSizeWeight is points subtracted per 100 changed lines, so small
+	// diffs go first. Zero disables the factor.
+	DiffSizeWeight *float64 `json:"diff_size_weight,omitempty"`
+
+	// MaxDiffSizePenalty caps the total diff size penalty.
+	MaxDiffSizePenalty *float64 `json:"max_diff_size_penalty,omitempty"`
+
+	// HotPaths are glob patterns (e.g., "internal/config/*") for files that
+	// are expensive to get wrong or often conflict.
+	HotPaths []string `json:"hot_paths,omitempty"`
+
+	// HotPathWeight is points added per changed file matching HotPaths.
+	// Use a negative value to push hot-path changes later instead.
+	HotPathWeight *float64 `json:"hot_path_weight,omitempty"`
+
+	// DependentWeight is points added per open issue blocked on the MR's
+	// source issue, so work that unblocks others lands first.
+	DependentWeight *float64 `json:"dependent_weight,omitempty"`
+
+	// LabelBoosts adds points for each label present on the MR bead
+	// (e.g., {"urgent": 200, "docs": -50}).
+	LabelBoosts map[string]float64 `json:"label_boosts,omitempty"`
 }
 
 // OnConflict strategy constants.
@@ -863,12 +919,26 @@ type EscalationConfig struct {
 	//   - "email:human" → Send email to contacts.human_email
 	//   - "sms:human"   → Send SMS to contacts.human_sms
 	//   - "slack"       → Post to contacts.slack_webhook
+	//   - "discord"     → Post to contacts.discord_webhook
+	//   - "webhook:<name>" → Post to webhooks[<name>]
 	//   - "log"         → Write to escalation log file
 	Routes map[string][]string `json:"routes"`
 
 	// Contacts contains contact information for external notification actions.
 	Contacts EscalationContacts `json:"contacts"`
 
+	// SMTP configures the mail server used by email: actions.
+	SMTP *EscalationSMTPConfig `json:"smtp,omitempty"`
+
+	// SMS configures the provider used by sms: actions.
+	SMS *EscalationSMSConfig `json:"sms,omitempty"`
+
+	// Webhooks defines named HTTP webhooks for webhook:<name> actions.
+	Webhooks map[string]*EscalationWebhookConfig `json:"webhooks,omitempty"`
+
+	// Delivery controls retries for external notifications.
+	Delivery *EscalationDeliveryConfig `json:"delivery,omitempty"`
+
 	// StaleThreshold is how long before an unacknowledged escalation
 	// is considered stale and gets re-escalated.
 	// Format: Go duration string (e.g., "4h", "30m", "24h")
@@ -885,6 +955,67 @@ type EscalationContacts struct {
 	HumanEmail   string `json:"human_email,omitempty"`   // email address for email:human action
 	HumanSMS     string `json:"human_sms,omitempty"`     // phone number for sms:human action
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
+
+	DiscordWebhook string `json:"discord_webhook,omitempty"` // webhook URL for discord action
+}
+
+// EscalationSMTPConfig configures outgoing email for escalations.
+type EscalationSMTPConfig struct {
+	Host     string `json:"host"`           // SMTP server host
+	Port     int    `json:"port,omitempty"` // default: 587
+	Username string `json:"username,omitempty"`
+
+	// Password authenticates Username. Prefer PasswordEnv so the secret
+	// stays out of settings/escalation.json.
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"` // env var holding the password
+
+	From string `json:"from"` // envelope and header sender
+
+	// TLS selects transport security: "starttls" (default), "tls" for
+	// implicit TLS (port 465), or "none" for a local relay.
+	TLS string `json:"tls,omitempty"`
+}
+
+// EscalationSMSConfig configures the SMS provider for escalations.
+type EscalationSMSConfig struct {
+	// Provider selects the SMS backend: "twilio" or "webhook".
+	Provider string `json:"provider"`
+
+	From string `json:"from,omitempty"` // sending phone number
+
+	// Twilio credentials. AuthTokenEnv names an env var holding the token.
+	AccountSID   string `json:"account_sid,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty"`
+	AuthTokenEnv string `json:"auth_token_env,omitempty"`
+
+	// URL overrides the provider endpoint. Required for "webhook", which
+	// POSTs {"to", "from", "body"} as JSON.
+	URL string `json:"url,omitempty"`
+}
+
+// EscalationWebhookConfig defines an HTTP webhook target.
+type EscalationWebhookConfig struct {
+	URL string `json:"url"`
+
+	// Format selects the payload shape: "slack", "discord", or "json" (default).
+	Format string `json:"format,omitempty"`
+
+	// Template is a Go text/template rendering the JSON body for "json"
+	// webhooks. Fields: .ID .Severity .Subject .Body .From .Source
+	// .Related .Time. Empty sends the notification as a JSON object.
+	Template string `json:"template,omitempty"`
+
+	// Headers are extra HTTP headers (e.g., Authorization).
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// EscalationDeliveryConfig controls retry behavior for external notifications.
+type EscalationDeliveryConfig struct {
+	MaxAttempts    int    `json:"max_attempts,omitempty"`    // default: 3
+	InitialBackoff string `json:"initial_backoff,omitempty"` // default: "2s", doubles each retry
+	MaxBackoff     string `json:"max_backoff,omitempty"`     // default: "30s"
+	Timeout        string `json:"timeout,omitempty"`         // per-attempt timeout, default: "10s"
 }
 
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
@@ -944,3 +1075,185 @@ func NewEscalationConfig() *EscalationConfig {
 		MaxReescalations: 2,
 	}
 }
+
+// CurrentDashboardVersion is the current schema version for DashboardConfig.
+const CurrentDashboardVersion = 1
+
+// Dashboard access scopes.
+const (
+	// DashboardScopeReadOnly may view the dashboard and run safe commands.
+	DashboardScopeReadOnly = "read-only"
+
+	// DashboardScopeOperator may also send mail and run action commands.
+	DashboardScopeOperator = "operator"
+)
+
+// DashboardConfig represents web dashboard access control (settings/dashboard.json).
+// With no tokens or users configured the dashboard is open to anyone who can
+// reach its port.
+type DashboardConfig struct {
+	Type    string `json:"type"`    // "dashboard"
+	Version int    `json:"version"` // schema version
+
+	// Tokens authenticate with "Authorization: Bearer <secret>". Browsers can
+	// open the dashboard once with ?token=<secret> to get a session cookie.
+	Tokens []DashboardCredential `json:"tokens,omitempty"`
+
+	// Users authenticate with HTTP basic auth (name and secret).
+	Users []DashboardCredential `json:"users,omitempty"`
+}
+
+// DashboardCredential is a token or basic-auth user allowed into the dashboard.
+type DashboardCredential struct {
+	// Name identifies the credential in the audit log. For users it is
+	// also the basic-auth username.
+	Name string `json:"name"`
+
+	// Secret is the token or password. Prefer SecretEnv so the secret
+	// stays out of settings/dashboard.json.
+	Secret    string `json:"secret,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"` // env var holding the secret
+
+	// Scope is "read-only" (default) or "operator".
+	Scope string `json:"scope,omitempty"`
+}
+
+// NewDashboardConfig creates a DashboardConfig with authentication disabled.
+func NewDashboardConfig() *DashboardConfig {
+	return &DashboardConfig{
+		Type:    "dashboard",
+		Version: CurrentDashboardVersion,
+	}
+}
+
+// CurrentFeedRulesVersion is the current schema version for FeedRulesConfig.
+const CurrentFeedRulesVersion = 1
+
+// Feed rule actions.
+const (
+	// FeedActionKeep writes matching events to the feed (the default).
+	FeedActionKeep = "keep"
+
+	// FeedActionDrop suppresses matching events.
+	FeedActionDrop = "drop"
+)
+
+// Feed event severities, lowest first.
+const (
+	FeedSeverityInfo     = "info"
+	FeedSeverityWarning  = "warning"
+	FeedSeverityCritical = "critical"
+)
+
+// FeedRulesConfig controls how the feed curator turns raw events into the
+// curated feed (settings/feed-rules.json). Rules are checked in order and
+// the first rule that matches an event decides what happens to it; events
+// no rule matches are kept with the built-in summary.
+type FeedRulesConfig struct {
+	Type    string `json:"type"`    // "feed-rules"
+	Version int    `json:"version"` // schema version
+
+	Rules []FeedRule `json:"rules"`
+}
+
+// FeedRule selects events and says how to curate them.
+//
+// Summary, dedupe keys and aggregate summaries are Go templates over the
+// event: {{.Type}}, {{.Actor}}, {{.Source}}, {{.Payload.<field>}} and
+// {{.Summary}} (the built-in summary); aggregate summaries also get {{.Count}}.
+type FeedRule struct {
+	// Name identifies the rule in gt feed rules test output.
+	Name string `json:"name,omitempty"`
+
+	// Match selects the events the rule applies to.
+	Match FeedRuleMatch `json:"match"`
+
+	// Action is "keep" (default) or "drop".
+	Action string `json:"action,omitempty"`
+
+	// Severity is "info" (default), "warning" or "critical".
+	Severity string `json:"severity,omitempty"`
+
+	// Summary replaces the built-in summary.
+	Summary string `json:"summary,omitempty"`
+
+	// Dedupe drops an event when one with the same key was written to the
+	// feed within the window.
+	Dedupe *FeedDedupe `json:"dedupe,omitempty"`
+
+	// Aggregate rewrites the summary once enough events with the same key
+	// arrive within the window.
+	Aggregate *FeedAggregate `json:"aggregate,omitempty"`
+}
+
+// FeedRuleMatch selects events. Every non-empty field must match; values are
+// glob patterns (patrol_*, gastown/*).
+type FeedRuleMatch struct {
+	Types   []string          `json:"types,omitempty"`
+	Actors  []string          `json:"actors,omitempty"`
+	Sources []string          `json:"sources,omitempty"`
+	Payload map[string]string `json:"payload,omitempty"` // field → pattern
+}
+
+// FeedDedupe configures deduplication for a rule.
+type FeedDedupe struct {
+	// Key is a template; events with equal keys are duplicates.
+	// Default: "{{.Actor}}".
+	Key string `json:"key,omitempty"`
+
+	// Window is how long a written event suppresses duplicates (e.g. "10s").
+	Window string `json:"window"`
+}
+
+// FeedAggregate configures aggregation for a rule.
+type FeedAggregate struct {
+	// Key is a template; events with equal keys are counted together.
+	// Default: "{{.Actor}}".
+	Key string `json:"key,omitempty"`
+
+	// Window is how far back events are counted (e.g. "30s").
+	Window string `json:"window"`
+
+	// MinCount is how many events trigger the aggregate summary (default 3).
+	MinCount int `json:"min_count,omitempty"`
+
+	// Summary is the aggregate summary template.
+	Summary string `json:"summary"`
+}
+
+// NewFeedRulesConfig creates a FeedRulesConfig with the built-in rules:
+// repeated done signals are deduplicated, bursts of slings are summarized,
+// and session deaths and merge failures are flagged.
+func NewFeedRulesConfig() *FeedRulesConfig {
+	return &FeedRulesConfig{
+		Type:    "feed-rules",
+		Version: CurrentFeedRulesVersion,
+		Rules: []FeedRule{
+			{
+				Name:   "dedupe-done",
+				Match:  FeedRuleMatch{Types: []string{"done"}},
+				Dedupe: &FeedDedupe{Key: "{{.Actor}}", Window: "10s"},
+			},
+			{
+				Name:  "aggregate-sling",
+				Match: FeedRuleMatch{Types: []string{"sling"}},
+				Aggregate: &FeedAggregate{
+					Key:      "{{.Actor}}",
+					Window:   "30s",
+					MinCount: 3,
+					Summary:  "{{.Actor}} dispatching work to {{.Count}} agents",
+				},
+			},
+			{
+				Name:     "mass-death",
+				Match:    FeedRuleMatch{Types: []string{"mass_death"}},
+				Severity: FeedSeverityCritical,
+			},
+			{
+				Name:     "failures",
+				Match:    FeedRuleMatch{Types: []string{"session_death", "merge_failed"}},
+				Severity: FeedSeverityWarning,
+			},
+		},
+	}
+}
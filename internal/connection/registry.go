@@ -28,6 +28,7 @@ type registryData struct {
 type MachineRegistry struct {
 	path     string
 	machines map[string]*Machine
+	conns    map[string]*SSHConnection // pooled SSH connections by machine name
 	mu       sync.RWMutex
 }
 
@@ -37,6 +38,7 @@ func NewMachineRegistry(configPath string) (*MachineRegistry, error) {
 	r := &MachineRegistry{
 		path:     configPath,
 		machines: make(map[string]*Machine),
+		conns:    make(map[string]*SSHConnection),
 	}
 
 	// Load existing config if present
@@ -133,6 +135,7 @@ func (r *MachineRegistry) Add(m *Machine) error {
 	defer r.mu.Unlock()
 
 	r.machines[m.Name] = m
+	r.dropConnLocked(m.Name)
 	return r.save()
 }
 
@@ -150,6 +153,7 @@ func (r *MachineRegistry) Remove(name string) error {
 	}
 
 	delete(r.machines, name)
+	r.dropConnLocked(name)
 	return r.save()
 }
 
@@ -176,13 +180,53 @@ func (r *MachineRegistry) Connection(name string) (Connection, error) {
 	case "local":
 		return NewLocalConnection(), nil
 	case "ssh":
-		// SSH connection not yet implemented
-		return nil, fmt.Errorf("ssh connections not yet implemented")
+		return r.sshConnection(m)
 	default:
 		return nil, fmt.Errorf("unknown machine type: %s", m.Type)
 	}
 }
 
+// sshConnection returns the pooled SSH connection for a machine, creating it
+// on first use. Reusing the same connection keeps one multiplexed master
+// per machine instead of a new handshake per operation.
+func (r *MachineRegistry) sshConnection(m *Machine) (*SSHConnection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.conns[m.Name]; ok {
+		return c, nil
+	}
+	c, err := NewSSHConnection(m)
+	if err != nil {
+		return nil, err
+	}
+	r.conns[m.Name] = c
+	return c, nil
+}
+
+// dropConnLocked closes and forgets a pooled connection. Caller must hold r.mu.
+func (r *MachineRegistry) dropConnLocked(name string) {
+	if c, ok := r.conns[name]; ok {
+		_ = c.Close()
+		delete(r.conns, name)
+	}
+}
+
+// Close closes all pooled SSH connections.
+func (r *MachineRegistry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var firstErr error
+	for name, c := range r.conns {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(r.conns, name)
+	}
+	return firstErr
+}
+
 // LocalConnection returns the local connection.
 // This is a convenience method for the common case.
 func (r *MachineRegistry) LocalConnection() *LocalConnection {
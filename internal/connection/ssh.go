@@ -0,0 +1,512 @@
+package connection
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// sshExitConnectFailed is the exit status ssh uses for its own failures
+// (unreachable host, auth failure, bad config). Remote commands that exit
+// with 255 are indistinguishable, so callers should avoid relying on it.
+const sshExitConnectFailed = 255
+
+// defaultControlPersist is how long an idle pooled SSH master connection
+// stays open after the last command finishes.
+const defaultControlPersist = 10 * time.Minute
+
+// SSHConnection implements Connection for a remote machine over SSH.
+//
+// Commands are run through the system ssh binary so that the user's
+// ~/.ssh/config, agent, and known_hosts are honored. Connections are pooled
+// with OpenSSH multiplexing (ControlMaster): the first command opens a
+// master connection and later commands reuse it until ControlPersist expires
+// or Close is called.
+type SSHConnection struct {
+	machine        *Machine
+	sshPath        string
+	controlPath    string
+	controlPersist time.Duration
+	connectTimeout time.Duration
+}
+
+// NewSSHConnection creates a new SSH connection for the given machine.
+// No network traffic happens until the first operation.
+func NewSSHConnection(m *Machine) (*SSHConnection, error) {
+	if m == nil || m.Host == "" {
+		return nil, fmt.Errorf("ssh machine requires host")
+	}
+	return &SSHConnection{
+		machine:        m,
+		sshPath:        "ssh",
+		controlPath:    controlSocketPath(m.Host),
+		controlPersist: defaultControlPersist,
+		connectTimeout: 10 * time.Second,
+	}, nil
+}
+
+// controlSocketPath returns the multiplexing socket path for a host.
+// The host is hashed because unix socket paths are limited to ~104 bytes.
+func controlSocketPath(host string) string {
+	sum := sha256.Sum256([]byte(host))
+	return filepath.Join(os.TempDir(), "gt-ssh-"+hex.EncodeToString(sum[:])[:16])
+}
+
+// Name returns the machine name.
+func (c *SSHConnection) Name() string {
+	return c.machine.Name
+}
+
+// IsLocal returns false for SSH connections.
+func (c *SSHConnection) IsLocal() bool {
+	return false
+}
+
+// Close tears down the pooled master connection, if any.
+func (c *SSHConnection) Close() error {
+	args := append(c.baseArgs(), "-O", "exit", c.machine.Host)
+	cmd := exec.Command(c.sshPath, args...) //nolint:gosec // G204: args are built from registry config
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// No master running is not an error - there is nothing to close.
+		msg := stderr.String()
+		if strings.Contains(msg, "No such file") || strings.Contains(msg, "Control socket connect") {
+			return nil
+		}
+		return &ConnectionError{Op: "close", Machine: c.machine.Name, Err: err}
+	}
+	return nil
+}
+
+// baseArgs returns the ssh options shared by every invocation.
+func (c *SSHConnection) baseArgs() []string {
+	args := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath=" + c.controlPath,
+		"-o", fmt.Sprintf("ControlPersist=%d", int(c.controlPersist.Seconds())),
+		"-o", fmt.Sprintf("ConnectTimeout=%d", int(c.connectTimeout.Seconds())),
+	}
+	if c.machine.KeyPath != "" {
+		args = append(args, "-i", c.machine.KeyPath)
+	}
+	return args
+}
+
+// run executes a remote shell command, feeding stdin if non-nil.
+// It returns stdout and stderr separately. A non-nil error is either a
+// *ConnectionError (ssh itself failed) or an *exec.ExitError from the
+// remote command.
+func (c *SSHConnection) run(stdin []byte, remoteCmd string) ([]byte, []byte, error) {
+	args := append(c.baseArgs(), c.machine.Host, remoteCmd)
+	cmd := exec.Command(c.sshPath, args...) //nolint:gosec // G204: remoteCmd is shell-quoted by callers
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() == sshExitConnectFailed {
+			detail := strings.TrimSpace(stderr.String())
+			if detail != "" {
+				err = fmt.Errorf("%w: %s", err, detail)
+			}
+			return stdout.Bytes(), stderr.Bytes(), &ConnectionError{Op: "exec", Machine: c.machine.Name, Err: err}
+		}
+	}
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// runCombined executes a remote shell command and returns combined output,
+// matching exec.Cmd.CombinedOutput semantics.
+func (c *SSHConnection) runCombined(remoteCmd string) ([]byte, error) {
+	args := append(c.baseArgs(), c.machine.Host, remoteCmd)
+	cmd := exec.Command(c.sshPath, args...) //nolint:gosec // G204: remoteCmd is shell-quoted by callers
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	err := cmd.Run()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() == sshExitConnectFailed {
+			return out.Bytes(), &ConnectionError{Op: "exec", Machine: c.machine.Name, Err: err}
+		}
+	}
+	return out.Bytes(), err
+}
+
+// fileError maps a failed remote file operation onto the connection error types.
+func (c *SSHConnection) fileError(err error, stderr []byte, path, op string) error {
+	var connErr *ConnectionError
+	if errors.As(err, &connErr) {
+		return err
+	}
+	msg := strings.TrimSpace(string(stderr))
+	switch {
+	case strings.Contains(msg, "No such file or directory"):
+		return &NotFoundError{Path: path}
+	case strings.Contains(msg, "Permission denied"):
+		return &PermissionError{Path: path, Op: op}
+	case msg != "":
+		return fmt.Errorf("%s %s: %s", op, path, msg)
+	default:
+		return fmt.Errorf("%s %s: %w", op, path, err)
+	}
+}
+
+// ReadFile reads the named file on the remote machine.
+func (c *SSHConnection) ReadFile(path string) ([]byte, error) {
+	stdout, stderr, err := c.run(nil, "cat -- "+shellQuote(path))
+	if err != nil {
+		return nil, c.fileError(err, stderr, path, "read")
+	}
+	return stdout, nil
+}
+
+// WriteFile writes data to the named file on the remote machine.
+// Like os.WriteFile, perm is only applied when the file is created.
+func (c *SSHConnection) WriteFile(path string, data []byte, perm fs.FileMode) error {
+	q := shellQuote(path)
+	script := fmt.Sprintf("if [ -e %s ]; then cat > %s; else cat > %s && chmod %o %s; fi",
+		q, q, q, perm.Perm(), q)
+	_, stderr, err := c.run(data, script)
+	if err != nil {
+		return c.fileError(err, stderr, path, "write")
+	}
+	return nil
+}
+
+// MkdirAll creates a directory and all parent directories on the remote machine.
+func (c *SSHConnection) MkdirAll(path string, perm fs.FileMode) error {
+	_, stderr, err := c.run(nil, fmt.Sprintf("mkdir -p -m %o -- %s", perm.Perm(), shellQuote(path)))
+	if err != nil {
+		return c.fileError(err, stderr, path, "mkdir")
+	}
+	return nil
+}
+
+// Remove removes the named file or empty directory on the remote machine.
+// Removing a path that does not exist is not an error.
+func (c *SSHConnection) Remove(path string) error {
+	q := shellQuote(path)
+	script := fmt.Sprintf("if [ -d %s ] && [ ! -L %s ]; then rmdir -- %s; else rm -f -- %s; fi", q, q, q, q)
+	_, stderr, err := c.run(nil, script)
+	if err != nil {
+		return c.fileError(err, stderr, path, "remove")
+	}
+	return nil
+}
+
+// RemoveAll removes the named file or directory and any children on the remote machine.
+func (c *SSHConnection) RemoveAll(path string) error {
+	_, stderr, err := c.run(nil, "rm -rf -- "+shellQuote(path))
+	if err != nil {
+		return c.fileError(err, stderr, path, "remove")
+	}
+	return nil
+}
+
+// Stat returns file info for the named file on the remote machine.
+// Both GNU (Linux) and BSD (macOS) stat are supported.
+func (c *SSHConnection) Stat(path string) (FileInfo, error) {
+	q := shellQuote(path)
+	script := fmt.Sprintf("stat -c '%%s|%%f|%%Y' -- %s 2>/dev/null || stat -f '%%z|%%Xp|%%m' -- %s", q, q)
+	stdout, stderr, err := c.run(nil, script)
+	if err != nil {
+		return nil, c.fileError(err, stderr, path, "stat")
+	}
+	fi, err := parseStatOutput(filepath.Base(path), strings.TrimSpace(string(stdout)))
+	if err != nil {
+		return nil, fmt.Errorf("stat %s: %w", path, err)
+	}
+	return fi, nil
+}
+
+// parseStatOutput parses "size|hexmode|mtime" as produced by the Stat script.
+func parseStatOutput(name, out string) (BasicFileInfo, error) {
+	parts := strings.Split(out, "|")
+	if len(parts) != 3 {
+		return BasicFileInfo{}, fmt.Errorf("unexpected stat output %q", out)
+	}
+	size, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return BasicFileInfo{}, fmt.Errorf("parsing size: %w", err)
+	}
+	rawMode, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return BasicFileInfo{}, fmt.Errorf("parsing mode: %w", err)
+	}
+	mtime, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return BasicFileInfo{}, fmt.Errorf("parsing mtime: %w", err)
+	}
+	mode := unixModeToFileMode(uint32(rawMode))
+	return BasicFileInfo{
+		FileName:    name,
+		FileSize:    size,
+		FileMode:    mode,
+		FileModTime: time.Unix(mtime, 0),
+		FileIsDir:   mode.IsDir(),
+	}, nil
+}
+
+// unixModeToFileMode converts a raw st_mode into an fs.FileMode.
+func unixModeToFileMode(m uint32) fs.FileMode {
+	mode := fs.FileMode(m & 0777)
+	switch m & 0170000 {
+	case 0040000:
+		mode |= fs.ModeDir
+	case 0120000:
+		mode |= fs.ModeSymlink
+	case 0010000:
+		mode |= fs.ModeNamedPipe
+	case 0140000:
+		mode |= fs.ModeSocket
+	case 0020000:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 0060000:
+		mode |= fs.ModeDevice
+	}
+	if m&04000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if m&02000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if m&01000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+// Glob returns the names of all files matching the pattern on the remote machine.
+func (c *SSHConnection) Glob(pattern string) ([]string, error) {
+	script := fmt.Sprintf(`for f in %s; do [ -e "$f" ] || [ -L "$f" ] && printf '%%s\n' "$f"; done; true`, globQuote(pattern))
+	stdout, stderr, err := c.run(nil, script)
+	if err != nil {
+		return nil, c.fileError(err, stderr, pattern, "glob")
+	}
+	var matches []string
+	for _, line := range strings.Split(string(stdout), "\n") {
+		if line != "" {
+			matches = append(matches, line)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// Exists returns true if the path exists on the remote machine.
+func (c *SSHConnection) Exists(path string) (bool, error) {
+	_, stderr, err := c.run(nil, "test -e "+shellQuote(path))
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, c.fileError(err, stderr, path, "stat")
+	}
+	return true, nil
+}
+
+// Exec runs a command on the remote machine and returns its combined output.
+func (c *SSHConnection) Exec(cmd string, args ...string) ([]byte, error) {
+	return c.runCombined(shellJoin(cmd, args))
+}
+
+// ExecDir runs a command in the specified directory on the remote machine.
+func (c *SSHConnection) ExecDir(dir, cmd string, args ...string) ([]byte, error) {
+	return c.runCombined("cd " + shellQuote(dir) + " && " + shellJoin(cmd, args))
+}
+
+// ExecEnv runs a command with additional environment variables on the remote machine.
+func (c *SSHConnection) ExecEnv(env map[string]string, cmd string, args ...string) ([]byte, error) {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString("env")
+	for _, k := range keys {
+		sb.WriteString(" ")
+		sb.WriteString(shellQuote(k + "=" + env[k]))
+	}
+	sb.WriteString(" ")
+	sb.WriteString(shellJoin(cmd, args))
+	return c.runCombined(sb.String())
+}
+
+// tmux runs a tmux command on the remote machine and returns trimmed stdout.
+func (c *SSHConnection) tmux(args ...string) (string, error) {
+	stdout, stderr, err := c.run(nil, shellJoin("tmux", args))
+	if err != nil {
+		var connErr *ConnectionError
+		if errors.As(err, &connErr) {
+			return "", err
+		}
+		msg := strings.TrimSpace(string(stderr))
+		if msg != "" {
+			return "", fmt.Errorf("tmux %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("tmux %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(stdout)), nil
+}
+
+// isTmuxNoServer reports whether a tmux error means no server or session exists.
+func isTmuxNoServer(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "no server running") ||
+		strings.Contains(msg, "error connecting to") ||
+		strings.Contains(msg, "can't find session") ||
+		strings.Contains(msg, "session not found")
+}
+
+// TmuxNewSession creates a new tmux session on the remote machine.
+func (c *SSHConnection) TmuxNewSession(name, dir string) error {
+	args := []string{"new-session", "-d", "-s", name}
+	if dir != "" {
+		args = append(args, "-c", dir)
+	}
+	_, err := c.tmux(args...)
+	return err
+}
+
+// TmuxKillSession terminates a tmux session on the remote machine.
+// The pane's process group is signalled first so agents don't survive
+// the session as orphans, mirroring KillSessionWithProcesses locally.
+func (c *SSHConnection) TmuxKillSession(name string) error {
+	pid, err := c.tmux("display-message", "-t", name, "-p", "#{pane_pid}")
+	if err == nil && pid != "" {
+		if _, convErr := strconv.Atoi(pid); convErr == nil {
+			script := fmt.Sprintf("pgid=$(ps -o pgid= -p %s | tr -d ' '); "+
+				"if [ -n \"$pgid\" ] && [ \"$pgid\" != 0 ] && [ \"$pgid\" != 1 ]; then "+
+				"kill -TERM -- -$pgid 2>/dev/null; sleep 0.1; kill -KILL -- -$pgid 2>/dev/null; fi; true", pid)
+			_, _, _ = c.run(nil, script)
+		}
+	}
+	_, err = c.tmux("kill-session", "-t", name)
+	return err
+}
+
+// TmuxSendKeys sends keys to a tmux session on the remote machine.
+// Like the local implementation, text is sent literally and Enter follows
+// after a short debounce so the paste is processed first.
+func (c *SSHConnection) TmuxSendKeys(session, keys string) error {
+	script := fmt.Sprintf("%s && sleep %.3f && %s",
+		shellJoin("tmux", []string{"send-keys", "-t", session, "-l", keys}),
+		float64(constants.DefaultDebounceMs)/1000,
+		shellJoin("tmux", []string{"send-keys", "-t", session, "Enter"}))
+	_, stderr, err := c.run(nil, script)
+	if err != nil {
+		var connErr *ConnectionError
+		if errors.As(err, &connErr) {
+			return err
+		}
+		return fmt.Errorf("tmux send-keys: %s", strings.TrimSpace(string(stderr)))
+	}
+	return nil
+}
+
+// TmuxCapturePane captures the last N lines from a tmux pane on the remote machine.
+func (c *SSHConnection) TmuxCapturePane(session string, lines int) (string, error) {
+	return c.tmux("capture-pane", "-p", "-t", session, "-S", fmt.Sprintf("-%d", lines))
+}
+
+// TmuxHasSession returns true if the session exists on the remote machine.
+func (c *SSHConnection) TmuxHasSession(name string) (bool, error) {
+	_, err := c.tmux("has-session", "-t", "="+name)
+	if err != nil {
+		var connErr *ConnectionError
+		if errors.As(err, &connErr) {
+			return false, err
+		}
+		if isTmuxNoServer(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// TmuxListSessions returns all tmux session names on the remote machine.
+func (c *SSHConnection) TmuxListSessions() ([]string, error) {
+	out, err := c.tmux("list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		var connErr *ConnectionError
+		if errors.As(err, &connErr) {
+			return nil, err
+		}
+		if isTmuxNoServer(err) {
+			return nil, nil // No server = no sessions
+		}
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// shellQuote quotes s for safe use as a single POSIX shell word.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellJoin quotes a command and its arguments into a shell command line.
+func shellJoin(cmd string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, shellQuote(cmd))
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// globQuote escapes shell metacharacters in a glob pattern while leaving
+// the glob operators (*, ?, [, ]) active.
+func globQuote(pattern string) string {
+	var sb strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*', '?', '[', ']':
+			sb.WriteRune(r)
+		case '/', '.', '-', '_':
+			sb.WriteRune(r)
+		default:
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteRune('\\')
+				sb.WriteRune(r)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Verify SSHConnection implements Connection.
+var _ Connection = (*SSHConnection)(nil)
@@ -0,0 +1,257 @@
+package connection
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeSSHScript stands in for the ssh binary: it strips ssh options and the
+// host, then runs the remote command with the local shell. This exercises
+// the real quoting and error mapping without needing an sshd.
+const fakeSSHScript = `#!/bin/sh
+while [ $# -gt 0 ]; do
+	case "$1" in
+	-O) echo "Control socket connect(/tmp/x): No such file or directory" >&2; exit 255 ;;
+	-o|-i|-p) shift 2 ;;
+	*) break ;;
+	esac
+done
+if [ "$1" = "unreachable" ]; then
+	echo "ssh: connect to host unreachable port 22: Connection refused" >&2
+	exit 255
+fi
+shift
+exec sh -c "$1"
+`
+
+func newFakeSSH(t *testing.T, host string) *SSHConnection {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "ssh")
+	if err := os.WriteFile(script, []byte(fakeSSHScript), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewSSHConnection(&Machine{Name: "vm", Type: "ssh", Host: host})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.sshPath = script
+	return c
+}
+
+func TestSSHConnection_FileOps(t *testing.T) {
+	c := newFakeSSH(t, "user@vm")
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sub dir", "it's.txt")
+
+	if err := c.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := c.WriteFile(path, []byte("hello\n"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	data, err := c.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "hello\n" {
+		t.Errorf("ReadFile = %q, want %q", data, "hello\n")
+	}
+
+	fi, err := c.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Size() != 6 || fi.IsDir() || fi.Mode().Perm() != 0600 || fi.Name() != "it's.txt" {
+		t.Errorf("Stat = %+v, want 6-byte 0600 file named it's.txt", fi)
+	}
+
+	dirInfo, err := c.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("Stat dir: %v", err)
+	}
+	if !dirInfo.IsDir() {
+		t.Error("Stat dir: IsDir = false, want true")
+	}
+
+	matches, err := c.Glob(filepath.Join(dir, "sub dir", "*.txt"))
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != 1 || matches[0] != path {
+		t.Errorf("Glob = %v, want [%s]", matches, path)
+	}
+
+	if ok, err := c.Exists(path); err != nil || !ok {
+		t.Errorf("Exists = %v, %v; want true, nil", ok, err)
+	}
+	if err := c.Remove(path); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if ok, err := c.Exists(path); err != nil || ok {
+		t.Errorf("Exists after Remove = %v, %v; want false, nil", ok, err)
+	}
+	if err := c.Remove(path); err != nil {
+		t.Errorf("Remove missing file: %v, want nil", err)
+	}
+	if err := c.RemoveAll(filepath.Join(dir, "sub dir")); err != nil {
+		t.Fatalf("RemoveAll: %v", err)
+	}
+}
+
+func TestSSHConnection_NotFound(t *testing.T) {
+	c := newFakeSSH(t, "user@vm")
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	_, err := c.ReadFile(missing)
+	var nf *NotFoundError
+	if !errors.As(err, &nf) {
+		t.Fatalf("ReadFile error = %v, want NotFoundError", err)
+	}
+	if nf.Path != missing {
+		t.Errorf("NotFoundError.Path = %q, want %q", nf.Path, missing)
+	}
+
+	if _, err := c.Stat(missing); !errors.As(err, &nf) {
+		t.Errorf("Stat error = %v, want NotFoundError", err)
+	}
+
+	matches, err := c.Glob(filepath.Join(missing, "*"))
+	if err != nil || len(matches) != 0 {
+		t.Errorf("Glob no matches = %v, %v; want empty, nil", matches, err)
+	}
+}
+
+func TestSSHConnection_Exec(t *testing.T) {
+	c := newFakeSSH(t, "user@vm")
+	dir := t.TempDir()
+
+	out, err := c.Exec("echo", "a b", "$HOME", "it's")
+	if err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "a b $HOME it's" {
+		t.Errorf("Exec output = %q, want arguments passed literally", got)
+	}
+
+	out, err = c.ExecDir(dir, "pwd")
+	if err != nil {
+		t.Fatalf("ExecDir: %v", err)
+	}
+	if got, _ := filepath.EvalSymlinks(strings.TrimSpace(string(out))); got != mustEvalSymlinks(t, dir) {
+		t.Errorf("ExecDir pwd = %q, want %q", got, dir)
+	}
+
+	out, err = c.ExecEnv(map[string]string{"GT_TEST": "x y"}, "sh", "-c", "echo $GT_TEST")
+	if err != nil {
+		t.Fatalf("ExecEnv: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "x y" {
+		t.Errorf("ExecEnv output = %q, want %q", got, "x y")
+	}
+
+	out, err = c.Exec("sh", "-c", "echo oops >&2; exit 3")
+	if err == nil {
+		t.Fatal("Exec failing command: want error")
+	}
+	var connErr *ConnectionError
+	if errors.As(err, &connErr) {
+		t.Errorf("remote command failure mapped to ConnectionError: %v", err)
+	}
+	if !strings.Contains(string(out), "oops") {
+		t.Errorf("Exec combined output = %q, want stderr included", out)
+	}
+}
+
+func TestSSHConnection_ConnectionError(t *testing.T) {
+	c := newFakeSSH(t, "unreachable")
+
+	_, err := c.ReadFile("/etc/hostname")
+	var connErr *ConnectionError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("ReadFile error = %v, want ConnectionError", err)
+	}
+	if connErr.Machine != "vm" {
+		t.Errorf("ConnectionError.Machine = %q, want %q", connErr.Machine, "vm")
+	}
+
+	if _, err := c.Exec("true"); !errors.As(err, &connErr) {
+		t.Errorf("Exec error = %v, want ConnectionError", err)
+	}
+	if _, err := c.TmuxHasSession("gt-x"); !errors.As(err, &connErr) {
+		t.Errorf("TmuxHasSession error = %v, want ConnectionError", err)
+	}
+}
+
+func TestSSHConnection_Close(t *testing.T) {
+	c := newFakeSSH(t, "user@vm")
+	if err := c.Close(); err != nil {
+		t.Errorf("Close with no master = %v, want nil", err)
+	}
+}
+
+func TestMachineRegistry_SSHConnectionPooled(t *testing.T) {
+	r, err := NewMachineRegistry(filepath.Join(t.TempDir(), "machines.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(&Machine{Name: "vm", Type: "ssh", Host: "user@vm"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c1, err := r.Connection("vm")
+	if err != nil {
+		t.Fatalf("Connection: %v", err)
+	}
+	c2, err := r.Connection("vm")
+	if err != nil {
+		t.Fatalf("Connection: %v", err)
+	}
+	if c1 != c2 {
+		t.Error("Connection returned a new SSHConnection, want pooled instance")
+	}
+	if c1.IsLocal() || c1.Name() != "vm" {
+		t.Errorf("Connection = %s (local=%v), want remote vm", c1.Name(), c1.IsLocal())
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"":          "''",
+		"abc":       "'abc'",
+		"a b":       "'a b'",
+		"it's":      `'it'\''s'`,
+		"$(rm -rf)": "'$(rm -rf)'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestUnixModeToFileMode(t *testing.T) {
+	if m := unixModeToFileMode(0x41ed); !m.IsDir() || m.Perm() != 0755 {
+		t.Errorf("unixModeToFileMode(dir 0755) = %v", m)
+	}
+	if m := unixModeToFileMode(0x81a4); !m.IsRegular() || m.Perm() != 0644 {
+		t.Errorf("unixModeToFileMode(file 0644) = %v", m)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, p string) string {
+	t.Helper()
+	r, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
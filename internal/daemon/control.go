@@ -0,0 +1,597 @@
+package daemon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ControlSocketPath returns the path of the daemon's control socket.
+func ControlSocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "control.sock")
+}
+
+// Control socket commands.
+const (
+	CmdStatus    = "status"
+	CmdHeartbeat = "heartbeat"
+	CmdPause     = "pause"
+	CmdResume    = "resume"
+	CmdRestart   = "restart"
+	CmdReload    = "reload"
+	CmdLog       = "log"
+)
+
+// ControlRequest is one command sent to the control socket, as a single
+// JSON line.
+type ControlRequest struct {
+	Command  string   `json:"command"`
+	Patrols  []string `json:"patrols,omitempty"`  // pause, resume
+	Identity string   `json:"identity,omitempty"` // restart
+	Wait     bool     `json:"wait,omitempty"`     // heartbeat: reply once it completes
+	Lines    int      `json:"lines,omitempty"`    // log
+	Follow   bool     `json:"follow,omitempty"`   // log: stream new lines after the reply
+}
+
+// ControlResponse is the daemon's reply to a ControlRequest, as a single
+// JSON line. Only the field for the command is set.
+type ControlResponse struct {
+	Error     string          `json:"error,omitempty"`
+	Status    *StatusReply    `json:"status,omitempty"`
+	Heartbeat *HeartbeatReply `json:"heartbeat,omitempty"`
+	Paused    []string        `json:"paused,omitempty"`
+	Session   string          `json:"session,omitempty"`
+	Reload    *ReloadReply    `json:"reload,omitempty"`
+	Lines     []string        `json:"lines,omitempty"`
+}
+
+// StatusReply describes a running daemon.
+type StatusReply struct {
+	PID              int       `json:"pid"`
+	StartedAt        time.Time `json:"started_at"`
+	LastHeartbeat    time.Time `json:"last_heartbeat"`
+	HeartbeatCount   int64     `json:"heartbeat_count"`
+	HeartbeatRunning bool      `json:"heartbeat_running"`
+	NextHeartbeat    time.Time `json:"next_heartbeat"`
+	Paused           []string  `json:"paused,omitempty"`   // paused over the control socket
+	Disabled         []string  `json:"disabled,omitempty"` // disabled in mayor/daemon.json
+	SessionBackend   string    `json:"session_backend"`
+}
+
+// HeartbeatReply reports a heartbeat requested over the control socket.
+type HeartbeatReply struct {
+	// Queued is false when a requested heartbeat was already pending, so
+	// this request was folded into it.
+	Queued bool `json:"queued"`
+
+	// Count is the heartbeat count once the heartbeat completed; zero
+	// unless the request waited.
+	Count int64 `json:"count,omitempty"`
+}
+
+// ReloadReply reports the patrol config the daemon reloaded.
+type ReloadReply struct {
+	ConfigFile string   `json:"config_file"`
+	Found      bool     `json:"found"` // false: no config file, defaults apply
+	Disabled   []string `json:"disabled,omitempty"`
+}
+
+// Controller is what the control socket exposes. *Daemon implements it;
+// tests serve a fake.
+type Controller interface {
+	Status() StatusReply
+	TriggerHeartbeat(ctx context.Context, wait bool) (HeartbeatReply, error)
+	PausePatrols(patrols []string) ([]string, error)
+	ResumePatrols(patrols []string) ([]string, error)
+	RestartAgent(identity string) (string, error)
+	ReloadConfig() (ReloadReply, error)
+	LogLines(n int) ([]string, error)
+	// FollowLog returns new log lines as they are written. cancel stops
+	// the subscription and closes the channel.
+	FollowLog() (lines <-chan string, cancel func())
+}
+
+// controlRequestTimeout bounds how long a client may take to send its request.
+const controlRequestTimeout = 10 * time.Second
+
+// ControlServer serves a Controller on a unix socket. Each connection
+// carries one request and one response; a followed log then streams
+// plain text lines until the client hangs up.
+type ControlServer struct {
+	socketPath string
+	controller Controller
+	logf       func(format string, args ...interface{})
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewControlServer creates a control server for socketPath. logf receives
+// lifecycle messages (the daemon passes its logger's Printf).
+func NewControlServer(socketPath string, c Controller, logf func(format string, args ...interface{})) *ControlServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ControlServer{
+		socketPath: socketPath,
+		controller: c,
+		logf:       logf,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start listens on the socket and serves requests in the background.
+func (s *ControlServer) Start() error {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0755); err != nil {
+		return fmt.Errorf("creating socket directory: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", s.socketPath, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("control socket already served at %s", s.socketPath)
+	}
+	// A socket file left by a daemon that crashed would make Listen fail
+	_ = os.Remove(s.socketPath)
+
+	ln, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.socketPath, err)
+	}
+	if err := os.Chmod(s.socketPath, 0600); err != nil {
+		_ = ln.Close()
+		return fmt.Errorf("securing %s: %w", s.socketPath, err)
+	}
+
+	s.mu.Lock()
+	s.listener = ln
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.serve(ln)
+	return nil
+}
+
+// Stop closes the socket and ends in-flight requests.
+func (s *ControlServer) Stop() {
+	s.mu.Lock()
+	ln := s.listener
+	s.listener = nil
+	s.mu.Unlock()
+
+	s.cancel()
+	if ln != nil {
+		_ = ln.Close()
+		_ = os.Remove(s.socketPath)
+	}
+	s.wg.Wait()
+}
+
+func (s *ControlServer) serve(ln net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+func (s *ControlServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	// Hanging up cancels the request, and so does stopping the server
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = conn.SetDeadline(time.Now())
+	}()
+
+	_ = conn.SetReadDeadline(time.Now().Add(controlRequestTimeout))
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return
+	}
+	var req ControlRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		_ = json.NewEncoder(conn).Encode(ControlResponse{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+	go func() {
+		// Clients send nothing after the request, so any read result
+		// means they have gone
+		_, _ = r.ReadByte()
+		cancel()
+	}()
+
+	if req.Command == CmdLog && req.Follow {
+		s.followLog(ctx, conn, req)
+		return
+	}
+	_ = json.NewEncoder(conn).Encode(s.dispatch(ctx, req))
+}
+
+func (s *ControlServer) dispatch(ctx context.Context, req ControlRequest) ControlResponse {
+	c := s.controller
+	switch req.Command {
+	case CmdStatus:
+		status := c.Status()
+		return ControlResponse{Status: &status}
+	case CmdHeartbeat:
+		reply, err := c.TriggerHeartbeat(ctx, req.Wait)
+		if err != nil {
+			return ControlResponse{Error: err.Error()}
+		}
+		return ControlResponse{Heartbeat: &reply}
+	case CmdPause, CmdResume:
+		if len(req.Patrols) == 0 {
+			return ControlResponse{Error: "no patrols given"}
+		}
+		var paused []string
+		var err error
+		if req.Command == CmdPause {
+			paused, err = c.PausePatrols(req.Patrols)
+		} else {
+			paused, err = c.ResumePatrols(req.Patrols)
+		}
+		if err != nil {
+			return ControlResponse{Error: err.Error()}
+		}
+		return ControlResponse{Paused: paused}
+	case CmdRestart:
+		if req.Identity == "" {
+			return ControlResponse{Error: "no agent identity given"}
+		}
+		sessionName, err := c.RestartAgent(req.Identity)
+		if err != nil {
+			return ControlResponse{Error: err.Error()}
+		}
+		return ControlResponse{Session: sessionName}
+	case CmdReload:
+		reply, err := c.ReloadConfig()
+		if err != nil {
+			return ControlResponse{Error: err.Error()}
+		}
+		return ControlResponse{Reload: &reply}
+	case CmdLog:
+		lines, err := c.LogLines(req.Lines)
+		if err != nil {
+			return ControlResponse{Error: err.Error()}
+		}
+		return ControlResponse{Lines: lines}
+	}
+	return ControlResponse{Error: fmt.Sprintf("unknown command %q", req.Command)}
+}
+
+// followLog replies with the last req.Lines lines, then writes each new
+// line as plain text until the client hangs up or the server stops.
+func (s *ControlServer) followLog(ctx context.Context, conn net.Conn, req ControlRequest) {
+	// Subscribe first so no line falls between the tail and the stream
+	live, stop := s.controller.FollowLog()
+	defer stop()
+
+	lines, err := s.controller.LogLines(req.Lines)
+	if err != nil {
+		_ = json.NewEncoder(conn).Encode(ControlResponse{Error: err.Error()})
+		return
+	}
+	if err := json.NewEncoder(conn).Encode(ControlResponse{Lines: lines}); err != nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-live:
+			if !ok {
+				return
+			}
+			if _, err := io.WriteString(conn, line+"\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// logSubscriberBuffer is how many lines a log follower may fall behind
+// before it is disconnected.
+const logSubscriberBuffer = 256
+
+// logBroadcaster is an io.Writer that copies each log line written to it
+// to the followers subscribed at the time. The daemon's logger writes
+// through it alongside the log file.
+type logBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan string]struct{}
+}
+
+func newLogBroadcaster() *logBroadcaster {
+	return &logBroadcaster{subscribers: make(map[chan string]struct{})}
+}
+
+// Write sends each complete line in p to the subscribers. log.Logger
+// writes one whole entry per call.
+func (b *logBroadcaster) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.subscribers) == 0 {
+		return len(p), nil
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		for ch := range b.subscribers {
+			select {
+			case ch <- line:
+			default:
+				// Too slow to keep up; drop it rather than stall the daemon
+				delete(b.subscribers, ch)
+				close(ch)
+			}
+		}
+	}
+	return len(p), nil
+}
+
+func (b *logBroadcaster) subscribe() (<-chan string, func()) {
+	ch := make(chan string, logSubscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// lastLogLines returns the last n lines of the file at path, reading
+// backwards so a long log is not read whole.
+func lastLogLines(path string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunk = 64 << 10
+	end := info.Size()
+	var data []byte
+	for end > 0 && bytes.Count(data, []byte("\n")) <= n {
+		start := end - chunk
+		if start < 0 {
+			start = 0
+		}
+		buf := make([]byte, end-start)
+		if _, err := f.ReadAt(buf, start); err != nil && err != io.EOF {
+			return nil, err
+		}
+		data = append(buf, data...)
+		end = start
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil, nil
+	}
+	if end > 0 {
+		// The first line may be cut off mid-way
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// Status implements Controller.
+func (d *Daemon) Status() StatusReply {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	reply := StatusReply{
+		PID:              os.Getpid(),
+		HeartbeatRunning: d.heartbeatRunning,
+		NextHeartbeat:    d.nextHeartbeat,
+		Paused:           d.pausedPatrolList(),
+		Disabled:         disabledPatrols(d.patrolConfig),
+		SessionBackend:   d.sessionBackend,
+	}
+	if d.state != nil {
+		reply.StartedAt = d.state.StartedAt
+		reply.LastHeartbeat = d.state.LastHeartbeat
+		reply.HeartbeatCount = d.state.HeartbeatCount
+	}
+	return reply
+}
+
+// TriggerHeartbeat implements Controller. The heartbeat runs on the main
+// loop; requests that arrive while one is pending share it. With wait it
+// returns once the heartbeat has completed.
+func (d *Daemon) TriggerHeartbeat(ctx context.Context, wait bool) (HeartbeatReply, error) {
+	if !wait {
+		select {
+		case d.heartbeatNow <- nil:
+			return HeartbeatReply{Queued: true}, nil
+		default:
+			return HeartbeatReply{}, nil
+		}
+	}
+
+	done := make(chan struct{})
+	select {
+	case d.heartbeatNow <- done:
+	case <-ctx.Done():
+		return HeartbeatReply{}, ctx.Err()
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return HeartbeatReply{}, ctx.Err()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	reply := HeartbeatReply{Queued: true}
+	if d.state != nil {
+		reply.Count = d.state.HeartbeatCount
+	}
+	return reply, nil
+}
+
+// PausePatrols implements Controller. Pauses last until resumed or the
+// daemon restarts.
+func (d *Daemon) PausePatrols(patrols []string) ([]string, error) {
+	if err := validatePatrols(patrols); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pausedPatrols == nil {
+		d.pausedPatrols = make(map[string]bool)
+	}
+	for _, p := range patrols {
+		d.pausedPatrols[p] = true
+	}
+	d.logger.Printf("Paused patrols: %s", strings.Join(patrols, ", "))
+	return d.pausedPatrolList(), nil
+}
+
+// ResumePatrols implements Controller.
+func (d *Daemon) ResumePatrols(patrols []string) ([]string, error) {
+	if err := validatePatrols(patrols); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range patrols {
+		delete(d.pausedPatrols, p)
+	}
+	d.logger.Printf("Resumed patrols: %s", strings.Join(patrols, ", "))
+	return d.pausedPatrolList(), nil
+}
+
+// RestartAgent implements Controller: it kills the agent's session, if
+// any, and starts a fresh one, as a lifecycle restart request does. It
+// waits for a heartbeat in progress so the two don't race to start the
+// same session.
+func (d *Daemon) RestartAgent(identity string) (string, error) {
+	sessionName := d.identityToSession(identity)
+	if sessionName == "" {
+		return "", fmt.Errorf("unknown agent identity: %s", identity)
+	}
+
+	d.workMu.Lock()
+	defer d.workMu.Unlock()
+	d.logger.Printf("Restart of %s requested over control socket", identity)
+	if err := d.executeLifecycleAction(&LifecycleRequest{From: identity, Action: ActionRestart, Timestamp: time.Now()}); err != nil {
+		return "", err
+	}
+	return sessionName, nil
+}
+
+// ReloadConfig implements Controller. It re-reads mayor/daemon.json;
+// Dolt server settings still need a daemon restart.
+func (d *Daemon) ReloadConfig() (ReloadReply, error) {
+	path := PatrolConfigFile(d.config.TownRoot)
+	if _, err := os.Stat(path); err != nil && !os.IsNotExist(err) {
+		return ReloadReply{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	cfg := LoadPatrolConfig(d.config.TownRoot)
+
+	d.mu.Lock()
+	d.patrolConfig = cfg
+	d.mu.Unlock()
+
+	reply := ReloadReply{ConfigFile: path, Found: cfg != nil, Disabled: disabledPatrols(cfg)}
+	d.logger.Printf("Reloaded patrol config from %s", path)
+	return reply, nil
+}
+
+// LogLines implements Controller.
+func (d *Daemon) LogLines(n int) ([]string, error) {
+	lines, err := lastLogLines(d.config.LogFile, n)
+	if err != nil {
+		return nil, fmt.Errorf("reading log: %w", err)
+	}
+	return lines, nil
+}
+
+// FollowLog implements Controller.
+func (d *Daemon) FollowLog() (<-chan string, func()) {
+	if d.logs == nil {
+		ch := make(chan string)
+		close(ch)
+		return ch, func() {}
+	}
+	return d.logs.subscribe()
+}
+
+// pausedPatrolList returns the paused patrols in heartbeat order. d.mu
+// must be held.
+func (d *Daemon) pausedPatrolList() []string {
+	var paused []string
+	for _, p := range Patrols {
+		if d.pausedPatrols[p] {
+			paused = append(paused, p)
+		}
+	}
+	return paused
+}
+
+// disabledPatrols returns the patrols cfg disables.
+func disabledPatrols(cfg *DaemonPatrolConfig) []string {
+	var disabled []string
+	for _, p := range Patrols {
+		if !IsPatrolEnabled(cfg, p) {
+			disabled = append(disabled, p)
+		}
+	}
+	return disabled
+}
+
+func validatePatrols(patrols []string) error {
+	for _, p := range patrols {
+		if !isPatrol(p) {
+			valid := append([]string(nil), Patrols...)
+			sort.Strings(valid)
+			return fmt.Errorf("unknown patrol %q (valid: %s)", p, strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+func isPatrol(name string) bool {
+	for _, p := range Patrols {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
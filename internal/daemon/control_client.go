@@ -0,0 +1,178 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// ErrControlUnavailable is returned when nothing serves the control
+// socket, usually because the daemon is not running.
+var ErrControlUnavailable = errors.New("daemon control socket not available (start the daemon with 'gt daemon start')")
+
+const (
+	controlDialTimeout = 2 * time.Second
+	controlCallTimeout = 30 * time.Second
+	// controlLongCallTimeout covers requests that wait on sessions: a
+	// restart, or a full heartbeat.
+	controlLongCallTimeout = 10 * time.Minute
+)
+
+// ControlClient sends commands to a running daemon over its control socket.
+type ControlClient struct {
+	socketPath string
+}
+
+// NewControlClient returns a client for the daemon serving townRoot.
+func NewControlClient(townRoot string) *ControlClient {
+	return &ControlClient{socketPath: ControlSocketPath(townRoot)}
+}
+
+// dial connects to the control socket and sends req.
+func (c *ControlClient) dial(req ControlRequest, timeout time.Duration) (net.Conn, *bufio.Reader, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, controlDialTimeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrControlUnavailable, err)
+	}
+	if timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(timeout))
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		_ = conn.Close()
+		return nil, nil, fmt.Errorf("daemon %s: %w", req.Command, err)
+	}
+	return conn, bufio.NewReader(conn), nil
+}
+
+// readResponse reads the response line for command.
+func readResponse(r *bufio.Reader, command string) (*ControlResponse, error) {
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("daemon %s: reading response: %w", command, err)
+	}
+	var resp ControlResponse
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, fmt.Errorf("daemon %s: %w", command, err)
+	}
+	if resp.Error != "" {
+		return nil, fmt.Errorf("daemon %s: %s", command, resp.Error)
+	}
+	return &resp, nil
+}
+
+// call sends one request and reads its response.
+func (c *ControlClient) call(req ControlRequest, timeout time.Duration) (*ControlResponse, error) {
+	conn, r, err := c.dial(req, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return readResponse(r, req.Command)
+}
+
+// Status returns the daemon's live status.
+func (c *ControlClient) Status() (*StatusReply, error) {
+	resp, err := c.call(ControlRequest{Command: CmdStatus}, controlCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status == nil {
+		return nil, fmt.Errorf("daemon %s: empty response", CmdStatus)
+	}
+	return resp.Status, nil
+}
+
+// Heartbeat asks the daemon to run a heartbeat now instead of at the next
+// interval. With wait it returns once the heartbeat has completed.
+func (c *ControlClient) Heartbeat(wait bool) (*HeartbeatReply, error) {
+	timeout := controlCallTimeout
+	if wait {
+		timeout = controlLongCallTimeout
+	}
+	resp, err := c.call(ControlRequest{Command: CmdHeartbeat, Wait: wait}, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Heartbeat == nil {
+		return &HeartbeatReply{}, nil
+	}
+	return resp.Heartbeat, nil
+}
+
+// Pause stops the daemon running patrols in its heartbeat. It returns the
+// patrols now paused.
+func (c *ControlClient) Pause(patrols ...string) ([]string, error) {
+	resp, err := c.call(ControlRequest{Command: CmdPause, Patrols: patrols}, controlCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Paused, nil
+}
+
+// Resume undoes Pause. It returns the patrols still paused.
+func (c *ControlClient) Resume(patrols ...string) ([]string, error) {
+	resp, err := c.call(ControlRequest{Command: CmdResume, Patrols: patrols}, controlCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Paused, nil
+}
+
+// Restart restarts the session of the agent with identity (e.g.
+// "gastown-witness") and returns the session name.
+func (c *ControlClient) Restart(identity string) (string, error) {
+	resp, err := c.call(ControlRequest{Command: CmdRestart, Identity: identity}, controlLongCallTimeout)
+	if err != nil {
+		return "", err
+	}
+	return resp.Session, nil
+}
+
+// Reload makes the daemon re-read its patrol config.
+func (c *ControlClient) Reload() (*ReloadReply, error) {
+	resp, err := c.call(ControlRequest{Command: CmdReload}, controlCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Reload == nil {
+		return &ReloadReply{}, nil
+	}
+	return resp.Reload, nil
+}
+
+// Log returns the last n lines of the daemon log.
+func (c *ControlClient) Log(n int) ([]string, error) {
+	resp, err := c.call(ControlRequest{Command: CmdLog, Lines: n}, controlCallTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Lines, nil
+}
+
+// FollowLog writes the last n lines of the daemon log to w, then each new
+// line as the daemon logs it, until the daemon stops or w fails.
+func (c *ControlClient) FollowLog(n int, w io.Writer) error {
+	req := ControlRequest{Command: CmdLog, Lines: n, Follow: true}
+	conn, r, err := c.dial(req, controlCallTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := readResponse(r, req.Command)
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Time{})
+	for _, line := range resp.Lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
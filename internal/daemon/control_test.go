@@ -0,0 +1,325 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeController records control requests instead of driving a daemon.
+type fakeController struct {
+	mu         sync.Mutex
+	heartbeats int
+	paused     map[string]bool
+	restarted  []string
+	logs       *logBroadcaster
+}
+
+func newFakeController() *fakeController {
+	return &fakeController{paused: make(map[string]bool), logs: newLogBroadcaster()}
+}
+
+func (f *fakeController) Status() StatusReply {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return StatusReply{PID: 42, HeartbeatCount: int64(f.heartbeats), SessionBackend: "tmux"}
+}
+
+func (f *fakeController) TriggerHeartbeat(ctx context.Context, wait bool) (HeartbeatReply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.heartbeats++
+	reply := HeartbeatReply{Queued: true}
+	if wait {
+		reply.Count = int64(f.heartbeats)
+	}
+	return reply, nil
+}
+
+func (f *fakeController) PausePatrols(patrols []string) ([]string, error) {
+	if err := validatePatrols(patrols); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range patrols {
+		f.paused[p] = true
+	}
+	return f.pausedList(), nil
+}
+
+func (f *fakeController) ResumePatrols(patrols []string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range patrols {
+		delete(f.paused, p)
+	}
+	return f.pausedList(), nil
+}
+
+func (f *fakeController) pausedList() []string {
+	var out []string
+	for _, p := range Patrols {
+		if f.paused[p] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func (f *fakeController) RestartAgent(identity string) (string, error) {
+	if identity == "nobody" {
+		return "", fmt.Errorf("unknown agent identity: %s", identity)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.restarted = append(f.restarted, identity)
+	return "gt-" + identity, nil
+}
+
+func (f *fakeController) ReloadConfig() (ReloadReply, error) {
+	return ReloadReply{ConfigFile: "mayor/daemon.json", Found: true, Disabled: []string{"refinery"}}, nil
+}
+
+func (f *fakeController) LogLines(n int) ([]string, error) {
+	return []string{"one", "two"}[:n], nil
+}
+
+func (f *fakeController) FollowLog() (<-chan string, func()) {
+	return f.logs.subscribe()
+}
+
+// startControl serves c on a socket in a short temp dir (unix socket
+// paths are length-limited) and returns a client for it.
+func startControl(t *testing.T, c Controller) (*ControlServer, *ControlClient) {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "gtctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "control.sock")
+	s := NewControlServer(path, c, t.Logf)
+	if err := s.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(s.Stop)
+	return s, &ControlClient{socketPath: path}
+}
+
+func TestControlRoundTrip(t *testing.T) {
+	fake := newFakeController()
+	_, client := startControl(t, fake)
+
+	status, err := client.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.PID != 42 || status.SessionBackend != "tmux" {
+		t.Errorf("Status = %+v", status)
+	}
+
+	if hb, err := client.Heartbeat(false); err != nil || !hb.Queued || hb.Count != 0 {
+		t.Errorf("Heartbeat(false) = %+v, %v", hb, err)
+	}
+	if hb, err := client.Heartbeat(true); err != nil || hb.Count != 2 {
+		t.Errorf("Heartbeat(true) = %+v, %v; want count 2", hb, err)
+	}
+
+	paused, err := client.Pause("gupp", "witness")
+	if err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if want := []string{"witness", "gupp"}; !reflect.DeepEqual(paused, want) {
+		t.Errorf("Pause = %v, want %v", paused, want)
+	}
+	if _, err := client.Pause("bogus"); err == nil || !strings.Contains(err.Error(), `unknown patrol "bogus"`) {
+		t.Errorf("Pause(bogus) error = %v", err)
+	}
+	if paused, err := client.Resume("witness"); err != nil || !reflect.DeepEqual(paused, []string{"gupp"}) {
+		t.Errorf("Resume = %v, %v", paused, err)
+	}
+	if _, err := client.Pause(); err == nil {
+		t.Error("Pause() with no patrols should fail")
+	}
+
+	if sess, err := client.Restart("gastown-witness"); err != nil || sess != "gt-gastown-witness" {
+		t.Errorf("Restart = %q, %v", sess, err)
+	}
+	if _, err := client.Restart("nobody"); err == nil || !strings.Contains(err.Error(), "unknown agent identity") {
+		t.Errorf("Restart(nobody) error = %v", err)
+	}
+
+	if reload, err := client.Reload(); err != nil || !reload.Found || len(reload.Disabled) != 1 {
+		t.Errorf("Reload = %+v, %v", reload, err)
+	}
+
+	if lines, err := client.Log(1); err != nil || !reflect.DeepEqual(lines, []string{"one"}) {
+		t.Errorf("Log = %v, %v", lines, err)
+	}
+}
+
+func TestControlFollowLog(t *testing.T) {
+	fake := newFakeController()
+	s, client := startControl(t, fake)
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- client.FollowLog(2, pw)
+		_ = pw.Close()
+	}()
+
+	r := bufio.NewReader(pr)
+	readLine := func() string {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading followed log: %v", err)
+		}
+		return strings.TrimSuffix(line, "\n")
+	}
+	if got := readLine(); got != "one" {
+		t.Errorf("first line = %q, want one", got)
+	}
+	if got := readLine(); got != "two" {
+		t.Errorf("second line = %q, want two", got)
+	}
+
+	// The server subscribes before replying, so this line is streamed
+	_, _ = fake.logs.Write([]byte("2026/10/16 12:00:00 Heartbeat starting\n"))
+	if got := readLine(); got != "2026/10/16 12:00:00 Heartbeat starting" {
+		t.Errorf("streamed line = %q", got)
+	}
+
+	// Stopping the server ends the stream
+	s.Stop()
+	go func() { _, _ = io.Copy(io.Discard, pr) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("FollowLog did not return after the server stopped")
+	}
+}
+
+func TestControlClientWithoutDaemon(t *testing.T) {
+	client := NewControlClient(t.TempDir())
+	if _, err := client.Status(); !errors.Is(err, ErrControlUnavailable) {
+		t.Errorf("Status error = %v, want ErrControlUnavailable", err)
+	}
+}
+
+func TestDaemonPauseSkipsPatrol(t *testing.T) {
+	d := testDaemon()
+
+	if !d.patrolActive("gupp") {
+		t.Fatal("gupp should be active by default")
+	}
+	if _, err := d.PausePatrols([]string{"gupp"}); err != nil {
+		t.Fatalf("PausePatrols: %v", err)
+	}
+	if d.patrolActive("gupp") {
+		t.Error("paused gupp patrol should not be active")
+	}
+	if got := d.Status().Paused; !reflect.DeepEqual(got, []string{"gupp"}) {
+		t.Errorf("Status().Paused = %v", got)
+	}
+	if _, err := d.PausePatrols([]string{"dolt"}); err == nil {
+		t.Error("pausing an unknown patrol should fail")
+	}
+	if _, err := d.ResumePatrols([]string{"gupp"}); err != nil {
+		t.Fatalf("ResumePatrols: %v", err)
+	}
+	if !d.patrolActive("gupp") {
+		t.Error("resumed gupp patrol should be active")
+	}
+}
+
+func TestDaemonReloadConfig(t *testing.T) {
+	d, cleanup := testDaemonWithTown(t, "test-town")
+	defer cleanup()
+
+	reply, err := d.ReloadConfig()
+	if err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	if reply.Found || len(reply.Disabled) != 0 {
+		t.Errorf("ReloadConfig without config = %+v", reply)
+	}
+
+	cfg := `{"type": "daemon-patrol-config", "version": 1, "patrols": {"refinery": {"enabled": false}}}`
+	if err := os.WriteFile(PatrolConfigFile(d.config.TownRoot), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reply, err = d.ReloadConfig()
+	if err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	if !reply.Found || !reflect.DeepEqual(reply.Disabled, []string{"refinery"}) {
+		t.Errorf("ReloadConfig = %+v, want refinery disabled", reply)
+	}
+	if d.patrolActive("refinery") {
+		t.Error("refinery should be inactive after reload")
+	}
+}
+
+func TestDaemonTriggerHeartbeatCoalesces(t *testing.T) {
+	d := testDaemon()
+	d.heartbeatNow = make(chan chan struct{}, 1)
+
+	if reply, _ := d.TriggerHeartbeat(context.Background(), false); !reply.Queued {
+		t.Error("first trigger should be queued")
+	}
+	if reply, _ := d.TriggerHeartbeat(context.Background(), false); reply.Queued {
+		t.Error("second trigger should share the pending heartbeat")
+	}
+
+	// A waiting request gives up with its context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := d.TriggerHeartbeat(ctx, true); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting trigger error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestLastLogLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.log")
+	var b strings.Builder
+	for i := 1; i <= 5000; i++ {
+		fmt.Fprintf(&b, "line %d with some padding to make the log span chunks\n", i)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := lastLogLines(path, 3)
+	if err != nil {
+		t.Fatalf("lastLogLines: %v", err)
+	}
+	want := []string{
+		"line 4998 with some padding to make the log span chunks",
+		"line 4999 with some padding to make the log span chunks",
+		"line 5000 with some padding to make the log span chunks",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lastLogLines(3) = %v", lines)
+	}
+
+	all, err := lastLogLines(path, 10000)
+	if err != nil || len(all) != 5000 || all[0] != "line 1 with some padding to make the log span chunks" {
+		t.Errorf("lastLogLines(10000) returned %d lines, first %q, err %v", len(all), all[0], err)
+	}
+
+	if _, err := lastLogLines(filepath.Join(t.TempDir(), "missing.log"), 3); err == nil {
+		t.Error("missing log should be an error")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
@@ -23,6 +24,8 @@ import (
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -40,7 +43,7 @@ import (
 type Daemon struct {
 	config       *Config
 	patrolConfig *DaemonPatrolConfig
-	tmux         *tmux.Tmux
+	sessions     session.Backend
 	logger       *log.Logger
 	ctx          context.Context
 	cancel       context.CancelFunc
@@ -49,6 +52,32 @@ type Daemon struct {
 	doltServer    *DoltServerManager
 	krcPruner     *KRCPruner
 
+	// supervisor hosts agent sessions when the town uses the headless
+	// session backend; nil with tmux.
+	supervisor *headless.Supervisor
+
+	// Control socket (gt daemon status/heartbeat/pause/...). logs copies
+	// log lines to followers of gt daemon logs -f.
+	control        *ControlServer
+	logs           *logBroadcaster
+	sessionBackend string
+
+	// heartbeatNow asks the main loop for an immediate heartbeat. A
+	// non-nil channel is closed once that heartbeat has completed.
+	heartbeatNow chan chan struct{}
+
+	// workMu serializes heartbeats with restarts requested over the
+	// control socket.
+	workMu sync.Mutex
+
+	// mu guards state, patrolConfig and the fields below, which control
+	// socket requests read and write from their own goroutines.
+	mu               sync.Mutex
+	state            *State
+	pausedPatrols    map[string]bool
+	heartbeatRunning bool
+	nextHeartbeat    time.Time
+
 	// Mass death detection: track recent session deaths
 	deathsMu     sync.Mutex
 	recentDeaths []sessionDeath
@@ -86,7 +115,8 @@ func New(config *Config) (*Daemon, error) {
 		return nil, fmt.Errorf("opening log file: %w", err)
 	}
 
-	logger := log.New(logFile, "", log.LstdFlags)
+	logs := newLogBroadcaster()
+	logger := log.New(io.MultiWriter(logFile, logs), "", log.LstdFlags)
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Load patrol config from mayor/daemon.json (optional - nil if missing)
@@ -104,15 +134,28 @@ func New(config *Config) (*Daemon, error) {
 		}
 	}
 
-	return &Daemon{
-		config:       config,
-		patrolConfig: patrolConfig,
-		tmux:         tmux.NewTmux(),
-		logger:       logger,
-		ctx:          ctx,
-		cancel:       cancel,
-		doltServer:   doltServer,
-	}, nil
+	// Headless towns run agents under PTYs owned by this process
+	backend := session.BackendName(config.TownRoot)
+	var supervisor *headless.Supervisor
+	if backend == session.BackendHeadless {
+		supervisor = headless.NewSupervisor(config.TownRoot, logger.Printf)
+	}
+
+	d := &Daemon{
+		config:         config,
+		patrolConfig:   patrolConfig,
+		sessions:       session.NewBackend(config.TownRoot),
+		logger:         logger,
+		ctx:            ctx,
+		cancel:         cancel,
+		doltServer:     doltServer,
+		supervisor:     supervisor,
+		logs:           logs,
+		sessionBackend: backend,
+		heartbeatNow:   make(chan chan struct{}, 16),
+	}
+	d.control = NewControlServer(ControlSocketPath(config.TownRoot), d, logger.Printf)
+	return d, nil
 }
 
 // Run starts the daemon main loop.
@@ -151,6 +194,24 @@ func (d *Daemon) Run() error {
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
+	d.mu.Lock()
+	d.state = state
+	d.nextHeartbeat = time.Now().Add(recoveryHeartbeatInterval)
+	d.mu.Unlock()
+
+	// Start the session supervisor before anything needs a session
+	if d.supervisor != nil {
+		if err := d.supervisor.Start(); err != nil {
+			return fmt.Errorf("starting session supervisor: %w", err)
+		}
+	}
+
+	// Start the control socket (gt daemon status, heartbeat, pause, ...)
+	if err := d.control.Start(); err != nil {
+		d.logger.Printf("Warning: failed to start control socket: %v", err)
+	} else {
+		d.logger.Printf("Control socket listening on %s", ControlSocketPath(d.config.TownRoot))
+	}
 
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
@@ -165,6 +226,7 @@ func (d *Daemon) Run() error {
 
 	// Start feed curator goroutine
 	d.curator = feed.NewCurator(d.config.TownRoot)
+	d.curator.SetLogger(d.logger.Printf)
 	if err := d.curator.Start(); err != nil {
 		d.logger.Printf("Warning: failed to start feed curator: %v", err)
 	} else {
@@ -205,19 +267,58 @@ func (d *Daemon) Run() error {
 			if isLifecycleSignal(sig) {
 				// Lifecycle signal: immediate lifecycle processing (from gt handoff)
 				d.logger.Println("Received lifecycle signal, processing lifecycle requests immediately")
+				d.workMu.Lock()
 				d.processLifecycleRequests()
+				d.workMu.Unlock()
 			} else {
 				d.logger.Printf("Received signal %v, shutting down", sig)
 				return d.shutdown(state)
 			}
 
+		case done := <-d.heartbeatNow:
+			// Heartbeat requested over the control socket (gt daemon heartbeat).
+			// Requests queued meanwhile share this heartbeat.
+			waiters := []chan struct{}{done}
+		drain:
+			for {
+				select {
+				case more := <-d.heartbeatNow:
+					waiters = append(waiters, more)
+				default:
+					break drain
+				}
+			}
+			d.logger.Println("Heartbeat requested via control socket")
+			d.heartbeat(state)
+			for _, w := range waiters {
+				if w != nil {
+					close(w)
+				}
+			}
+			d.resetHeartbeatTimer(timer)
+
 		case <-timer.C:
 			d.heartbeat(state)
 
 			// Fixed recovery interval (no activity-based backoff)
-			timer.Reset(recoveryHeartbeatInterval)
+			d.resetHeartbeatTimer(timer)
+		}
+	}
+}
+
+// resetHeartbeatTimer schedules the next heartbeat a full interval from now.
+func (d *Daemon) resetHeartbeatTimer(timer *time.Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
 		}
 	}
+	timer.Reset(recoveryHeartbeatInterval)
+
+	d.mu.Lock()
+	d.nextHeartbeat = time.Now().Add(recoveryHeartbeatInterval)
+	d.mu.Unlock()
 }
 
 // recoveryHeartbeatInterval is the fixed interval for recovery-focused daemon.
@@ -242,85 +343,149 @@ func (d *Daemon) heartbeat(state *State) {
 		return
 	}
 
+	d.workMu.Lock()
+	defer d.workMu.Unlock()
+	d.setHeartbeatRunning(true)
+	defer d.setHeartbeatRunning(false)
+
 	d.logger.Println("Heartbeat starting (recovery-focused)")
 
 	// 0. Ensure Dolt server is running (if configured)
 	// This must happen before beads operations that depend on Dolt.
 	d.ensureDoltServerRunning()
 
+	// Steps 1-3 share the deacon patrol; checked once so a pause can't
+	// split them
+	deaconActive := d.patrolActive("deacon")
+
 	// 1. Ensure Deacon is running (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
+	if deaconActive {
 		d.ensureDeaconRunning()
-	} else {
-		d.logger.Printf("Deacon patrol disabled in config, skipping")
 	}
 
 	// 2. Poke Boot for intelligent triage (stuck/nudge/interrupt)
 	// Boot handles nuanced "is Deacon responsive" decisions
 	// Only run if Deacon patrol is enabled
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
+	if deaconActive {
 		d.ensureBootRunning()
 	}
 
 	// 3. Direct Deacon heartbeat check (belt-and-suspenders)
 	// Boot may not detect all stuck states; this provides a fallback
 	// Only run if Deacon patrol is enabled
-	if IsPatrolEnabled(d.patrolConfig, "deacon") {
+	if deaconActive {
 		d.checkDeaconHeartbeat()
 	}
 
 	// 4. Ensure Witnesses are running for all rigs (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "witness") {
+	if d.patrolActive("witness") {
 		d.ensureWitnessesRunning()
-	} else {
-		d.logger.Printf("Witness patrol disabled in config, skipping")
 	}
 
 	// 5. Ensure Refineries are running for all rigs (restart if dead)
 	// Check patrol config - can be disabled in mayor/daemon.json
-	if IsPatrolEnabled(d.patrolConfig, "refinery") {
+	if d.patrolActive("refinery") {
 		d.ensureRefineriesRunning()
-	} else {
-		d.logger.Printf("Refinery patrol disabled in config, skipping")
 	}
 
 	// 6. Trigger pending polecat spawns (bootstrap mode - ZFC violation acceptable)
 	// This ensures polecats get nudged even when Deacon isn't in a patrol cycle.
 	// Uses regex-based WaitForRuntimeReady, which is acceptable for daemon bootstrap.
-	d.triggerPendingSpawns()
+	if d.patrolActive("spawns") {
+		d.triggerPendingSpawns()
+	}
 
 	// 7. Process lifecycle requests
-	d.processLifecycleRequests()
+	if d.patrolActive("lifecycle") {
+		d.processLifecycleRequests()
+	}
 
 	// 8. (Removed) Stale agent check - violated "discover, don't track"
 
 	// 9. Check for GUPP violations (agents with work-on-hook not progressing)
-	d.checkGUPPViolations()
+	if d.patrolActive("gupp") {
+		d.checkGUPPViolations()
+	}
 
 	// 10. Check for orphaned work (assigned to dead agents)
-	d.checkOrphanedWork()
+	if d.patrolActive("orphaned-work") {
+		d.checkOrphanedWork()
+	}
 
 	// 11. Check polecat session health (proactive crash detection)
 	// This validates tmux sessions are still alive for polecats with work-on-hook
-	d.checkPolecatSessionHealth()
+	if d.patrolActive("polecat-health") {
+		d.checkPolecatSessionHealth()
+	}
 
 	// 12. Clean up orphaned claude subagent processes (memory leak prevention)
 	// These are Task tool subagents that didn't clean up after completion.
 	// This is a safety net - Deacon patrol also does this more frequently.
-	d.cleanupOrphanedProcesses()
+	if d.patrolActive("orphan-processes") {
+		d.cleanupOrphanedProcesses()
+	}
+
+	// 13. Release scheduled mail that is due (gt mail send --at/--in)
+	if d.patrolActive("scheduled-mail") {
+		d.releaseScheduledMail()
+	}
 
 	// Update state
+	d.mu.Lock()
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
-	if err := SaveState(d.config.TownRoot, state); err != nil {
+	err := SaveState(d.config.TownRoot, state)
+	d.mu.Unlock()
+	if err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
 
 	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
 }
 
+// Patrols lists the heartbeat steps, in the order they run. Any of them
+// can be paused over the control socket (gt daemon pause); deacon,
+// witness and refinery can also be disabled in mayor/daemon.json.
+var Patrols = []string{
+	"deacon",
+	"witness",
+	"refinery",
+	"spawns",
+	"lifecycle",
+	"gupp",
+	"orphaned-work",
+	"polecat-health",
+	"orphan-processes",
+	"scheduled-mail",
+}
+
+// patrolActive reports whether a heartbeat step should run: not paused
+// over the control socket and not disabled in mayor/daemon.json.
+func (d *Daemon) patrolActive(patrol string) bool {
+	d.mu.Lock()
+	paused := d.pausedPatrols[patrol]
+	enabled := IsPatrolEnabled(d.patrolConfig, patrol)
+	d.mu.Unlock()
+
+	if paused {
+		d.logger.Printf("Patrol %s paused, skipping", patrol)
+		return false
+	}
+	if !enabled {
+		d.logger.Printf("Patrol %s disabled in config, skipping", patrol)
+		return false
+	}
+	return true
+}
+
+func (d *Daemon) setHeartbeatRunning(running bool) {
+	d.mu.Lock()
+	d.heartbeatRunning = running
+	d.mu.Unlock()
+}
+
 // ensureDoltServerRunning ensures the Dolt SQL server is running if configured.
 // This provides the backend for beads database access in server mode.
 func (d *Daemon) ensureDoltServerRunning() {
@@ -355,8 +520,9 @@ func (d *Daemon) ensureBootRunning() {
 	}
 
 	// Check for degraded mode
+	// Boot itself runs in tmux, so headless towns triage mechanically too
 	degraded := os.Getenv("GT_DEGRADED") == "true"
-	if degraded || !d.tmux.IsAvailable() {
+	if degraded || d.supervisor != nil || !d.sessions.IsAvailable() {
 		// In degraded mode, run mechanical triage directly
 		d.logger.Println("Degraded mode: running mechanical Boot triage")
 		d.runDegradedBootTriage(b)
@@ -385,7 +551,7 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 	}
 
 	// Simple check: is Deacon session alive?
-	hasDeacon, err := d.tmux.HasSession(d.getDeaconSessionName())
+	hasDeacon, err := d.sessions.HasSession(d.getDeaconSessionName())
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		status.LastAction = "error"
@@ -465,7 +631,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	sessionName := d.getDeaconSessionName()
 
 	// Check if session exists
-	hasSession, err := d.tmux.HasSession(sessionName)
+	hasSession, err := d.sessions.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking Deacon session: %v", err)
 		return
@@ -482,7 +648,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 		// Very stuck - restart the session.
 		// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 		d.logger.Printf("Deacon stuck for %s - restarting session", age.Round(time.Minute))
-		if err := d.tmux.KillSessionWithProcesses(sessionName); err != nil {
+		if err := d.sessions.KillSessionWithProcesses(sessionName); err != nil {
 			d.logger.Printf("Error killing stuck Deacon: %v", err)
 		}
 		// Spawn new Deacon immediately instead of waiting for next heartbeat
@@ -491,7 +657,7 @@ func (d *Daemon) checkDeaconHeartbeat() {
 	} else {
 		// Stuck but not critically - nudge to wake up
 		d.logger.Printf("Deacon stuck for %s - nudging session", age.Round(time.Minute))
-		if err := d.tmux.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
+		if err := d.sessions.NudgeSession(sessionName, "HEALTH_CHECK: heartbeat stale, respond to confirm responsiveness"); err != nil {
 			d.logger.Printf("Error nudging stuck Deacon: %v", err)
 		}
 	}
@@ -705,6 +871,20 @@ func (d *Daemon) triggerPendingSpawns() {
 	}
 }
 
+// releaseScheduledMail delivers scheduled messages whose time has come.
+// Delivery goes through the normal mail routing, so recipients with a live
+// session are notified (or interrupted, for interrupt delivery) as usual.
+func (d *Daemon) releaseScheduledMail() {
+	router := mail.NewRouterWithTownRoot(d.config.TownRoot, d.config.TownRoot)
+	released, err := router.ReleaseDue(time.Now())
+	for _, msg := range released {
+		d.logger.Printf("Released scheduled mail %s to %s: %s", msg.ID, msg.To, msg.Subject)
+	}
+	if err != nil {
+		d.logger.Printf("Error releasing scheduled mail: %v", err)
+	}
+}
+
 // processLifecycleRequests checks for and processes lifecycle requests.
 func (d *Daemon) processLifecycleRequests() {
 	d.ProcessLifecycleRequests()
@@ -714,6 +894,12 @@ func (d *Daemon) processLifecycleRequests() {
 func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return kept for future use
 	d.logger.Println("Daemon shutting down")
 
+	// Stop taking control requests
+	if d.control != nil {
+		d.control.Stop()
+		d.logger.Println("Control socket closed")
+	}
+
 	// Stop feed curator
 	if d.curator != nil {
 		d.curator.Stop()
@@ -741,8 +927,17 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		}
 	}
 
+	// Stop headless sessions; they cannot outlive the daemon
+	if d.supervisor != nil {
+		d.supervisor.Stop()
+		d.logger.Println("Session supervisor stopped")
+	}
+
+	d.mu.Lock()
 	state.Running = false
-	if err := SaveState(d.config.TownRoot, state); err != nil {
+	err := SaveState(d.config.TownRoot, state)
+	d.mu.Unlock()
+	if err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
 	}
 
@@ -994,7 +1189,7 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
 
 	// Check if tmux session exists
-	sessionAlive, err := d.tmux.HasSession(sessionName)
+	sessionAlive, err := d.sessions.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking session %s: %v", sessionName, err)
 		return
@@ -1119,7 +1314,7 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 
 	// Create new tmux session
 	// Use EnsureSessionFresh to handle zombie sessions that exist but have dead Claude
-	if err := d.tmux.EnsureSessionFresh(sessionName, workDir); err != nil {
+	if err := d.sessions.EnsureSessionFresh(sessionName, workDir); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
 
@@ -1134,30 +1329,30 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
 	for k, v := range envVars {
-		_ = d.tmux.SetEnvironment(sessionName, k, v)
+		_ = d.sessions.SetEnvironment(sessionName, k, v)
 	}
 
 	// Apply theme
 	theme := tmux.AssignTheme(rigName)
-	_ = d.tmux.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
+	_ = d.sessions.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
 
 	// Set pane-died hook for future crash detection
 	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
-	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
+	_ = d.sessions.SetPaneDiedHook(sessionName, agentID)
 
 	// Launch Claude with environment exported inline
 	// Pass rigPath so rig agent settings are honored (not town-level defaults)
 	startCmd := config.BuildStartupCommand(envVars, rigPath, "")
-	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
+	if err := d.sessions.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
 
 	// Wait for Claude to start, then accept bypass permissions warning if it appears.
 	// This ensures automated restarts aren't blocked by the warning dialog.
-	if err := d.tmux.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
+	if err := d.sessions.WaitForCommand(sessionName, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
 		// Non-fatal - Claude might still start
 	}
-	_ = d.tmux.AcceptBypassPermissionsWarning(sessionName)
+	_ = d.sessions.AcceptBypassPermissionsWarning(sessionName)
 
 	return nil
 }
@@ -82,8 +82,9 @@ func (p *KRCPruner) prune() {
 	}
 
 	if result.EventsPruned > 0 {
-		p.logger("KRC pruned %d events (saved %d bytes) in %v",
+		p.logger("KRC pruned %d events (%d segments, saved %d bytes) in %v",
 			result.EventsPruned,
+			result.SegmentsPruned,
 			result.BytesBefore-result.BytesAfter,
 			result.Duration.Round(time.Millisecond))
 	}
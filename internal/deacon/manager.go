@@ -52,7 +52,7 @@ func (m *Manager) deaconDir() string {
 // agentOverride allows specifying an alternate agent alias (e.g., for testing).
 // Restarts are handled by daemon via ensureDeaconRunning on each heartbeat.
 func (m *Manager) Start(agentOverride string) error {
-	t := tmux.NewTmux()
+	t := session.NewBackend(m.townRoot)
 	sessionID := m.SessionName()
 
 	// Check if session already exists
@@ -94,7 +94,7 @@ func (m *Manager) Start(agentOverride string) error {
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := t.NewSessionWithCommand(sessionID, deaconDir, startupCmd); err != nil {
-		return fmt.Errorf("creating tmux session: %w", err)
+		return fmt.Errorf("creating session: %w", err)
 	}
 
 	// Set environment variables (non-fatal: session works without these)
@@ -128,7 +128,7 @@ func (m *Manager) Start(agentOverride string) error {
 
 // Stop stops the deacon session.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := session.NewBackend(m.townRoot)
 	sessionID := m.SessionName()
 
 	// Check if session exists
@@ -156,13 +156,13 @@ func (m *Manager) Stop() error {
 
 // IsRunning checks if the deacon session is active.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend(m.townRoot)
 	return t.HasSession(m.SessionName())
 }
 
 // Status returns information about the deacon session.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend(m.townRoot)
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -1,14 +1,14 @@
 // Package events provides event logging for the gt activity feed.
 //
 // Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// curated by the feed daemon into ~/.feed.jsonl (user-facing). Older events
+// are rotated into compressed segments under ~/gt/.events/ (see Each).
 package events
 
 import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"sync"
 	"time"
 
@@ -68,6 +68,9 @@ const (
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Dashboard audit events (changes made through gt dashboard)
+	TypeDashboardRequest = "dashboard_request"
 )
 
 // EventsFile is the name of the raw events log.
@@ -77,7 +80,7 @@ const EventsFile = ".events.jsonl"
 var mutex sync.Mutex
 
 // Log writes an event to the events log.
-// The event is appended to ~/gt/.events.jsonl.
+// The event is appended to ~/gt/.events.jsonl, rotating it first if due.
 // Returns nil if logging fails (events are best-effort).
 func Log(eventType, actor string, payload map[string]interface{}, visibility string) error {
 	event := Event{
@@ -110,7 +113,7 @@ func write(event Event) error {
 		return nil
 	}
 
-	eventsPath := filepath.Join(townRoot, EventsFile)
+	eventsPath := ActivePath(townRoot)
 
 	// Marshal event to JSON
 	data, err := json.Marshal(event)
@@ -119,21 +122,28 @@ func write(event Event) error {
 	}
 	data = append(data, '\n')
 
-	// Append to file with proper locking
+	// Append to file with proper locking; the file lock also keeps other
+	// processes from rotating the file underneath us
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
-	if err != nil {
-		return fmt.Errorf("opening events file: %w", err)
-	}
-	defer f.Close()
-
-	if _, err := f.Write(data); err != nil {
-		return fmt.Errorf("writing event: %w", err)
-	}
-
-	return nil
+	return withLock(townRoot, func() error {
+		if now := time.Now(); rotationDue(eventsPath, now) {
+			// Best effort: a failed rotation must not lose the event
+			_ = rotateLocked(townRoot, now)
+		}
+
+		f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+		if err != nil {
+			return fmt.Errorf("opening events file: %w", err)
+		}
+		defer f.Close()
+
+		if _, err := f.Write(data); err != nil {
+			return fmt.Errorf("writing event: %w", err)
+		}
+		return nil
+	})
 }
 
 // Payload helpers for common event structures.
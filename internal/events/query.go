@@ -0,0 +1,191 @@
+package events
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter selects events from the log. Empty fields match every event.
+type Filter struct {
+	// Types are glob patterns matched against the event type (merge_*).
+	Types []string
+
+	// Actors match the actor exactly, by glob, or as a path prefix, so
+	// "gastown" matches "gastown/witness".
+	Actors []string
+
+	// Rig and Bead match the values Field reports for "rig" and "bead".
+	Rig  string
+	Bead string
+
+	// Since and Until bound the event timestamp: Since <= ts < Until.
+	Since time.Time
+	Until time.Time
+}
+
+// Match reports whether the filter selects an event.
+func (f *Filter) Match(e Event) bool {
+	if len(f.Types) > 0 && !matchAny(f.Types, e.Type, false) {
+		return false
+	}
+	if len(f.Actors) > 0 && !matchAny(f.Actors, e.Actor, true) {
+		return false
+	}
+	if f.Rig != "" && !strings.EqualFold(e.Field("rig"), f.Rig) {
+		return false
+	}
+	if f.Bead != "" && !strings.EqualFold(e.Field("bead"), f.Bead) {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts, ok := eventTime(e)
+		if !ok {
+			return false
+		}
+		if !f.Since.IsZero() && ts.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && !ts.Before(f.Until) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(patterns []string, s string, prefix bool) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, s); ok {
+			return true
+		}
+		if prefix && strings.HasPrefix(s, strings.TrimSuffix(p, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Query returns the events in the town's log, including rotated segments,
+// that the filter selects, oldest first.
+func Query(townRoot string, f Filter) ([]Event, error) {
+	var result []Event
+	err := Each(townRoot, f.Since, func(e Event) bool {
+		if f.Match(e) {
+			result = append(result, e)
+		}
+		return true
+	})
+	return result, err
+}
+
+// Fields are the names Field understands, besides payload.<key>.
+var Fields = []string{"type", "actor", "source", "visibility", "rig", "bead", "day", "hour"}
+
+// ValidField reports whether name is a field Field understands.
+func ValidField(name string) bool {
+	if key, ok := strings.CutPrefix(name, "payload."); ok {
+		return key != ""
+	}
+	for _, f := range Fields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// townRoles are actors that belong to the town rather than a rig.
+var townRoles = map[string]bool{"mayor": true, "deacon": true, "daemon": true, "gt": true}
+
+// Field returns the value of a named field of the event, or "" if the
+// event has none:
+//
+//	type, actor, source, visibility  the event's own fields
+//	rig         payload rig, else the rig of the payload agent or actor
+//	bead        payload bead (or issue)
+//	day, hour   the UTC day (2006-01-02) or hour (2006-01-02T15) of ts
+//	payload.X   payload field X
+func (e Event) Field(name string) string {
+	switch name {
+	case "type":
+		return e.Type
+	case "actor":
+		return e.Actor
+	case "source":
+		return e.Source
+	case "visibility":
+		return e.Visibility
+	case "rig":
+		if rig := e.payloadString("rig"); rig != "" {
+			return rig
+		}
+		for _, addr := range []string{e.payloadString("agent"), e.Actor} {
+			if rig, _, ok := strings.Cut(addr, "/"); ok && rig != "" && !townRoles[rig] {
+				return rig
+			}
+		}
+		return ""
+	case "bead":
+		if bead := e.payloadString("bead"); bead != "" {
+			return bead
+		}
+		return e.payloadString("issue")
+	case "day", "hour":
+		ts, ok := eventTime(e)
+		if !ok {
+			return ""
+		}
+		if name == "day" {
+			return ts.UTC().Format("2006-01-02")
+		}
+		return ts.UTC().Format("2006-01-02T15")
+	}
+	if key, ok := strings.CutPrefix(name, "payload."); ok {
+		if v, ok := e.Payload[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
+
+func (e Event) payloadString(key string) string {
+	s, _ := e.Payload[key].(string)
+	return s
+}
+
+// GroupCount is the number of events sharing the same values for a set of
+// fields. Values are in the order the fields were given.
+type GroupCount struct {
+	Values []string
+	Count  int
+}
+
+// Count groups events by the values of fields and counts each group, most
+// frequent first (ties in value order).
+func Count(evs []Event, fields []string) []GroupCount {
+	index := make(map[string]int)
+	var groups []GroupCount
+	for _, e := range evs {
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			values[i] = e.Field(f)
+		}
+		key := strings.Join(values, "\x00")
+		if i, ok := index[key]; ok {
+			groups[i].Count++
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, GroupCount{Values: values, Count: 1})
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return strings.Join(groups[i].Values, "\x00") < strings.Join(groups[j].Values, "\x00")
+	})
+	return groups
+}
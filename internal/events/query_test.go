@@ -0,0 +1,107 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventField(t *testing.T) {
+	ts := time.Date(2026, 1, 10, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		event Event
+		field string
+		want  string
+	}{
+		{Event{Actor: "gastown/witness"}, "rig", "gastown"},
+		{Event{Actor: "mayor/"}, "rig", ""},
+		{Event{Actor: "mayor"}, "rig", ""},
+		{Event{Actor: "daemon", Payload: map[string]interface{}{"agent": "beads/polecats/Toast"}}, "rig", "beads"},
+		{Event{Actor: "gastown/witness", Payload: map[string]interface{}{"rig": "other"}}, "rig", "other"},
+		{Event{Payload: map[string]interface{}{"bead": "gt-1"}}, "bead", "gt-1"},
+		{Event{Payload: map[string]interface{}{"issue": "gt-2"}}, "bead", "gt-2"},
+		{Event{Timestamp: ts.Format(time.RFC3339)}, "day", "2026-01-10"},
+		{Event{Timestamp: ts.Format(time.RFC3339)}, "hour", "2026-01-10T15"},
+		{Event{Payload: map[string]interface{}{"count": float64(3)}}, "payload.count", "3"},
+		{Event{}, "payload.missing", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.event.Field(tt.field); got != tt.want {
+			t.Errorf("%+v.Field(%q) = %q, want %q", tt.event, tt.field, got, tt.want)
+		}
+	}
+
+	for _, f := range []string{"rig", "day", "payload.reason"} {
+		if !ValidField(f) {
+			t.Errorf("ValidField(%q) = false", f)
+		}
+	}
+	for _, f := range []string{"payload.", "colour", ""} {
+		if ValidField(f) {
+			t.Errorf("ValidField(%q) = true", f)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	e := eventAt(now, TypeMergeFailed, "gastown/refinery")
+	e.Payload = map[string]interface{}{"bead": "gt-7"}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty", Filter{}, true},
+		{"type glob", Filter{Types: []string{"merge_*"}}, true},
+		{"other type", Filter{Types: []string{"sling", "done"}}, false},
+		{"actor prefix", Filter{Actors: []string{"gastown"}}, true},
+		{"actor glob", Filter{Actors: []string{"*/refinery"}}, true},
+		{"actor partial name", Filter{Actors: []string{"gas"}}, false},
+		{"rig", Filter{Rig: "GASTOWN"}, true},
+		{"other rig", Filter{Rig: "beads"}, false},
+		{"bead", Filter{Bead: "gt-7"}, true},
+		{"in range", Filter{Since: now.Add(-time.Hour), Until: now.Add(time.Hour)}, true},
+		{"until is exclusive", Filter{Until: now}, false},
+		{"since is inclusive", Filter{Since: now}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(e); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestQueryAndCount(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().UTC()
+
+	// One event has already been rotated into a segment
+	appendEvents(t, townRoot, eventAt(now.Add(-72*time.Hour), TypeSessionDeath, "gastown/polecats/Toast"))
+	if err := MaybeRotate(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	appendEvents(t, townRoot,
+		eventAt(now.Add(-2*time.Hour), TypeSessionDeath, "gastown/polecats/Nux"),
+		eventAt(now.Add(-time.Hour), TypeSessionDeath, "beads/polecats/Max"),
+		eventAt(now.Add(-time.Hour), TypeSling, "mayor"),
+	)
+
+	evs, err := Query(townRoot, Filter{Types: []string{TypeSessionDeath}, Since: now.Add(-7 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actors(evs) != "gastown/polecats/Toast,gastown/polecats/Nux,beads/polecats/Max" {
+		t.Errorf("Query = %s, want deaths from the segment and the active file", actors(evs))
+	}
+
+	var got []string
+	for _, g := range Count(evs, []string{"rig"}) {
+		got = append(got, fmt.Sprintf("%s=%d", strings.Join(g.Values, "/"), g.Count))
+	}
+	if strings.Join(got, ",") != "gastown=2,beads=1" {
+		t.Errorf("Count by rig = %v, want gastown=2,beads=1", got)
+	}
+}
@@ -0,0 +1,538 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// The event log is stored as segments. Writers only ever append to the
+// active file, ~/gt/.events.jsonl. When it grows past RotateBytes, or the
+// first write of a new (UTC) day finds it holding an earlier day's events,
+// it is rotated: its events move to gzip-compressed segments under
+// ~/gt/.events/, one or more per day, listed in ~/gt/.events/index.json.
+// Readers iterate the segments and then the active file; KRC prunes by
+// dropping whole segments.
+
+// SegmentsDir is the directory, under the town root, holding rotated segments.
+const SegmentsDir = ".events"
+
+const (
+	segmentIndexFile    = "index.json"
+	segmentLockFile     = ".lock"
+	segmentIndexVersion = 1
+	segmentPrefix       = "events-"
+	segmentSuffix       = ".jsonl.gz"
+)
+
+// RotateBytes is the size at which the active events file is rotated.
+var RotateBytes int64 = 16 << 20
+
+// Segment describes one rotated, compressed segment of the event log.
+type Segment struct {
+	File  string         `json:"file"`  // file name under SegmentsDir
+	First time.Time      `json:"first"` // earliest event timestamp
+	Last  time.Time      `json:"last"`  // latest event timestamp
+	Count int            `json:"count"`
+	Bytes int64          `json:"bytes"` // compressed size on disk
+	Types map[string]int `json:"types"` // event count by type
+}
+
+// SegmentIndex lists the rotated segments, oldest first.
+type SegmentIndex struct {
+	Version  int       `json:"version"`
+	Segments []Segment `json:"segments"`
+}
+
+// ActivePath returns the path of the active events file.
+func ActivePath(townRoot string) string {
+	return filepath.Join(townRoot, EventsFile)
+}
+
+// SegmentPath returns the path of a rotated segment file.
+func SegmentPath(townRoot, file string) string {
+	return filepath.Join(townRoot, SegmentsDir, file)
+}
+
+func segmentIndexPath(townRoot string) string {
+	return filepath.Join(townRoot, SegmentsDir, segmentIndexFile)
+}
+
+// LoadSegmentIndex loads the segment index. A missing or unreadable index
+// is rebuilt from the segment files on disk.
+func LoadSegmentIndex(townRoot string) (*SegmentIndex, error) {
+	data, err := os.ReadFile(segmentIndexPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err == nil {
+		var ix SegmentIndex
+		if json.Unmarshal(data, &ix) == nil && ix.Version == segmentIndexVersion {
+			return &ix, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading segment index: %w", err)
+	}
+	return rebuildSegmentIndex(townRoot)
+}
+
+// rebuildSegmentIndex scans the segment files to recreate the index.
+func rebuildSegmentIndex(townRoot string) (*SegmentIndex, error) {
+	ix := &SegmentIndex{Version: segmentIndexVersion}
+	matches, err := filepath.Glob(SegmentPath(townRoot, segmentPrefix+"*"+segmentSuffix))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range matches {
+		seg := Segment{File: filepath.Base(path), Types: make(map[string]int)}
+		if info, err := os.Stat(path); err == nil {
+			seg.Bytes = info.Size()
+		}
+		if _, err := eachInFile(path, time.Time{}, func(e Event) bool {
+			seg.add(e)
+			return true
+		}); err != nil {
+			continue
+		}
+		ix.Segments = append(ix.Segments, seg)
+	}
+	ix.sort()
+	return ix, nil
+}
+
+func (ix *SegmentIndex) sort() {
+	sort.SliceStable(ix.Segments, func(i, j int) bool {
+		return ix.Segments[i].First.Before(ix.Segments[j].First)
+	})
+}
+
+// add records an event in the segment's summary.
+func (s *Segment) add(e Event) {
+	s.Count++
+	s.Types[e.Type]++
+	if ts, ok := eventTime(e); ok {
+		if s.First.IsZero() || ts.Before(s.First) {
+			s.First = ts
+		}
+		if ts.After(s.Last) {
+			s.Last = ts
+		}
+	}
+}
+
+// withLock runs fn holding the town's event log lock, which serializes
+// appends, rotation and pruning across processes.
+func withLock(townRoot string, fn func() error) error {
+	if err := os.MkdirAll(filepath.Join(townRoot, SegmentsDir), 0755); err != nil {
+		return fmt.Errorf("creating segments dir: %w", err)
+	}
+	lock := flock.New(filepath.Join(townRoot, SegmentsDir, segmentLockFile))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking event log: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+	return fn()
+}
+
+// MaybeRotate rotates the active events file if it is due: if it has
+// reached RotateBytes or holds events from an earlier day.
+func MaybeRotate(townRoot string) error {
+	return withLock(townRoot, func() error {
+		now := time.Now()
+		if !rotationDue(ActivePath(townRoot), now) {
+			return nil
+		}
+		return rotateLocked(townRoot, now)
+	})
+}
+
+// rotationDue reports whether the active file should be rotated.
+func rotationDue(path string, now time.Time) bool {
+	f, err := os.Open(path) //nolint:gosec // G304: path is the town events file
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	if info.Size() >= RotateBytes {
+		return true
+	}
+
+	// The first event is the oldest; rotate once the day has turned
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+	day, ok := lineDay(line)
+	return ok && day < dayOf(now)
+}
+
+// rotateLocked moves the active file's events into compressed segments,
+// one per day. Unless the file has reached RotateBytes, today's events
+// stay in the active file. The caller holds the event log lock.
+func rotateLocked(townRoot string, now time.Time) error {
+	path := ActivePath(townRoot)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the town events file
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading events file: %w", err)
+	}
+
+	today := dayOf(now)
+	keepToday := int64(len(data)) < RotateBytes
+
+	// Group lines by day; a line without a timestamp goes with the one before
+	var keep [][]byte
+	var days []string
+	groups := make(map[string][][]byte)
+	lastDay := today
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if day, ok := lineDay(line); ok {
+			lastDay = day
+		}
+		if keepToday && lastDay >= today {
+			keep = append(keep, line)
+			continue
+		}
+		if _, ok := groups[lastDay]; !ok {
+			days = append(days, lastDay)
+		}
+		groups[lastDay] = append(groups[lastDay], line)
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	ix, err := LoadSegmentIndex(townRoot)
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		seg, err := writeSegment(townRoot, groups[day])
+		if err != nil {
+			return err
+		}
+		ix.Segments = append(ix.Segments, *seg)
+	}
+	ix.sort()
+	if err := util.AtomicWriteJSON(segmentIndexPath(townRoot), ix); err != nil {
+		return fmt.Errorf("writing segment index: %w", err)
+	}
+
+	if len(keep) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing rotated events file: %w", err)
+		}
+		return nil
+	}
+	rest := append(bytes.Join(keep, []byte("\n")), '\n')
+	if err := util.AtomicWriteFile(path, rest, 0644); err != nil {
+		return fmt.Errorf("rewriting events file: %w", err)
+	}
+	return nil
+}
+
+// writeSegment compresses lines into a new segment file.
+func writeSegment(townRoot string, lines [][]byte) (*Segment, error) {
+	seg := &Segment{Types: make(map[string]int)}
+	for _, line := range lines {
+		var e Event
+		if json.Unmarshal(line, &e) == nil {
+			seg.add(e)
+		}
+	}
+
+	stamp := seg.First
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	base := segmentPrefix + stamp.UTC().Format("20060102T150405Z")
+	seg.File = base + segmentSuffix
+	for n := 1; ; n++ {
+		if _, err := os.Stat(SegmentPath(townRoot, seg.File)); os.IsNotExist(err) {
+			break
+		}
+		seg.File = fmt.Sprintf("%s-%d%s", base, n, segmentSuffix)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, line := range lines {
+		_, _ = zw.Write(line)
+		_, _ = zw.Write([]byte("\n"))
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("compressing segment: %w", err)
+	}
+	if err := util.AtomicWriteFile(SegmentPath(townRoot, seg.File), buf.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("writing segment %s: %w", seg.File, err)
+	}
+	seg.Bytes = int64(buf.Len())
+	return seg, nil
+}
+
+// DropSegments deletes every segment for which drop returns true and
+// removes it from the index, returning the dropped segments.
+func DropSegments(townRoot string, drop func(Segment) bool) ([]Segment, error) {
+	var dropped []Segment
+	err := withLock(townRoot, func() error {
+		ix, err := LoadSegmentIndex(townRoot)
+		if err != nil {
+			return err
+		}
+		kept := ix.Segments[:0]
+		for _, seg := range ix.Segments {
+			if !drop(seg) {
+				kept = append(kept, seg)
+				continue
+			}
+			if err := os.Remove(SegmentPath(townRoot, seg.File)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("removing segment %s: %w", seg.File, err)
+			}
+			dropped = append(dropped, seg)
+		}
+		if len(dropped) == 0 {
+			return nil
+		}
+		ix.Segments = kept
+		if err := util.AtomicWriteJSON(segmentIndexPath(townRoot), ix); err != nil {
+			return fmt.Errorf("writing segment index: %w", err)
+		}
+		return nil
+	})
+	return dropped, err
+}
+
+// Each calls fn for every event at or after since, oldest first, reading
+// the rotated segments and then the active file. A zero since reads the
+// whole log. Iteration stops early if fn returns false. Malformed lines
+// are skipped.
+func Each(townRoot string, since time.Time, fn func(Event) bool) error {
+	ix, err := LoadSegmentIndex(townRoot)
+	if err != nil {
+		return err
+	}
+	for _, seg := range ix.Segments {
+		if !since.IsZero() && seg.Last.Before(since) {
+			continue
+		}
+		more, err := eachInFile(SegmentPath(townRoot, seg.File), since, fn)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+	_, err = eachInFile(ActivePath(townRoot), since, fn)
+	return err
+}
+
+// Tail returns the last n events in the log, oldest first.
+func Tail(townRoot string, n int) ([]Event, error) {
+	result, err := ReadFile(ActivePath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	if len(result) < n {
+		ix, err := LoadSegmentIndex(townRoot)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(ix.Segments) - 1; i >= 0 && len(result) < n; i-- {
+			older, err := ReadFile(SegmentPath(townRoot, ix.Segments[i].File))
+			if err != nil {
+				return nil, err
+			}
+			result = append(older, result...)
+		}
+	}
+	if len(result) > n {
+		result = result[len(result)-n:]
+	}
+	return result, nil
+}
+
+// ReadFile reads every event in an events file or compressed segment,
+// oldest first. Malformed lines are skipped; a missing file has no events.
+func ReadFile(path string) ([]Event, error) {
+	var result []Event
+	_, err := eachInFile(path, time.Time{}, func(e Event) bool {
+		result = append(result, e)
+		return true
+	})
+	return result, err
+}
+
+// eachInFile calls fn for each event at or after since in one file,
+// reporting false if fn stopped the iteration. A missing file is empty.
+func eachInFile(path string, since time.Time, fn func(Event) bool) (bool, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is an events file or segment
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return false, fmt.Errorf("reading %s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if !since.IsZero() {
+			if ts, ok := eventTime(event); !ok || ts.Before(since) {
+				continue
+			}
+		}
+		if !fn(event) {
+			return false, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// eventTime parses an event's timestamp.
+func eventTime(e Event) (time.Time, bool) {
+	ts, err := time.Parse(time.RFC3339, e.Timestamp)
+	return ts, err == nil
+}
+
+// lineDay returns the UTC day (2006-01-02) of a raw event line.
+func lineDay(line []byte) (string, bool) {
+	var e struct {
+		Timestamp string `json:"ts"`
+	}
+	if json.Unmarshal(line, &e) != nil {
+		return "", false
+	}
+	ts, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		return "", false
+	}
+	return dayOf(ts), true
+}
+
+func dayOf(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// Tailer follows the active events file as it is appended to and rotated.
+// It starts at the current end of the file; when the file is rotated away
+// it finishes reading the old file and continues from the start of the new.
+type Tailer struct {
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	partial string // an incomplete trailing line, held until its newline arrives
+}
+
+// NewTailer starts tailing the town's active events file. The file need
+// not exist yet.
+func NewTailer(townRoot string) *Tailer {
+	t := &Tailer{path: ActivePath(townRoot)}
+	if t.open() {
+		_, _ = t.file.Seek(0, io.SeekEnd)
+		t.reader.Reset(t.file)
+	}
+	return t
+}
+
+func (t *Tailer) open() bool {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return false
+	}
+	t.file = f
+	t.reader = bufio.NewReader(f)
+	t.partial = ""
+	return true
+}
+
+// Lines returns the complete lines appended since the last call.
+func (t *Tailer) Lines() []string {
+	if t.file == nil && !t.open() {
+		return nil
+	}
+	lines := t.drain()
+	if t.replaced() {
+		// Anything appended before the rotation finished is in the old file
+		lines = append(lines, t.drain()...)
+		_ = t.file.Close()
+		t.file = nil
+		if t.open() {
+			lines = append(lines, t.drain()...)
+		}
+	}
+	return lines
+}
+
+// drain reads every complete line currently available.
+func (t *Tailer) drain() []string {
+	var lines []string
+	for {
+		chunk, err := t.reader.ReadString('\n')
+		if err != nil {
+			t.partial += chunk
+			return lines
+		}
+		lines = append(lines, t.partial+chunk)
+		t.partial = ""
+	}
+}
+
+// replaced reports whether the path no longer names the open file.
+func (t *Tailer) replaced() bool {
+	open, err := t.file.Stat()
+	if err != nil {
+		return true
+	}
+	current, err := os.Stat(t.path)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	return !os.SameFile(open, current)
+}
+
+// Close stops tailing.
+func (t *Tailer) Close() error {
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
@@ -0,0 +1,223 @@
+package events
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// appendEvents appends events to the town's active file.
+func appendEvents(t *testing.T, townRoot string, evs ...Event) {
+	t.Helper()
+	f, err := os.OpenFile(ActivePath(townRoot), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for _, e := range evs {
+		data, _ := json.Marshal(e)
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func eventAt(ts time.Time, typ, actor string) Event {
+	return Event{Timestamp: ts.UTC().Format(time.RFC3339), Type: typ, Actor: actor, Visibility: VisibilityFeed}
+}
+
+func actors(evs []Event) string {
+	var names []string
+	for _, e := range evs {
+		names = append(names, e.Actor)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestRotateSplitsByDayAndKeepsToday(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	appendEvents(t, townRoot,
+		eventAt(now.Add(-48*time.Hour), TypeSling, "a"),
+		eventAt(now.Add(-47*time.Hour), TypeDone, "b"),
+		eventAt(now.Add(-24*time.Hour), TypeMail, "c"),
+		eventAt(now.Add(-time.Hour), TypeSling, "d"),
+	)
+
+	if !rotationDue(ActivePath(townRoot), now) {
+		t.Fatal("file holding earlier days should be due for rotation")
+	}
+	if err := withLock(townRoot, func() error { return rotateLocked(townRoot, now) }); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	ix, err := LoadSegmentIndex(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ix.Segments) != 2 {
+		t.Fatalf("got %d segments, want one per earlier day: %+v", len(ix.Segments), ix.Segments)
+	}
+	first := ix.Segments[0]
+	if first.Count != 2 || first.Types[TypeSling] != 1 || first.Types[TypeDone] != 1 {
+		t.Errorf("first segment = %+v, want sling and done", first)
+	}
+	if !first.First.Equal(now.Add(-48*time.Hour)) || !first.Last.Equal(now.Add(-47*time.Hour)) {
+		t.Errorf("first segment spans %v..%v", first.First, first.Last)
+	}
+	if !strings.HasSuffix(first.File, segmentSuffix) {
+		t.Errorf("segment file %q should be compressed", first.File)
+	}
+
+	active, err := ReadFile(ActivePath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actors(active) != "d" {
+		t.Errorf("active file = %s, want only today's event", actors(active))
+	}
+	if rotationDue(ActivePath(townRoot), now) {
+		t.Error("active file should not be due again the same day")
+	}
+
+	var all []Event
+	if err := Each(townRoot, time.Time{}, func(e Event) bool { all = append(all, e); return true }); err != nil {
+		t.Fatal(err)
+	}
+	if actors(all) != "a,b,c,d" {
+		t.Errorf("Each = %s, want a,b,c,d across segments", actors(all))
+	}
+
+	var recent []Event
+	if err := Each(townRoot, now.Add(-25*time.Hour), func(e Event) bool { recent = append(recent, e); return true }); err != nil {
+		t.Fatal(err)
+	}
+	if actors(recent) != "c,d" {
+		t.Errorf("Each since = %s, want c,d", actors(recent))
+	}
+
+	tail, err := Tail(townRoot, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actors(tail) != "b,c,d" {
+		t.Errorf("Tail(3) = %s, want b,c,d", actors(tail))
+	}
+}
+
+func TestRotateBySize(t *testing.T) {
+	townRoot := t.TempDir()
+	old := RotateBytes
+	RotateBytes = 100
+	defer func() { RotateBytes = old }()
+
+	now := time.Now()
+	appendEvents(t, townRoot, eventAt(now, TypeSling, "a"), eventAt(now, TypeSling, "b"))
+	if err := MaybeRotate(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(ActivePath(townRoot)); !os.IsNotExist(err) {
+		t.Errorf("oversized active file should be rotated away entirely, stat err = %v", err)
+	}
+
+	appendEvents(t, townRoot, eventAt(now, TypeSling, "c"), eventAt(now, TypeSling, "d"))
+	if err := MaybeRotate(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	ix, _ := LoadSegmentIndex(townRoot)
+	if len(ix.Segments) != 2 || ix.Segments[0].File == ix.Segments[1].File {
+		t.Fatalf("segments = %+v, want two distinct files", ix.Segments)
+	}
+}
+
+func TestDropSegmentsAndRebuildIndex(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	appendEvents(t, townRoot,
+		eventAt(now.Add(-72*time.Hour), TypeSling, "a"),
+		eventAt(now.Add(-48*time.Hour), TypeSling, "b"),
+	)
+	if err := withLock(townRoot, func() error { return rotateLocked(townRoot, now) }); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lost index is rebuilt from the segment files
+	if err := os.Remove(segmentIndexPath(townRoot)); err != nil {
+		t.Fatal(err)
+	}
+	ix, err := LoadSegmentIndex(townRoot)
+	if err != nil || len(ix.Segments) != 2 || ix.Segments[0].Count != 1 {
+		t.Fatalf("rebuilt index = %+v, %v", ix, err)
+	}
+
+	dropped, err := DropSegments(townRoot, func(s Segment) bool { return s.Last.Before(now.Add(-60 * time.Hour)) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dropped) != 1 {
+		t.Fatalf("dropped %d segments, want 1", len(dropped))
+	}
+	if _, err := os.Stat(SegmentPath(townRoot, dropped[0].File)); !os.IsNotExist(err) {
+		t.Error("dropped segment file should be removed")
+	}
+	all, _ := Tail(townRoot, 10)
+	if actors(all) != "b" {
+		t.Errorf("after drop = %s, want b", actors(all))
+	}
+}
+
+func TestTailerFollowsRotation(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	appendEvents(t, townRoot, eventAt(now, TypeSling, "before"))
+
+	tail := NewTailer(townRoot)
+	defer tail.Close()
+	if lines := tail.Lines(); len(lines) != 0 {
+		t.Fatalf("tailer should start at the end, got %v", lines)
+	}
+
+	appendEvents(t, townRoot, eventAt(now, TypeSling, "a"))
+
+	// Rotate the whole file away, as a size rotation would
+	old := RotateBytes
+	RotateBytes = 1
+	if err := MaybeRotate(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	RotateBytes = old
+	appendEvents(t, townRoot, eventAt(now, TypeSling, "b"))
+
+	var got []string
+	for _, line := range tail.Lines() {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("bad line %q: %v", line, err)
+		}
+		got = append(got, e.Actor)
+	}
+	if strings.Join(got, ",") != "a,b" {
+		t.Errorf("tailer read %v, want a then b across the rotation", got)
+	}
+}
+
+func TestTailerWaitsForFile(t *testing.T) {
+	townRoot := t.TempDir()
+	tail := NewTailer(townRoot)
+	defer tail.Close()
+
+	if lines := tail.Lines(); lines != nil {
+		t.Fatalf("no file yet, got %v", lines)
+	}
+	appendEvents(t, townRoot, eventAt(time.Now(), TypeSling, "a"))
+	if lines := tail.Lines(); len(lines) != 1 {
+		t.Errorf("new file should be read from the start, got %v", lines)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, SegmentsDir)); !os.IsNotExist(err) {
+		t.Error("tailing should not create the segments dir")
+	}
+}
@@ -1,25 +1,25 @@
 // Package feed provides the feed daemon that curates raw events into a user-facing feed.
 //
 // The curator:
-// 1. Tails ~/gt/.events.jsonl (raw events)
-// 2. Filters by visibility tag (drops audit-only events)
-// 3. Deduplicates repeated updates (5 molecule updates → "agent active")
-// 4. Aggregates related events (3 issues closed → "batch complete")
-// 5. Writes curated events to ~/gt/.feed.jsonl
+//  1. Tails ~/gt/.events.jsonl (raw events)
+//  2. Filters by visibility tag (drops audit-only events)
+//  3. Applies the curation rules in ~/gt/settings/feed-rules.json: drops,
+//     deduplicates (5 done signals → 1) and aggregates (3 slings → "dispatching
+//     work to 3 agents") events, and sets summaries and severities
+//  4. Writes curated events to ~/gt/.feed.jsonl
 package feed
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 )
 
@@ -33,6 +33,7 @@ type FeedEvent struct {
 	Type      string                 `json:"type"`
 	Actor     string                 `json:"actor"`
 	Summary   string                 `json:"summary"`
+	Severity  string                 `json:"severity,omitempty"` // Set by feed rules; empty means info
 	Payload   map[string]interface{} `json:"payload,omitempty"`
 	Count     int                    `json:"count,omitempty"` // For aggregated events
 }
@@ -44,51 +45,59 @@ type Curator struct {
 	ctx      context.Context
 	cancel   context.CancelFunc
 	wg       sync.WaitGroup
-}
-
-// Deduplication/aggregation settings
-const (
-	// Dedupe window for repeated done events from same actor
-	doneDedupeWindow = 10 * time.Second
-
-	// Aggregation window for sling events
-	slingAggregateWindow = 30 * time.Second
 
-	// Mail aggregation window
-	mailAggregateWindow = 30 * time.Second
-
-	// Minimum events to trigger aggregation
-	minAggregateCount = 3
-)
+	// rules are reloaded when settings/feed-rules.json changes
+	rules    *Rules
+	rulesMod time.Time
+	logf     func(format string, args ...interface{})
+}
 
 // NewCurator creates a new feed curator.
 func NewCurator(townRoot string) *Curator {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Curator{
+	c := &Curator{
 		townRoot: townRoot,
 		ctx:      ctx,
 		cancel:   cancel,
+		rules:    DefaultRules(),
+		logf:     func(string, ...interface{}) {},
 	}
+	c.reloadRules()
+	return c
 }
 
-// Start begins the curator goroutine.
-func (c *Curator) Start() error {
-	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
+// SetLogger sets where the curator reports problems, such as an invalid
+// rules file. By default they are discarded.
+func (c *Curator) SetLogger(logf func(format string, args ...interface{})) {
+	c.logf = logf
+}
 
-	// Open events file, creating if needed
-	file, err := os.OpenFile(eventsPath, os.O_RDONLY|os.O_CREATE, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
-	if err != nil {
-		return fmt.Errorf("opening events file: %w", err)
+// reloadRules loads the rules file if it changed since the last load. An
+// invalid file is reported and the previous rules stay in effect.
+func (c *Curator) reloadRules() {
+	path := config.FeedRulesConfigPath(c.townRoot)
+	var modTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		modTime = info.ModTime()
+	}
+	if modTime.Equal(c.rulesMod) {
+		return
 	}
+	c.rulesMod = modTime
 
-	// Seek to end to only process new events
-	if _, err := file.Seek(0, io.SeekEnd); err != nil {
-		_ = file.Close() //nolint:gosec // G104: best effort cleanup on error
-		return fmt.Errorf("seeking to end: %w", err)
+	rules, err := LoadRules(c.townRoot)
+	if err != nil {
+		c.logf("feed: keeping previous rules: %v", err)
+		return
 	}
+	c.rules = rules
+}
 
+// Start begins the curator goroutine.
+func (c *Curator) Start() error {
+	// Tail from the current end to only process new events
 	c.wg.Add(1)
-	go c.run(file)
+	go c.run(events.NewTailer(c.townRoot))
 
 	return nil
 }
@@ -101,11 +110,10 @@ func (c *Curator) Stop() {
 
 // run is the main curator loop.
 // ZFC: No in-memory state to clean up - state is derived from the events file.
-func (c *Curator) run(file *os.File) {
+func (c *Curator) run(tail *events.Tailer) {
 	defer c.wg.Done()
-	defer file.Close()
+	defer tail.Close()
 
-	reader := bufio.NewReader(file)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -115,12 +123,8 @@ func (c *Curator) run(file *os.File) {
 			return
 
 		case <-ticker.C:
-			// Read available lines
-			for {
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					break // No more data available
-				}
+			// Read available lines, following the file across rotations
+			for _, line := range tail.Lines() {
 				c.processLine(line)
 			}
 		}
@@ -138,44 +142,21 @@ func (c *Curator) processLine(line string) {
 		return // Skip malformed lines
 	}
 
-	// Filter by visibility - only process feed-visible events
-	if rawEvent.Visibility != events.VisibilityFeed && rawEvent.Visibility != events.VisibilityBoth {
-		return
-	}
-
-	// Apply deduplication and aggregation
-	if c.shouldDedupe(&rawEvent) {
+	// Filter, dedupe and aggregate according to the rules
+	c.reloadRules()
+	decision := c.rules.curate(&rawEvent, c, time.Now())
+	if decision.Feed == nil {
 		return
 	}
 
 	// Write to feed
-	c.writeFeedEvent(&rawEvent)
+	c.writeFeedEvent(decision.Feed)
 }
 
-// shouldDedupe checks if an event should be deduplicated.
-// ZFC: Derives state from the FEED file (what we've already output), not in-memory cache.
-// Returns true if the event should be dropped.
-func (c *Curator) shouldDedupe(event *events.Event) bool {
-	switch event.Type {
-	case events.TypeDone:
-		// Dedupe repeated done events from same actor within window
-		// Check if we've already written a done event for this actor to the feed
-		recentFeedEvents := c.readRecentFeedEvents(doneDedupeWindow)
-		for _, e := range recentFeedEvents {
-			if e.Type == events.TypeDone && e.Actor == event.Actor {
-				return true // Skip duplicate (already in feed)
-			}
-		}
-		return false
-	}
-
-	// Sling and mail events are not deduplicated, only aggregated in writeFeedEvent
-	return false
-}
-
-// readRecentFeedEvents reads feed events from the feed file within the given time window.
+// recentFeed reads feed events from the feed file within the given time
+// window, most recent first. It implements history for dedupe.
 // ZFC: The feed file is the observable state of what we've already output.
-func (c *Curator) readRecentFeedEvents(window time.Duration) []FeedEvent {
+func (c *Curator) recentFeed(window time.Duration, now time.Time) []FeedEvent {
 	feedPath := filepath.Join(c.townRoot, FeedFile)
 
 	data, err := os.ReadFile(feedPath)
@@ -183,7 +164,6 @@ func (c *Curator) readRecentFeedEvents(window time.Duration) []FeedEvent {
 		return nil
 	}
 
-	now := time.Now()
 	cutoff := now.Add(-window)
 	var result []FeedEvent
 
@@ -217,86 +197,24 @@ func (c *Curator) readRecentFeedEvents(window time.Duration) []FeedEvent {
 	return result
 }
 
-// readRecentEvents reads events from the events file within the given time window.
+// recentEvents reads events from the event log within the given time
+// window, most recent first. It implements history for aggregation.
 // ZFC: This is the observable state that replaces in-memory caching.
-// Uses tail-like reading for performance (reads last N lines).
-func (c *Curator) readRecentEvents(window time.Duration) []events.Event {
-	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
-
-	// Read the file (for small files, this is fine; for large files, consider tail-like reading)
-	data, err := os.ReadFile(eventsPath)
-	if err != nil {
-		return nil
-	}
-
-	now := time.Now()
-	cutoff := now.Add(-window)
+// Rotated segments older than the window are skipped via the segment index.
+func (c *Curator) recentEvents(window time.Duration, now time.Time) []events.Event {
 	var result []events.Event
-
-	// Parse lines from the end (most recent first) for efficiency
-	lines := strings.Split(string(data), "\n")
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		var event events.Event
-		if err := json.Unmarshal([]byte(line), &event); err != nil {
-			continue
-		}
-
-		// Parse timestamp
-		ts, err := time.Parse(time.RFC3339, event.Timestamp)
-		if err != nil {
-			continue
-		}
-
-		// Stop if we've gone past the window
-		if ts.Before(cutoff) {
-			break
-		}
-
-		result = append(result, event)
+	_ = events.Each(c.townRoot, now.Add(-window), func(e events.Event) bool {
+		result = append(result, e)
+		return true
+	})
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
 	}
-
 	return result
 }
 
-// countRecentSlings counts sling events from an actor within the given window.
-// ZFC: Derives count from the events file, not in-memory cache.
-func (c *Curator) countRecentSlings(actor string, window time.Duration) int {
-	recentEvents := c.readRecentEvents(window)
-	count := 0
-	for _, e := range recentEvents {
-		if e.Type == events.TypeSling && e.Actor == actor {
-			count++
-		}
-	}
-	return count
-}
-
-// writeFeedEvent writes a curated event to the feed file.
-// ZFC: Aggregation is derived from the events file, not in-memory cache.
-func (c *Curator) writeFeedEvent(event *events.Event) {
-	feedEvent := FeedEvent{
-		Timestamp: event.Timestamp,
-		Source:    event.Source,
-		Type:      event.Type,
-		Actor:     event.Actor,
-		Summary:   c.generateSummary(event),
-		Payload:   event.Payload,
-	}
-
-	// Check for aggregation opportunity (ZFC: derive from events file)
-	if event.Type == events.TypeSling {
-		slingCount := c.countRecentSlings(event.Actor, slingAggregateWindow)
-		if slingCount >= minAggregateCount {
-			feedEvent.Count = slingCount
-			feedEvent.Summary = fmt.Sprintf("%s dispatching work to %d agents", event.Actor, slingCount)
-		}
-	}
-
+// writeFeedEvent appends a curated event to the feed file.
+func (c *Curator) writeFeedEvent(feedEvent *FeedEvent) {
 	data, err := json.Marshal(feedEvent)
 	if err != nil {
 		return
@@ -313,8 +231,9 @@ func (c *Curator) writeFeedEvent(event *events.Event) {
 	_, _ = f.Write(data)
 }
 
-// generateSummary creates a human-readable summary of an event.
-func (c *Curator) generateSummary(event *events.Event) string {
+// generateSummary creates the built-in human-readable summary of an event.
+// Feed rules can replace it with a summary template.
+func generateSummary(event *events.Event) string {
 	switch event.Type {
 	case events.TypeSling:
 		if target, ok := event.Payload["target"].(string); ok {
@@ -153,11 +153,6 @@ func TestCurator_DedupesDoneEvents(t *testing.T) {
 }
 
 func TestCurator_GeneratesSummary(t *testing.T) {
-	tmpDir, _ := os.MkdirTemp("", "feed-test-*")
-	defer os.RemoveAll(tmpDir)
-
-	curator := NewCurator(tmpDir)
-
 	tests := []struct {
 		event    *events.Event
 		expected string
@@ -188,7 +183,7 @@ func TestCurator_GeneratesSummary(t *testing.T) {
 	}
 
 	for _, tc := range tests {
-		summary := curator.generateSummary(tc.event)
+		summary := generateSummary(tc.event)
 		if summary != tc.expected {
 			t.Errorf("generateSummary(%s): expected %q, got %q", tc.event.Type, tc.expected, summary)
 		}
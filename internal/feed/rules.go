@@ -0,0 +1,349 @@
+package feed
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// defaultRuleKey groups events by actor when a rule sets no key.
+const defaultRuleKey = "{{.Actor}}"
+
+// defaultMinAggregateCount is how many events trigger an aggregate summary
+// when a rule does not say.
+const defaultMinAggregateCount = 3
+
+// Rules is a compiled set of feed curation rules (settings/feed-rules.json).
+type Rules struct {
+	rules []*rule
+}
+
+// rule is a compiled config.FeedRule.
+type rule struct {
+	name     string
+	match    config.FeedRuleMatch
+	drop     bool
+	severity string
+	summary  *template.Template
+
+	dedupeKey    *template.Template
+	dedupeWindow time.Duration
+
+	aggKey     *template.Template
+	aggWindow  time.Duration
+	aggMin     int
+	aggSummary *template.Template
+}
+
+// ruleData is what rule templates see.
+type ruleData struct {
+	Type    string
+	Actor   string
+	Source  string
+	Payload map[string]interface{}
+	Summary string
+	Count   int
+}
+
+// Decision is what the curator did with one raw event.
+type Decision struct {
+	// Event is the raw event.
+	Event events.Event `json:"event"`
+
+	// Rule is the name of the rule that matched, if any.
+	Rule string `json:"rule,omitempty"`
+
+	// Feed is the curated event, or nil if the event was dropped.
+	Feed *FeedEvent `json:"feed,omitempty"`
+
+	// Reason says why the event was dropped: audit-only, dropped or duplicate.
+	Reason string `json:"reason,omitempty"`
+}
+
+// history is the curator's view of what it has seen and written, used for
+// dedupe and aggregation windows ending at now.
+type history interface {
+	recentFeed(window time.Duration, now time.Time) []FeedEvent
+	recentEvents(window time.Duration, now time.Time) []events.Event
+}
+
+// LoadRules loads and compiles the town's feed rules, falling back to the
+// built-in rules if settings/feed-rules.json does not exist.
+func LoadRules(townRoot string) (*Rules, error) {
+	cfg, err := config.LoadOrCreateFeedRulesConfig(config.FeedRulesConfigPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	return CompileRules(cfg)
+}
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() *Rules {
+	rules, err := CompileRules(config.NewFeedRulesConfig())
+	if err != nil {
+		panic(fmt.Sprintf("compiling built-in feed rules: %v", err))
+	}
+	return rules
+}
+
+// CompileRules parses the templates and windows of a rules config.
+func CompileRules(cfg *config.FeedRulesConfig) (*Rules, error) {
+	rs := &Rules{}
+	for i, fr := range cfg.Rules {
+		r := &rule{
+			name:     fr.Name,
+			match:    fr.Match,
+			drop:     fr.Action == config.FeedActionDrop,
+			severity: fr.Severity,
+		}
+		if r.name == "" {
+			r.name = fmt.Sprintf("rules[%d]", i)
+		}
+
+		var err error
+		if fr.Summary != "" {
+			if r.summary, err = parseRuleTemplate(r.name+".summary", fr.Summary); err != nil {
+				return nil, err
+			}
+		}
+		if d := fr.Dedupe; d != nil {
+			if r.dedupeKey, err = parseRuleTemplate(r.name+".dedupe.key", orDefault(d.Key, defaultRuleKey)); err != nil {
+				return nil, err
+			}
+			if r.dedupeWindow, err = time.ParseDuration(d.Window); err != nil {
+				return nil, fmt.Errorf("%s.dedupe.window: %w", r.name, err)
+			}
+		}
+		if a := fr.Aggregate; a != nil {
+			if r.aggKey, err = parseRuleTemplate(r.name+".aggregate.key", orDefault(a.Key, defaultRuleKey)); err != nil {
+				return nil, err
+			}
+			if r.aggSummary, err = parseRuleTemplate(r.name+".aggregate.summary", a.Summary); err != nil {
+				return nil, err
+			}
+			if r.aggWindow, err = time.ParseDuration(a.Window); err != nil {
+				return nil, fmt.Errorf("%s.aggregate.window: %w", r.name, err)
+			}
+			r.aggMin = a.MinCount
+			if r.aggMin <= 0 {
+				r.aggMin = defaultMinAggregateCount
+			}
+		}
+		rs.rules = append(rs.rules, r)
+	}
+	return rs, nil
+}
+
+func parseRuleTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", name, err)
+	}
+	return t, nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// match returns the first rule that selects the event, or nil.
+func (rs *Rules) match(ev *events.Event) *rule {
+	for _, r := range rs.rules {
+		if r.matches(ev.Type, ev.Actor, ev.Source, ev.Payload) {
+			return r
+		}
+	}
+	return nil
+}
+
+// matches reports whether every non-empty part of the rule's match selects
+// the event.
+func (r *rule) matches(typ, actor, source string, payload map[string]interface{}) bool {
+	m := r.match
+	if !globAny(m.Types, typ) || !globAny(m.Actors, actor) || !globAny(m.Sources, source) {
+		return false
+	}
+	for field, pattern := range m.Payload {
+		value, ok := payload[field]
+		if !ok {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern, fmt.Sprint(value)); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// globAny reports whether s matches any pattern; no patterns match anything.
+func globAny(patterns []string, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// render executes a rule template; a template that fails to execute falls
+// back to fallback so a bad rule cannot blank the feed.
+func render(t *template.Template, data ruleData, fallback string) string {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return fallback
+	}
+	return strings.ReplaceAll(buf.String(), "<no value>", "")
+}
+
+// curate applies the rules to one raw event. Dedupe and aggregation windows
+// end at now and are evaluated against h.
+func (rs *Rules) curate(ev *events.Event, h history, now time.Time) Decision {
+	d := Decision{Event: *ev}
+
+	// Only feed-visible events reach the feed
+	if ev.Visibility != events.VisibilityFeed && ev.Visibility != events.VisibilityBoth {
+		d.Reason = "audit-only"
+		return d
+	}
+
+	summary := generateSummary(ev)
+	fe := &FeedEvent{
+		Timestamp: ev.Timestamp,
+		Source:    ev.Source,
+		Type:      ev.Type,
+		Actor:     ev.Actor,
+		Summary:   summary,
+		Payload:   ev.Payload,
+	}
+
+	r := rs.match(ev)
+	if r == nil {
+		d.Feed = fe
+		return d
+	}
+	d.Rule = r.name
+
+	if r.drop {
+		d.Reason = "dropped"
+		return d
+	}
+
+	data := ruleData{Type: ev.Type, Actor: ev.Actor, Source: ev.Source, Payload: ev.Payload, Summary: summary}
+
+	if r.dedupeKey != nil {
+		key := render(r.dedupeKey, data, ev.Actor)
+		for _, prev := range h.recentFeed(r.dedupeWindow, now) {
+			if !r.matches(prev.Type, prev.Actor, prev.Source, prev.Payload) {
+				continue
+			}
+			prevData := ruleData{Type: prev.Type, Actor: prev.Actor, Source: prev.Source, Payload: prev.Payload}
+			if render(r.dedupeKey, prevData, prev.Actor) == key {
+				d.Reason = "duplicate"
+				return d
+			}
+		}
+	}
+
+	fe.Severity = r.severity
+	if r.summary != nil {
+		fe.Summary = render(r.summary, data, summary)
+		data.Summary = fe.Summary
+	}
+
+	if r.aggKey != nil {
+		key := render(r.aggKey, data, ev.Actor)
+		count := 0
+		for _, prev := range h.recentEvents(r.aggWindow, now) {
+			if prev.Visibility != events.VisibilityFeed && prev.Visibility != events.VisibilityBoth {
+				continue
+			}
+			if !r.matches(prev.Type, prev.Actor, prev.Source, prev.Payload) {
+				continue
+			}
+			prevData := ruleData{Type: prev.Type, Actor: prev.Actor, Source: prev.Source, Payload: prev.Payload}
+			if render(r.aggKey, prevData, prev.Actor) == key {
+				count++
+			}
+		}
+		if count >= r.aggMin {
+			data.Count = count
+			fe.Count = count
+			fe.Summary = render(r.aggSummary, data, fe.Summary)
+		}
+	}
+
+	d.Feed = fe
+	return d
+}
+
+// Replay runs raw events through the rules as the curator would, measuring
+// each dedupe and aggregation window back from the event's own timestamp.
+// It returns one decision per event, in order.
+func (rs *Rules) Replay(evs []events.Event) []Decision {
+	h := &replayHistory{}
+	decisions := make([]Decision, 0, len(evs))
+	for i := range evs {
+		ev := evs[i]
+		now, err := time.Parse(time.RFC3339, ev.Timestamp)
+		if err != nil {
+			now = time.Now()
+		}
+		// The live curator reads the event file after the event is
+		// appended, so the event counts toward its own aggregate.
+		h.events = append(h.events, ev)
+		d := rs.curate(&ev, h, now)
+		if d.Feed != nil {
+			h.feed = append(h.feed, *d.Feed)
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions
+}
+
+// replayHistory is an in-memory history for Replay.
+type replayHistory struct {
+	events []events.Event
+	feed   []FeedEvent
+}
+
+func (h *replayHistory) recentFeed(window time.Duration, now time.Time) []FeedEvent {
+	var result []FeedEvent
+	for i := len(h.feed) - 1; i >= 0; i-- {
+		ts, err := time.Parse(time.RFC3339, h.feed[i].Timestamp)
+		if err != nil {
+			continue
+		}
+		if ts.Before(now.Add(-window)) {
+			break
+		}
+		result = append(result, h.feed[i])
+	}
+	return result
+}
+
+func (h *replayHistory) recentEvents(window time.Duration, now time.Time) []events.Event {
+	var result []events.Event
+	for i := len(h.events) - 1; i >= 0; i-- {
+		ts, err := time.Parse(time.RFC3339, h.events[i].Timestamp)
+		if err != nil {
+			continue
+		}
+		if ts.Before(now.Add(-window)) {
+			break
+		}
+		result = append(result, h.events[i])
+	}
+	return result
+}
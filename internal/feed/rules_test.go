@@ -0,0 +1,168 @@
+package feed
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// testEvent builds a feed-visible event offset seconds after a fixed base.
+func testEvent(offset int, typ, actor string, payload map[string]interface{}) events.Event {
+	base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	return events.Event{
+		Timestamp:  base.Add(time.Duration(offset) * time.Second).Format(time.RFC3339),
+		Source:     "gt",
+		Type:       typ,
+		Actor:      actor,
+		Payload:    payload,
+		Visibility: events.VisibilityFeed,
+	}
+}
+
+func TestReplay_DefaultRules(t *testing.T) {
+	audit := testEvent(0, "internal_check", "daemon", nil)
+	audit.Visibility = events.VisibilityAudit
+
+	evs := []events.Event{
+		audit,
+		testEvent(0, events.TypeDone, "gastown/Toast", map[string]interface{}{"bead": "gt-1"}),
+		testEvent(5, events.TypeDone, "gastown/Toast", map[string]interface{}{"bead": "gt-1"}),
+		testEvent(20, events.TypeDone, "gastown/Toast", map[string]interface{}{"bead": "gt-1"}),
+		testEvent(30, events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-2", "target": "gastown/a"}),
+		testEvent(31, events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-3", "target": "gastown/b"}),
+		testEvent(32, events.TypeSling, "mayor", map[string]interface{}{"bead": "gt-4", "target": "gastown/c"}),
+		testEvent(40, events.TypeSessionDeath, "gastown/Toast", nil),
+	}
+
+	ds := DefaultRules().Replay(evs)
+	if len(ds) != len(evs) {
+		t.Fatalf("got %d decisions, want %d", len(ds), len(evs))
+	}
+
+	if ds[0].Feed != nil || ds[0].Reason != "audit-only" {
+		t.Errorf("audit event: %+v, want dropped as audit-only", ds[0])
+	}
+	if ds[1].Feed == nil {
+		t.Error("first done should be kept")
+	}
+	if ds[2].Feed != nil || ds[2].Reason != "duplicate" || ds[2].Rule != "dedupe-done" {
+		t.Errorf("second done within 10s: %+v, want duplicate via dedupe-done", ds[2])
+	}
+	if ds[3].Feed == nil {
+		t.Error("done outside the dedupe window should be kept")
+	}
+
+	if ds[5].Feed == nil || ds[5].Feed.Count != 0 {
+		t.Errorf("second sling should not be aggregated yet: %+v", ds[5].Feed)
+	}
+	if ds[6].Feed == nil || ds[6].Feed.Count != 3 {
+		t.Fatalf("third sling: %+v, want aggregated with count 3", ds[6].Feed)
+	}
+	if want := "mayor dispatching work to 3 agents"; ds[6].Feed.Summary != want {
+		t.Errorf("aggregate summary = %q, want %q", ds[6].Feed.Summary, want)
+	}
+
+	if ds[7].Feed == nil || ds[7].Feed.Severity != config.FeedSeverityWarning {
+		t.Errorf("session death: %+v, want severity warning", ds[7].Feed)
+	}
+}
+
+func TestReplay_CustomRules(t *testing.T) {
+	cfg := &config.FeedRulesConfig{
+		Type:    "feed-rules",
+		Version: config.CurrentFeedRulesVersion,
+		Rules: []config.FeedRule{
+			{Name: "quiet-patrols", Match: config.FeedRuleMatch{Types: []string{"patrol_*"}}, Action: config.FeedActionDrop},
+			{Name: "merges", Match: config.FeedRuleMatch{Types: []string{"merged"}, Payload: map[string]string{"branch": "polecat/*"}},
+				Summary: "✓ {{.Payload.branch}} merged{{.Payload.missing}}", Severity: config.FeedSeverityInfo},
+			{Name: "done-by-bead", Match: config.FeedRuleMatch{Types: []string{"done"}},
+				Dedupe: &config.FeedDedupe{Key: "{{.Payload.bead}}", Window: "1m"}},
+		},
+	}
+	rules, err := CompileRules(cfg)
+	if err != nil {
+		t.Fatalf("CompileRules: %v", err)
+	}
+
+	ds := rules.Replay([]events.Event{
+		testEvent(0, "patrol_started", "deacon", nil),
+		testEvent(1, "merged", "gastown/refinery", map[string]interface{}{"branch": "polecat/Toast"}),
+		testEvent(2, "merged", "gastown/refinery", map[string]interface{}{"branch": "main"}),
+		testEvent(3, "done", "gastown/Toast", map[string]interface{}{"bead": "gt-1"}),
+		testEvent(4, "done", "gastown/Nux", map[string]interface{}{"bead": "gt-2"}),
+		testEvent(5, "done", "gastown/Nux", map[string]interface{}{"bead": "gt-1"}),
+	})
+
+	if ds[0].Feed != nil || ds[0].Reason != "dropped" || ds[0].Rule != "quiet-patrols" {
+		t.Errorf("patrol: %+v, want dropped by quiet-patrols", ds[0])
+	}
+	if ds[1].Feed == nil || ds[1].Feed.Summary != "✓ polecat/Toast merged" {
+		t.Errorf("merge summary = %+v, want template with missing field blank", ds[1].Feed)
+	}
+	if ds[2].Rule != "" || ds[2].Feed == nil || ds[2].Feed.Severity != "" {
+		t.Errorf("merge of main should match no rule: %+v", ds[2])
+	}
+	if ds[3].Feed == nil || ds[4].Feed == nil {
+		t.Error("done events for different beads should both be kept")
+	}
+	if ds[5].Feed != nil || ds[5].Reason != "duplicate" {
+		t.Errorf("done for gt-1 again: %+v, want duplicate by bead key", ds[5])
+	}
+}
+
+func TestCompileRules_Errors(t *testing.T) {
+	tests := []config.FeedRule{
+		{Name: "bad-summary", Summary: "{{.Actor"},
+		{Name: "bad-key", Dedupe: &config.FeedDedupe{Key: "{{if}}", Window: "10s"}},
+		{Name: "bad-window", Aggregate: &config.FeedAggregate{Window: "soon", Summary: "x"}},
+	}
+	for _, r := range tests {
+		t.Run(r.Name, func(t *testing.T) {
+			_, err := CompileRules(&config.FeedRulesConfig{Rules: []config.FeedRule{r}})
+			if err == nil || !strings.Contains(err.Error(), r.Name) {
+				t.Errorf("CompileRules(%s) error = %v, want error naming the rule", r.Name, err)
+			}
+		})
+	}
+}
+
+func TestCurator_ReloadsRules(t *testing.T) {
+	townRoot := t.TempDir()
+	c := NewCurator(townRoot)
+	ev := testEvent(0, "patrol_started", "deacon", nil)
+
+	if d := c.rules.curate(&ev, c, time.Now()); d.Feed == nil {
+		t.Fatal("without a rules file the event should be kept")
+	}
+
+	path := config.FeedRulesConfigPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rules := `{"type":"feed-rules","version":1,"rules":[{"match":{"types":["patrol_*"]},"action":"drop"}]}`
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c.reloadRules()
+	if d := c.rules.curate(&ev, c, time.Now()); d.Feed != nil || d.Reason != "dropped" {
+		t.Errorf("after reload: %+v, want dropped", d)
+	}
+
+	// An invalid file keeps the rules already in effect
+	if err := os.WriteFile(path, []byte(`{"type":"feed-rules","version":1,"rules":[{"summary":"{{"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+	c.reloadRules()
+	if d := c.rules.curate(&ev, c, time.Now()); d.Reason != "dropped" {
+		t.Errorf("after invalid reload: %+v, want previous rules kept", d)
+	}
+}
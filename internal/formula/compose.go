@@ -0,0 +1,397 @@
+package formula
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned by a Loader when no formula has the given name.
+var ErrNotFound = errors.New("formula not found")
+
+// Loader finds and parses a formula by name, without resolving it.
+type Loader func(name string) (*Formula, error)
+
+// DirLoader returns a Loader that looks for <name>.formula.toml in each
+// directory in turn.
+func DirLoader(dirs ...string) Loader {
+	return func(name string) (*Formula, error) {
+		for _, dir := range dirs {
+			path := filepath.Join(dir, name+".formula.toml")
+			if _, err := os.Stat(path); err == nil {
+				return ParseFile(path)
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+}
+
+// IsComposed reports whether the formula extends or includes other
+// formulas and so must be resolved before use.
+func (f *Formula) IsComposed() bool {
+	return len(f.Extends) > 0 || len(f.Include) > 0
+}
+
+// validateComposed checks what can be checked before a composed formula is
+// resolved: its own fields, not references into its parents.
+func (f *Formula) validateComposed() error {
+	if f.Name == "" {
+		return fmt.Errorf("formula field is required")
+	}
+	if f.Type != "" && !f.Type.IsValid() {
+		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
+	}
+
+	seen := make(map[string]bool)
+	for _, step := range f.Steps {
+		if step.ID == "" {
+			return fmt.Errorf("step missing required id field")
+		}
+		if seen[step.ID] {
+			return fmt.Errorf("duplicate step id: %s", step.ID)
+		}
+		seen[step.ID] = true
+		if step.Before != "" && step.After != "" {
+			return fmt.Errorf("step %q sets both before and after", step.ID)
+		}
+	}
+	for _, inc := range f.Include {
+		if inc.Formula == "" {
+			return fmt.Errorf("include missing required formula field")
+		}
+		if inc.Before != "" && inc.After != "" {
+			return fmt.Errorf("include %q sets both before and after", inc.Formula)
+		}
+	}
+	return nil
+}
+
+// Resolve flattens a composed formula into a plain one, loading the
+// formulas it extends and includes with load. Formulas that are not
+// composed are returned unchanged.
+//
+// The merged formula is built in order:
+//  1. Each formula in extends is resolved and merged in turn. Steps from a
+//     later parent replace earlier ones with the same ID; vars are merged.
+//  2. Each [[include]] takes the listed steps (or all of them) from a
+//     library formula and splices them in as a block.
+//  3. The formula's own steps are applied. A step whose ID matches an
+//     inherited step overrides it, keeping inherited fields it leaves
+//     empty. Other steps are inserted at before/after, or appended.
+//
+// Splicing at after = "X" makes the block depend on X and moves X's
+// dependents onto the block. Splicing at before = "Y" gives the block Y's
+// needs and makes Y depend on the block. Extends and include cycles are
+// reported as errors, like step cycles in Validate.
+func (f *Formula) Resolve(load Loader) (*Formula, error) {
+	return f.resolve(load, nil)
+}
+
+func (f *Formula) resolve(load Loader, chain []string) (*Formula, error) {
+	for _, name := range chain {
+		if name == f.Name {
+			return nil, fmt.Errorf("composition cycle detected: %s -> %s", strings.Join(chain, " -> "), f.Name)
+		}
+	}
+	if !f.IsComposed() {
+		return f, nil
+	}
+	chain = append(chain, f.Name)
+
+	loadResolved := func(name string) (*Formula, error) {
+		g, err := load(name)
+		if err != nil {
+			return nil, err
+		}
+		return g.resolve(load, chain)
+	}
+
+	out := &Formula{}
+	for _, name := range f.Extends {
+		parent, err := loadResolved(name)
+		if err != nil {
+			return nil, fmt.Errorf("formula %s extends %s: %w", f.Name, name, err)
+		}
+		out.inherit(parent)
+		for _, step := range parent.Steps {
+			out.Steps = overrideStep(out.Steps, step)
+		}
+	}
+
+	for _, inc := range f.Include {
+		lib, err := loadResolved(inc.Formula)
+		if err != nil {
+			return nil, fmt.Errorf("formula %s includes %s: %w", f.Name, inc.Formula, err)
+		}
+		block, err := includedSteps(lib, inc.Steps)
+		if err != nil {
+			return nil, fmt.Errorf("formula %s includes %s: %w", f.Name, inc.Formula, err)
+		}
+		for name, v := range lib.Vars {
+			out.setVar(name, v, false)
+		}
+		if out.Steps, err = spliceSteps(out.Steps, block, inc.Before, inc.After); err != nil {
+			return nil, fmt.Errorf("formula %s includes %s: %w", f.Name, inc.Formula, err)
+		}
+	}
+
+	for _, step := range f.Steps {
+		if stepIndex(out.Steps, step.ID) >= 0 {
+			out.Steps = overrideStep(out.Steps, step)
+			continue
+		}
+		var err error
+		before, after := step.Before, step.After
+		step.Before, step.After = "", ""
+		if out.Steps, err = spliceSteps(out.Steps, []Step{step}, before, after); err != nil {
+			return nil, fmt.Errorf("formula %s: step %s: %w", f.Name, step.ID, err)
+		}
+	}
+
+	// The formula's own fields win over everything it inherited.
+	own := *f
+	own.Steps = nil
+	out.inherit(&own)
+	out.Name = f.Name
+	out.inferType()
+
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", f.Name, err)
+	}
+	return out, nil
+}
+
+// inherit copies g's non-step fields into f. Non-empty fields in g replace
+// f's; vars are merged with g's definitions winning.
+func (f *Formula) inherit(g *Formula) {
+	if g.Description != "" {
+		f.Description = g.Description
+	}
+	if g.Type != "" {
+		f.Type = g.Type
+	}
+	if g.Version != 0 {
+		f.Version = g.Version
+	}
+	for name, v := range g.Vars {
+		f.setVar(name, v, true)
+	}
+	if len(g.Inputs) > 0 {
+		f.Inputs = g.Inputs
+	}
+	if len(g.Prompts) > 0 {
+		f.Prompts = g.Prompts
+	}
+	if g.Output != nil {
+		f.Output = g.Output
+	}
+	if len(g.Legs) > 0 {
+		f.Legs = g.Legs
+	}
+	if g.Synthesis != nil {
+		f.Synthesis = g.Synthesis
+	}
+	if len(g.Template) > 0 {
+		f.Template = g.Template
+	}
+	if len(g.Aspects) > 0 {
+		f.Aspects = g.Aspects
+	}
+}
+
+func (f *Formula) setVar(name string, v Var, replace bool) {
+	if f.Vars == nil {
+		f.Vars = make(map[string]Var)
+	}
+	if _, ok := f.Vars[name]; ok && !replace {
+		return
+	}
+	f.Vars[name] = v
+}
+
+func stepIndex(steps []Step, id string) int {
+	for i := range steps {
+		if steps[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// overrideStep replaces the step with step.ID in place, keeping fields the
+// override leaves empty, or appends step if there is none.
+func overrideStep(steps []Step, step Step) []Step {
+	i := stepIndex(steps, step.ID)
+	if i < 0 {
+		return append(steps, step)
+	}
+	merged := steps[i]
+	if step.Title != "" {
+		merged.Title = step.Title
+	}
+	if step.Description != "" {
+		merged.Description = step.Description
+	}
+	if step.Needs != nil {
+		merged.Needs = step.Needs
+	}
+	if step.Parallel {
+		merged.Parallel = true
+	}
+	if step.Timeout != "" {
+		merged.Timeout = step.Timeout
+	}
+	if step.MaxAttempts != 0 {
+		merged.MaxAttempts = step.MaxAttempts
+	}
+	if step.OnFailure != "" {
+		merged.OnFailure = step.OnFailure
+	}
+	if step.When != "" {
+		merged.When = step.When
+	}
+	steps[i] = merged
+	return steps
+}
+
+// includedSteps returns the steps of lib named in ids (all if empty), in
+// lib's order. A selected step may not need a step left out.
+func includedSteps(lib *Formula, ids []string) ([]Step, error) {
+	if len(ids) == 0 {
+		return append([]Step(nil), lib.Steps...), nil
+	}
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if lib.GetStep(id) == nil {
+			return nil, fmt.Errorf("unknown step: %s", id)
+		}
+		want[id] = true
+	}
+	var block []Step
+	for _, step := range lib.Steps {
+		if !want[step.ID] {
+			continue
+		}
+		for _, need := range step.Needs {
+			if !want[need] {
+				return nil, fmt.Errorf("step %q needs %s, which is not included", step.ID, need)
+			}
+		}
+		block = append(block, step)
+	}
+	return block, nil
+}
+
+// spliceSteps inserts block into steps next to the before or after anchor
+// and rewires needs around it (see Resolve). Without an anchor the block
+// is appended as is.
+func spliceSteps(steps, block []Step, before, after string) ([]Step, error) {
+	if before == "" && after == "" {
+		return append(steps, block...), nil
+	}
+	if before != "" && after != "" {
+		return nil, fmt.Errorf("cannot set both before and after")
+	}
+
+	inBlock := make(map[string]bool, len(block))
+	for _, step := range block {
+		if stepIndex(steps, step.ID) >= 0 {
+			return nil, fmt.Errorf("duplicate step id: %s", step.ID)
+		}
+		inBlock[step.ID] = true
+	}
+	// Entries need nothing inside the block; exits are needed by nothing
+	// inside it.
+	neededInBlock := make(map[string]bool)
+	for _, step := range block {
+		for _, need := range step.Needs {
+			neededInBlock[need] = true
+		}
+	}
+	block = append([]Step(nil), block...)
+	var exits []string
+	for _, step := range block {
+		if !neededInBlock[step.ID] {
+			exits = append(exits, step.ID)
+		}
+	}
+	isEntry := func(step Step) bool {
+		for _, need := range step.Needs {
+			if inBlock[need] {
+				return false
+			}
+		}
+		return true
+	}
+
+	anchor := after
+	if before != "" {
+		anchor = before
+	}
+	at := stepIndex(steps, anchor)
+	if at < 0 {
+		return nil, fmt.Errorf("unknown anchor step: %s", anchor)
+	}
+
+	out := make([]Step, 0, len(steps)+len(block))
+	if after != "" {
+		for i := range block {
+			if isEntry(block[i]) {
+				block[i].Needs = appendMissing(append([]string(nil), block[i].Needs...), after)
+			}
+		}
+		for _, step := range steps {
+			step.Needs = replaceNeed(step.Needs, after, exits)
+			out = append(out, step)
+		}
+		at++
+	} else {
+		anchorNeeds := steps[at].Needs
+		for i := range block {
+			if isEntry(block[i]) {
+				block[i].Needs = appendMissing(append([]string(nil), block[i].Needs...), anchorNeeds...)
+			}
+		}
+		out = append(out, steps...)
+		out[at].Needs = append([]string(nil), exits...)
+	}
+
+	out = append(out[:at], append(block, out[at:]...)...)
+	return out, nil
+}
+
+// replaceNeed returns needs with old replaced by repl.
+func replaceNeed(needs []string, old string, repl []string) []string {
+	var out []string
+	replaced := false
+	for _, need := range needs {
+		if need == old {
+			replaced = true
+			out = appendMissing(out, repl...)
+			continue
+		}
+		out = appendMissing(out, need)
+	}
+	if !replaced {
+		return needs
+	}
+	return out
+}
+
+func appendMissing(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, x := range list {
+			if x == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
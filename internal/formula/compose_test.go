@@ -0,0 +1,237 @@
+package formula
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// mapLoader loads formulas from TOML sources keyed by name.
+func mapLoader(t *testing.T, sources map[string]string) Loader {
+	t.Helper()
+	return func(name string) (*Formula, error) {
+		src, ok := sources[name]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		return Parse([]byte(src))
+	}
+}
+
+const baseReview = `
+formula = "review"
+type = "workflow"
+version = 1
+description = "Base review"
+[[steps]]
+id = "design"
+title = "Design"
+[[steps]]
+id = "implement"
+title = "Implement"
+needs = ["design"]
+[[steps]]
+id = "submit"
+title = "Submit"
+needs = ["implement"]
+[vars.feature]
+required = true
+`
+
+func stepNeeds(f *Formula, id string) []string {
+	if s := f.GetStep(id); s != nil {
+		return s.Needs
+	}
+	return nil
+}
+
+func TestResolve_ExtendsOverrideAndInsert(t *testing.T) {
+	load := mapLoader(t, map[string]string{"review": baseReview})
+	child, err := Parse([]byte(`
+formula = "review-secure"
+extends = ["review"]
+[[steps]]
+id = "implement"
+description = "Implement with care"
+[[steps]]
+id = "audit"
+title = "Security audit"
+after = "implement"
+[[steps]]
+id = "threat-model"
+title = "Threat model"
+before = "implement"
+[vars.feature]
+default = "auth"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	f, err := child.Resolve(load)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if f.Name != "review-secure" || f.Type != TypeWorkflow || f.Description != "Base review" || f.IsComposed() {
+		t.Errorf("resolved header = %q %q %q composed=%v", f.Name, f.Type, f.Description, f.IsComposed())
+	}
+
+	impl := f.GetStep("implement")
+	if impl.Title != "Implement" || impl.Description != "Implement with care" {
+		t.Errorf("override lost inherited fields: %+v", impl)
+	}
+	if got := stepNeeds(f, "audit"); !reflect.DeepEqual(got, []string{"implement"}) {
+		t.Errorf("audit needs %v, want [implement]", got)
+	}
+	if got := stepNeeds(f, "submit"); !reflect.DeepEqual(got, []string{"audit"}) {
+		t.Errorf("submit needs %v, want [audit]", got)
+	}
+	if got := stepNeeds(f, "threat-model"); !reflect.DeepEqual(got, []string{"design"}) {
+		t.Errorf("threat-model needs %v, want [design]", got)
+	}
+	if got := stepNeeds(f, "implement"); !reflect.DeepEqual(got, []string{"threat-model"}) {
+		t.Errorf("implement needs %v, want [threat-model]", got)
+	}
+	if f.Vars["feature"].Default != "auth" {
+		t.Errorf("vars = %+v, want child default", f.Vars)
+	}
+
+	order, err := f.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"design", "threat-model", "implement", "audit", "submit"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
+
+func TestResolve_Include(t *testing.T) {
+	load := mapLoader(t, map[string]string{
+		"review": baseReview,
+		"checks": `
+formula = "checks"
+type = "workflow"
+version = 1
+[[steps]]
+id = "lint"
+title = "Lint"
+parallel = true
+[[steps]]
+id = "test"
+title = "Test"
+parallel = true
+[[steps]]
+id = "bench"
+title = "Bench"
+needs = ["test"]
+[vars.race]
+default = "true"
+`,
+	})
+	child, err := Parse([]byte(`
+formula = "review-checked"
+extends = ["review"]
+[[include]]
+formula = "checks"
+steps = ["lint", "test"]
+after = "implement"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	f, err := child.Resolve(load)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if f.GetStep("bench") != nil {
+		t.Error("bench was not selected but was included")
+	}
+	if got := stepNeeds(f, "submit"); !reflect.DeepEqual(got, []string{"lint", "test"}) {
+		t.Errorf("submit needs %v, want [lint test]", got)
+	}
+	if waves := f.Waves(); len(waves) != 4 || len(waves[2]) != 2 {
+		t.Errorf("waves = %v, want lint and test together", waves)
+	}
+	if f.Vars["race"].Default != "true" {
+		t.Error("included vars not merged")
+	}
+
+	// Selecting a step without what it needs is an error.
+	bad, _ := Parse([]byte(`
+formula = "bad"
+extends = ["review"]
+[[include]]
+formula = "checks"
+steps = ["bench"]
+`))
+	if _, err := bad.Resolve(load); err == nil || !strings.Contains(err.Error(), "not included") {
+		t.Errorf("expected not-included error, got %v", err)
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	load := mapLoader(t, map[string]string{
+		"review": baseReview,
+		"a":      "formula = \"a\"\nextends = [\"b\"]\n",
+		"b":      "formula = \"b\"\nextends = [\"a\"]\n",
+	})
+
+	a, err := load("a")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if _, err := a.Resolve(load); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+
+	missing, _ := Parse([]byte("formula = \"x\"\nextends = [\"nope\"]\n"))
+	if _, err := missing.Resolve(load); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	anchor, _ := Parse([]byte(`
+formula = "x"
+extends = ["review"]
+[[steps]]
+id = "extra"
+after = "nope"
+`))
+	if _, err := anchor.Resolve(load); err == nil || !strings.Contains(err.Error(), "unknown anchor") {
+		t.Errorf("expected unknown anchor error, got %v", err)
+	}
+
+	if _, err := Parse([]byte(`
+formula = "x"
+extends = ["review"]
+[[steps]]
+id = "extra"
+before = "design"
+after = "submit"
+`)); err == nil {
+		t.Error("expected error for both before and after")
+	}
+}
+
+func TestResolve_EmbeddedShinyVariants(t *testing.T) {
+	load := DirLoader("formulas")
+	shiny, err := load("shiny")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"shiny-secure", "shiny-enterprise"} {
+		f, err := load(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		resolved, err := f.Resolve(load)
+		if err != nil {
+			t.Fatalf("%s: Resolve failed: %v", name, err)
+		}
+		if len(resolved.Steps) != len(shiny.Steps) || resolved.Vars["feature"].Required != true {
+			t.Errorf("%s resolved to %d steps, vars %v", name, len(resolved.Steps), resolved.Vars)
+		}
+	}
+}
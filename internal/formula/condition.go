@@ -0,0 +1,303 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Condition is a parsed step `when` expression. Conditions compare
+// references to formula vars and earlier step results:
+//
+//	vars.channel == "stable"
+//	steps.bump.outputs.bumped == "true" && !vars.dry_run
+//	steps.test.outcome != "skipped" || vars.force
+//
+// References are vars.<name>, steps.<id>.outputs.<key> and
+// steps.<id>.outcome. A bare reference is true unless it is empty, "false"
+// or "0". Operators are ==, !=, !, && and ||, with parentheses for grouping.
+type Condition struct {
+	src  string
+	root condNode
+}
+
+type condNode interface {
+	eval(lookup func(ref string) string) string
+}
+
+type (
+	condRef     string
+	condLiteral string
+	condNot     struct{ x condNode }
+	condBinary  struct {
+		op   string
+		x, y condNode
+	}
+)
+
+func (r condRef) eval(lookup func(string) string) string { return lookup(string(r)) }
+func (l condLiteral) eval(func(string) string) string    { return string(l) }
+
+func (n condNot) eval(lookup func(string) string) string {
+	return boolString(!truthy(n.x.eval(lookup)))
+}
+
+func (n condBinary) eval(lookup func(string) string) string {
+	switch n.op {
+	case "==":
+		return boolString(n.x.eval(lookup) == n.y.eval(lookup))
+	case "!=":
+		return boolString(n.x.eval(lookup) != n.y.eval(lookup))
+	case "&&":
+		return boolString(truthy(n.x.eval(lookup)) && truthy(n.y.eval(lookup)))
+	default: // "||"
+		return boolString(truthy(n.x.eval(lookup)) || truthy(n.y.eval(lookup)))
+	}
+}
+
+func truthy(s string) bool {
+	return s != "" && s != "false" && s != "0"
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+// ParseCondition parses a `when` expression.
+func ParseCondition(src string) (*Condition, error) {
+	toks, err := lexCondition(src)
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", src, err)
+	}
+	p := &condParser{toks: toks}
+	root, err := p.or()
+	if err == nil && p.pos < len(p.toks) {
+		err = fmt.Errorf("unexpected %q", p.toks[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("condition %q: %w", src, err)
+	}
+	return &Condition{src: src, root: root}, nil
+}
+
+// String returns the expression the condition was parsed from.
+func (c *Condition) String() string { return c.src }
+
+// Eval evaluates the condition. lookup returns the value of a reference,
+// or "" if it has none (such as an output a step did not record).
+func (c *Condition) Eval(lookup func(ref string) string) bool {
+	return truthy(c.root.eval(lookup))
+}
+
+// Refs returns the references the condition reads, in order of appearance.
+func (c *Condition) Refs() []string {
+	var refs []string
+	var walk func(n condNode)
+	walk = func(n condNode) {
+		switch n := n.(type) {
+		case condRef:
+			refs = appendMissing(refs, string(n))
+		case condNot:
+			walk(n.x)
+		case condBinary:
+			walk(n.x)
+			walk(n.y)
+		}
+	}
+	walk(c.root)
+	return refs
+}
+
+// StepRef splits a steps.<id>.<field> reference into the step ID and the
+// rest. ok is false for other references.
+func StepRef(ref string) (id, field string, ok bool) {
+	rest, found := strings.CutPrefix(ref, "steps.")
+	if !found {
+		return "", "", false
+	}
+	id, field, found = strings.Cut(rest, ".")
+	return id, field, found
+}
+
+// validateCondition checks that a step's condition parses and only refers
+// to declared vars and to steps the step depends on, whose results are
+// known by the time it becomes ready.
+func (f *Formula) validateCondition(step Step, upstream map[string]bool) error {
+	c, err := ParseCondition(step.When)
+	if err != nil {
+		return fmt.Errorf("step %q: %w", step.ID, err)
+	}
+	for _, ref := range c.Refs() {
+		if name, ok := strings.CutPrefix(ref, "vars."); ok {
+			_, isVar := f.Vars[name]
+			_, isInput := f.Inputs[name]
+			if !isVar && !isInput {
+				return fmt.Errorf("step %q: when references undeclared var: %s", step.ID, name)
+			}
+			continue
+		}
+		id, field, ok := StepRef(ref)
+		if !ok {
+			return fmt.Errorf("step %q: when reference %q must start with vars. or steps.", step.ID, ref)
+		}
+		if field != "outcome" && !strings.HasPrefix(field, "outputs.") {
+			return fmt.Errorf("step %q: when reference %q must end in .outcome or .outputs.<key>", step.ID, ref)
+		}
+		if !upstream[id] {
+			return fmt.Errorf("step %q: when references step %s, which it does not depend on", step.ID, id)
+		}
+	}
+	return nil
+}
+
+type condToken struct {
+	kind string // "ref", "str", "op", "(", ")"
+	text string
+}
+
+func lexCondition(src string) ([]condToken, error) {
+	var toks []condToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			toks = append(toks, condToken{kind: string(c), text: string(c)})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			toks = append(toks, condToken{kind: "str", text: src[i+1 : i+1+end]})
+			i += end + 2
+		case strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="),
+			strings.HasPrefix(src[i:], "&&"), strings.HasPrefix(src[i:], "||"):
+			toks = append(toks, condToken{kind: "op", text: src[i : i+2]})
+			i += 2
+		case c == '!':
+			toks = append(toks, condToken{kind: "op", text: "!"})
+			i++
+		case isCondWordByte(c):
+			j := i
+			for j < len(src) && isCondWordByte(src[j]) {
+				j++
+			}
+			word := src[i:j]
+			if strings.Contains(word, ".") && !isNumber(word) {
+				toks = append(toks, condToken{kind: "ref", text: word})
+			} else {
+				// Bare words and numbers are literals: true, 3, stable.
+				toks = append(toks, condToken{kind: "str", text: word})
+			}
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	return toks, nil
+}
+
+func isCondWordByte(c byte) bool {
+	return c == '.' || c == '_' || c == '-' ||
+		c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isNumber(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && c != '.' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+type condParser struct {
+	toks []condToken
+	pos  int
+}
+
+func (p *condParser) peek(kind, text string) bool {
+	if p.pos >= len(p.toks) {
+		return false
+	}
+	t := p.toks[p.pos]
+	return t.kind == kind && (text == "" || t.text == text)
+}
+
+func (p *condParser) or() (condNode, error) {
+	x, err := p.and()
+	for err == nil && p.peek("op", "||") {
+		p.pos++
+		var y condNode
+		if y, err = p.and(); err == nil {
+			x = condBinary{op: "||", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *condParser) and() (condNode, error) {
+	x, err := p.unary()
+	for err == nil && p.peek("op", "&&") {
+		p.pos++
+		var y condNode
+		if y, err = p.unary(); err == nil {
+			x = condBinary{op: "&&", x: x, y: y}
+		}
+	}
+	return x, err
+}
+
+func (p *condParser) unary() (condNode, error) {
+	if p.peek("op", "!") {
+		p.pos++
+		x, err := p.unary()
+		return condNot{x: x}, err
+	}
+	x, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek("op", "==") || p.peek("op", "!=") {
+		op := p.toks[p.pos].text
+		p.pos++
+		y, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return condBinary{op: op, x: x, y: y}, nil
+	}
+	return x, nil
+}
+
+func (p *condParser) operand() (condNode, error) {
+	if p.pos >= len(p.toks) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	t := p.toks[p.pos]
+	p.pos++
+	switch t.kind {
+	case "ref":
+		return condRef(t.text), nil
+	case "str":
+		return condLiteral(t.text), nil
+	case "(":
+		x, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")", "") {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return x, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
@@ -0,0 +1,138 @@
+package formula
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCondition_Eval(t *testing.T) {
+	env := map[string]string{
+		"vars.channel":               "stable",
+		"vars.dry_run":               "false",
+		"steps.bump.outputs.bumped":  "true",
+		"steps.test.outcome":         "passed",
+		"steps.bump.outputs.version": "1.2.0",
+	}
+	lookup := func(ref string) string { return env[ref] }
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`vars.channel == "stable"`, true},
+		{`vars.channel != 'stable'`, false},
+		{`vars.channel == stable`, true},
+		{`steps.bump.outputs.bumped`, true},
+		{`vars.dry_run`, false},
+		{`!vars.dry_run`, true},
+		{`steps.missing.outputs.x`, false},
+		{`steps.bump.outputs.version == 1.2.0`, true},
+		{`steps.bump.outputs.bumped && vars.channel == "beta"`, false},
+		{`vars.channel == "beta" || steps.test.outcome == "passed"`, true},
+		{`!(vars.dry_run || vars.channel == "beta")`, true},
+	}
+	for _, tt := range tests {
+		c, err := ParseCondition(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCondition(%q): %v", tt.expr, err)
+		}
+		if got := c.Eval(lookup); got != tt.want {
+			t.Errorf("Eval(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCondition_Refs(t *testing.T) {
+	c, err := ParseCondition(`steps.bump.outputs.bumped == "true" && (vars.force || steps.bump.outputs.bumped)`)
+	if err != nil {
+		t.Fatalf("ParseCondition: %v", err)
+	}
+	want := []string{"steps.bump.outputs.bumped", "vars.force"}
+	if got := c.Refs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Refs() = %v, want %v", got, want)
+	}
+}
+
+func TestParseCondition_Errors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`vars.a ==`,
+		`(vars.a`,
+		`vars.a == "x`,
+		`vars.a > 1`,
+		`vars.a vars.b`,
+	} {
+		if _, err := ParseCondition(expr); err == nil {
+			t.Errorf("ParseCondition(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestValidateStepPolicy(t *testing.T) {
+	base := `
+formula = "release"
+[vars.channel]
+[[steps]]
+id = "bump"
+[[steps]]
+id = "test"
+needs = ["bump"]
+[[steps]]
+id = "publish"
+needs = ["test"]
+`
+	valid := base + `timeout = "30m"
+max_attempts = 2
+on_failure = "escalate"
+when = 'steps.bump.outputs.bumped == "true" && vars.channel != "none"'
+`
+	if _, err := Parse([]byte(valid)); err != nil {
+		t.Fatalf("Parse valid policy: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{"bad timeout", `timeout = "soon"`, "invalid timeout"},
+		{"negative attempts", `max_attempts = -1`, "max_attempts"},
+		{"bad on_failure", `on_failure = "ignore"`, "invalid on_failure"},
+		{"bad when", `when = "vars.channel =="`, "condition"},
+		{"undeclared var", `when = "vars.chanel"`, "undeclared var: chanel"},
+		{"not upstream", `when = "steps.publish.outcome"`, "does not depend on"},
+		{"bad field", `when = "steps.bump.status"`, ".outcome or .outputs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(base + tt.policy + "\n"))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFailureAction(t *testing.T) {
+	tests := []struct {
+		onFailure   string
+		maxAttempts int
+		attempts    int
+		want        string
+	}{
+		{"", 0, 1, OnFailureAbort},
+		{OnFailureSkip, 0, 1, OnFailureSkip},
+		{OnFailureEscalate, 2, 1, OnFailureRetry},
+		{OnFailureEscalate, 2, 2, OnFailureEscalate},
+		{OnFailureRetry, 0, 2, OnFailureRetry},
+		{OnFailureRetry, 0, DefaultRetryAttempts, OnFailureAbort},
+		{OnFailureRetry, 5, 4, OnFailureRetry},
+	}
+	for _, tt := range tests {
+		if got := FailureAction(tt.onFailure, tt.maxAttempts, tt.attempts); got != tt.want {
+			t.Errorf("FailureAction(%q, %d, %d) = %q, want %q",
+				tt.onFailure, tt.maxAttempts, tt.attempts, got, tt.want)
+		}
+	}
+}
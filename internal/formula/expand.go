@@ -0,0 +1,61 @@
+package formula
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Target is an input an expansion formula is instantiated for, usually a bead.
+type Target struct {
+	ID          string
+	Title       string
+	Description string
+}
+
+// Expand instantiates an expansion formula's templates once per target and
+// returns the result as a workflow formula.
+//
+// The placeholders {target}, {target.title} and {target.description} are
+// replaced in template ids, titles, descriptions and needs. Instantiated
+// steps are marked parallel so that the chains for different targets run
+// concurrently; needs still order the steps within each chain.
+func (f *Formula) Expand(targets []Target) (*Formula, error) {
+	if f.Type != TypeExpansion {
+		return nil, fmt.Errorf("formula %s is a %s formula, not an expansion", f.Name, f.Type)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("expansion formula %s requires at least one target", f.Name)
+	}
+
+	out := &Formula{
+		Name:        f.Name,
+		Description: f.Description,
+		Type:        TypeWorkflow,
+		Version:     f.Version,
+		Vars:        f.Vars,
+	}
+	for _, t := range targets {
+		r := strings.NewReplacer(
+			"{target.title}", t.Title,
+			"{target.description}", t.Description,
+			"{target}", t.ID,
+		)
+		for _, tmpl := range f.Template {
+			step := Step{
+				ID:          r.Replace(tmpl.ID),
+				Title:       r.Replace(tmpl.Title),
+				Description: r.Replace(tmpl.Description),
+				Parallel:    true,
+			}
+			for _, need := range tmpl.Needs {
+				step.Needs = append(step.Needs, r.Replace(need))
+			}
+			out.Steps = append(out.Steps, step)
+		}
+	}
+
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("expanding %s: %w", f.Name, err)
+	}
+	return out, nil
+}
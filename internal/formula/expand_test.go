@@ -0,0 +1,80 @@
+package formula
+
+import (
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	data := []byte(`
+formula = "refine"
+type = "expansion"
+version = 1
+[[template]]
+id = "{target}.draft"
+title = "Draft: {target.title}"
+description = "Initial attempt at: {target.description}"
+[[template]]
+id = "{target}.polish"
+title = "Polish"
+needs = ["{target}.draft"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expanded, err := f.Expand([]Target{
+		{ID: "gt-abc", Title: "Login page", Description: "Add a login page"},
+		{ID: "gt-def", Title: "Logout", Description: "Add logout"},
+	})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+
+	if expanded.Type != TypeWorkflow {
+		t.Errorf("Type = %q, want %q", expanded.Type, TypeWorkflow)
+	}
+	if len(expanded.Steps) != 4 {
+		t.Fatalf("len(Steps) = %d, want 4", len(expanded.Steps))
+	}
+	draft := expanded.GetStep("gt-abc.draft")
+	if draft == nil {
+		t.Fatal("step gt-abc.draft not found")
+	}
+	if draft.Title != "Draft: Login page" || draft.Description != "Initial attempt at: Add a login page" {
+		t.Errorf("draft = %+v", draft)
+	}
+	polish := expanded.GetStep("gt-def.polish")
+	if polish == nil || len(polish.Needs) != 1 || polish.Needs[0] != "gt-def.draft" {
+		t.Errorf("gt-def.polish = %+v, want needs [gt-def.draft]", polish)
+	}
+
+	// Each target's chain starts at once; steps within a chain stay ordered.
+	waves := expanded.Waves()
+	if len(waves) != 2 || len(waves[0]) != 2 {
+		t.Errorf("Waves() = %v, want both drafts first", waves)
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	f, err := Parse([]byte(`
+formula = "fixed"
+type = "expansion"
+version = 1
+[[template]]
+id = "draft"
+title = "Draft"
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if _, err := f.Expand(nil); err == nil {
+		t.Error("expected error for no targets")
+	}
+	// Templates without {target} collide when expanded more than once.
+	if _, err := f.Expand([]Target{{ID: "a"}, {ID: "b"}}); err == nil {
+		t.Error("expected duplicate step error")
+	}
+}
@@ -0,0 +1,381 @@
+package formula
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Input and var types.
+const (
+	ParamString = "string"
+	ParamInt    = "int"
+	ParamBool   = "bool"
+	ParamEnum   = "enum"
+	ParamBeadID = "bead-id"
+	ParamPath   = "path"
+	ParamRig    = "rig"
+
+	// paramNumber is accepted as an alias for int.
+	paramNumber = "number"
+)
+
+var (
+	beadIDPattern  = regexp.MustCompile(`^[a-z][a-z0-9]*-[a-z0-9][a-z0-9.-]*$`)
+	rigNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+)
+
+// Param is a declared input or var with its validation rules.
+type Param struct {
+	Name           string
+	Description    string
+	Type           string // One of the Param* types; empty means string
+	Required       bool
+	RequiredUnless []string // Required unless one of these has a value
+	Default        string
+	Pattern        string
+	Min            *int
+	Max            *int
+	Choices        []string
+}
+
+// Params returns the formula's inputs followed by its vars, each sorted
+// by name.
+func (f *Formula) Params() []Param {
+	var inputs, vars []Param
+	for name, in := range f.Inputs {
+		inputs = append(inputs, Param{
+			Name: name, Description: in.Description, Type: in.Type,
+			Required: in.Required, RequiredUnless: in.RequiredUnless, Default: in.Default,
+			Pattern: in.Pattern, Min: in.Min, Max: in.Max, Choices: in.Choices,
+		})
+	}
+	for name, v := range f.Vars {
+		vars = append(vars, Param{
+			Name: name, Description: v.Description, Type: v.Type,
+			Required: v.Required, Default: v.Default,
+			Pattern: v.Pattern, Min: v.Min, Max: v.Max, Choices: v.Choices,
+		})
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].Name < inputs[j].Name })
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return append(inputs, vars...)
+}
+
+// kind returns the param's normalized type.
+func (p Param) kind() string {
+	switch p.Type {
+	case "":
+		return ParamString
+	case paramNumber:
+		return ParamInt
+	}
+	return p.Type
+}
+
+// validate checks the param's declaration.
+func (p Param) validate() error {
+	switch p.kind() {
+	case ParamString, ParamInt, ParamBool, ParamBeadID, ParamPath, ParamRig:
+	case ParamEnum:
+		if len(p.Choices) == 0 {
+			return fmt.Errorf("%s: enum requires choices", p.Name)
+		}
+	default:
+		return fmt.Errorf("%s: unknown type %q (must be string, int, bool, enum, bead-id, path, or rig)", p.Name, p.Type)
+	}
+	if (p.Min != nil || p.Max != nil) && p.kind() != ParamInt {
+		return fmt.Errorf("%s: min and max only apply to int", p.Name)
+	}
+	if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+		return fmt.Errorf("%s: min %d is greater than max %d", p.Name, *p.Min, *p.Max)
+	}
+	if p.Pattern != "" {
+		if _, err := regexp.Compile(p.Pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", p.Name, err)
+		}
+	}
+	if p.Default != "" {
+		if err := p.Check(p.Default); err != nil {
+			return fmt.Errorf("%w (default)", err)
+		}
+	}
+	return nil
+}
+
+// Check validates a value against the param's type and rules. It does not
+// check that beads, paths or rigs exist; see BindOptions.Check.
+func (p Param) Check(value string) error {
+	switch p.kind() {
+	case ParamInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not an integer", p.Name, value)
+		}
+		if p.Min != nil && n < *p.Min {
+			return fmt.Errorf("%s: %d is less than the minimum %d", p.Name, n, *p.Min)
+		}
+		if p.Max != nil && n > *p.Max {
+			return fmt.Errorf("%s: %d is greater than the maximum %d", p.Name, n, *p.Max)
+		}
+	case ParamBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s: %q is not a boolean (use true or false)", p.Name, value)
+		}
+	case ParamBeadID:
+		if !beadIDPattern.MatchString(value) {
+			return fmt.Errorf("%s: %q is not a bead ID (like gt-abc12)", p.Name, value)
+		}
+	case ParamRig:
+		if !rigNamePattern.MatchString(value) {
+			return fmt.Errorf("%s: %q is not a rig name", p.Name, value)
+		}
+	case ParamPath:
+		if strings.ContainsRune(value, 0) {
+			return fmt.Errorf("%s: invalid path", p.Name)
+		}
+	}
+
+	if len(p.Choices) > 0 {
+		found := false
+		for _, c := range p.Choices {
+			if value == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %q is not one of %s", p.Name, value, strings.Join(p.Choices, ", "))
+		}
+	}
+	if p.Pattern != "" {
+		re, err := regexp.Compile(`^(?:` + p.Pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", p.Name, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%s: %q does not match %s", p.Name, value, p.Pattern)
+		}
+	}
+	return nil
+}
+
+// Usage describes the param for prompts and error messages.
+func (p Param) Usage() string {
+	var hints []string
+	hints = append(hints, p.kind())
+	if len(p.Choices) > 0 {
+		hints = append(hints, "one of "+strings.Join(p.Choices, "|"))
+	}
+	if p.Min != nil {
+		hints = append(hints, fmt.Sprintf("min %d", *p.Min))
+	}
+	if p.Max != nil {
+		hints = append(hints, fmt.Sprintf("max %d", *p.Max))
+	}
+	if len(p.RequiredUnless) > 0 {
+		hints = append(hints, "or set "+strings.Join(p.RequiredUnless, "/"))
+	}
+	s := fmt.Sprintf("%s (%s)", p.Name, strings.Join(hints, ", "))
+	if p.Description != "" {
+		s += ": " + p.Description
+	}
+	return s
+}
+
+// validateParams checks every input and var declaration.
+func (f *Formula) validateParams() error {
+	for name := range f.Inputs {
+		if _, ok := f.Vars[name]; ok {
+			return fmt.Errorf("%s is declared as both an input and a var", name)
+		}
+	}
+	for _, p := range f.Params() {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("invalid declaration: %w", err)
+		}
+		for _, other := range p.RequiredUnless {
+			if _, ok := f.Inputs[other]; !ok {
+				return fmt.Errorf("input %s: required_unless references unknown input: %s", p.Name, other)
+			}
+		}
+	}
+	return nil
+}
+
+// MissingInputsError lists every required input or var without a value.
+type MissingInputsError struct {
+	Formula string
+	Missing []Param
+}
+
+func (e *MissingInputsError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "formula %s is missing %d required input(s):", e.Formula, len(e.Missing))
+	for _, p := range e.Missing {
+		fmt.Fprintf(&b, "\n  %s", p.Usage())
+	}
+	b.WriteString("\nSet them with --var name=value")
+	return b.String()
+}
+
+// BindOptions customises Bind.
+type BindOptions struct {
+	// Prompt asks for a value the caller did not supply. problem is why
+	// the previous answer was rejected, or nil. A nil Prompt means the run
+	// is non-interactive. Returning "" leaves the value unset.
+	Prompt func(p Param, problem error) (string, error)
+
+	// Check runs after Param.Check for checks that need the outside
+	// world, such as whether a bead or rig exists.
+	Check func(p Param, value string) error
+}
+
+// maxPromptAttempts bounds how often Bind re-prompts for an invalid value.
+const maxPromptAttempts = 3
+
+// Bind validates values against the formula's declared inputs and vars,
+// fills in defaults and prompts for what is missing. It returns the bound
+// values, or an error naming an unknown or invalid value, or a
+// *MissingInputsError listing every required value still missing.
+func (f *Formula) Bind(values map[string]string, opts BindOptions) (map[string]string, error) {
+	params := f.Params()
+	declared := make(map[string]bool, len(params))
+	for _, p := range params {
+		declared[p.Name] = true
+	}
+
+	var unknown []string
+	for name := range values {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		var names []string
+		for _, p := range params {
+			names = append(names, p.Name)
+		}
+		known := "none"
+		if len(names) > 0 {
+			known = strings.Join(names, ", ")
+		}
+		return nil, fmt.Errorf("formula %s has no input named %s (declared: %s)",
+			f.Name, strings.Join(unknown, ", "), known)
+	}
+
+	check := func(p Param, value string) error {
+		if err := p.Check(value); err != nil {
+			return err
+		}
+		if opts.Check != nil {
+			return opts.Check(p, value)
+		}
+		return nil
+	}
+
+	bound := make(map[string]string, len(params))
+	for _, p := range params {
+		if v, ok := values[p.Name]; ok {
+			if err := check(p, v); err != nil {
+				return nil, err
+			}
+			bound[p.Name] = v
+		}
+	}
+
+	satisfied := func(p Param) bool {
+		for _, other := range p.RequiredUnless {
+			if bound[other] != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	byName := make(map[string]Param, len(params))
+	for _, p := range params {
+		byName[p.Name] = p
+	}
+	ask := func(p Param) (bool, error) {
+		if opts.Prompt == nil {
+			return false, nil
+		}
+		v, err := promptParam(p, opts.Prompt, check)
+		if err != nil || v == "" {
+			return false, err
+		}
+		bound[p.Name] = v
+		return true, nil
+	}
+
+	var missing []Param
+	for _, p := range params {
+		if _, ok := bound[p.Name]; ok {
+			continue
+		}
+		if p.Default != "" {
+			bound[p.Name] = p.Default
+			continue
+		}
+		needed := p.Required || (len(p.RequiredUnless) > 0 && !satisfied(p))
+		if !needed {
+			continue
+		}
+		ok, err := ask(p)
+		if err != nil {
+			return nil, err
+		}
+		// Skipping a required_unless input moves on to its alternatives.
+		for _, other := range p.RequiredUnless {
+			if ok || p.Required {
+				break
+			}
+			if _, set := bound[other]; set {
+				continue
+			}
+			if _, err := ask(byName[other]); err != nil {
+				return nil, err
+			}
+			ok = satisfied(p)
+		}
+		if !ok {
+			missing = append(missing, p)
+		}
+	}
+
+	// An alternative answered later satisfies a required_unless input.
+	var stillMissing []Param
+	for _, p := range missing {
+		if !p.Required && satisfied(p) {
+			continue
+		}
+		stillMissing = append(stillMissing, p)
+	}
+	if len(stillMissing) > 0 {
+		return nil, &MissingInputsError{Formula: f.Name, Missing: stillMissing}
+	}
+	return bound, nil
+}
+
+// promptParam prompts until it gets a valid value, an empty answer, or
+// runs out of attempts.
+func promptParam(p Param, prompt func(Param, error) (string, error), check func(Param, string) error) (string, error) {
+	var lastErr error
+	for i := 0; i < maxPromptAttempts; i++ {
+		v, err := prompt(p, lastErr)
+		if err != nil {
+			return "", fmt.Errorf("prompting for %s: %w", p.Name, err)
+		}
+		if v == "" {
+			return "", nil
+		}
+		if lastErr = check(p, v); lastErr == nil {
+			return v, nil
+		}
+	}
+	return "", lastErr
+}
@@ -0,0 +1,188 @@
+package formula
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestParamCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   Param
+		value   string
+		wantErr string
+	}{
+		{"string ok", Param{Name: "s"}, "anything", ""},
+		{"int ok", Param{Name: "n", Type: ParamInt}, "42", ""},
+		{"number alias", Param{Name: "n", Type: "number"}, "x", "not an integer"},
+		{"int below min", Param{Name: "n", Type: ParamInt, Min: intPtr(1)}, "0", "less than the minimum 1"},
+		{"int above max", Param{Name: "n", Type: ParamInt, Max: intPtr(5)}, "6", "greater than the maximum 5"},
+		{"bool ok", Param{Name: "b", Type: ParamBool}, "true", ""},
+		{"bool bad", Param{Name: "b", Type: ParamBool}, "yes", "not a boolean"},
+		{"enum ok", Param{Name: "e", Type: ParamEnum, Choices: []string{"a", "b"}}, "b", ""},
+		{"enum bad", Param{Name: "e", Type: ParamEnum, Choices: []string{"a", "b"}}, "c", "not one of a, b"},
+		{"bead ok", Param{Name: "issue", Type: ParamBeadID}, "gt-abc12", ""},
+		{"bead bad", Param{Name: "issue", Type: ParamBeadID}, "abc12", "not a bead ID"},
+		{"rig ok", Param{Name: "r", Type: ParamRig}, "gastown", ""},
+		{"rig bad", Param{Name: "r", Type: ParamRig}, "gas/town", "not a rig name"},
+		{"pattern full match", Param{Name: "v", Pattern: `\d+\.\d+`}, "1.2", ""},
+		{"pattern partial", Param{Name: "v", Pattern: `\d+\.\d+`}, "v1.2", "does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.param.Check(tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Check(%q) = %v, want nil", tt.value, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Check(%q) = %v, want error containing %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		decl    string
+		wantErr string
+	}{
+		{"enum without choices", "[inputs.mode]\ntype = \"enum\"", "enum requires choices"},
+		{"unknown type", "[inputs.mode]\ntype = \"float\"", "unknown type"},
+		{"min on string", "[vars.name]\nmin = 1", "only apply to int"},
+		{"bad default", "[vars.count]\ntype = \"int\"\ndefault = \"many\"", "(default)"},
+		{"bad pattern", "[vars.v]\npattern = \"(\"", "invalid pattern"},
+		{"input and var", "[inputs.x]\n[vars.x]", "both an input and a var"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "formula = \"f\"\n[[steps]]\nid = \"a\"\n" + tt.decl
+			_, err := Parse([]byte(data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+const bindFormula = `
+formula = "release"
+[[steps]]
+id = "bump"
+[inputs.issue]
+type = "bead-id"
+required_unless = ["title"]
+[inputs.title]
+[vars.version]
+required = true
+pattern = '\d+\.\d+\.\d+'
+[vars.channel]
+type = "enum"
+choices = ["stable", "beta"]
+default = "stable"
+[vars.retries]
+type = "int"
+min = 0
+max = 3
+`
+
+func TestBind(t *testing.T) {
+	f, err := Parse([]byte(bindFormula))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		got, err := f.Bind(map[string]string{"version": "1.0.0", "title": "x"}, BindOptions{})
+		if err != nil {
+			t.Fatalf("Bind: %v", err)
+		}
+		if got["channel"] != "stable" {
+			t.Errorf("channel = %q, want default stable", got["channel"])
+		}
+		if _, ok := got["retries"]; ok {
+			t.Errorf("optional retries bound without a value")
+		}
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		_, err := f.Bind(map[string]string{"versoin": "1.0.0"}, BindOptions{})
+		if err == nil || !strings.Contains(err.Error(), "no input named versoin") {
+			t.Errorf("Bind error = %v, want unknown input", err)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := f.Bind(map[string]string{"version": "1.0.0", "title": "x", "retries": "9"}, BindOptions{})
+		if err == nil || !strings.Contains(err.Error(), "maximum 3") {
+			t.Errorf("Bind error = %v, want max error", err)
+		}
+	})
+
+	t.Run("lists every missing input", func(t *testing.T) {
+		_, err := f.Bind(nil, BindOptions{})
+		var missing *MissingInputsError
+		if !errors.As(err, &missing) {
+			t.Fatalf("Bind error = %v, want *MissingInputsError", err)
+		}
+		var names []string
+		for _, p := range missing.Missing {
+			names = append(names, p.Name)
+		}
+		if strings.Join(names, ",") != "issue,version" {
+			t.Errorf("missing = %v, want [issue version]", names)
+		}
+		if !strings.Contains(err.Error(), "--var") {
+			t.Errorf("error should explain how to set inputs: %v", err)
+		}
+	})
+
+	t.Run("prompts and retries", func(t *testing.T) {
+		answers := map[string][]string{
+			"issue":   {""},
+			"title":   {"Fix login"},
+			"version": {"one", "1.2.3"},
+		}
+		var problems []error
+		prompt := func(p Param, problem error) (string, error) {
+			if problem != nil {
+				problems = append(problems, problem)
+			}
+			a := answers[p.Name]
+			if len(a) == 0 {
+				t.Fatalf("unexpected prompt for %s", p.Name)
+			}
+			answers[p.Name] = a[1:]
+			return a[0], nil
+		}
+		got, err := f.Bind(nil, BindOptions{Prompt: prompt})
+		if err != nil {
+			t.Fatalf("Bind: %v", err)
+		}
+		if got["version"] != "1.2.3" {
+			t.Errorf("version = %q, want 1.2.3", got["version"])
+		}
+		if len(problems) != 1 {
+			t.Errorf("problems = %v, want one rejected answer", problems)
+		}
+	})
+
+	t.Run("check hook", func(t *testing.T) {
+		check := func(p Param, value string) error {
+			if p.Type == ParamBeadID {
+				return errors.New("no such bead")
+			}
+			return nil
+		}
+		_, err := f.Bind(map[string]string{"version": "1.0.0", "issue": "gt-abc"}, BindOptions{Check: check})
+		if err == nil || !strings.Contains(err.Error(), "no such bead") {
+			t.Errorf("Bind error = %v, want check error", err)
+		}
+	})
+}
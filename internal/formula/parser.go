@@ -3,6 +3,7 @@ package formula
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -26,6 +27,15 @@ func Parse(data []byte) (*Formula, error) {
 	// Infer type from content if not explicitly set
 	f.inferType()
 
+	// Composed formulas are incomplete until resolved; Resolve validates
+	// the merged result.
+	if f.IsComposed() {
+		if err := f.validateComposed(); err != nil {
+			return nil, err
+		}
+		return &f, nil
+	}
+
 	if err := f.Validate(); err != nil {
 		return nil, err
 	}
@@ -62,6 +72,10 @@ func (f *Formula) Validate() error {
 		return fmt.Errorf("invalid formula type %q (must be convoy, workflow, expansion, or aspect)", f.Type)
 	}
 
+	if err := f.validateParams(); err != nil {
+		return err
+	}
+
 	// Type-specific validation
 	switch f.Type {
 	case TypeConvoy:
@@ -137,9 +151,56 @@ func (f *Formula) validateWorkflow() error {
 		return err
 	}
 
+	for _, step := range f.Steps {
+		if err := f.validateStepPolicy(step); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// validateStepPolicy checks a step's timeout, retry and when settings.
+func (f *Formula) validateStepPolicy(step Step) error {
+	if step.Timeout != "" {
+		if d, err := time.ParseDuration(step.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("step %q: invalid timeout %q (use a duration like 30m)", step.ID, step.Timeout)
+		}
+	}
+	if step.MaxAttempts < 0 {
+		return fmt.Errorf("step %q: max_attempts must be positive", step.ID)
+	}
+	switch step.OnFailure {
+	case "", OnFailureRetry, OnFailureSkip, OnFailureEscalate, OnFailureAbort:
+	default:
+		return fmt.Errorf("step %q: invalid on_failure %q (must be retry, skip, escalate, or abort)", step.ID, step.OnFailure)
+	}
+	if step.When != "" {
+		return f.validateCondition(step, f.upstreamSteps(step.ID))
+	}
+	return nil
+}
+
+// upstreamSteps returns every step id transitively needs.
+func (f *Formula) upstreamSteps(id string) map[string]bool {
+	upstream := make(map[string]bool)
+	var visit func(id string)
+	visit = func(id string) {
+		step := f.GetStep(id)
+		if step == nil {
+			return
+		}
+		for _, need := range step.Needs {
+			if !upstream[need] {
+				upstream[need] = true
+				visit(need)
+			}
+		}
+	}
+	visit(id)
+	return upstream
+}
+
 func (f *Formula) validateExpansion() error {
 	if len(f.Template) == 0 {
 		return fmt.Errorf("expansion formula requires at least one template")
@@ -430,6 +491,36 @@ func (f *Formula) ParallelReadySteps(completed map[string]bool) (parallel []stri
 	return nil, ""
 }
 
+// Waves returns steps in the batches ParallelReadySteps releases them,
+// assuming each batch completes before the next starts. The first wave is
+// the work that can be dispatched immediately.
+func (f *Formula) Waves() [][]string {
+	return f.WavesAfter(nil)
+}
+
+// WavesAfter is like Waves, treating the steps in done as already
+// complete (for example, steps skipped by their when condition).
+func (f *Formula) WavesAfter(done map[string]bool) [][]string {
+	completed := make(map[string]bool, len(done))
+	for id := range done {
+		completed[id] = true
+	}
+	var waves [][]string
+	for {
+		wave, sequential := f.ParallelReadySteps(completed)
+		if sequential != "" {
+			wave = []string{sequential}
+		}
+		if len(wave) == 0 {
+			return waves
+		}
+		for _, id := range wave {
+			completed[id] = true
+		}
+		waves = append(waves, wave)
+	}
+}
+
 // GetLeg returns a leg by ID, or nil if not found.
 func (f *Formula) GetLeg(id string) *Leg {
 	for i := range f.Legs {
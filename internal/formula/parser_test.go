@@ -353,3 +353,47 @@ title = "Leg 3"
 		t.Errorf("ReadySteps({leg1}) = %v, want 2 legs", ready)
 	}
 }
+
+func TestWaves(t *testing.T) {
+	data := []byte(`
+formula = "test"
+type = "workflow"
+version = 1
+[[steps]]
+id = "setup"
+title = "Setup"
+[[steps]]
+id = "lint"
+title = "Lint"
+needs = ["setup"]
+parallel = true
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["setup"]
+parallel = true
+[[steps]]
+id = "ship"
+title = "Ship"
+needs = ["lint", "test"]
+`)
+
+	f, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	waves := f.Waves()
+	if len(waves) != 3 {
+		t.Fatalf("Waves() = %v, want 3 waves", waves)
+	}
+	if len(waves[0]) != 1 || waves[0][0] != "setup" {
+		t.Errorf("wave 0 = %v, want [setup]", waves[0])
+	}
+	if len(waves[1]) != 2 {
+		t.Errorf("wave 1 = %v, want [lint test]", waves[1])
+	}
+	if len(waves[2]) != 1 || waves[2][0] != "ship" {
+		t.Errorf("wave 2 = %v, want [ship]", waves[2])
+	}
+}
@@ -0,0 +1,35 @@
+package formula
+
+// Step failure policies (Step.OnFailure).
+const (
+	OnFailureRetry    = "retry"    // Run the step again
+	OnFailureSkip     = "skip"     // Close the step and carry on with its dependents
+	OnFailureEscalate = "escalate" // Block the step and escalate to a human
+	OnFailureAbort    = "abort"    // Block the step and stop the molecule
+)
+
+// DefaultRetryAttempts is how many attempts on_failure = "retry" allows
+// when max_attempts is not set.
+const DefaultRetryAttempts = 3
+
+// FailureAction decides what to do after a step's attempt number attempts
+// (counting from 1) fails. While attempts remain it returns OnFailureRetry;
+// after that it returns the step's policy, with retry and the default
+// falling back to OnFailureAbort. A step without max_attempts gets one
+// attempt, or DefaultRetryAttempts if its policy is retry.
+func FailureAction(onFailure string, maxAttempts, attempts int) string {
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+		if onFailure == OnFailureRetry {
+			maxAttempts = DefaultRetryAttempts
+		}
+	}
+	if attempts < maxAttempts {
+		return OnFailureRetry
+	}
+	switch onFailure {
+	case OnFailureSkip, OnFailureEscalate:
+		return onFailure
+	}
+	return OnFailureAbort
+}
@@ -30,6 +30,10 @@ type Formula struct {
 	Type        FormulaType `toml:"type"`
 	Version     int         `toml:"version"`
 
+	// Composition, flattened away by Resolve.
+	Extends []string  `toml:"extends,omitempty"` // Parent formulas, merged in order
+	Include []Include `toml:"include,omitempty"` // Step libraries spliced into the steps
+
 	// Convoy-specific
 	Inputs    map[string]Input `toml:"inputs"`
 	Prompts   map[string]string `toml:"prompts"`
@@ -63,6 +67,12 @@ type Input struct {
 	Required       bool     `toml:"required"`
 	RequiredUnless []string `toml:"required_unless"`
 	Default        string   `toml:"default"`
+
+	// Validation rules; see Param.Check.
+	Pattern string   `toml:"pattern,omitempty"` // Regexp the whole value must match
+	Min     *int     `toml:"min,omitempty"`     // Bounds for int inputs
+	Max     *int     `toml:"max,omitempty"`
+	Choices []string `toml:"choices,omitempty"` // Allowed values (required for enum)
 }
 
 // Output configures where formula outputs are written.
@@ -93,7 +103,26 @@ type Step struct {
 	Title       string   `toml:"title"`
 	Description string   `toml:"description"`
 	Needs       []string `toml:"needs"`
-	Parallel    bool     `toml:"parallel"` // If true, this step can run concurrently with other parallel steps that share the same needs
+	Parallel    bool     `toml:"parallel,omitempty"` // If true, this step can run concurrently with other parallel steps that share the same needs
+
+	// Before and After insert a step into an inherited workflow next to the
+	// named step (see Resolve). At most one may be set.
+	Before string `toml:"before,omitempty"`
+	After  string `toml:"after,omitempty"`
+
+	// Execution policy, enforced by gt mol step done.
+	Timeout     string `toml:"timeout,omitempty"`      // Max duration of one attempt, e.g. "30m"
+	MaxAttempts int    `toml:"max_attempts,omitempty"` // Attempts before on_failure applies
+	OnFailure   string `toml:"on_failure,omitempty"`   // retry, skip, escalate or abort (default)
+	When        string `toml:"when,omitempty"`         // Condition (see Condition); skipped when false
+}
+
+// Include pulls steps from another workflow formula into this one.
+type Include struct {
+	Formula string   `toml:"formula"`         // Formula to take steps from
+	Steps   []string `toml:"steps,omitempty"` // Step IDs to take (default: all)
+	Before  string   `toml:"before,omitempty"`
+	After   string   `toml:"after,omitempty"`
 }
 
 // Template represents a template step in an expansion formula.
@@ -107,8 +136,15 @@ type Template struct {
 // Var represents a variable definition for formulas.
 type Var struct {
 	Description string `toml:"description"`
-	Required    bool   `toml:"required"`
-	Default     string `toml:"default"`
+	Type        string `toml:"type,omitempty"`
+	Required    bool   `toml:"required,omitempty"`
+	Default     string `toml:"default,omitempty"`
+
+	// Validation rules, as for Input.
+	Pattern string   `toml:"pattern,omitempty"`
+	Min     *int     `toml:"min,omitempty"`
+	Max     *int     `toml:"max,omitempty"`
+	Choices []string `toml:"choices,omitempty"`
 }
 
 // IsValid returns true if the formula type is recognized.
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -1036,6 +1037,39 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// FileStat is the per-file line count from a diff.
+type FileStat struct {
+	Path    string
+	Added   int
+	Deleted int
+}
+
+// DiffStat returns per-file added/deleted line counts for the changes on
+// branch since it diverged from base (base...branch). Binary files are
+// reported with zero counts.
+func (g *Git) DiffStat(base, branch string) ([]FileStat, error) {
+	out, err := g.run("diff", "--numstat", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+
+	var stats []FileStat
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		// Binary files show "-" for both counts; Atoi failure leaves 0.
+		added, _ := strconv.Atoi(parts[0])
+		deleted, _ := strconv.Atoi(parts[1])
+		stats = append(stats, FileStat{Path: parts[2], Added: added, Deleted: deleted})
+	}
+	return stats, nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
@@ -298,6 +298,47 @@ func TestRev(t *testing.T) {
 	}
 }
 
+func TestDiffStat(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Changed\nmore\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "new.txt"), []byte("a\nb\nc\n"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	_ = g.Add(".")
+	if err := g.Commit("feature"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	stats, err := g.DiffStat(base, "feature")
+	if err != nil {
+		t.Fatalf("DiffStat: %v", err)
+	}
+	got := make(map[string]FileStat)
+	for _, s := range stats {
+		got[s.Path] = s
+	}
+	if s := got["README.md"]; s.Added != 2 || s.Deleted != 1 {
+		t.Errorf("README.md = %+v, want +2 -1", s)
+	}
+	if s := got["new.txt"]; s.Added != 3 || s.Deleted != 0 {
+		t.Errorf("new.txt = %+v, want +3 -0", s)
+	}
+}
+
 func TestFetchBranch(t *testing.T) {
 	// Create a "remote" repo
 	remoteDir := t.TempDir()
@@ -0,0 +1,113 @@
+package headless
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// detachKey ends an attach without stopping the session (Ctrl-\).
+const detachKey = 0x1c
+
+// AttachSession connects the terminal to a session: its scrollback is
+// replayed, then input and output flow until the session exits or the
+// user detaches with Ctrl-\.
+func (c *Client) AttachSession(session string) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("attaching to %s: stdin is not a terminal", session)
+	}
+	cols, rows, err := term.GetSize(fd)
+	if err != nil {
+		cols, rows = DefaultCols, DefaultRows
+	}
+
+	conn, err := net.DialTimeout("unix", c.socketPath, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoSupervisor, err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(request{Op: opAttach, Session: session, Rows: rows, Cols: cols}); err != nil {
+		return fmt.Errorf("headless attach: %w", err)
+	}
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return fmt.Errorf("headless attach: reading response: %w", err)
+	}
+	var resp response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return fmt.Errorf("headless attach: %w", err)
+	}
+	if err := resp.err(opAttach); err != nil {
+		return err
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("setting raw mode: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	var writeMu sync.Mutex
+	send := func(kind byte, payload []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return writeFrame(conn, kind, payload)
+	}
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer func() {
+		signal.Stop(resized)
+		close(resized)
+	}()
+	go func() {
+		for range resized {
+			if cols, rows, err := term.GetSize(fd); err == nil {
+				_ = send(frameResize, resizePayload(rows, cols))
+			}
+		}
+	}()
+
+	// The stdin reader may outlive the attach, blocked in Read; gt exits
+	// right after attaching, so it is not worth interrupting.
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if n > 0 {
+				input := buf[:n]
+				i := bytes.IndexByte(input, detachKey)
+				if i >= 0 {
+					input = input[:i]
+				}
+				if len(input) > 0 {
+					if err := send(frameData, input); err != nil {
+						return
+					}
+				}
+				if i >= 0 {
+					_ = conn.Close()
+					return
+				}
+			}
+			if err != nil {
+				_ = conn.Close()
+				return
+			}
+		}
+	}()
+
+	_, _ = io.Copy(os.Stdout, r)
+	return nil
+}
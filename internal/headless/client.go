@@ -0,0 +1,311 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// ErrNoSupervisor is returned when no supervisor is serving the town,
+// the headless counterpart of tmux.ErrNoServer.
+var ErrNoSupervisor = errors.New("headless session supervisor not running (start the daemon with 'gt daemon start')")
+
+const (
+	dialTimeout = 2 * time.Second
+	// callTimeout covers the slowest request, a kill that escalates to SIGKILL.
+	callTimeout = 3*killGrace + 5*time.Second
+)
+
+// nudgeLocks serializes nudges to the same session, as tmux.Tmux does.
+var nudgeLocks sync.Map // map[string]*sync.Mutex
+
+// Client drives sessions hosted by a town's Supervisor. Its methods mirror
+// tmux.Tmux so it can stand in as the town's session backend.
+type Client struct {
+	socketPath string
+}
+
+// NewClient returns a client for the supervisor serving townRoot.
+func NewClient(townRoot string) *Client {
+	return &Client{socketPath: SocketPath(townRoot)}
+}
+
+// call sends one request and reads its response.
+func (c *Client) call(req request) (*response, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoSupervisor, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(callTimeout))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("headless %s: %w", req.Op, err)
+	}
+	var resp response
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("headless %s: reading response: %w", req.Op, err)
+	}
+	if err := resp.err(req.Op); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// IsAvailable reports whether the supervisor is reachable.
+func (c *Client) IsAvailable() bool {
+	conn, err := net.DialTimeout("unix", c.socketPath, dialTimeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// HasSession checks if a session exists.
+func (c *Client) HasSession(name string) (bool, error) {
+	resp, err := c.call(request{Op: opHas, Session: name})
+	if err != nil {
+		if errors.Is(err, ErrNoSupervisor) {
+			return false, nil
+		}
+		return false, err
+	}
+	return resp.Exists, nil
+}
+
+// ListSessions returns all session names.
+func (c *Client) ListSessions() ([]string, error) {
+	resp, err := c.call(request{Op: opList})
+	if err != nil {
+		if errors.Is(err, ErrNoSupervisor) {
+			return nil, nil // No supervisor = no sessions
+		}
+		return nil, err
+	}
+	return resp.Sessions, nil
+}
+
+// GetSessionInfo returns detailed information about a session.
+func (c *Client) GetSessionInfo(name string) (*tmux.SessionInfo, error) {
+	resp, err := c.call(request{Op: opInfo, Session: name})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Info, nil
+}
+
+// NewSession starts a session running an interactive shell.
+func (c *Client) NewSession(name, workDir string) error {
+	_, err := c.call(request{Op: opNew, Session: name, WorkDir: workDir})
+	return err
+}
+
+// NewSessionWithCommand starts a session whose initial process is command.
+func (c *Client) NewSessionWithCommand(name, workDir, command string) error {
+	_, err := c.call(request{Op: opNew, Session: name, WorkDir: workDir, Command: command})
+	return err
+}
+
+// EnsureSessionFresh ensures a session is available and healthy, killing a
+// zombie session (agent no longer running) and starting a fresh shell.
+func (c *Client) EnsureSessionFresh(name, workDir string) error {
+	exists, err := c.HasSession(name)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if exists {
+		if c.IsAgentRunning(name) {
+			return nil
+		}
+		if err := c.KillSessionWithProcesses(name); err != nil {
+			return fmt.Errorf("killing zombie session: %w", err)
+		}
+	}
+	return c.NewSession(name, workDir)
+}
+
+// KillSession hangs up a session's terminal and waits for it to exit.
+func (c *Client) KillSession(name string) error {
+	_, err := c.call(request{Op: opKill, Session: name})
+	return err
+}
+
+// KillSessionWithProcesses terminates a session's whole process tree.
+func (c *Client) KillSessionWithProcesses(name string) error {
+	_, err := c.call(request{Op: opKill, Session: name, Force: true})
+	return err
+}
+
+// send writes raw input to a session's terminal.
+func (c *Client) send(session, data string) error {
+	_, err := c.call(request{Op: opSend, Session: session, Data: data})
+	return err
+}
+
+// SendKeys sends text to a session and presses Enter.
+func (c *Client) SendKeys(session, keys string) error {
+	return c.SendKeysDebounced(session, keys, constants.DefaultDebounceMs)
+}
+
+// SendKeysDebounced sends text, waits debounceMs, then presses Enter.
+func (c *Client) SendKeysDebounced(session, keys string, debounceMs int) error {
+	if err := c.send(session, keys); err != nil {
+		return err
+	}
+	if debounceMs > 0 {
+		time.Sleep(time.Duration(debounceMs) * time.Millisecond)
+	}
+	return c.send(session, keyInput("Enter"))
+}
+
+// SendKeysRaw sends a tmux key name (C-c, Down, Enter) or literal text
+// without pressing Enter.
+func (c *Client) SendKeysRaw(session, keys string) error {
+	return c.send(session, keyInput(keys))
+}
+
+// NudgeSession sends a message to an agent the same way tmux.Tmux does:
+// text, a pause for the paste, Escape (for vim mode), then Enter.
+func (c *Client) NudgeSession(session, message string) error {
+	lock, _ := nudgeLocks.LoadOrStore(session, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	if err := c.send(session, message); err != nil {
+		return err
+	}
+	time.Sleep(500 * time.Millisecond)
+	_ = c.send(session, keyInput("Escape"))
+	time.Sleep(100 * time.Millisecond)
+	return c.send(session, keyInput("Enter"))
+}
+
+// AcceptBypassPermissionsWarning dismisses Claude's bypass permissions
+// dialog if it is showing.
+func (c *Client) AcceptBypassPermissionsWarning(session string) error {
+	time.Sleep(1 * time.Second)
+	content, err := c.CapturePane(session, 30)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(content, "Bypass Permissions mode") {
+		return nil
+	}
+	if err := c.SendKeysRaw(session, "Down"); err != nil {
+		return err
+	}
+	time.Sleep(200 * time.Millisecond)
+	return c.SendKeysRaw(session, "Enter")
+}
+
+// CapturePane returns the last lines of a session's output as plain text.
+func (c *Client) CapturePane(session string, lines int) (string, error) {
+	resp, err := c.call(request{Op: opCapture, Session: session, Lines: lines})
+	if err != nil {
+		return "", err
+	}
+	return resp.Output, nil
+}
+
+// SetEnvironment records a variable on the session. Like tmux, it does not
+// change the environment of processes already running.
+func (c *Client) SetEnvironment(session, key, value string) error {
+	_, err := c.call(request{Op: opSetEnv, Session: session, Key: key, Value: value})
+	return err
+}
+
+// GetEnvironment returns a variable recorded on the session.
+func (c *Client) GetEnvironment(session, key string) (string, error) {
+	resp, err := c.call(request{Op: opGetEnv, Session: session, Key: key})
+	if err != nil {
+		return "", err
+	}
+	return resp.Value, nil
+}
+
+// GetPaneCommand returns the session's foreground command (bash, node, ...).
+func (c *Client) GetPaneCommand(session string) (string, error) {
+	resp, err := c.call(request{Op: opCommand, Session: session})
+	if err != nil {
+		return "", err
+	}
+	return resp.Value, nil
+}
+
+// IsAgentRunning checks if an agent appears to be running in the session.
+// With expectedPaneCommands, the foreground command must be one of them;
+// otherwise any non-shell command counts.
+func (c *Client) IsAgentRunning(session string, expectedPaneCommands ...string) bool {
+	cmd, err := c.GetPaneCommand(session)
+	if err != nil {
+		return false
+	}
+	if len(expectedPaneCommands) > 0 {
+		for _, expected := range expectedPaneCommands {
+			if expected != "" && cmd == expected {
+				return true
+			}
+		}
+		return false
+	}
+	for _, shell := range constants.SupportedShells {
+		if cmd == shell {
+			return false
+		}
+	}
+	return cmd != ""
+}
+
+// IsAgentAlive checks for the session's agent process, using GT_AGENT to
+// pick the process names as tmux.Tmux does.
+func (c *Client) IsAgentAlive(session string) bool {
+	agentName, _ := c.GetEnvironment(session, "GT_AGENT")
+	resp, err := c.call(request{Op: opRunning, Session: session, Names: config.GetProcessNames(agentName)})
+	return err == nil && resp.Running
+}
+
+// WaitForCommand polls until the session's foreground command is not one
+// of excludeCommands.
+func (c *Client) WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		cmd, err := c.GetPaneCommand(session)
+		if err == nil && !contains(excludeCommands, cmd) {
+			return nil
+		}
+		time.Sleep(constants.PollInterval)
+	}
+	return fmt.Errorf("timeout waiting for command (still running excluded command)")
+}
+
+// ConfigureGasTownSession is a no-op: headless sessions have no status bar
+// or key bindings to theme.
+func (c *Client) ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error {
+	return nil
+}
+
+// SetPaneDiedHook makes the supervisor log a crash for agentID (gt log
+// crash) if the session's process exits without being killed.
+func (c *Client) SetPaneDiedHook(session, agentID string) error {
+	_, err := c.call(request{Op: opHook, Session: session, Value: agentID})
+	return err
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
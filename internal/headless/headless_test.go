@@ -0,0 +1,97 @@
+package headless
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRingKeepsNewestBytes(t *testing.T) {
+	r := NewRing(8)
+	_, _ = r.Write([]byte("abc"))
+	if got := string(r.Bytes()); got != "abc" {
+		t.Errorf("Bytes() = %q, want abc", got)
+	}
+	_, _ = r.Write([]byte("defgh"))
+	if got := string(r.Bytes()); got != "abcdefgh" {
+		t.Errorf("Bytes() at capacity = %q, want abcdefgh", got)
+	}
+	_, _ = r.Write([]byte("ij"))
+	if got := string(r.Bytes()); got != "cdefghij" {
+		t.Errorf("Bytes() after wrap = %q, want cdefghij", got)
+	}
+	_, _ = r.Write([]byte("0123456789"))
+	if got := string(r.Bytes()); got != "23456789" {
+		t.Errorf("Bytes() after oversized write = %q, want 23456789", got)
+	}
+}
+
+func TestPlainLines(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{"crlf", "one\r\ntwo\r\n", []string{"one", "two"}},
+		{"colors", "\x1b[1;32mok\x1b[0m done\n", []string{"ok done"}},
+		{"overwrite", "50%\r100%\n", []string{"100%"}},
+		{"erase line", "stale text\r\x1b[Kfresh\n", []string{"fresh"}},
+		{"osc title", "\x1b]0;claude\x07prompt>", []string{"prompt>"}},
+		{"charset", "\x1b(Bplain\n", []string{"plain"}},
+		{"backspace", "ab\bc\n", []string{"ac"}},
+		{"tab", "a\tb\n", []string{"a       b"}},
+		{"utf8", "✓ Bypass Permissions mode\n", []string{"✓ Bypass Permissions mode"}},
+	}
+	for _, tt := range tests {
+		got := plainLines([]byte(tt.in))
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: plainLines(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+
+	lines := lastLines([]string{"a", "b", "c", "", ""}, 2)
+	if strings.Join(lines, ",") != "b,c" {
+		t.Errorf("lastLines = %v, want b,c", lines)
+	}
+}
+
+func TestKeyInput(t *testing.T) {
+	tests := map[string]string{
+		"Enter":  "\r",
+		"Down":   "\x1b[B",
+		"Escape": "\x1b",
+		"C-c":    "\x03",
+		"C-u":    "\x15",
+		"y":      "y",
+		"hello":  "hello",
+	}
+	for keys, want := range tests {
+		if got := keyInput(keys); got != want {
+			t.Errorf("keyInput(%q) = %q, want %q", keys, got, want)
+		}
+	}
+}
+
+func TestFrames(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeFrame(&buf, frameData, []byte("ls\r")); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFrame(&buf, frameResize, resizePayload(40, 120)); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(&buf)
+	kind, payload, err := readFrame(r)
+	if err != nil || kind != frameData || string(payload) != "ls\r" {
+		t.Fatalf("first frame = %c %q %v", kind, payload, err)
+	}
+	kind, payload, err = readFrame(r)
+	if err != nil || kind != frameResize {
+		t.Fatalf("second frame = %c %v", kind, err)
+	}
+	if rows, cols, ok := parseResize(payload); !ok || rows != 40 || cols != 120 {
+		t.Errorf("resize = %dx%d (%v), want 40x120", rows, cols, ok)
+	}
+}
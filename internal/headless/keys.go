@@ -0,0 +1,41 @@
+package headless
+
+// namedKeys maps the tmux key names Gas Town sends to terminal input.
+var namedKeys = map[string]string{
+	"Enter":  "\r",
+	"Escape": "\x1b",
+	"Tab":    "\t",
+	"BSpace": "\x7f",
+	"Space":  " ",
+	"Up":     "\x1b[A",
+	"Down":   "\x1b[B",
+	"Right":  "\x1b[C",
+	"Left":   "\x1b[D",
+	"Home":   "\x1b[H",
+	"End":    "\x1b[F",
+	"PPage":  "\x1b[5~",
+	"NPage":  "\x1b[6~",
+}
+
+// keyInput translates a tmux send-keys argument (Enter, Down, C-c) into the
+// bytes a terminal would send. Anything else is sent literally, as tmux
+// does for strings that aren't key names.
+func keyInput(keys string) string {
+	if s, ok := namedKeys[keys]; ok {
+		return s
+	}
+	if len(keys) == 3 && (keys[:2] == "C-" || keys[:2] == "c-") {
+		c := keys[2]
+		switch {
+		case c >= 'a' && c <= 'z':
+			return string(rune(c - 'a' + 1))
+		case c >= 'A' && c <= 'Z':
+			return string(rune(c - 'A' + 1))
+		case c == '[':
+			return "\x1b"
+		case c == '\\':
+			return "\x1c"
+		}
+	}
+	return keys
+}
@@ -0,0 +1,109 @@
+//go:build unix
+
+package headless
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// startInPTY starts cmd as a session leader whose controlling terminal is a
+// new pseudo-terminal, and returns the terminal's master.
+func startInPTY(cmd *exec.Cmd, rows, cols int) (*os.File, error) {
+	master, tty, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+	defer tty.Close()
+
+	if err := setSize(master, rows, cols); err != nil {
+		_ = master.Close()
+		return nil, err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err := cmd.Start(); err != nil {
+		_ = master.Close()
+		return nil, err
+	}
+	return master, nil
+}
+
+// control runs fn with the file's descriptor without switching the file to
+// blocking mode, so reads on it stay interruptible by Close.
+func control(f *os.File, fn func(fd int) error) error {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var opErr error
+	if err := rc.Control(func(fd uintptr) { opErr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return opErr
+}
+
+// setSize sets the terminal's window size, which also signals SIGWINCH to
+// its foreground process group.
+func setSize(master *os.File, rows, cols int) error {
+	return control(master, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Row: uint16(rows), Col: uint16(cols)})
+	})
+}
+
+// foregroundPID returns the terminal's foreground process group, which is
+// what tmux reports as the pane's current command.
+func foregroundPID(master *os.File) (int, error) {
+	var pgrp int
+	err := control(master, func(fd int) error {
+		var err error
+		pgrp, err = unix.IoctlGetInt(fd, unix.TIOCGPGRP)
+		return err
+	})
+	return pgrp, err
+}
+
+// signalTree signals a session leader's process group and all of its
+// descendants, deepest first, so children that moved to their own process
+// group (shell jobs) are reached too.
+func signalTree(pid int, sig syscall.Signal) {
+	for _, child := range descendants(pid) {
+		_ = syscall.Kill(child, sig)
+	}
+	_ = syscall.Kill(-pid, sig)
+}
+
+// hangup sends SIGHUP to a session leader's process group, as the kernel
+// does when a terminal goes away.
+func hangup(pid int) {
+	_ = syscall.Kill(-pid, syscall.SIGHUP)
+}
+
+// descendants returns the descendants of pid, deepest first.
+func descendants(pid int) []int {
+	out, err := exec.Command("pgrep", "-P", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return nil
+	}
+	var result []int
+	for _, field := range strings.Fields(string(out)) {
+		child, err := strconv.Atoi(field)
+		if err != nil {
+			continue
+		}
+		result = append(result, descendants(child)...)
+		result = append(result, child)
+	}
+	return result
+}
+
+// notifyResize relays terminal resizes to ch.
+func notifyResize(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH)
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package headless
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// Windows has no pseudo-terminals the supervisor can drive; these stubs
+// keep the package building so the tmux backend remains usable.
+
+func startInPTY(cmd *exec.Cmd, rows, cols int) (*os.File, error) {
+	return nil, ErrUnsupported
+}
+
+func setSize(master *os.File, rows, cols int) error {
+	return ErrUnsupported
+}
+
+func foregroundPID(master *os.File) (int, error) {
+	return 0, ErrUnsupported
+}
+
+func signalTree(pid int, sig syscall.Signal) {}
+
+func hangup(pid int) {}
+
+func descendants(pid int) []int {
+	return nil
+}
+
+func notifyResize(ch chan<- os.Signal) {}
@@ -0,0 +1,128 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// SocketPath returns the path of the supervisor socket for a town.
+func SocketPath(townRoot string) string {
+	return filepath.Join(townRoot, "daemon", "sessions.sock")
+}
+
+// Operations understood by the supervisor. Each connection carries one
+// request line and one response line, except attach, which switches the
+// connection to streaming after the response.
+const (
+	opNew     = "new"     // start a session (Command empty = interactive shell)
+	opKill    = "kill"    // stop a session (Force = whole process tree)
+	opHas     = "has"     // report whether a session exists
+	opList    = "list"    // list session names
+	opInfo    = "info"    // session metadata in tmux.SessionInfo form
+	opSend    = "send"    // write Data to the session's terminal
+	opCapture = "capture" // last Lines lines of scrollback as plain text
+	opSetEnv  = "setenv"  // set session environment Key=Value
+	opGetEnv  = "getenv"  // get session environment Key
+	opCommand = "command" // name of the terminal's foreground process
+	opRunning = "running" // whether one of Names runs in the session
+	opHook    = "hook"    // log a crash for agent Value when the session dies
+	opAttach  = "attach"  // stream output; read input and resize frames
+)
+
+// Response error codes that map back to the tmux package's errors, so
+// callers can treat both backends alike.
+const (
+	codeNotFound = "not_found"
+	codeExists   = "exists"
+)
+
+type request struct {
+	Op      string   `json:"op"`
+	Session string   `json:"session,omitempty"`
+	WorkDir string   `json:"work_dir,omitempty"`
+	Command string   `json:"command,omitempty"`
+	Data    string   `json:"data,omitempty"`
+	Key     string   `json:"key,omitempty"`
+	Value   string   `json:"value,omitempty"`
+	Lines   int      `json:"lines,omitempty"`
+	Names   []string `json:"names,omitempty"`
+	Force   bool     `json:"force,omitempty"`
+	Rows    int      `json:"rows,omitempty"`
+	Cols    int      `json:"cols,omitempty"`
+}
+
+type response struct {
+	Error    string            `json:"error,omitempty"`
+	Code     string            `json:"code,omitempty"`
+	Exists   bool              `json:"exists,omitempty"`
+	Running  bool              `json:"running,omitempty"`
+	Sessions []string          `json:"sessions,omitempty"`
+	Info     *tmux.SessionInfo `json:"info,omitempty"`
+	Output   string            `json:"output,omitempty"`
+	Value    string            `json:"value,omitempty"`
+}
+
+// err converts an error response back into an error.
+func (r *response) err(op string) error {
+	switch {
+	case r.Error == "":
+		return nil
+	case r.Code == codeNotFound:
+		return tmux.ErrSessionNotFound
+	case r.Code == codeExists:
+		return tmux.ErrSessionExists
+	}
+	return fmt.Errorf("headless %s: %s", op, r.Error)
+}
+
+// Attach input frames: a kind byte, a big-endian uint32 length, then the
+// payload. Output flows back to the client as raw terminal bytes.
+const (
+	frameData   byte = 'd' // keyboard input
+	frameResize byte = 'r' // uint16 rows, uint16 cols
+
+	maxFrame = 64 << 10
+)
+
+func writeFrame(w io.Writer, kind byte, payload []byte) error {
+	header := make([]byte, 5, 5+len(payload))
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	_, err := w.Write(append(header, payload...))
+	return err
+}
+
+func readFrame(r *bufio.Reader) (byte, []byte, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > maxFrame {
+		return 0, nil, fmt.Errorf("frame too large (%d bytes)", n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+func resizePayload(rows, cols int) []byte {
+	p := make([]byte, 4)
+	binary.BigEndian.PutUint16(p, uint16(rows))
+	binary.BigEndian.PutUint16(p[2:], uint16(cols))
+	return p
+}
+
+func parseResize(p []byte) (rows, cols int, ok bool) {
+	if len(p) != 4 {
+		return 0, 0, false
+	}
+	return int(binary.BigEndian.Uint16(p)), int(binary.BigEndian.Uint16(p[2:])), true
+}
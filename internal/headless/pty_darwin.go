@@ -0,0 +1,53 @@
+//go:build darwin
+
+package headless
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY allocates a pseudo-terminal and returns its master and the
+// terminal the child will use.
+func openPTY() (master, tty *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("opening /dev/ptmx: %w", err)
+	}
+
+	var name string
+	err = control(master, func(fd int) error {
+		if err := unix.IoctlSetInt(fd, unix.TIOCPTYGRANT, 0); err != nil {
+			return fmt.Errorf("granting pty: %w", err)
+		}
+		if err := unix.IoctlSetInt(fd, unix.TIOCPTYUNLK, 0); err != nil {
+			return fmt.Errorf("unlocking pty: %w", err)
+		}
+		buf := make([]byte, 128)
+		_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), uintptr(unix.TIOCPTYGNAME), uintptr(unsafe.Pointer(&buf[0])))
+		if errno != 0 {
+			return fmt.Errorf("getting pty name: %w", errno)
+		}
+		if i := bytes.IndexByte(buf, 0); i >= 0 {
+			buf = buf[:i]
+		}
+		name = string(buf)
+		return nil
+	})
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+
+	tty, err = os.OpenFile(name, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("opening pty: %w", err)
+	}
+	return master, tty, nil
+}
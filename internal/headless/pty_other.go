@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package headless
+
+import "os"
+
+// openPTY is only implemented for Linux and macOS.
+func openPTY() (master, tty *os.File, err error) {
+	return nil, nil, ErrUnsupported
+}
@@ -0,0 +1,157 @@
+package headless
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Ring is a fixed-size byte buffer that keeps the most recent output.
+// It is not safe for concurrent use; sessions guard it with their mutex.
+type Ring struct {
+	buf  []byte
+	pos  int // next write position
+	full bool
+}
+
+// NewRing returns a ring holding up to size bytes.
+func NewRing(size int) *Ring {
+	return &Ring{buf: make([]byte, size)}
+}
+
+// Write appends p, overwriting the oldest bytes once the ring is full.
+func (r *Ring) Write(p []byte) (int, error) {
+	n := len(p)
+	size := len(r.buf)
+	if n >= size {
+		copy(r.buf, p[n-size:])
+		r.pos = 0
+		r.full = true
+		return n, nil
+	}
+	c := copy(r.buf[r.pos:], p)
+	if c < n {
+		copy(r.buf, p[c:])
+	}
+	if r.pos+n >= size {
+		r.full = true
+	}
+	r.pos = (r.pos + n) % size
+	return n, nil
+}
+
+// Bytes returns a copy of the buffered output, oldest first.
+func (r *Ring) Bytes() []byte {
+	if !r.full {
+		return append([]byte(nil), r.buf[:r.pos]...)
+	}
+	out := make([]byte, 0, len(r.buf))
+	out = append(out, r.buf[r.pos:]...)
+	return append(out, r.buf[:r.pos]...)
+}
+
+// plainLines renders terminal output as lines of plain text, the headless
+// counterpart of tmux capture-pane. It is not a terminal emulator: escape
+// sequences are dropped, CR returns to the start of the line so later text
+// overwrites it, and erase-line truncates at the cursor. That is enough to
+// find prompts and dialogs in an agent's output.
+func plainLines(data []byte) []string {
+	var lines []string
+	var line []rune
+	col := 0
+
+	put := func(r rune) {
+		if col < len(line) {
+			line[col] = r
+		} else {
+			for len(line) < col {
+				line = append(line, ' ')
+			}
+			line = append(line, r)
+		}
+		col++
+	}
+
+	for i := 0; i < len(data); {
+		b := data[i]
+		switch {
+		case b == 0x1b:
+			i = skipEscape(data, i, func(final byte, params string) {
+				if final == 'K' && (params == "" || params == "0") && col < len(line) {
+					line = line[:col]
+				}
+			})
+			continue
+		case b == '\n':
+			lines = append(lines, strings.TrimRight(string(line), " "))
+			line, col = line[:0], 0
+		case b == '\r':
+			col = 0
+		case b == '\b':
+			if col > 0 {
+				col--
+			}
+		case b == '\t':
+			put(' ')
+			for col%8 != 0 {
+				put(' ')
+			}
+		case b < 0x20 || b == 0x7f:
+			// Other control characters don't print
+		default:
+			r, size := utf8.DecodeRune(data[i:])
+			put(r)
+			i += size
+			continue
+		}
+		i++
+	}
+	if len(line) > 0 {
+		lines = append(lines, strings.TrimRight(string(line), " "))
+	}
+	return lines
+}
+
+// skipEscape returns the index just past the escape sequence starting at
+// data[i]. CSI sequences are reported to csi with their final byte.
+func skipEscape(data []byte, i int, csi func(final byte, params string)) int {
+	i++ // ESC
+	if i >= len(data) {
+		return i
+	}
+	switch data[i] {
+	case '[':
+		start := i + 1
+		for i = start; i < len(data); i++ {
+			if data[i] >= 0x40 && data[i] <= 0x7e {
+				csi(data[i], string(data[start:i]))
+				return i + 1
+			}
+		}
+		return i
+	case ']', 'P', '_', '^':
+		// String sequences end with BEL or ST (ESC \)
+		for i++; i < len(data); i++ {
+			if data[i] == 0x07 {
+				return i + 1
+			}
+			if data[i] == 0x1b && i+1 < len(data) && data[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return i
+	case '(', ')', '*', '+', '#', '%':
+		return i + 2
+	}
+	return i + 1
+}
+
+// lastLines returns the last n lines of text, without trailing blank lines.
+func lastLines(lines []string, n int) []string {
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
@@ -0,0 +1,290 @@
+package headless
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Defaults for new sessions. Agents are started detached, so the terminal
+// is sized for a wide window rather than a classic 80x24.
+var (
+	DefaultRows = 50
+	DefaultCols = 200
+
+	// ScrollbackBytes is the output kept per session for capture and attach.
+	ScrollbackBytes = 1 << 20
+)
+
+// killGrace is how long a session has to exit after being signalled
+// before it is sent SIGKILL.
+const killGrace = 3 * time.Second
+
+// subscriberBuffer is how many output chunks an attached client may fall
+// behind before it is disconnected.
+const subscriberBuffer = 256
+
+// ptySession is one agent process running under a pseudo-terminal.
+type ptySession struct {
+	name    string
+	created time.Time
+	cmd     *exec.Cmd
+	pty     *os.File
+	done    chan struct{} // closed once the process has exited
+
+	mu           sync.Mutex
+	scroll       *Ring
+	env          map[string]string
+	activity     time.Time
+	lastAttached time.Time
+	subscribers  map[chan []byte]struct{}
+	agentID      string // crash-log identity set by SetPaneDiedHook
+	killed       bool   // stopped on request rather than by dying
+	exitCode     int
+}
+
+// startSession runs command (or an interactive shell) under a new PTY.
+func startSession(name, workDir, command string) (*ptySession, error) {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	cmd := exec.Command(shell)
+	if command != "" {
+		cmd = exec.Command(shell, "-c", command)
+	}
+	cmd.Dir = workDir
+	cmd.Env = sessionEnv(os.Environ())
+
+	master, err := startInPTY(cmd, DefaultRows, DefaultCols)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s := &ptySession{
+		name:        name,
+		created:     now,
+		cmd:         cmd,
+		pty:         master,
+		done:        make(chan struct{}),
+		scroll:      NewRing(ScrollbackBytes),
+		env:         make(map[string]string),
+		activity:    now,
+		subscribers: make(map[chan []byte]struct{}),
+	}
+	return s, nil
+}
+
+// sessionEnv is the supervisor's environment with a terminal type set and
+// any tmux variables inherited from the shell that started the daemon
+// removed, so agents don't believe they run inside tmux.
+func sessionEnv(environ []string) []string {
+	env := make([]string, 0, len(environ)+1)
+	for _, kv := range environ {
+		if strings.HasPrefix(kv, "TERM=") || strings.HasPrefix(kv, "TMUX=") || strings.HasPrefix(kv, "TMUX_PANE=") {
+			continue
+		}
+		env = append(env, kv)
+	}
+	return append(env, "TERM=xterm-256color")
+}
+
+// pump copies terminal output into the scrollback and to attached
+// clients until the terminal is closed.
+func (s *ptySession) pump() {
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := s.pty.Read(buf)
+		if n > 0 {
+			chunk := append([]byte(nil), buf[:n]...)
+			s.mu.Lock()
+			_, _ = s.scroll.Write(chunk)
+			s.activity = time.Now()
+			for ch := range s.subscribers {
+				select {
+				case ch <- chunk:
+				default:
+					// Too slow to keep up; drop it rather than stall the agent
+					delete(s.subscribers, ch)
+					close(ch)
+				}
+			}
+			s.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// write sends input to the terminal.
+func (s *ptySession) write(p []byte) error {
+	_, err := s.pty.Write(p)
+	return err
+}
+
+// subscribe returns a channel that receives the scrollback followed by
+// live output, until the session exits or the client falls behind.
+func (s *ptySession) subscribe() chan []byte {
+	ch := make(chan []byte, subscriberBuffer)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch <- s.scroll.Bytes()
+	s.subscribers[ch] = struct{}{}
+	s.lastAttached = time.Now()
+	return ch
+}
+
+func (s *ptySession) unsubscribe(ch chan []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscribers[ch]; ok {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+}
+
+// closeSubscribers disconnects all attached clients.
+func (s *ptySession) closeSubscribers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = make(map[chan []byte]struct{})
+}
+
+// capture returns the last n lines of output as plain text.
+func (s *ptySession) capture(n int) string {
+	s.mu.Lock()
+	data := s.scroll.Bytes()
+	s.mu.Unlock()
+	return strings.Join(lastLines(plainLines(data), n), "\n")
+}
+
+// info describes the session the way tmux.Tmux.GetSessionInfo does.
+func (s *ptySession) info() *tmux.SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := &tmux.SessionInfo{
+		Name:     s.name,
+		Windows:  1,
+		Created:  s.created.Format(time.ANSIC),
+		Attached: len(s.subscribers) > 0,
+		Activity: strconv.FormatInt(s.activity.Unix(), 10),
+	}
+	if !s.lastAttached.IsZero() {
+		info.LastAttached = strconv.FormatInt(s.lastAttached.Unix(), 10)
+	}
+	return info
+}
+
+func (s *ptySession) setEnv(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.env[key] = value
+}
+
+func (s *ptySession) getEnv(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.env[key]
+	return v, ok
+}
+
+// command returns the name of the terminal's foreground process.
+func (s *ptySession) command() (string, error) {
+	pid, err := foregroundPID(s.pty)
+	if err != nil {
+		return "", err
+	}
+	return processName(pid)
+}
+
+// runs reports whether one of names is the foreground process, or, when a
+// shell is in the foreground, one of its children.
+func (s *ptySession) runs(names []string) bool {
+	cmd, err := s.command()
+	if err != nil {
+		return false
+	}
+	for _, name := range names {
+		if cmd == name {
+			return true
+		}
+	}
+	for _, shell := range constants.SupportedShells {
+		if cmd == shell {
+			return hasChildNamed(s.cmd.Process.Pid, names)
+		}
+	}
+	return false
+}
+
+// stop signals the session and waits for it to exit, escalating to
+// SIGKILL after killGrace. Without force it hangs up the terminal, as
+// closing a tmux pane does; with force it terminates the process tree.
+func (s *ptySession) stop(force bool) error {
+	s.mu.Lock()
+	s.killed = true
+	s.mu.Unlock()
+
+	pid := s.cmd.Process.Pid
+	if force {
+		signalTree(pid, syscall.SIGTERM)
+	} else {
+		hangup(pid)
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(killGrace):
+	}
+	signalTree(pid, syscall.SIGKILL)
+	select {
+	case <-s.done:
+		return nil
+	case <-time.After(killGrace):
+		return errors.New("session did not exit after SIGKILL")
+	}
+}
+
+// processName returns the command name of a process, as ps reports it.
+func processName(pid int) (string, error) {
+	out, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Base(strings.TrimSpace(string(out))), nil
+}
+
+// hasChildNamed reports whether a direct child of pid has one of names.
+func hasChildNamed(pid int, names []string) bool {
+	out, err := exec.Command("pgrep", "-P", strconv.Itoa(pid), "-l").Output()
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		// Format: "PID name"
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, name := range names {
+			if fields[1] == name {
+				return true
+			}
+		}
+	}
+	return false
+}
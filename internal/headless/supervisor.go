@@ -0,0 +1,336 @@
+// Package headless runs agent sessions under pseudo-terminals owned by the
+// daemon, as an alternative to tmux where tmux is unavailable or flaky
+// (containers, CI).
+//
+// The daemon hosts a Supervisor, which serves a unix socket at
+// <town>/daemon/sessions.sock. Each session's output is kept in a ring
+// buffer, so it can be captured like a tmux pane and replayed to clients
+// that attach later. Client performs the same session operations as
+// tmux.Tmux against that socket.
+//
+// Sessions belong to the daemon process: stopping the daemon stops them.
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported is returned where the platform has no pseudo-terminals
+// the supervisor can drive.
+var ErrUnsupported = errors.New("headless sessions are not supported on this platform")
+
+// validSessionName matches the session names tmux.Tmux accepts from Gas Town.
+var validSessionName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// requestTimeout bounds how long a client may take to send its request.
+const requestTimeout = 10 * time.Second
+
+// Supervisor owns headless sessions and serves them on a unix socket.
+type Supervisor struct {
+	socketPath string
+	logf       func(format string, args ...interface{})
+
+	mu       sync.Mutex
+	sessions map[string]*ptySession
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewSupervisor creates a supervisor for a town. logf receives lifecycle
+// messages (the daemon passes its logger's Printf).
+func NewSupervisor(townRoot string, logf func(format string, args ...interface{})) *Supervisor {
+	return &Supervisor{
+		socketPath: SocketPath(townRoot),
+		logf:       logf,
+		sessions:   make(map[string]*ptySession),
+	}
+}
+
+// Start listens on the supervisor socket and serves requests in the
+// background. It fails if another supervisor is already serving the town.
+func (s *Supervisor) Start() error {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), 0755); err != nil {
+		return fmt.Errorf("creating socket directory: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", s.socketPath, time.Second); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("session supervisor already running at %s", s.socketPath)
+	}
+	// A socket file left by a daemon that crashed would make Listen fail
+	_ = os.Remove(s.socketPath)
+
+	ln, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.socketPath, err)
+	}
+	if err := os.Chmod(s.socketPath, 0600); err != nil {
+		_ = ln.Close()
+		return fmt.Errorf("securing %s: %w", s.socketPath, err)
+	}
+
+	s.mu.Lock()
+	s.listener = ln
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go s.serve(ln)
+	s.logf("Headless session supervisor listening on %s", s.socketPath)
+	return nil
+}
+
+// Stop closes the socket and stops every session.
+func (s *Supervisor) Stop() {
+	s.mu.Lock()
+	ln := s.listener
+	s.listener = nil
+	sessions := make([]*ptySession, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.Unlock()
+
+	if ln != nil {
+		_ = ln.Close()
+		_ = os.Remove(s.socketPath)
+	}
+	s.wg.Wait()
+
+	var wg sync.WaitGroup
+	for _, sess := range sessions {
+		wg.Add(1)
+		go func(sess *ptySession) {
+			defer wg.Done()
+			if err := sess.stop(false); err != nil {
+				s.logf("Stopping session %s: %v", sess.name, err)
+			}
+		}(sess)
+	}
+	wg.Wait()
+}
+
+func (s *Supervisor) serve(ln net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle serves one connection: a request line and a response line, or an
+// attach stream.
+func (s *Supervisor) handle(conn net.Conn) {
+	defer conn.Close()
+
+	_ = conn.SetReadDeadline(time.Now().Add(requestTimeout))
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		return
+	}
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		_ = json.NewEncoder(conn).Encode(response{Error: fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	if req.Op == opAttach {
+		s.attach(conn, r, req)
+		return
+	}
+	_ = json.NewEncoder(conn).Encode(s.dispatch(req))
+}
+
+func (s *Supervisor) dispatch(req request) response {
+	switch req.Op {
+	case opList:
+		s.mu.Lock()
+		names := make([]string, 0, len(s.sessions))
+		for name := range s.sessions {
+			names = append(names, name)
+		}
+		s.mu.Unlock()
+		sort.Strings(names)
+		return response{Sessions: names}
+	case opHas:
+		return response{Exists: s.get(req.Session) != nil}
+	case opNew:
+		return s.newSession(req)
+	}
+
+	sess := s.get(req.Session)
+	if sess == nil {
+		return notFound(req.Session)
+	}
+	switch req.Op {
+	case opKill:
+		if err := sess.stop(req.Force); err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{}
+	case opInfo:
+		return response{Info: sess.info()}
+	case opSend:
+		if err := sess.write([]byte(req.Data)); err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{}
+	case opCapture:
+		return response{Output: sess.capture(req.Lines)}
+	case opSetEnv:
+		sess.setEnv(req.Key, req.Value)
+		return response{}
+	case opGetEnv:
+		v, ok := sess.getEnv(req.Key)
+		if !ok {
+			return response{Error: fmt.Sprintf("unknown variable: %s", req.Key)}
+		}
+		return response{Value: v}
+	case opCommand:
+		cmd, err := sess.command()
+		if err != nil {
+			return response{Error: err.Error()}
+		}
+		return response{Value: cmd}
+	case opRunning:
+		return response{Running: sess.runs(req.Names)}
+	case opHook:
+		sess.mu.Lock()
+		sess.agentID = req.Value
+		sess.mu.Unlock()
+		return response{}
+	}
+	return response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+}
+
+func notFound(name string) response {
+	return response{Error: fmt.Sprintf("session not found: %s", name), Code: codeNotFound}
+}
+
+func (s *Supervisor) get(name string) *ptySession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[name]
+}
+
+func (s *Supervisor) newSession(req request) response {
+	if !validSessionName.MatchString(req.Session) {
+		return response{Error: fmt.Sprintf("invalid session name %q", req.Session)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[req.Session]; ok {
+		return response{Error: fmt.Sprintf("duplicate session: %s", req.Session), Code: codeExists}
+	}
+	sess, err := startSession(req.Session, req.WorkDir, req.Command)
+	if err != nil {
+		return response{Error: fmt.Sprintf("starting session: %v", err)}
+	}
+	s.sessions[req.Session] = sess
+
+	pumped := make(chan struct{})
+	go func() {
+		sess.pump()
+		close(pumped)
+	}()
+	go s.reap(sess, pumped)
+	return response{}
+}
+
+// reap waits for a session's process to exit, then removes the session,
+// disconnects its clients, and logs a crash if the agent died on its own.
+func (s *Supervisor) reap(sess *ptySession, pumped <-chan struct{}) {
+	_ = sess.cmd.Wait()
+
+	// Drain output the process wrote before exiting. Background children
+	// may hold the terminal open, so don't wait for them.
+	select {
+	case <-pumped:
+	case <-time.After(time.Second):
+	}
+	_ = sess.pty.Close()
+
+	s.mu.Lock()
+	if s.sessions[sess.name] == sess {
+		delete(s.sessions, sess.name)
+	}
+	s.mu.Unlock()
+
+	sess.mu.Lock()
+	sess.exitCode = sess.cmd.ProcessState.ExitCode()
+	agentID, killed, code := sess.agentID, sess.killed, sess.exitCode
+	sess.mu.Unlock()
+	sess.closeSubscribers()
+	close(sess.done)
+
+	if killed {
+		return
+	}
+	s.logf("Headless session %s exited (code %d)", sess.name, code)
+	if agentID != "" {
+		// Same record tmux's pane-died hook writes
+		cmd := exec.Command("gt", "log", "crash", "--agent", agentID, "--session", sess.name, "--exit-code", strconv.Itoa(code))
+		if err := cmd.Run(); err != nil {
+			s.logf("Logging crash for %s: %v", agentID, err)
+		}
+	}
+}
+
+// attach streams a session's scrollback and output to conn, and feeds
+// input and resize frames from it back to the session.
+func (s *Supervisor) attach(conn net.Conn, r *bufio.Reader, req request) {
+	sess := s.get(req.Session)
+	if sess == nil {
+		_ = json.NewEncoder(conn).Encode(notFound(req.Session))
+		return
+	}
+	if err := json.NewEncoder(conn).Encode(response{}); err != nil {
+		return
+	}
+	if req.Rows > 0 && req.Cols > 0 {
+		_ = setSize(sess.pty, req.Rows, req.Cols)
+	}
+
+	out := sess.subscribe()
+	defer sess.unsubscribe(out)
+	go func() {
+		for chunk := range out {
+			if _, err := conn.Write(chunk); err != nil {
+				break
+			}
+		}
+		// Session ended or the client fell behind
+		_ = conn.Close()
+	}()
+
+	for {
+		kind, payload, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		switch kind {
+		case frameData:
+			_ = sess.write(payload)
+		case frameResize:
+			if rows, cols, ok := parseResize(payload); ok && rows > 0 && cols > 0 {
+				_ = setSize(sess.pty, rows, cols)
+			}
+		}
+	}
+}
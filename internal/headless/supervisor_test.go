@@ -0,0 +1,208 @@
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// startSupervisor serves a supervisor for a throwaway town. The town lives
+// directly under the temp dir to keep the socket path short.
+func startSupervisor(t *testing.T) *Client {
+	t.Helper()
+	townRoot, err := os.MkdirTemp("", "gt-headless")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(townRoot) })
+
+	sup := NewSupervisor(townRoot, func(string, ...interface{}) {})
+	if err := sup.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	t.Cleanup(sup.Stop)
+
+	c := NewClient(townRoot)
+	// Probe for PTY support before relying on it
+	if err := c.NewSessionWithCommand("gt-probe", "", "exit 0"); err != nil {
+		if strings.Contains(err.Error(), ErrUnsupported.Error()) || strings.Contains(err.Error(), "/dev/ptmx") {
+			t.Skipf("no pseudo-terminals: %v", err)
+		}
+		t.Fatalf("probe session: %v", err)
+	}
+	return c
+}
+
+// waitFor polls cond until it holds or five seconds pass.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestSupervisorSessionLifecycle(t *testing.T) {
+	c := startSupervisor(t)
+	const name = "gt-test-cat"
+
+	if !c.IsAvailable() {
+		t.Fatal("IsAvailable() = false with a supervisor running")
+	}
+	if err := c.NewSessionWithCommand(name, os.TempDir(), "exec cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if err := c.NewSessionWithCommand(name, "", "exec cat"); !errors.Is(err, tmux.ErrSessionExists) {
+		t.Errorf("duplicate session error = %v, want ErrSessionExists", err)
+	}
+	if ok, err := c.HasSession(name); !ok || err != nil {
+		t.Fatalf("HasSession = %v, %v", ok, err)
+	}
+
+	if err := c.WaitForCommand(name, constants.SupportedShells, 5*time.Second); err != nil {
+		t.Fatalf("WaitForCommand: %v", err)
+	}
+	if cmd, _ := c.GetPaneCommand(name); cmd != "cat" {
+		t.Errorf("GetPaneCommand = %q, want cat", cmd)
+	}
+	if !c.IsAgentRunning(name) || c.IsAgentRunning(name, "node") {
+		t.Error("IsAgentRunning should match the foreground command")
+	}
+
+	if err := c.SendKeys(name, "hello headless"); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	waitFor(t, "output in scrollback", func() bool {
+		out, _ := c.CapturePane(name, 10)
+		return strings.Count(out, "hello headless") == 2 // terminal echo and cat
+	})
+
+	if err := c.SetEnvironment(name, "GT_AGENT", "claude"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.GetEnvironment(name, "GT_AGENT"); v != "claude" {
+		t.Errorf("GetEnvironment = %q, want claude", v)
+	}
+	if c.IsAgentAlive(name) {
+		t.Error("IsAgentAlive = true, but cat is not a claude process")
+	}
+
+	info, err := c.GetSessionInfo(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != name || info.Windows != 1 || info.Attached {
+		t.Errorf("GetSessionInfo = %+v", info)
+	}
+	if _, err := time.Parse(time.ANSIC, info.Created); err != nil {
+		t.Errorf("Created %q should parse like tmux's: %v", info.Created, err)
+	}
+	if sessions, _ := c.ListSessions(); !strings.Contains(strings.Join(sessions, ","), name) {
+		t.Errorf("ListSessions = %v, want %s", sessions, name)
+	}
+
+	if err := c.KillSessionWithProcesses(name); err != nil {
+		t.Fatalf("KillSessionWithProcesses: %v", err)
+	}
+	if ok, _ := c.HasSession(name); ok {
+		t.Error("session still exists after kill")
+	}
+	if err := c.KillSession(name); !errors.Is(err, tmux.ErrSessionNotFound) {
+		t.Errorf("killing a missing session = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestSupervisorAttach(t *testing.T) {
+	c := startSupervisor(t)
+	const name = "gt-test-attach"
+	if err := c.NewSessionWithCommand(name, "", "exec cat"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SendKeys(name, "before attach"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "output before attaching", func() bool {
+		out, _ := c.CapturePane(name, 10)
+		return strings.Contains(out, "before attach")
+	})
+
+	conn, err := net.Dial("unix", c.socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := json.NewEncoder(conn).Encode(request{Op: opAttach, Session: name, Rows: 30, Cols: 100}); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(conn)
+	line, err := r.ReadBytes('\n')
+	if err != nil || strings.TrimSpace(string(line)) != "{}" {
+		t.Fatalf("attach response = %q, %v", line, err)
+	}
+
+	// readUntil reads the stream until it has seen want
+	var seen strings.Builder
+	readUntil := func(want string) {
+		t.Helper()
+		buf := make([]byte, 4096)
+		for !strings.Contains(seen.String(), want) {
+			n, err := r.Read(buf)
+			if err != nil {
+				t.Fatalf("reading attach stream for %q: %v (got %q)", want, err, seen.String())
+			}
+			seen.Write(buf[:n])
+		}
+	}
+	readUntil("before attach") // scrollback is replayed
+
+	if err := writeFrame(conn, frameData, []byte("typed live\r")); err != nil {
+		t.Fatal(err)
+	}
+	readUntil("typed live")
+
+	if info, _ := c.GetSessionInfo(name); info == nil || !info.Attached || info.LastAttached == "" {
+		t.Errorf("GetSessionInfo while attached = %+v", info)
+	}
+}
+
+func TestSupervisorReapsExitedSessions(t *testing.T) {
+	c := startSupervisor(t)
+
+	if err := c.NewSessionWithCommand("gt-test-exit", "", "echo bye; exit 3"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "session to be reaped", func() bool {
+		ok, _ := c.HasSession("gt-test-exit")
+		return !ok
+	})
+}
+
+func TestClientWithoutSupervisor(t *testing.T) {
+	c := NewClient(t.TempDir())
+
+	if c.IsAvailable() {
+		t.Error("IsAvailable() = true with no supervisor")
+	}
+	if ok, err := c.HasSession("gt-x"); ok || err != nil {
+		t.Errorf("HasSession = %v, %v; want false, nil like tmux with no server", ok, err)
+	}
+	if sessions, err := c.ListSessions(); sessions != nil || err != nil {
+		t.Errorf("ListSessions = %v, %v; want nil, nil", sessions, err)
+	}
+	if err := c.NewSessionWithCommand("gt-x", "", "true"); !errors.Is(err, ErrNoSupervisor) {
+		t.Errorf("NewSessionWithCommand = %v, want ErrNoSupervisor", err)
+	}
+}
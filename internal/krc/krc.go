@@ -11,6 +11,10 @@
 // - Configurable TTLs per event type (default: 7 days)
 // - Auto-pruning on daemon startup and periodic intervals
 // - Stats and visibility into ephemeral data lifecycle
+//
+// The event log is pruned by dropping whole rotated segments (see
+// events.Each) once every event in them has expired; the small feed file
+// is still rewritten line by line.
 package krc
 
 import (
@@ -170,6 +174,7 @@ type PruneResult struct {
 	BytesBefore     int64          `json:"bytes_before"`
 	BytesAfter      int64          `json:"bytes_after"`
 	PrunedByType    map[string]int `json:"pruned_by_type"`
+	SegmentsPruned  int            `json:"segments_pruned"`
 	Duration        time.Duration  `json:"duration"`
 }
 
@@ -187,46 +192,109 @@ func NewPruner(townRoot string, config *Config) *Pruner {
 	}
 }
 
-// Prune removes expired events from the events and feed files.
-// It operates atomically by writing to temp files then renaming.
+// Prune removes expired events from the event log and the feed file.
+// Event log segments are dropped whole; the feed file is rewritten
+// atomically via a temp file and rename.
 func (p *Pruner) Prune() (*PruneResult, error) {
 	start := time.Now()
 	result := &PruneResult{
 		PrunedByType: make(map[string]int),
 	}
 
-	// Prune events file
-	eventsResult, err := p.pruneFile(filepath.Join(p.townRoot, events.EventsFile))
+	// Prune event log segments
+	eventsResult, err := p.pruneSegments()
 	if err != nil {
 		return nil, fmt.Errorf("pruning events: %w", err)
 	}
-	result.EventsProcessed += eventsResult.EventsProcessed
-	result.EventsPruned += eventsResult.EventsPruned
-	result.EventsRetained += eventsResult.EventsRetained
-	result.BytesBefore += eventsResult.BytesBefore
-	result.BytesAfter += eventsResult.BytesAfter
-	for k, v := range eventsResult.PrunedByType {
-		result.PrunedByType[k] += v
-	}
+	result.add(eventsResult)
 
 	// Prune feed file
 	feedResult, err := p.pruneFile(filepath.Join(p.townRoot, ".feed.jsonl"))
 	if err != nil {
 		return nil, fmt.Errorf("pruning feed: %w", err)
 	}
-	result.EventsProcessed += feedResult.EventsProcessed
-	result.EventsPruned += feedResult.EventsPruned
-	result.EventsRetained += feedResult.EventsRetained
-	result.BytesBefore += feedResult.BytesBefore
-	result.BytesAfter += feedResult.BytesAfter
-	for k, v := range feedResult.PrunedByType {
-		result.PrunedByType[k] += v
-	}
+	result.add(feedResult)
 
 	result.Duration = time.Since(start)
 	return result, nil
 }
 
+// add accumulates another result's counts.
+func (r *PruneResult) add(o *PruneResult) {
+	r.EventsProcessed += o.EventsProcessed
+	r.EventsPruned += o.EventsPruned
+	r.EventsRetained += o.EventsRetained
+	r.BytesBefore += o.BytesBefore
+	r.BytesAfter += o.BytesAfter
+	r.SegmentsPruned += o.SegmentsPruned
+	for k, v := range o.PrunedByType {
+		r.PrunedByType[k] += v
+	}
+}
+
+// pruneSegments drops every event log segment whose events have all
+// expired. Earlier days are first rotated out of the active file so an
+// idle town's events still age out.
+func (p *Pruner) pruneSegments() (*PruneResult, error) {
+	result := &PruneResult{
+		PrunedByType: make(map[string]int),
+	}
+
+	if err := events.MaybeRotate(p.townRoot); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dropped, err := events.DropSegments(p.townRoot, func(seg events.Segment) bool {
+		result.EventsProcessed += seg.Count
+		result.BytesBefore += seg.Bytes
+		return p.config.segmentExpired(seg, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, seg := range dropped {
+		result.SegmentsPruned++
+		result.EventsPruned += seg.Count
+		result.BytesAfter -= seg.Bytes
+		for typ, n := range seg.Types {
+			result.PrunedByType[typ] += n
+		}
+	}
+
+	// The active file only holds today's events and is never pruned
+	active, err := events.ReadFile(events.ActivePath(p.townRoot))
+	if err != nil {
+		return nil, err
+	}
+	result.EventsProcessed += len(active)
+	if info, err := os.Stat(events.ActivePath(p.townRoot)); err == nil {
+		result.BytesBefore += info.Size()
+	}
+
+	result.EventsRetained = result.EventsProcessed - result.EventsPruned
+	result.BytesAfter += result.BytesBefore
+	return result, nil
+}
+
+// segmentExpired reports whether every event in a segment has outlived
+// its type's TTL. Segments are judged by their newest event.
+func (c *Config) segmentExpired(seg events.Segment, now time.Time) bool {
+	if seg.Last.IsZero() {
+		return false
+	}
+	age := now.Sub(seg.Last)
+	if len(seg.Types) == 0 {
+		return age > c.DefaultTTL
+	}
+	for typ := range seg.Types {
+		if age <= c.GetTTL(typ) {
+			return false
+		}
+	}
+	return true
+}
+
 // pruneFile prunes a single JSONL file.
 func (p *Pruner) pruneFile(filePath string) (*PruneResult, error) {
 	result := &PruneResult{
@@ -344,13 +412,16 @@ func (p *Pruner) pruneFile(filePath string) (*PruneResult, error) {
 
 // Stats contains statistics about the current ephemeral data.
 type Stats struct {
-	EventsFile   FileStats          `json:"events_file"`
+	EventsFile   FileStats          `json:"events_file"` // active file plus rotated segments
 	FeedFile     FileStats          `json:"feed_file"`
 	ByType       map[string]int     `json:"by_type"`
 	ByAge        map[string]int     `json:"by_age"` // "0-1d", "1-7d", "7-30d", "30d+"
 	OldestEvent  time.Time          `json:"oldest_event"`
 	NewestEvent  time.Time          `json:"newest_event"`
 	TTLBreakdown map[string]TTLInfo `json:"ttl_breakdown"`
+
+	Segments        int `json:"segments"`         // rotated event log segments
+	ExpiredSegments int `json:"expired_segments"` // segments a prune would drop
 }
 
 // FileStats contains statistics for a single file.
@@ -378,50 +449,118 @@ func GetStats(townRoot string, config *Config) (*Stats, error) {
 
 	now := time.Now()
 
-	// Process events file
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	eventsStats, oldest, newest, err := getFileStats(eventsPath, config, now, stats.ByType, stats.ByAge, stats.TTLBreakdown)
-	if err != nil && !os.IsNotExist(err) {
+	// Process the event log: rotated segments, then the active file
+	log := &statsCollector{config: config, now: now, stats: stats}
+	stats.EventsFile.Path = events.ActivePath(townRoot)
+	ix, err := events.LoadSegmentIndex(townRoot)
+	if err != nil {
 		return nil, err
 	}
-	stats.EventsFile = eventsStats
-	if !oldest.IsZero() {
-		stats.OldestEvent = oldest
+	for _, seg := range ix.Segments {
+		stats.Segments++
+		stats.EventsFile.Size += seg.Bytes
+		if config.segmentExpired(seg, now) {
+			stats.ExpiredSegments++
+		}
 	}
-	if !newest.IsZero() {
-		stats.NewestEvent = newest
+	if info, err := os.Stat(stats.EventsFile.Path); err == nil {
+		stats.EventsFile.Size += info.Size()
+	}
+	if err := events.Each(townRoot, time.Time{}, func(e events.Event) bool {
+		stats.EventsFile.EventCount++
+		log.add(e.Type, e.Timestamp)
+		return true
+	}); err != nil {
+		return nil, err
 	}
 
 	// Process feed file
-	feedPath := filepath.Join(townRoot, ".feed.jsonl")
-	feedStats, oldest2, newest2, err := getFileStats(feedPath, config, now, stats.ByType, stats.ByAge, stats.TTLBreakdown)
+	feed := &statsCollector{config: config, now: now, stats: stats}
+	feedStats, err := getFileStats(filepath.Join(townRoot, ".feed.jsonl"), feed)
 	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
 	stats.FeedFile = feedStats
-	if !oldest2.IsZero() && (stats.OldestEvent.IsZero() || oldest2.Before(stats.OldestEvent)) {
-		stats.OldestEvent = oldest2
-	}
-	if !newest2.IsZero() && newest2.After(stats.NewestEvent) {
-		stats.NewestEvent = newest2
+
+	for _, c := range []*statsCollector{log, feed} {
+		if !c.oldest.IsZero() && (stats.OldestEvent.IsZero() || c.oldest.Before(stats.OldestEvent)) {
+			stats.OldestEvent = c.oldest
+		}
+		if c.newest.After(stats.NewestEvent) {
+			stats.NewestEvent = c.newest
+		}
 	}
 
 	return stats, nil
 }
 
-func getFileStats(filePath string, config *Config, now time.Time, byType, byAge map[string]int, ttlBreakdown map[string]TTLInfo) (FileStats, time.Time, time.Time, error) {
+// statsCollector accumulates per-event statistics into a Stats.
+type statsCollector struct {
+	config         *Config
+	now            time.Time
+	stats          *Stats
+	oldest, newest time.Time
+}
+
+// add records one event by type and timestamp.
+func (c *statsCollector) add(eventType, timestamp string) {
+	c.stats.ByType[eventType]++
+
+	ts, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return
+	}
+
+	// Track oldest/newest
+	if c.oldest.IsZero() || ts.Before(c.oldest) {
+		c.oldest = ts
+	}
+	if c.newest.IsZero() || ts.After(c.newest) {
+		c.newest = ts
+	}
+
+	// Age bucket
+	age := c.now.Sub(ts)
+	switch {
+	case age < 24*time.Hour:
+		c.stats.ByAge["0-1d"]++
+	case age < 7*24*time.Hour:
+		c.stats.ByAge["1-7d"]++
+	case age < 30*24*time.Hour:
+		c.stats.ByAge["7-30d"]++
+	default:
+		c.stats.ByAge["30d+"]++
+	}
+
+	// TTL breakdown
+	ttl := c.config.GetTTL(eventType)
+	info := c.stats.TTLBreakdown[eventType]
+	info.TTL = ttl
+	info.Count++
+	if age > ttl {
+		info.Expired++
+	} else {
+		// Calculate time until this event expires
+		expiresIn := ttl - age
+		if info.ExpiresIn == 0 || expiresIn < info.ExpiresIn {
+			info.ExpiresIn = expiresIn
+		}
+	}
+	c.stats.TTLBreakdown[eventType] = info
+}
+
+func getFileStats(filePath string, c *statsCollector) (FileStats, error) {
 	stats := FileStats{Path: filePath}
-	var oldest, newest time.Time
 
 	info, err := os.Stat(filePath)
 	if err != nil {
-		return stats, oldest, newest, err
+		return stats, err
 	}
 	stats.Size = info.Size()
 
 	file, err := os.Open(filePath)
 	if err != nil {
-		return stats, oldest, newest, err
+		return stats, err
 	}
 	defer file.Close()
 
@@ -442,51 +581,8 @@ func getFileStats(filePath string, config *Config, now time.Time, byType, byAge
 		if err := json.Unmarshal([]byte(line), &event); err != nil {
 			continue
 		}
-
-		byType[event.Type]++
-
-		ts, err := time.Parse(time.RFC3339, event.Timestamp)
-		if err != nil {
-			continue
-		}
-
-		// Track oldest/newest
-		if oldest.IsZero() || ts.Before(oldest) {
-			oldest = ts
-		}
-		if newest.IsZero() || ts.After(newest) {
-			newest = ts
-		}
-
-		// Age bucket
-		age := now.Sub(ts)
-		switch {
-		case age < 24*time.Hour:
-			byAge["0-1d"]++
-		case age < 7*24*time.Hour:
-			byAge["1-7d"]++
-		case age < 30*24*time.Hour:
-			byAge["7-30d"]++
-		default:
-			byAge["30d+"]++
-		}
-
-		// TTL breakdown
-		ttl := config.GetTTL(event.Type)
-		info := ttlBreakdown[event.Type]
-		info.TTL = ttl
-		info.Count++
-		if age > ttl {
-			info.Expired++
-		} else {
-			// Calculate time until this event expires
-			expiresIn := ttl - age
-			if info.ExpiresIn == 0 || expiresIn < info.ExpiresIn {
-				info.ExpiresIn = expiresIn
-			}
-		}
-		ttlBreakdown[event.Type] = info
+		c.add(event.Type, event.Timestamp)
 	}
 
-	return stats, oldest, newest, scanner.Err()
+	return stats, scanner.Err()
 }
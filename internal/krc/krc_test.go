@@ -273,3 +273,49 @@ func TestGetStats(t *testing.T) {
 		t.Errorf("expected 3 events in 0-1d bucket, got %d", stats.ByAge["0-1d"])
 	}
 }
+
+func TestPruner_DropsOnlyFullyExpiredSegments(t *testing.T) {
+	tmpDir := t.TempDir()
+	now := time.Now().UTC()
+
+	// Two earlier days: one holds only expired patrol events, the other
+	// also holds a mail event that is still within its 30-day TTL
+	lines := []struct {
+		ts  time.Time
+		typ string
+	}{
+		{now.Add(-5 * 24 * time.Hour), "patrol_started"},
+		{now.Add(-5 * 24 * time.Hour), "patrol_complete"},
+		{now.Add(-3 * 24 * time.Hour), "patrol_started"},
+		{now.Add(-3 * 24 * time.Hour), "mail"},
+	}
+	f, err := os.Create(filepath.Join(tmpDir, ".events.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range lines {
+		data, _ := json.Marshal(map[string]interface{}{"ts": l.ts.Format(time.RFC3339), "type": l.typ})
+		f.Write(append(data, '\n'))
+	}
+	f.Close()
+
+	result, err := NewPruner(tmpDir, DefaultConfig()).Prune()
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if result.SegmentsPruned != 1 || result.EventsPruned != 2 || result.EventsRetained != 2 {
+		t.Errorf("result = %+v, want 1 segment and 2 events pruned, 2 retained", result)
+	}
+	if result.PrunedByType["patrol_started"] != 1 || result.PrunedByType["patrol_complete"] != 1 {
+		t.Errorf("PrunedByType = %v", result.PrunedByType)
+	}
+
+	stats, err := GetStats(tmpDir, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Segments != 1 || stats.ExpiredSegments != 0 || stats.EventsFile.EventCount != 2 {
+		t.Errorf("stats = %d segments (%d expired), %d events; want 1, 0, 2",
+			stats.Segments, stats.ExpiredSegments, stats.EventsFile.EventCount)
+	}
+}
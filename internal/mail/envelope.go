@@ -0,0 +1,144 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ProtocolVersion is the version of the structured envelope written into
+// protocol message bodies. Readers decode envelopes up to this version and
+// fall back to the human-readable text for anything newer.
+const ProtocolVersion = 1
+
+// envelopeFence opens the JSON front block of a protocol message body.
+// The block is a fenced code block so it renders cleanly in mail readers:
+//
+//	```gt-protocol
+//	{"protocol_version":1,"type":"MERGED","payload":{...}}
+//	```
+const envelopeFence = "```gt-protocol"
+
+// envelopeClose closes the JSON front block.
+const envelopeClose = "```"
+
+// Envelope is the typed, versioned header of a protocol message.
+// The text written after it is for humans; handlers read the envelope.
+type Envelope struct {
+	// ProtocolVersion is the envelope format version.
+	ProtocolVersion int `json:"protocol_version"`
+
+	// Type is the protocol message type (e.g., "MERGED", "POLECAT_DONE").
+	Type string `json:"type"`
+
+	// Payload is the type-specific payload, decoded with Decode.
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Decode unmarshals the envelope payload into v.
+func (e *Envelope) Decode(v interface{}) error {
+	if len(e.Payload) == 0 {
+		return fmt.Errorf("envelope %s has no payload", e.Type)
+	}
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("decoding %s payload: %w", e.Type, err)
+	}
+	return nil
+}
+
+// EncodeEnvelope returns a message body consisting of a JSON front block for
+// msgType and payload followed by the human-readable text.
+func EncodeEnvelope(msgType string, payload interface{}, text string) (string, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("encoding %s payload: %w", msgType, err)
+	}
+	header, err := json.Marshal(Envelope{
+		ProtocolVersion: ProtocolVersion,
+		Type:            msgType,
+		Payload:         raw,
+	})
+	if err != nil {
+		return "", fmt.Errorf("encoding %s envelope: %w", msgType, err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(envelopeFence)
+	sb.WriteString("\n")
+	sb.Write(header)
+	sb.WriteString("\n")
+	sb.WriteString(envelopeClose)
+	sb.WriteString("\n\n")
+	sb.WriteString(text)
+	return sb.String(), nil
+}
+
+// ParseEnvelope extracts the JSON front block from a message body.
+// It returns false if the body has no envelope, the envelope is malformed,
+// or its version is newer than ProtocolVersion; callers then fall back to
+// parsing the human-readable text.
+func ParseEnvelope(body string) (*Envelope, bool) {
+	block, _, ok := splitEnvelope(body)
+	if !ok {
+		return nil, false
+	}
+	var env Envelope
+	if err := json.Unmarshal([]byte(block), &env); err != nil {
+		return nil, false
+	}
+	if env.ProtocolVersion < 1 || env.ProtocolVersion > ProtocolVersion || env.Type == "" {
+		return nil, false
+	}
+	return &env, true
+}
+
+// DecodeEnvelope decodes the payload of a body's envelope into v if the
+// envelope is present, supported, and of type msgType. It reports whether v
+// was populated.
+func DecodeEnvelope(body, msgType string, v interface{}) bool {
+	env, ok := ParseEnvelope(body)
+	if !ok || env.Type != msgType {
+		return false
+	}
+	return env.Decode(v) == nil
+}
+
+// StripEnvelope returns the human-readable part of a message body,
+// without the JSON front block.
+func StripEnvelope(body string) string {
+	_, text, ok := splitEnvelope(body)
+	if !ok {
+		return body
+	}
+	return text
+}
+
+// splitEnvelope splits a body into the envelope JSON and the text after it.
+// The front block must be the first non-blank content of the body.
+func splitEnvelope(body string) (block, text string, ok bool) {
+	rest := strings.TrimLeft(body, " \t\r\n")
+	if !strings.HasPrefix(rest, envelopeFence+"\n") && !strings.HasPrefix(rest, envelopeFence+"\r\n") {
+		return "", "", false
+	}
+	rest = rest[len(envelopeFence):]
+
+	var lines []string
+	for {
+		nl := strings.IndexByte(rest, '\n')
+		if nl < 0 {
+			if strings.TrimSpace(rest) != envelopeClose {
+				return "", "", false // unterminated block
+			}
+			return strings.Join(lines, "\n"), "", true
+		}
+		line := strings.TrimSpace(rest[:nl])
+		rest = rest[nl+1:]
+		if line == envelopeClose {
+			break
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), strings.TrimLeft(rest, "\r\n"), true
+}
@@ -0,0 +1,70 @@
+package mail
+
+import (
+	"strings"
+	"testing"
+)
+
+type testPayload struct {
+	Branch string `json:"branch"`
+	Count  int    `json:"count"`
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	body, err := EncodeEnvelope("MERGED", testPayload{Branch: "polecat/nux", Count: 2}, "Branch: polecat/nux\n")
+	if err != nil {
+		t.Fatalf("EncodeEnvelope: %v", err)
+	}
+
+	env, ok := ParseEnvelope(body)
+	if !ok {
+		t.Fatalf("ParseEnvelope: no envelope in %q", body)
+	}
+	if env.ProtocolVersion != ProtocolVersion || env.Type != "MERGED" {
+		t.Errorf("envelope = v%d %q", env.ProtocolVersion, env.Type)
+	}
+
+	var got testPayload
+	if !DecodeEnvelope(body, "MERGED", &got) {
+		t.Fatal("DecodeEnvelope = false")
+	}
+	if got.Branch != "polecat/nux" || got.Count != 2 {
+		t.Errorf("payload = %+v", got)
+	}
+	if DecodeEnvelope(body, "MERGE_FAILED", &got) {
+		t.Error("DecodeEnvelope with wrong type = true")
+	}
+
+	if text := StripEnvelope(body); text != "Branch: polecat/nux\n" {
+		t.Errorf("StripEnvelope = %q", text)
+	}
+}
+
+func TestParseEnvelope_Rejects(t *testing.T) {
+	tests := map[string]string{
+		"legacy text":    "Branch: polecat/nux\nIssue: gt-abc\n",
+		"unterminated":   "```gt-protocol\n{\"protocol_version\":1,\"type\":\"MERGED\"}\n",
+		"malformed json": "```gt-protocol\n{not json\n```\n",
+		"future version": "```gt-protocol\n{\"protocol_version\":99,\"type\":\"MERGED\",\"payload\":{}}\n```\n",
+		"no type":        "```gt-protocol\n{\"protocol_version\":1,\"payload\":{}}\n```\n",
+		"not at start":   "hello\n```gt-protocol\n{\"protocol_version\":1,\"type\":\"MERGED\",\"payload\":{}}\n```\n",
+	}
+	for name, body := range tests {
+		if _, ok := ParseEnvelope(body); ok {
+			t.Errorf("%s: ParseEnvelope = true, want false", name)
+		}
+	}
+}
+
+func TestParseEnvelope_ToleratesReformatting(t *testing.T) {
+	// Leading blank lines, CRLF endings, pretty-printed JSON, and a closing
+	// fence at end of body all still parse.
+	body := "\r\n```gt-protocol\r\n{\r\n  \"protocol_version\": 1,\r\n  \"type\": \"MERGED\",\r\n  \"payload\": {\"branch\": \"b\"}\r\n}\r\n```"
+	var got testPayload
+	if !DecodeEnvelope(body, "MERGED", &got) || got.Branch != "b" {
+		t.Errorf("DecodeEnvelope = %+v", got)
+	}
+	if !strings.HasPrefix(StripEnvelope(body+"\n\ntext"), "text") {
+		t.Errorf("StripEnvelope = %q", StripEnvelope(body+"\n\ntext"))
+	}
+}
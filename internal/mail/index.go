@@ -0,0 +1,490 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// indexVersion is bumped whenever the index format or term weighting
+// changes; an index with another version is rebuilt from scratch.
+const indexVersion = 1
+
+// indexFullSyncInterval bounds how long an index goes without a full sync.
+// Incremental refreshes only see messages that changed, so deleted messages
+// linger in the index until the next full sync.
+const indexFullSyncInterval = time.Hour
+
+// Field weights for ranking: a term in the subject counts three times as
+// much as the same term in the body.
+const (
+	weightSubject = 3
+	weightAddress = 2
+	weightBody    = 1
+	weightLabel   = 1
+)
+
+// IndexedMessage is a message as stored in the search index.
+type IndexedMessage struct {
+	*Message
+
+	// Labels are the raw labels of the message bead.
+	Labels []string `json:"labels,omitempty"`
+
+	// UpdatedAt is when the message bead last changed.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Archived is true for messages from the mail archive file.
+	Archived bool `json:"archived,omitempty"`
+}
+
+func (d *IndexedMessage) isScheduled() bool {
+	for _, l := range d.Labels {
+		if l == LabelScheduled {
+			return true
+		}
+	}
+	return false
+}
+
+// Index is a local inverted index over all mail in town beads, persisted in
+// the town's runtime directory. Refresh keeps it current by fetching only
+// the messages that changed since the last refresh.
+type Index struct {
+	Version int `json:"version"`
+
+	// Watermark is the newest UpdatedAt seen; the next incremental refresh
+	// asks beads for messages updated after it.
+	Watermark time.Time `json:"watermark"`
+
+	// FullSync is when the index was last rebuilt from a full listing.
+	FullSync time.Time `json:"full_sync"`
+
+	// ArchiveModTime is the modification time of the indexed archive file.
+	ArchiveModTime time.Time `json:"archive_mod_time"`
+
+	// Docs holds the indexed messages by ID.
+	Docs map[string]*IndexedMessage `json:"docs"`
+
+	// Postings maps a term to the weighted frequency of that term in
+	// each message that contains it.
+	Postings map[string]map[string]float64 `json:"postings"`
+
+	path string
+}
+
+// IndexSearchOptions restricts an index search.
+type IndexSearchOptions struct {
+	// Identity restricts results to messages addressed or CC'd to this
+	// address. Empty searches all mail in the town.
+	Identity string
+
+	// IncludeArchived includes messages from the archive file.
+	IncludeArchived bool
+
+	// Limit caps the number of results (0 = no limit).
+	Limit int
+}
+
+// SearchResult is a ranked search hit.
+type SearchResult struct {
+	*Message
+
+	Archived bool    `json:"archived,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// IndexPath returns where the town's mail index is stored.
+func (r *Router) IndexPath() string {
+	townRoot := r.townRoot
+	if townRoot == "" {
+		townRoot = filepath.Dir(r.resolveBeadsDir(""))
+	}
+	return filepath.Join(constants.TownRuntimePath(townRoot), "mail-index.json")
+}
+
+// OpenIndex loads the town's mail index. A missing, unreadable or outdated
+// index file yields an empty index, which the next Refresh rebuilds.
+func (r *Router) OpenIndex() *Index {
+	path := r.IndexPath()
+	ix := newIndex(path)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town runtime dir
+	if err != nil {
+		return ix
+	}
+	var loaded Index
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != indexVersion {
+		return ix
+	}
+	if loaded.Docs == nil || loaded.Postings == nil {
+		return ix
+	}
+	loaded.path = path
+	return &loaded
+}
+
+func newIndex(path string) *Index {
+	return &Index{
+		Version:  indexVersion,
+		Docs:     make(map[string]*IndexedMessage),
+		Postings: make(map[string]map[string]float64),
+		path:     path,
+	}
+}
+
+// SearchMail refreshes the town's mail index and runs a query against it.
+// If rebuild is true the index is rebuilt from a full listing first.
+func (r *Router) SearchMail(query string, opts IndexSearchOptions, rebuild bool) ([]SearchResult, error) {
+	q, err := ParseQuery(query, timeNow())
+	if err != nil {
+		return nil, err
+	}
+	ix := r.OpenIndex()
+	if err := ix.Refresh(r, rebuild); err != nil {
+		return nil, err
+	}
+	return ix.Search(q, opts), nil
+}
+
+// Refresh updates the index with messages changed since the last refresh
+// and re-reads the archive file if it changed. A full sync, which also
+// drops deleted messages, happens when rebuild is set, when the index is
+// new, or when the last full sync is older than indexFullSyncInterval.
+// The refreshed index is saved before returning.
+func (ix *Index) Refresh(r *Router, rebuild bool) error {
+	now := timeNow()
+	full := rebuild || ix.FullSync.IsZero() || ix.Watermark.IsZero() ||
+		now.Sub(ix.FullSync) > indexFullSyncInterval
+
+	var since time.Time
+	if !full {
+		since = ix.Watermark
+	}
+	docs, err := r.listIndexable(since)
+	if err != nil && !full {
+		// Older bd versions lack --updated-after; fall back to a full sync
+		full = true
+		docs, err = r.listIndexable(time.Time{})
+	}
+	if err != nil {
+		return fmt.Errorf("indexing mail: %w", err)
+	}
+
+	if full {
+		live := make(map[string]bool, len(docs))
+		for _, d := range docs {
+			live[d.ID] = true
+		}
+		for id, d := range ix.Docs {
+			if !d.Archived && !live[id] {
+				ix.remove(id)
+			}
+		}
+		ix.FullSync = now
+	}
+	for _, d := range docs {
+		ix.put(d)
+		if d.UpdatedAt.After(ix.Watermark) {
+			ix.Watermark = d.UpdatedAt
+		}
+	}
+
+	if err := ix.refreshArchive(filepath.Join(r.resolveBeadsDir(""), "archive.jsonl")); err != nil {
+		return fmt.Errorf("indexing mail archive: %w", err)
+	}
+
+	return ix.save()
+}
+
+// refreshArchive re-indexes the archive file if it changed since the last
+// refresh. Archived copies replace the live message with the same ID.
+func (ix *Index) refreshArchive(path string) error {
+	info, err := os.Stat(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var modTime time.Time
+	if info != nil {
+		modTime = info.ModTime()
+	}
+	if modTime.Equal(ix.ArchiveModTime) {
+		return nil
+	}
+
+	for id, d := range ix.Docs {
+		if d.Archived {
+			ix.remove(id)
+		}
+	}
+	if info != nil {
+		archived, err := (&Mailbox{beadsDir: filepath.Dir(path)}).ListArchived()
+		if err != nil {
+			return err
+		}
+		for _, msg := range archived {
+			ix.put(&IndexedMessage{Message: msg, UpdatedAt: msg.Timestamp, Archived: true})
+		}
+	}
+	ix.ArchiveModTime = modTime
+	return nil
+}
+
+// save writes the index atomically, so a concurrent reader never sees a
+// partial file.
+func (ix *Index) save() error {
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(ix.path, ix)
+}
+
+// listIndexable lists every message in town beads, or only those updated
+// after since if it is non-zero.
+func (r *Router) listIndexable(since time.Time) ([]*IndexedMessage, error) {
+	beadsDir := r.resolveBeadsDir("")
+	if err := r.ensureCustomTypes(beadsDir); err != nil {
+		return nil, err
+	}
+	args := []string{"list",
+		"--type", "message",
+		"--status=all",
+		"--limit=0",
+		"--json",
+	}
+	if !since.IsZero() {
+		args = append(args, "--updated-after="+since.UTC().Format(time.RFC3339))
+	}
+	stdout, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(stdout) == 0 || string(stdout) == "null" {
+		return nil, nil
+	}
+
+	var bms []struct {
+		BeadsMessage
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+	if err := json.Unmarshal(stdout, &bms); err != nil {
+		return nil, fmt.Errorf("parsing messages: %w", err)
+	}
+	docs := make([]*IndexedMessage, 0, len(bms))
+	for i := range bms {
+		docs = append(docs, &IndexedMessage{
+			Message:   bms[i].ToMessage(),
+			Labels:    bms[i].Labels,
+			UpdatedAt: bms[i].UpdatedAt,
+		})
+	}
+	return docs, nil
+}
+
+// put adds or replaces a message in the index.
+func (ix *Index) put(d *IndexedMessage) {
+	ix.remove(d.ID)
+	ix.Docs[d.ID] = d
+	for term, w := range docTerms(d) {
+		postings := ix.Postings[term]
+		if postings == nil {
+			postings = make(map[string]float64)
+			ix.Postings[term] = postings
+		}
+		postings[d.ID] = w
+	}
+}
+
+// remove drops a message and its postings from the index.
+func (ix *Index) remove(id string) {
+	d, ok := ix.Docs[id]
+	if !ok {
+		return
+	}
+	for term := range docTerms(d) {
+		delete(ix.Postings[term], id)
+		if len(ix.Postings[term]) == 0 {
+			delete(ix.Postings, term)
+		}
+	}
+	delete(ix.Docs, id)
+}
+
+// docTerms returns the field-weighted term frequencies of a message.
+// Only plain labels (reply-required, scheduled) are indexed as terms;
+// key:value metadata labels are reachable through query filters.
+func docTerms(d *IndexedMessage) map[string]float64 {
+	terms := make(map[string]float64)
+	add := func(text string, weight float64) {
+		for _, t := range tokenize(text) {
+			terms[t] += weight
+		}
+	}
+	add(d.Subject, weightSubject)
+	add(d.Body, weightBody)
+	add(d.From, weightAddress)
+	add(d.To, weightAddress)
+	for _, cc := range d.CC {
+		add(cc, weightAddress)
+	}
+	add(d.ThreadID, weightLabel)
+	for _, l := range d.Labels {
+		if !strings.Contains(l, ":") {
+			add(l, weightLabel)
+		}
+	}
+	return terms
+}
+
+// Search returns the messages matching q, best match first. Messages with
+// equal scores, and all results of a query without free terms, are ordered
+// newest first.
+func (ix *Index) Search(q *Query, opts IndexSearchOptions) []SearchResult {
+	terms := make([][]string, 0, len(q.Terms))
+	for _, t := range q.Terms {
+		terms = append(terms, ix.expand(t))
+	}
+	for _, p := range q.Phrases {
+		for _, t := range tokenize(p) {
+			terms = append(terms, []string{t})
+		}
+	}
+
+	includeArchived := opts.IncludeArchived || containsState(q, "archived")
+	includeScheduled := opts.Identity == "" || containsState(q, "scheduled")
+
+	var results []SearchResult
+	for id, d := range ix.Docs {
+		if d.Archived && !includeArchived {
+			continue
+		}
+		if d.isScheduled() && !includeScheduled {
+			continue
+		}
+		if opts.Identity != "" && !addressedTo(d.Message, opts.Identity) {
+			continue
+		}
+		if !q.selects(d) {
+			continue
+		}
+
+		score, ok := ix.score(id, terms)
+		if !ok {
+			continue
+		}
+		excluded := false
+		for _, t := range q.Exclude {
+			if _, hit := ix.Postings[t][id]; hit {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+		results = append(results, SearchResult{Message: d.Message, Archived: d.Archived, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].Timestamp.Equal(results[j].Timestamp) {
+			return results[i].Timestamp.After(results[j].Timestamp)
+		}
+		return results[i].ID < results[j].ID
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results
+}
+
+// expand returns the index terms a query term stands for: itself, or every
+// term with its prefix for a term ending in "*".
+func (ix *Index) expand(term string) []string {
+	prefix, ok := strings.CutSuffix(term, "*")
+	if !ok {
+		return []string{term}
+	}
+	var expanded []string
+	for t := range ix.Postings {
+		if strings.HasPrefix(t, prefix) {
+			expanded = append(expanded, t)
+		}
+	}
+	return expanded
+}
+
+// score computes a TF-IDF score for a message. Every term group must match
+// at least one of its terms; ok is false if one does not.
+func (ix *Index) score(id string, terms [][]string) (float64, bool) {
+	n := float64(len(ix.Docs))
+	var score float64
+	for _, group := range terms {
+		matched := false
+		for _, t := range group {
+			postings := ix.Postings[t]
+			w, hit := postings[id]
+			if !hit {
+				continue
+			}
+			matched = true
+			idf := math.Log(1 + n/float64(len(postings)))
+			score += (1 + math.Log(w)) * idf
+		}
+		if !matched {
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+// selects applies the query's phrases and field filters to a message.
+func (q *Query) selects(d *IndexedMessage) bool {
+	for _, f := range q.Filters {
+		if !f.matches(d) {
+			return false
+		}
+	}
+	if len(q.Phrases) > 0 {
+		text := strings.ToLower(d.Subject + "\n" + d.Body)
+		for _, p := range q.Phrases {
+			if !strings.Contains(text, p) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// containsState reports whether q asks for an is: state explicitly.
+func containsState(q *Query, state string) bool {
+	for _, f := range q.Filters {
+		if f.Field == "is" && f.Value == state && !f.Negate {
+			return true
+		}
+	}
+	return false
+}
+
+// addressedTo reports whether address is the recipient or a CC of msg.
+func addressedTo(msg *Message, address string) bool {
+	identity := AddressToIdentity(address)
+	if AddressToIdentity(msg.To) == identity {
+		return true
+	}
+	for _, cc := range msg.CC {
+		if AddressToIdentity(cc) == identity {
+			return true
+		}
+	}
+	return false
+}
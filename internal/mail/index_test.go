@@ -0,0 +1,227 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	q, err := ParseQuery(`from:witness subject:MERGED after:2d is:unread "merge queue" -flaky deploy* -label:read`, now)
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+	if strings.Join(q.Terms, ",") != "deploy*" {
+		t.Errorf("Terms = %v, want [deploy*]", q.Terms)
+	}
+	if strings.Join(q.Phrases, ",") != "merge queue" {
+		t.Errorf("Phrases = %v, want [merge queue]", q.Phrases)
+	}
+	if strings.Join(q.Exclude, ",") != "flaky" {
+		t.Errorf("Exclude = %v, want [flaky]", q.Exclude)
+	}
+
+	want := []struct {
+		field, value string
+		negate       bool
+	}{
+		{"from", "witness", false},
+		{"subject", "merged", false},
+		{"after", "2d", false},
+		{"is", "unread", false},
+		{"label", "read", true},
+	}
+	if len(q.Filters) != len(want) {
+		t.Fatalf("Filters = %+v, want %d", q.Filters, len(want))
+	}
+	for i, w := range want {
+		f := q.Filters[i]
+		if f.Field != w.field || f.Value != w.value || f.Negate != w.negate {
+			t.Errorf("Filters[%d] = %+v, want %+v", i, f, w)
+		}
+	}
+	if got := q.Filters[2].at; !got.Equal(now.Add(-48 * time.Hour)) {
+		t.Errorf("after:2d = %v, want %v", got, now.Add(-48*time.Hour))
+	}
+}
+
+func TestParseQueryQuotedFilterAndErrors(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	q, err := ParseQuery(`subject:"merge ready" priority:1 http://example`, now)
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+	if len(q.Filters) != 2 || q.Filters[0].Value != "merge ready" || q.Filters[1].Value != "high" {
+		t.Errorf("Filters = %+v, want subject:merge ready and priority:high", q.Filters)
+	}
+	if strings.Join(q.Terms, ",") != "http,example" {
+		t.Errorf("Terms = %v, unknown fields should be free text", q.Terms)
+	}
+
+	for _, bad := range []string{`is:starred`, `after:soon`, `type:memo`, `priority:9x`, `from:`, `"open quote`} {
+		if _, err := ParseQuery(bad, now); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func newTestIndex(t *testing.T) *Index {
+	t.Helper()
+	base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	ix := newIndex(filepath.Join(t.TempDir(), "mail-index.json"))
+	docs := []*IndexedMessage{
+		{Message: &Message{ID: "hq-1", From: "gastown/witness", To: "mayor/", Subject: "MERGED: polecat Toast",
+			Body: "Merged to main.", Timestamp: base.Add(-time.Hour), Type: TypeNotification, Priority: PriorityNormal}},
+		{Message: &Message{ID: "hq-2", From: "gastown/refinery", To: "mayor/", Subject: "Merge queue stalled",
+			Body: "The merge queue is stalled on a flaky test.", Timestamp: base.Add(-2 * time.Hour), Read: true,
+			Type: TypeTask, Priority: PriorityHigh}},
+		{Message: &Message{ID: "hq-3", From: "gastown/witness", To: "gastown/Toast", CC: []string{"mayor/"},
+			Subject: "Status check", Body: "Please report: merged yet?", Timestamp: base.Add(-72 * time.Hour),
+			Type: TypeNotification, Priority: PriorityNormal}},
+		{Message: &Message{ID: "hq-4", From: "mayor/", To: "gastown/witness", Subject: "Deploy tonight",
+			Body: "Scheduled deploy.", Timestamp: base, Type: TypeNotification, Priority: PriorityNormal},
+			Labels: []string{LabelScheduled, "from:mayor/"}},
+	}
+	for _, d := range docs {
+		ix.put(d)
+	}
+	return ix
+}
+
+func searchIDs(t *testing.T, ix *Index, query string, opts IndexSearchOptions) string {
+	t.Helper()
+	q, err := ParseQuery(query, time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ParseQuery(%q): %v", query, err)
+	}
+	var ids []string
+	for _, r := range ix.Search(q, opts) {
+		ids = append(ids, r.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := newTestIndex(t)
+
+	tests := []struct {
+		query string
+		opts  IndexSearchOptions
+		want  string
+	}{
+		// Subject hits outrank body hits
+		{"merged", IndexSearchOptions{}, "hq-1,hq-3"},
+		{"merge*", IndexSearchOptions{}, "hq-2,hq-1,hq-3"},
+		{`"merge queue"`, IndexSearchOptions{}, "hq-2"},
+		{"merge* -flaky", IndexSearchOptions{}, "hq-1,hq-3"},
+		{"from:witness subject:MERGED after:2d is:unread", IndexSearchOptions{}, "hq-1"},
+		{"is:read", IndexSearchOptions{}, "hq-2"},
+		{"type:task priority:high", IndexSearchOptions{}, "hq-2"},
+		{"to:mayor before:1d", IndexSearchOptions{}, "hq-3"},
+		// No free terms: newest first
+		{"", IndexSearchOptions{}, "hq-4,hq-1,hq-2,hq-3"},
+		{"", IndexSearchOptions{Limit: 2}, "hq-4,hq-1"},
+		// CC'd messages are in the mailbox, scheduled ones are not
+		{"", IndexSearchOptions{Identity: "mayor"}, "hq-1,hq-2,hq-3"},
+		{"", IndexSearchOptions{Identity: "gastown/witness"}, ""},
+		{"is:scheduled", IndexSearchOptions{Identity: "gastown/witness"}, "hq-4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := searchIDs(t, ix, tt.query, tt.opts); got != tt.want {
+				t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexPutReplacesPostings(t *testing.T) {
+	ix := newTestIndex(t)
+
+	ix.put(&IndexedMessage{Message: &Message{ID: "hq-1", From: "gastown/witness", To: "mayor/",
+		Subject: "Rebased", Body: "Rebased onto main."}})
+	if got := searchIDs(t, ix, "merged", IndexSearchOptions{}); got != "hq-3" {
+		t.Errorf("after update, merged = %q, want hq-3", got)
+	}
+	if got := searchIDs(t, ix, "rebased", IndexSearchOptions{}); got != "hq-1" {
+		t.Errorf("after update, rebased = %q, want hq-1", got)
+	}
+
+	ix.remove("hq-3")
+	if _, ok := ix.Postings["report"]; ok {
+		t.Error("postings for a removed message's only terms should be dropped")
+	}
+}
+
+func TestIndexArchive(t *testing.T) {
+	ix := newTestIndex(t)
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "archive.jsonl")
+
+	mb := &Mailbox{beadsDir: dir}
+	if err := mb.appendToArchive(&Message{ID: "hq-0", From: "gastown/witness", To: "mayor/",
+		Subject: "Old merged work", Timestamp: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.refreshArchive(archive); err != nil {
+		t.Fatalf("refreshArchive: %v", err)
+	}
+
+	if got := searchIDs(t, ix, "merged", IndexSearchOptions{}); got != "hq-1,hq-3" {
+		t.Errorf("without archive = %q, want hq-1,hq-3", got)
+	}
+	if got := searchIDs(t, ix, "merged", IndexSearchOptions{IncludeArchived: true}); got != "hq-1,hq-0,hq-3" {
+		t.Errorf("with archive = %q, want hq-1,hq-0,hq-3 (subject hits first)", got)
+	}
+	if got := searchIDs(t, ix, "is:archived", IndexSearchOptions{}); got != "hq-0" {
+		t.Errorf("is:archived = %q, want hq-0", got)
+	}
+
+	if err := os.Remove(archive); err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.refreshArchive(archive); err != nil {
+		t.Fatalf("refreshArchive: %v", err)
+	}
+	if _, ok := ix.Docs["hq-0"]; ok {
+		t.Error("archived message should be dropped when the archive is purged")
+	}
+}
+
+func TestIndexSaveAndOpen(t *testing.T) {
+	townRoot := t.TempDir()
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	ix := r.OpenIndex()
+	if len(ix.Docs) != 0 {
+		t.Fatalf("new index has %d docs", len(ix.Docs))
+	}
+	ix.put(&IndexedMessage{Message: &Message{ID: "hq-1", Subject: "Hello world", To: "mayor/"}})
+	ix.Watermark = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	if err := ix.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loaded := r.OpenIndex()
+	if loaded.Docs["hq-1"] == nil || loaded.Docs["hq-1"].Subject != "Hello world" {
+		t.Errorf("loaded docs = %+v", loaded.Docs)
+	}
+	if !loaded.Watermark.Equal(ix.Watermark) {
+		t.Errorf("Watermark = %v, want %v", loaded.Watermark, ix.Watermark)
+	}
+	if got := searchIDs(t, loaded, "hello", IndexSearchOptions{}); got != "hq-1" {
+		t.Errorf("search after reload = %q, want hq-1", got)
+	}
+
+	if err := os.WriteFile(r.IndexPath(), []byte(`{"version":0}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if stale := r.OpenIndex(); len(stale.Docs) != 0 || stale.Version != indexVersion {
+		t.Errorf("outdated index should open empty, got %+v", stale)
+	}
+}
@@ -360,6 +360,8 @@ func (m *Mailbox) MarkRead(id string) error {
 }
 
 func (m *Mailbox) markReadBeads(id string) error {
+	// Read receipt is best-effort; closing is what marks the message read
+	_ = m.addReadReceipt(id, m.beadsDir)
 	// Single DB - wisps and persistent messages in same store
 	return m.closeInDir(id, m.beadsDir)
 }
@@ -427,6 +429,9 @@ func (m *Mailbox) markReadOnlyBeads(id string) error {
 		return err
 	}
 
+	// Read receipt is best-effort; the "read" label is what marks it read
+	_ = m.addReadReceipt(id, m.beadsDir)
+
 	return nil
 }
 
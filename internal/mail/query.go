@@ -0,0 +1,280 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalidQuery is returned (wrapped) for queries that do not parse.
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// Query is a parsed mail search query. Every part must match for a message
+// to be a hit:
+//
+//	from:witness subject:MERGED after:2d is:unread "merge queue" -flaky
+//
+// Free terms and quoted phrases are matched against the index and ranked;
+// field filters only select. Any term or filter can be negated with a
+// leading "-". A trailing "*" makes a free term a prefix (merg*).
+type Query struct {
+	// Terms are free-text terms, already tokenized.
+	Terms []string
+
+	// Phrases are quoted phrases that must appear verbatim (case-insensitive)
+	// in the subject or body.
+	Phrases []string
+
+	// Exclude are free-text terms that must not appear.
+	Exclude []string
+
+	// Filters are field filters such as from:, is: and after:.
+	Filters []QueryFilter
+}
+
+// QueryFilter is a single field:value clause of a query.
+type QueryFilter struct {
+	Field  string
+	Value  string
+	Negate bool
+
+	at time.Time // parsed value of after: and before:
+}
+
+// queryFields are the fields a query can filter on.
+var queryFields = map[string]bool{
+	"from": true, "to": true, "subject": true, "body": true,
+	"thread": true, "label": true, "type": true, "priority": true,
+	"is": true, "after": true, "before": true, "id": true,
+}
+
+// queryStates are the values accepted by is:.
+var queryStates = map[string]bool{
+	"unread": true, "read": true, "archived": true,
+	"pinned": true, "wisp": true, "scheduled": true,
+}
+
+// ParseQuery parses a search query. Relative dates in after: and before:
+// (2d, 6h, 1w) are resolved against now.
+func ParseQuery(s string, now time.Time) (*Query, error) {
+	words, err := splitQuery(s)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	for _, w := range words {
+		text := w.text
+		negate := false
+		if strings.HasPrefix(text, "-") && len(text) > 1 {
+			negate = true
+			text = text[1:]
+		}
+
+		if field, value, ok := strings.Cut(text, ":"); ok && queryFields[strings.ToLower(field)] &&
+			(w.quoteAt < 0 || w.quoteAt > len(field)) {
+			f, err := parseFilter(strings.ToLower(field), value, negate, now)
+			if err != nil {
+				return nil, err
+			}
+			q.Filters = append(q.Filters, f)
+			continue
+		}
+
+		switch {
+		case w.quoteAt >= 0 && !negate:
+			if phrase := strings.ToLower(strings.TrimSpace(text)); phrase != "" {
+				q.Phrases = append(q.Phrases, phrase)
+			}
+		case negate:
+			q.Exclude = append(q.Exclude, tokenize(text)...)
+		case strings.HasSuffix(text, "*"):
+			if prefix := tokenize(text); len(prefix) == 1 {
+				q.Terms = append(q.Terms, prefix[0]+"*")
+			} else {
+				q.Terms = append(q.Terms, prefix...)
+			}
+		default:
+			q.Terms = append(q.Terms, tokenize(text)...)
+		}
+	}
+	return q, nil
+}
+
+// queryWord is one whitespace-separated word of a query. quoteAt is the
+// offset in text where a quoted section began, or -1.
+type queryWord struct {
+	text    string
+	quoteAt int
+}
+
+// splitQuery splits a query into words, keeping quoted sections together.
+func splitQuery(s string) ([]queryWord, error) {
+	var words []queryWord
+	var b strings.Builder
+	quoteAt := -1
+	inQuote := false
+	flush := func() {
+		if b.Len() > 0 || quoteAt >= 0 {
+			words = append(words, queryWord{text: b.String(), quoteAt: quoteAt})
+		}
+		b.Reset()
+		quoteAt = -1
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			if !inQuote && quoteAt < 0 {
+				quoteAt = b.Len()
+			}
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("%w: unterminated quote in %q", ErrInvalidQuery, s)
+	}
+	flush()
+	return words, nil
+}
+
+// parseFilter validates a field:value clause.
+func parseFilter(field, value string, negate bool, now time.Time) (QueryFilter, error) {
+	f := QueryFilter{Field: field, Value: strings.ToLower(value), Negate: negate}
+	if f.Value == "" {
+		return f, fmt.Errorf("%w: empty value for %s:", ErrInvalidQuery, field)
+	}
+
+	switch field {
+	case "is":
+		if !queryStates[f.Value] {
+			return f, fmt.Errorf("%w: unknown is:%s (want unread, read, archived, pinned, wisp or scheduled)", ErrInvalidQuery, value)
+		}
+	case "type":
+		switch MessageType(f.Value) {
+		case TypeTask, TypeScavenge, TypeNotification, TypeReply:
+		default:
+			return f, fmt.Errorf("%w: unknown type:%s (want task, scavenge, notification or reply)", ErrInvalidQuery, value)
+		}
+	case "priority":
+		if n, err := strconv.Atoi(f.Value); err == nil {
+			f.Value = string(PriorityFromInt(n))
+		}
+		if ParsePriority(f.Value) != Priority(f.Value) {
+			return f, fmt.Errorf("%w: unknown priority:%s (want urgent, high, normal, low or 0-3)", ErrInvalidQuery, value)
+		}
+	case "after", "before":
+		at, err := parseQueryTime(f.Value, now)
+		if err != nil {
+			return f, fmt.Errorf("%w: %s:%s: %v", ErrInvalidQuery, field, value, err)
+		}
+		f.at = at
+	}
+	return f, nil
+}
+
+var relativeTimeRe = regexp.MustCompile(`^(\d+)([mhdw])$`)
+
+// parseQueryTime parses the value of after: and before:. It accepts a
+// relative age (30m, 6h, 2d, 1w), today, yesterday, a date, or RFC3339.
+func parseQueryTime(s string, now time.Time) (time.Time, error) {
+	if m := relativeTimeRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{
+			"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour,
+		}[m[2]]
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch s {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(s)); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("use an age like 2d, a date like 2006-01-02, or RFC3339")
+}
+
+// matches reports whether a filter selects a message.
+func (f QueryFilter) matches(doc *IndexedMessage) bool {
+	return f.test(doc) != f.Negate
+}
+
+func (f QueryFilter) test(doc *IndexedMessage) bool {
+	contains := func(s string) bool { return strings.Contains(strings.ToLower(s), f.Value) }
+
+	switch f.Field {
+	case "from":
+		return contains(doc.From)
+	case "to":
+		if contains(doc.To) {
+			return true
+		}
+		for _, cc := range doc.CC {
+			if contains(cc) {
+				return true
+			}
+		}
+		return false
+	case "subject":
+		return contains(doc.Subject)
+	case "body":
+		return contains(doc.Body)
+	case "thread":
+		return strings.EqualFold(doc.ThreadID, f.Value)
+	case "id":
+		return strings.EqualFold(doc.ID, f.Value)
+	case "label":
+		for _, l := range doc.Labels {
+			if strings.EqualFold(l, f.Value) {
+				return true
+			}
+		}
+		return false
+	case "type":
+		return string(doc.Type) == f.Value
+	case "priority":
+		return string(doc.Priority) == f.Value
+	case "after":
+		return !doc.Timestamp.Before(f.at)
+	case "before":
+		return doc.Timestamp.Before(f.at)
+	case "is":
+		switch f.Value {
+		case "unread":
+			return !doc.Read
+		case "read":
+			return doc.Read
+		case "archived":
+			return doc.Archived
+		case "pinned":
+			return doc.Pinned
+		case "wisp":
+			return doc.Wisp
+		case "scheduled":
+			return doc.isScheduled()
+		}
+	}
+	return false
+}
+
+// tokenize splits text into lowercase search terms.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
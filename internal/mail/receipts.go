@@ -0,0 +1,353 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Labels for delivery tracking and reply deadlines on message beads.
+// Receipts are recorded per recipient identity, so one bead carries the
+// state of its primary recipient and every CC recipient:
+//
+//	delivered:gastown/polecats/Toast@2026-01-02T15:04:05Z
+//	read-by:mayor/@2026-01-02T15:10:00Z
+const (
+	labelDelivered = "delivered:"
+	labelReadBy    = "read-by:"
+	labelReplyBy   = "reply-by:"
+	labelFanout    = "fanout:"
+
+	// LabelReplyRequired marks messages that carry a reply-by deadline,
+	// so the Deacon can find them with a single label query.
+	LabelReplyRequired = "reply-required"
+
+	// LabelReplyEscalated marks overdue messages that were already escalated.
+	LabelReplyEscalated = "reply-escalated"
+)
+
+// Receipt is one recipient's delivery and read state for a message.
+type Receipt struct {
+	// Recipient is the beads identity of the recipient.
+	Recipient string `json:"recipient"`
+
+	// DeliveredAt is when the message first showed up in the recipient's inbox.
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+
+	// ReadAt is when the recipient first read the message.
+	ReadAt *time.Time `json:"read_at,omitempty"`
+}
+
+// receiptLabel formats a delivered: or read-by: label for identity.
+func receiptLabel(prefix, identity string, at time.Time) string {
+	return prefix + identity + "@" + at.UTC().Format(time.RFC3339)
+}
+
+// parseReceipts collects the receipts recorded in a message's labels.
+// If a receipt was recorded more than once, the earliest time wins.
+func parseReceipts(labels []string) []Receipt {
+	var receipts []Receipt
+	index := make(map[string]int)
+	for _, label := range labels {
+		var prefix string
+		switch {
+		case strings.HasPrefix(label, labelDelivered):
+			prefix = labelDelivered
+		case strings.HasPrefix(label, labelReadBy):
+			prefix = labelReadBy
+		default:
+			continue
+		}
+		rest := strings.TrimPrefix(label, prefix)
+		at := strings.LastIndex(rest, "@")
+		if at <= 0 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, rest[at+1:])
+		if err != nil {
+			continue
+		}
+		identity := rest[:at]
+		i, ok := index[identity]
+		if !ok {
+			i = len(receipts)
+			index[identity] = i
+			receipts = append(receipts, Receipt{Recipient: identity})
+		}
+		field := &receipts[i].DeliveredAt
+		if prefix == labelReadBy {
+			field = &receipts[i].ReadAt
+		}
+		if *field == nil || t.Before(**field) {
+			*field = &t
+		}
+	}
+	return receipts
+}
+
+// ReceiptFor returns the receipt recorded for an address or identity.
+// The zero Receipt is returned if the recipient has none.
+func (m *Message) ReceiptFor(address string) Receipt {
+	identity := AddressToIdentity(address)
+	for _, r := range m.Receipts {
+		if r.Recipient == identity {
+			return r
+		}
+	}
+	return Receipt{Recipient: identity}
+}
+
+// ReplyOverdue reports whether a task message has passed its reply-by deadline.
+// It does not know about replies; callers check those separately.
+func (m *Message) ReplyOverdue(now time.Time) bool {
+	return m.Type == TypeTask && m.ReplyBy != nil && now.After(*m.ReplyBy)
+}
+
+// MarkDelivered records a delivery receipt for this mailbox's identity on
+// each message that does not have one yet. It is called when messages are
+// listed for the recipient, so delivered means "seen in the inbox".
+// Receipts are best-effort: a failure on one message does not stop the rest.
+func (m *Mailbox) MarkDelivered(messages []*Message) error {
+	if m.legacy {
+		return nil
+	}
+	now := timeNow()
+	var lastErr error
+	for _, msg := range messages {
+		if msg.ReceiptFor(m.identity).DeliveredAt != nil {
+			continue
+		}
+		label := receiptLabel(labelDelivered, m.identity, now)
+		if _, err := runBdCommand([]string{"label", "add", msg.ID, label}, m.workDir, m.beadsDir); err != nil {
+			lastErr = err
+			continue
+		}
+		msg.Receipts = append(msg.Receipts, Receipt{Recipient: m.identity, DeliveredAt: &now})
+	}
+	return lastErr
+}
+
+// addReadReceipt records that this mailbox's identity read a message.
+func (m *Mailbox) addReadReceipt(id, beadsDir string) error {
+	label := receiptLabel(labelReadBy, m.identity, timeNow())
+	_, err := runBdCommand([]string{"label", "add", id, label}, m.workDir, beadsDir)
+	if err != nil {
+		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
+			return ErrMessageNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RecipientStatus is the state of one recipient of a message.
+type RecipientStatus struct {
+	// Recipient is the recipient's address.
+	Recipient string `json:"recipient"`
+
+	// MessageID is the bead holding the recipient's copy.
+	MessageID string `json:"message_id"`
+
+	// CC is true for CC recipients, who share the primary recipient's copy.
+	CC bool `json:"cc,omitempty"`
+
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	RepliedAt   *time.Time `json:"replied_at,omitempty"`
+
+	// Read is true if the recipient read the message, even if no read
+	// receipt was recorded (messages read before receipts existed).
+	Read bool `json:"read"`
+
+	// Overdue is true if a reply was required by a deadline that passed.
+	Overdue bool `json:"overdue,omitempty"`
+}
+
+// State summarizes the recipient's progress: pending, delivered, read or replied.
+func (s RecipientStatus) State() string {
+	switch {
+	case s.RepliedAt != nil:
+		return "replied"
+	case s.Read:
+		return "read"
+	case s.DeliveredAt != nil:
+		return "delivered"
+	default:
+		return "pending"
+	}
+}
+
+// DeliveryStatus is the per-recipient state of a message and, for list and
+// group sends, of every copy in its fan-out.
+type DeliveryStatus struct {
+	Message    *Message          `json:"message"`
+	Recipients []RecipientStatus `json:"recipients"`
+}
+
+// DeliveryStatus loads a message, the other copies of its fan-out and the
+// replies to each copy, and reports the state of every recipient.
+func (r *Router) DeliveryStatus(id string, now time.Time) (*DeliveryStatus, error) {
+	msg, err := r.getMessage(id)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := []*Message{msg}
+	if msg.FanoutID != "" {
+		if copies, err = r.listMessages(labelFanout+msg.FanoutID, "all"); err != nil {
+			return nil, fmt.Errorf("listing fan-out %s: %w", msg.FanoutID, err)
+		}
+	}
+
+	replies := make(map[string][]*Message, len(copies))
+	for _, c := range copies {
+		if replies[c.ID], err = r.listMessages("reply-to:"+c.ID, "all"); err != nil {
+			return nil, fmt.Errorf("listing replies to %s: %w", c.ID, err)
+		}
+	}
+
+	return &DeliveryStatus{
+		Message:    msg,
+		Recipients: recipientStatuses(copies, replies, now),
+	}, nil
+}
+
+// recipientStatuses computes the state of the primary and CC recipients of
+// each copy. replies maps a copy's ID to the messages replying to it.
+func recipientStatuses(copies []*Message, replies map[string][]*Message, now time.Time) []RecipientStatus {
+	var statuses []RecipientStatus
+	for _, c := range copies {
+		recipients := append([]string{c.To}, c.CC...)
+		for i, addr := range recipients {
+			receipt := c.ReceiptFor(addr)
+			s := RecipientStatus{
+				Recipient:   addr,
+				MessageID:   c.ID,
+				CC:          i > 0,
+				DeliveredAt: receipt.DeliveredAt,
+				ReadAt:      receipt.ReadAt,
+				Read:        receipt.ReadAt != nil,
+			}
+			if !s.CC && c.Read {
+				s.Read = true
+			}
+			s.RepliedAt = firstReplyFrom(replies[c.ID], addr)
+			if !s.CC && s.RepliedAt == nil && c.ReplyOverdue(now) {
+				s.Overdue = true
+			}
+			statuses = append(statuses, s)
+		}
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].CC != statuses[j].CC {
+			return !statuses[i].CC
+		}
+		return statuses[i].Recipient < statuses[j].Recipient
+	})
+	return statuses
+}
+
+// firstReplyFrom returns when address first replied, or nil.
+func firstReplyFrom(replies []*Message, address string) *time.Time {
+	identity := AddressToIdentity(address)
+	var first *time.Time
+	for _, reply := range replies {
+		if AddressToIdentity(reply.From) != identity {
+			continue
+		}
+		if first == nil || reply.Timestamp.Before(*first) {
+			t := reply.Timestamp
+			first = &t
+		}
+	}
+	return first
+}
+
+// OverdueReplies returns task messages whose reply-by deadline has passed
+// without a reply from the recipient and that were not escalated yet.
+func (r *Router) OverdueReplies(now time.Time) ([]*Message, error) {
+	candidates, err := r.listMessages(LabelReplyRequired, "all")
+	if err != nil {
+		return nil, fmt.Errorf("listing reply-required mail: %w", err)
+	}
+
+	var overdue []*Message
+	for _, msg := range candidates {
+		if msg.ReplyEscalated || !msg.ReplyOverdue(now) {
+			continue
+		}
+		replies, err := r.listMessages("reply-to:"+msg.ID, "all")
+		if err != nil {
+			return nil, fmt.Errorf("listing replies to %s: %w", msg.ID, err)
+		}
+		if firstReplyFrom(replies, msg.To) == nil {
+			overdue = append(overdue, msg)
+		}
+	}
+	return overdue, nil
+}
+
+// MarkReplyEscalated records that an overdue message was escalated, so the
+// next patrol does not escalate it again.
+func (r *Router) MarkReplyEscalated(id string) error {
+	beadsDir := r.resolveBeadsDir("")
+	if _, err := runBdCommand([]string{"label", "add", id, LabelReplyEscalated}, filepath.Dir(beadsDir), beadsDir); err != nil {
+		return fmt.Errorf("marking %s escalated: %w", id, err)
+	}
+	return nil
+}
+
+// getMessage loads any message from town beads, regardless of recipient.
+func (r *Router) getMessage(id string) (*Message, error) {
+	beadsDir := r.resolveBeadsDir("")
+	stdout, err := runBdCommand([]string{"show", id, "--json"}, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	var bms []BeadsMessage
+	if err := json.Unmarshal(stdout, &bms); err != nil {
+		return nil, fmt.Errorf("parsing message %s: %w", id, err)
+	}
+	if len(bms) == 0 {
+		return nil, ErrMessageNotFound
+	}
+	return bms[0].ToMessage(), nil
+}
+
+// listMessages returns messages in town beads with a label and status
+// (open, closed or all).
+func (r *Router) listMessages(label, status string) ([]*Message, error) {
+	beadsDir := r.resolveBeadsDir("")
+	if err := r.ensureCustomTypes(beadsDir); err != nil {
+		return nil, err
+	}
+	args := []string{"list",
+		"--type", "message",
+		"--label", label,
+		"--status=" + status,
+		"--limit=0",
+		"--json",
+	}
+	stdout, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(stdout) == 0 || string(stdout) == "null" {
+		return nil, nil
+	}
+	var bms []BeadsMessage
+	if err := json.Unmarshal(stdout, &bms); err != nil {
+		return nil, fmt.Errorf("parsing messages: %w", err)
+	}
+	messages := make([]*Message, 0, len(bms))
+	for i := range bms {
+		messages = append(messages, bms[i].ToMessage())
+	}
+	return messages, nil
+}
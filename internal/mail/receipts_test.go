@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBeadsMessageToMessageWithReceipts(t *testing.T) {
+	bm := BeadsMessage{
+		ID:       "hq-task",
+		Status:   "open",
+		Assignee: "gastown/witness",
+		Labels: []string{
+			"from:mayor/",
+			"msg-type:task",
+			"cc:overseer",
+			"reply-by:2026-01-02T18:00:00Z",
+			LabelReplyRequired,
+			"fanout:fanout-abc",
+			"delivered:gastown/witness@2026-01-02T15:05:00Z",
+			"read-by:gastown/witness@2026-01-02T15:20:00Z",
+			"read-by:gastown/witness@2026-01-02T15:10:00Z",
+			"delivered:overseer@2026-01-02T16:00:00Z",
+			"delivered:bogus",
+		},
+	}
+
+	msg := bm.ToMessage()
+
+	if msg.Type != TypeTask {
+		t.Errorf("Type = %q, want task", msg.Type)
+	}
+	if msg.ReplyBy == nil || !msg.ReplyBy.Equal(time.Date(2026, 1, 2, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("ReplyBy = %v, want 2026-01-02T18:00:00Z", msg.ReplyBy)
+	}
+	if msg.FanoutID != "fanout-abc" {
+		t.Errorf("FanoutID = %q, want fanout-abc", msg.FanoutID)
+	}
+	if msg.ReplyEscalated {
+		t.Error("ReplyEscalated = true without the label")
+	}
+	if len(msg.Receipts) != 2 {
+		t.Fatalf("Receipts = %+v, want 2", msg.Receipts)
+	}
+
+	witness := msg.ReceiptFor("gastown/witness")
+	if witness.DeliveredAt == nil || witness.ReadAt == nil {
+		t.Fatalf("witness receipt = %+v, want delivered and read", witness)
+	}
+	if got := witness.ReadAt.Format("15:04"); got != "15:10" {
+		t.Errorf("witness ReadAt = %s, want the earliest (15:10)", got)
+	}
+	if overseer := msg.ReceiptFor("overseer"); overseer.DeliveredAt == nil || overseer.ReadAt != nil {
+		t.Errorf("overseer receipt = %+v, want delivered only", overseer)
+	}
+	if r := msg.ReceiptFor("gastown/refinery"); r.DeliveredAt != nil || r.ReadAt != nil {
+		t.Errorf("unknown recipient receipt = %+v, want zero", r)
+	}
+}
+
+func TestReplyOverdue(t *testing.T) {
+	deadline := time.Date(2026, 1, 2, 18, 0, 0, 0, time.UTC)
+	before := deadline.Add(-time.Minute)
+	after := deadline.Add(time.Minute)
+
+	tests := []struct {
+		name string
+		msg  Message
+		now  time.Time
+		want bool
+	}{
+		{"task before deadline", Message{Type: TypeTask, ReplyBy: &deadline}, before, false},
+		{"task after deadline", Message{Type: TypeTask, ReplyBy: &deadline}, after, true},
+		{"notification after deadline", Message{Type: TypeNotification, ReplyBy: &deadline}, after, false},
+		{"task without deadline", Message{Type: TypeTask}, after, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.msg.ReplyOverdue(tt.now); got != tt.want {
+				t.Errorf("ReplyOverdue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecipientStatuses(t *testing.T) {
+	deadline := time.Date(2026, 1, 2, 18, 0, 0, 0, time.UTC)
+	delivered := deadline.Add(-3 * time.Hour)
+	read := deadline.Add(-2 * time.Hour)
+	now := deadline.Add(time.Hour)
+
+	// A list fan-out to witness and refinery, CC'ing the overseer.
+	copies := []*Message{
+		{
+			ID: "hq-1", To: "gastown/witness", Type: TypeTask, ReplyBy: &deadline,
+			CC: []string{"overseer"},
+			Receipts: []Receipt{
+				{Recipient: "gastown/witness", DeliveredAt: &delivered, ReadAt: &read},
+				{Recipient: "overseer", DeliveredAt: &delivered},
+			},
+		},
+		{
+			ID: "hq-2", To: "gastown/refinery", Type: TypeTask, ReplyBy: &deadline,
+			CC:       []string{"overseer"},
+			Receipts: []Receipt{{Recipient: "gastown/refinery", DeliveredAt: &delivered}},
+		},
+	}
+	replies := map[string][]*Message{
+		"hq-1": {
+			{ID: "hq-9", From: "overseer", Timestamp: read},
+			{ID: "hq-3", From: "gastown/witness", Timestamp: read.Add(time.Minute)},
+		},
+	}
+
+	statuses := recipientStatuses(copies, replies, now)
+
+	type row struct {
+		recipient, id, state string
+		cc, overdue          bool
+	}
+	want := []row{
+		{"gastown/refinery", "hq-2", "delivered", false, true},
+		{"gastown/witness", "hq-1", "replied", false, false},
+		{"overseer", "hq-1", "replied", true, false},
+		{"overseer", "hq-2", "pending", true, false},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("got %d statuses, want %d: %+v", len(statuses), len(want), statuses)
+	}
+	for i, w := range want {
+		s := statuses[i]
+		got := row{s.Recipient, s.MessageID, s.State(), s.CC, s.Overdue}
+		if got != w {
+			t.Errorf("status[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
@@ -625,7 +626,14 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // Supports single-copy delivery for:
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
+// Messages with a future DeliverAt are stored as scheduled beads instead and
+// go through this same routing when the daemon releases them.
 func (r *Router) Send(msg *Message) error {
+	// Deferred delivery - held until the daemon releases it
+	if msg.DeliverAt != nil && msg.DeliverAt.After(timeNow()) {
+		return r.schedule(msg)
+	}
+
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -671,12 +679,17 @@ func (r *Router) sendToGroup(msg *Message) error {
 		return fmt.Errorf("no recipients found for group: %s", msg.To)
 	}
 
-	// Fan-out: send a copy to each recipient
+	// Fan-out: send a copy to each recipient, linked by a shared fan-out ID
+	fanoutID := msg.FanoutID
+	if fanoutID == "" {
+		fanoutID = NewFanoutID()
+	}
 	var errs []string
 	for _, recipient := range recipients {
 		// Create a copy of the message for this recipient
 		msgCopy := *msg
 		msgCopy.To = recipient
+		msgCopy.FanoutID = fanoutID
 
 		if err := r.sendToSingle(&msgCopy); err != nil {
 			errs = append(errs, fmt.Sprintf("%s: %v", recipient, err))
@@ -762,6 +775,17 @@ func (r *Router) sendToSingle(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	if msg.Type != "" && msg.Type != TypeNotification {
+		labels = append(labels, "msg-type:"+string(msg.Type))
+	}
+	// Reply deadline, plus a fixed label the Deacon can query for
+	if msg.ReplyBy != nil {
+		labels = append(labels, labelReplyBy+msg.ReplyBy.UTC().Format(time.RFC3339), LabelReplyRequired)
+	}
+	// Link the copies of a list/group fan-out for gt mail status
+	if msg.FanoutID != "" {
+		labels = append(labels, labelFanout+msg.FanoutID)
+	}
 
 	// Build command: bd create <subject> --type=message --assignee=<recipient> -d <body>
 	args := []string{"create", msg.Subject,
@@ -797,8 +821,9 @@ func (r *Router) sendToSingle(msg *Message) error {
 	}
 
 	// Notify recipient if they have an active session (best-effort notification)
-	// Skip notification for self-mail (handoffs to future-self don't need present-self notified)
-	if !isSelfMail(msg.From, msg.To) {
+	// Skip notification for self-mail (handoffs to future-self don't need present-self notified),
+	// unless it was scheduled: a delayed note to self is a reminder
+	if !isSelfMail(msg.From, msg.To) || msg.DeliverAt != nil {
 		_ = r.notifyRecipient(msg)
 	}
 
@@ -815,13 +840,18 @@ func (r *Router) sendToList(msg *Message) error {
 		return err
 	}
 
-	// Send to each recipient
+	// Send to each recipient, linking the copies by a shared fan-out ID
+	fanoutID := msg.FanoutID
+	if fanoutID == "" {
+		fanoutID = NewFanoutID()
+	}
 	var lastErr error
 	successCount := 0
 	for _, recipient := range recipients {
 		// Create a copy of the message for this recipient
 		copy := *msg
 		copy.To = recipient
+		copy.FanoutID = fanoutID
 
 		if err := r.Send(&copy); err != nil {
 			lastErr = err
@@ -1173,6 +1203,10 @@ func (r *Router) notifyRecipient(msg *Message) error {
 
 		// Send notification to the agent's conversation history
 		notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
+		if msg.Delivery == DeliveryInterrupt {
+			// Interrupt delivery puts the message itself in front of the agent
+			notification = fmt.Sprintf("📬 Message from %s: %s\n\n%s", msg.From, msg.Subject, StripEnvelope(msg.Body))
+		}
 		return r.tmux.NudgeSession(sessionID, notification)
 	}
 
@@ -0,0 +1,201 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Labels for scheduled (deferred) messages. A scheduled message is an open
+// message bead with no assignee; its recipients live in scheduled-to: and
+// scheduled-cc: labels so it does not show up in any inbox until released.
+const (
+	// LabelScheduled marks a message bead waiting for its delivery time.
+	LabelScheduled = "scheduled"
+
+	labelDeliverAt   = "deliver-at:"
+	labelDelivery    = "delivery:"
+	labelScheduledTo = "scheduled-to:"
+	labelScheduledCC = "scheduled-cc:"
+)
+
+// schedule stores a message with a future DeliverAt as a scheduled bead.
+// The message's ID is set to the scheduled bead's ID, which identifies it
+// for gt mail scheduled cancel.
+func (r *Router) schedule(msg *Message) error {
+	if err := r.validateScheduledAddress(msg.To); err != nil {
+		return err
+	}
+	for _, cc := range msg.CC {
+		if err := r.validateRecipient(AddressToIdentity(cc)); err != nil {
+			return fmt.Errorf("invalid CC recipient %q: %w", cc, err)
+		}
+	}
+
+	labels := []string{
+		"from:" + msg.From,
+		LabelScheduled,
+		labelDeliverAt + msg.DeliverAt.UTC().Format(time.RFC3339),
+		labelScheduledTo + msg.To,
+	}
+	for _, cc := range msg.CC {
+		labels = append(labels, labelScheduledCC+cc)
+	}
+	if msg.ThreadID != "" {
+		labels = append(labels, "thread:"+msg.ThreadID)
+	}
+	if msg.ReplyTo != "" {
+		labels = append(labels, "reply-to:"+msg.ReplyTo)
+	}
+	if msg.Type != "" && msg.Type != TypeNotification {
+		labels = append(labels, "msg-type:"+string(msg.Type))
+	}
+	if msg.Delivery != "" {
+		labels = append(labels, labelDelivery+string(msg.Delivery))
+	}
+	// reply-required is added on release, so the Deacon only sees the
+	// deadline once the recipient has the message
+	if msg.ReplyBy != nil {
+		labels = append(labels, labelReplyBy+msg.ReplyBy.UTC().Format(time.RFC3339))
+	}
+
+	args := []string{"create", msg.Subject,
+		"--type", "message",
+		"-d", msg.Body,
+		"--priority", fmt.Sprintf("%d", PriorityToBeads(msg.Priority)),
+		"--labels", strings.Join(labels, ","),
+		"--actor", msg.From,
+		"--json",
+	}
+
+	// Scheduled messages are never ephemeral - they must survive until released
+	beadsDir := r.resolveBeadsDir("")
+	if err := r.ensureCustomTypes(beadsDir); err != nil {
+		return err
+	}
+	stdout, err := runBdCommand(args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return fmt.Errorf("scheduling message: %w", err)
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(stdout, &created); err != nil {
+		return fmt.Errorf("parsing scheduled message: %w", err)
+	}
+	msg.ID = created.ID
+	return nil
+}
+
+// validateScheduledAddress checks the recipient of a scheduled message up
+// front, so that a typo fails at send time rather than when the daemon
+// releases the message hours later.
+func (r *Router) validateScheduledAddress(address string) error {
+	switch {
+	case isListAddress(address):
+		_, err := r.expandList(parseListName(address))
+		return err
+	case isQueueAddress(address):
+		_, err := r.expandQueue(parseQueueName(address))
+		return err
+	case isAnnounceAddress(address):
+		_, err := r.expandAnnounce(parseAnnounceName(address))
+		return err
+	case isChannelAddress(address), isGroupAddress(address):
+		// Membership is resolved on release
+		return nil
+	}
+	if err := r.validateRecipient(AddressToIdentity(address)); err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", address, err)
+	}
+	return nil
+}
+
+// ListScheduled returns the messages waiting for delivery, soonest first.
+func (r *Router) ListScheduled() ([]*Message, error) {
+	messages, err := r.listMessages(LabelScheduled, "open")
+	if err != nil {
+		return nil, fmt.Errorf("listing scheduled mail: %w", err)
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return deliverTime(messages[i]).Before(deliverTime(messages[j]))
+	})
+	return messages, nil
+}
+
+// deliverTime returns when a scheduled message is due; messages without a
+// parsable time are due immediately.
+func deliverTime(msg *Message) time.Time {
+	if msg.DeliverAt == nil {
+		return time.Time{}
+	}
+	return *msg.DeliverAt
+}
+
+// CancelScheduled cancels a scheduled message that has not been released.
+func (r *Router) CancelScheduled(id string) error {
+	msg, err := r.getMessage(id)
+	if err != nil {
+		return err
+	}
+	if msg.DeliverAt == nil {
+		return fmt.Errorf("%s is not a scheduled message", id)
+	}
+	if msg.Read {
+		return fmt.Errorf("%s was already released or cancelled", id)
+	}
+	return r.closeScheduled(id, "cancelled")
+}
+
+// ReleaseDue delivers every scheduled message whose time has come, through
+// the normal Send path (fan-out, notification and interrupt delivery). It
+// returns the released messages; a failure to deliver one message does not
+// stop the rest and leaves that message scheduled for the next attempt.
+func (r *Router) ReleaseDue(now time.Time) ([]*Message, error) {
+	scheduled, err := r.ListScheduled()
+	if err != nil {
+		return nil, err
+	}
+
+	var released []*Message
+	var errs []string
+	for _, s := range scheduled {
+		if deliverTime(s).After(now) {
+			continue
+		}
+		// Close first: a message that cannot be marked released must not be
+		// delivered, or every heartbeat would deliver it again.
+		if err := r.closeScheduled(s.ID, "released"); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", s.ID, err))
+			continue
+		}
+		msg := *s
+		msg.ID = ""
+		msg.Read = false
+		if err := r.Send(&msg); err != nil {
+			beadsDir := r.resolveBeadsDir("")
+			_, _ = runBdCommand([]string{"reopen", s.ID}, filepath.Dir(beadsDir), beadsDir)
+			errs = append(errs, fmt.Sprintf("%s: %v", s.ID, err))
+			continue
+		}
+		released = append(released, s)
+	}
+
+	if len(errs) > 0 {
+		return released, fmt.Errorf("releasing scheduled mail: %s", strings.Join(errs, "; "))
+	}
+	return released, nil
+}
+
+// closeScheduled closes a scheduled message bead with a reason.
+func (r *Router) closeScheduled(id, reason string) error {
+	beadsDir := r.resolveBeadsDir("")
+	if _, err := runBdCommand([]string{"close", id, "--reason=" + reason}, filepath.Dir(beadsDir), beadsDir); err != nil {
+		return fmt.Errorf("closing scheduled message %s: %w", id, err)
+	}
+	return nil
+}
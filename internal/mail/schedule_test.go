@@ -0,0 +1,51 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBeadsMessageToMessageScheduled(t *testing.T) {
+	bm := BeadsMessage{
+		ID:     "hq-sched",
+		Title:  "Rebase",
+		Status: "open",
+		Labels: []string{
+			"from:mayor/",
+			LabelScheduled,
+			"deliver-at:2026-01-03T02:00:00Z",
+			"scheduled-to:list:oncall",
+			"scheduled-cc:overseer",
+			"delivery:interrupt",
+			"msg-type:task",
+		},
+	}
+
+	msg := bm.ToMessage()
+
+	if msg.To != "list:oncall" {
+		t.Errorf("To = %q, want list:oncall", msg.To)
+	}
+	if len(msg.CC) != 1 || msg.CC[0] != "overseer" {
+		t.Errorf("CC = %v, want [overseer]", msg.CC)
+	}
+	if msg.DeliverAt == nil || !msg.DeliverAt.Equal(time.Date(2026, 1, 3, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("DeliverAt = %v, want 2026-01-03T02:00:00Z", msg.DeliverAt)
+	}
+	if msg.Delivery != DeliveryInterrupt {
+		t.Errorf("Delivery = %q, want interrupt", msg.Delivery)
+	}
+	if msg.Type != TypeTask {
+		t.Errorf("Type = %q, want task", msg.Type)
+	}
+}
+
+func TestDeliverTime(t *testing.T) {
+	at := time.Date(2026, 1, 3, 2, 0, 0, 0, time.UTC)
+	if got := deliverTime(&Message{DeliverAt: &at}); !got.Equal(at) {
+		t.Errorf("deliverTime = %v, want %v", got, at)
+	}
+	if got := deliverTime(&Message{}); !got.IsZero() {
+		t.Errorf("deliverTime without DeliverAt = %v, want zero (due now)", got)
+	}
+}
@@ -123,6 +123,24 @@ type Message struct {
 	// ClaimedAt is when the queue message was claimed.
 	// Only set for queue messages after claiming.
 	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+
+	// ReplyBy is the deadline for the recipient to reply to a task message.
+	// The Deacon escalates task messages still unanswered after it.
+	ReplyBy *time.Time `json:"reply_by,omitempty"`
+
+	// ReplyEscalated is set once an overdue reply has been escalated.
+	ReplyEscalated bool `json:"reply_escalated,omitempty"`
+
+	// FanoutID links the per-recipient copies of a list or group send.
+	FanoutID string `json:"fanout_id,omitempty"`
+
+	// Receipts records when each recipient (To or CC) got and read the message.
+	Receipts []Receipt `json:"receipts,omitempty"`
+
+	// DeliverAt defers delivery until the given time. Router.Send stores a
+	// message with a future DeliverAt as a scheduled bead, which the daemon
+	// releases once it is due.
+	DeliverAt *time.Time `json:"deliver_at,omitempty"`
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -266,6 +284,16 @@ func generateThreadID() string {
 	return "thread-" + hex.EncodeToString(b)
 }
 
+// NewFanoutID creates a random ID linking the copies of a fan-out send.
+// Falls back to time-based ID if crypto/rand fails (extremely rare).
+func NewFanoutID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("fanout-%x", time.Now().UnixNano())
+	}
+	return "fanout-" + hex.EncodeToString(b)
+}
+
 // BeadsMessage represents a message as returned by bd list/show commands.
 // Messages are beads issues with type=message and metadata stored in labels.
 type BeadsMessage struct {
@@ -276,7 +304,7 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X, reply-by:X, fanout:X, delivered:X, read-by:X)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (filtered from JSONL export)
 
@@ -290,6 +318,12 @@ type BeadsMessage struct {
 	channel   string     // Channel name (for broadcast messages)
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
+	replyBy   *time.Time // Reply deadline (for task messages)
+	fanoutID  string     // Shared ID of a list/group fan-out
+	deliverAt *time.Time // Delivery time (for scheduled messages)
+	delivery  string     // Delivery mode (for scheduled messages)
+	schedTo   string     // Recipient address (for scheduled messages)
+	schedCC   []string   // CC addresses (for scheduled messages)
 }
 
 // ParseLabels extracts metadata from the labels array.
@@ -316,6 +350,22 @@ func (bm *BeadsMessage) ParseLabels() {
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
 				bm.claimedAt = &t
 			}
+		} else if strings.HasPrefix(label, labelReplyBy) {
+			if t, err := time.Parse(time.RFC3339, strings.TrimPrefix(label, labelReplyBy)); err == nil {
+				bm.replyBy = &t
+			}
+		} else if strings.HasPrefix(label, labelFanout) {
+			bm.fanoutID = strings.TrimPrefix(label, labelFanout)
+		} else if strings.HasPrefix(label, labelDeliverAt) {
+			if t, err := time.Parse(time.RFC3339, strings.TrimPrefix(label, labelDeliverAt)); err == nil {
+				bm.deliverAt = &t
+			}
+		} else if strings.HasPrefix(label, labelDelivery) {
+			bm.delivery = strings.TrimPrefix(label, labelDelivery)
+		} else if strings.HasPrefix(label, labelScheduledTo) {
+			bm.schedTo = strings.TrimPrefix(label, labelScheduledTo)
+		} else if strings.HasPrefix(label, labelScheduledCC) {
+			bm.schedCC = append(bm.schedCC, strings.TrimPrefix(label, labelScheduledCC))
 		}
 	}
 }
@@ -366,10 +416,18 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		ccAddrs = append(ccAddrs, identityToAddress(cc))
 	}
 
+	// Scheduled messages keep their recipients out of the assignee and cc:
+	// labels so they stay out of inboxes until released
+	to := identityToAddress(bm.Assignee)
+	if bm.HasLabel(LabelScheduled) {
+		to = bm.schedTo
+		ccAddrs = bm.schedCC
+	}
+
 	return &Message{
 		ID:        bm.ID,
 		From:      identityToAddress(bm.sender),
-		To:        identityToAddress(bm.Assignee),
+		To:        to,
 		Subject:   bm.Title,
 		Body:      bm.Description,
 		Timestamp: bm.CreatedAt,
@@ -384,6 +442,13 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		Channel:   bm.channel,
 		ClaimedBy: bm.claimedBy,
 		ClaimedAt: bm.claimedAt,
+
+		ReplyBy:        bm.replyBy,
+		ReplyEscalated: bm.HasLabel(LabelReplyEscalated),
+		FanoutID:       bm.fanoutID,
+		Receipts:       parseReceipts(bm.Labels),
+		DeliverAt:      bm.deliverAt,
+		Delivery:       Delivery(bm.delivery),
 	}
 }
 
@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// defaultSMTPPort is the submission port used when smtp.port is unset.
+const defaultSMTPPort = 587
+
+// SMTPNotifier delivers notifications by email.
+type SMTPNotifier struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      string // "starttls", "tls", or "none"
+	To       string
+}
+
+// NewSMTPNotifier creates an email notifier from config.
+func NewSMTPNotifier(cfg *config.EscalationSMTPConfig, to string, getenv func(string) string) *SMTPNotifier {
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+	mode := cfg.TLS
+	if mode == "" {
+		mode = "starttls"
+	}
+	from := cfg.From
+	if from == "" {
+		from = cfg.Username
+	}
+	return &SMTPNotifier{
+		Host:     cfg.Host,
+		Port:     port,
+		Username: cfg.Username,
+		Password: resolveSecret(cfg.Password, cfg.PasswordEnv, getenv),
+		From:     from,
+		TLS:      mode,
+		To:       to,
+	}
+}
+
+// Channel implements Notifier.
+func (s *SMTPNotifier) Channel() string { return "email" }
+
+// Target implements Notifier.
+func (s *SMTPNotifier) Target() string { return s.To }
+
+// Send implements Notifier.
+func (s *SMTPNotifier) Send(ctx context.Context, n *Notification) error {
+	if s.From == "" {
+		return Permanent(fmt.Errorf("smtp.from not configured"))
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if s.TLS == "tls" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer c.Close()
+
+	if s.TLS == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+				return fmt.Errorf("smtp starttls: %w", err)
+			}
+		} else if s.Username != "" {
+			return Permanent(fmt.Errorf("smtp server %s does not support STARTTLS; refusing to send credentials (set smtp.tls to \"none\" for a local relay)", s.Host))
+		}
+	}
+
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return smtpError("smtp auth", err)
+		}
+	}
+
+	if err := c.Mail(s.From); err != nil {
+		return smtpError("smtp MAIL FROM", err)
+	}
+	if err := c.Rcpt(s.To); err != nil {
+		return smtpError("smtp RCPT TO", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError("smtp DATA", err)
+	}
+	if _, err := w.Write(s.message(n)); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return smtpError("smtp DATA", err)
+	}
+	return c.Quit()
+}
+
+// message renders the RFC 5322 message for a notification.
+func (s *SMTPNotifier) message(n *Notification) []byte {
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(n.Severity), n.Subject)
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+
+	date := n.Time
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var sb strings.Builder
+	sb.WriteString("From: " + s.From + "\r\n")
+	sb.WriteString("To: " + s.To + "\r\n")
+	sb.WriteString("Subject: " + subject + "\r\n")
+	sb.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	if n.ID != "" {
+		sb.WriteString("X-Gastown-Escalation: " + n.ID + "\r\n")
+	}
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	sb.WriteString("\r\n")
+
+	body := n.Body
+	if body == "" {
+		body = n.Subject
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		sb.WriteString(line + "\r\n")
+	}
+	return []byte(sb.String())
+}
+
+// smtpError wraps an SMTP error, marking 5xx replies as permanent.
+func smtpError(op string, err error) error {
+	wrapped := fmt.Errorf("%s: %w", op, err)
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) && tpErr.Code >= 500 {
+		return Permanent(wrapped)
+	}
+	return wrapped
+}
@@ -0,0 +1,244 @@
+// Package notify delivers escalations to humans over external channels:
+// SMTP email, HTTP webhooks (Slack, Discord, raw JSON), and SMS.
+//
+// Channels are configured in settings/escalation.json and selected by the
+// escalation route actions (email:human, sms:human, slack, discord,
+// webhook:<name>). Each delivery is retried with exponential backoff and
+// reported as a Delivery so callers can record the outcome on the
+// escalation bead.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Notification is the message delivered for an escalation.
+type Notification struct {
+	ID       string    `json:"id"`       // escalation bead ID
+	Severity string    `json:"severity"` // critical, high, medium, low
+	Subject  string    `json:"subject"`  // one-line summary
+	Body     string    `json:"body"`     // full text
+	From     string    `json:"from"`     // escalating agent
+	Source   string    `json:"source,omitempty"`
+	Related  string    `json:"related,omitempty"` // related bead ID
+	Time     time.Time `json:"time"`
+}
+
+// Text renders the notification as plain text for chat and SMS channels.
+func (n *Notification) Text() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("[%s] %s", strings.ToUpper(n.Severity), n.Subject))
+	if n.ID != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", n.ID))
+	}
+	if n.Body != "" && n.Body != n.Subject {
+		sb.WriteString("\n" + n.Body)
+	}
+	return sb.String()
+}
+
+// Notifier sends a notification over one channel.
+type Notifier interface {
+	// Channel names the channel for delivery records (e.g., "email").
+	Channel() string
+
+	// Target describes the recipient for delivery records.
+	Target() string
+
+	// Send delivers the notification once. Errors wrapped with Permanent
+	// are not retried.
+	Send(ctx context.Context, n *Notification) error
+}
+
+// Delivery records the outcome of one external action.
+type Delivery struct {
+	Action   string    `json:"action"`
+	Channel  string    `json:"channel,omitempty"`
+	Target   string    `json:"target,omitempty"`
+	Success  bool      `json:"success"`
+	Skipped  bool      `json:"skipped,omitempty"` // channel not configured
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// Dispatcher resolves route actions to notifiers and delivers with retries.
+type Dispatcher struct {
+	cfg    *config.EscalationConfig
+	retry  RetryPolicy
+	client *http.Client
+	getenv func(string) string
+
+	// sms overrides the configured SMS provider (for tests and custom backends).
+	sms SMSProvider
+}
+
+// NewDispatcher creates a Dispatcher for an escalation config.
+func NewDispatcher(cfg *config.EscalationConfig) *Dispatcher {
+	if cfg == nil {
+		cfg = config.NewEscalationConfig()
+	}
+	retry := RetryPolicyFromConfig(cfg.Delivery)
+	return &Dispatcher{
+		cfg:    cfg,
+		retry:  retry,
+		client: &http.Client{Timeout: retry.Timeout},
+		getenv: os.Getenv,
+	}
+}
+
+// SetSMSProvider replaces the SMS provider built from config.
+func (d *Dispatcher) SetSMSProvider(p SMSProvider) {
+	d.sms = p
+}
+
+// IsExternalAction reports whether an action is delivered by this package.
+// Other actions (bead, mail:, log) are handled by the escalate command.
+func IsExternalAction(action string) bool {
+	switch {
+	case strings.HasPrefix(action, "email:"),
+		strings.HasPrefix(action, "sms:"),
+		strings.HasPrefix(action, "webhook:"),
+		action == "slack",
+		action == "discord":
+		return true
+	}
+	return false
+}
+
+// Dispatch delivers the notification for each external action in order.
+// Non-external actions are ignored. Every external action yields a Delivery,
+// including ones skipped because the channel is not configured.
+func (d *Dispatcher) Dispatch(ctx context.Context, actions []string, n *Notification) []Delivery {
+	var deliveries []Delivery
+	for _, action := range actions {
+		if !IsExternalAction(action) {
+			continue
+		}
+		delivery := Delivery{Action: action, At: time.Now()}
+
+		notifier, err := d.notifierFor(action)
+		if err != nil {
+			delivery.Skipped = true
+			delivery.Error = err.Error()
+			deliveries = append(deliveries, delivery)
+			continue
+		}
+		delivery.Channel = notifier.Channel()
+		delivery.Target = notifier.Target()
+
+		attempts, err := d.retry.Do(ctx, func(ctx context.Context) error {
+			return notifier.Send(ctx, n)
+		})
+		delivery.Attempts = attempts
+		delivery.Success = err == nil
+		if err != nil {
+			delivery.Error = err.Error()
+		}
+		delivery.At = time.Now()
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+// notifierFor builds the notifier for an action from config. It returns an
+// error naming the missing setting if the channel is not configured.
+func (d *Dispatcher) notifierFor(action string) (Notifier, error) {
+	contacts := d.cfg.Contacts
+	switch {
+	case strings.HasPrefix(action, "email:"):
+		if contacts.HumanEmail == "" {
+			return nil, fmt.Errorf("contacts.human_email not configured")
+		}
+		if d.cfg.SMTP == nil || d.cfg.SMTP.Host == "" {
+			return nil, fmt.Errorf("smtp.host not configured")
+		}
+		return NewSMTPNotifier(d.cfg.SMTP, contacts.HumanEmail, d.getenv), nil
+
+	case strings.HasPrefix(action, "sms:"):
+		if contacts.HumanSMS == "" {
+			return nil, fmt.Errorf("contacts.human_sms not configured")
+		}
+		provider := d.sms
+		if provider == nil {
+			if d.cfg.SMS == nil {
+				return nil, fmt.Errorf("sms provider not configured")
+			}
+			var err error
+			provider, err = NewSMSProvider(d.cfg.SMS, d.client, d.getenv)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &SMSNotifier{Provider: provider, To: contacts.HumanSMS}, nil
+
+	case action == "slack":
+		if contacts.SlackWebhook == "" {
+			return nil, fmt.Errorf("contacts.slack_webhook not configured")
+		}
+		return NewWebhookNotifier("slack", &config.EscalationWebhookConfig{URL: contacts.SlackWebhook, Format: FormatSlack}, d.client), nil
+
+	case action == "discord":
+		if contacts.DiscordWebhook == "" {
+			return nil, fmt.Errorf("contacts.discord_webhook not configured")
+		}
+		return NewWebhookNotifier("discord", &config.EscalationWebhookConfig{URL: contacts.DiscordWebhook, Format: FormatDiscord}, d.client), nil
+
+	case strings.HasPrefix(action, "webhook:"):
+		name := strings.TrimPrefix(action, "webhook:")
+		wh := d.cfg.Webhooks[name]
+		if wh == nil || wh.URL == "" {
+			return nil, fmt.Errorf("webhooks.%s not configured", name)
+		}
+		return NewWebhookNotifier(name, wh, d.client), nil
+	}
+	return nil, fmt.Errorf("unknown action %q", action)
+}
+
+// FormatDeliveries renders delivery outcomes as a bead comment.
+func FormatDeliveries(deliveries []Delivery) string {
+	var sb strings.Builder
+	sb.WriteString("External notification delivery:\n")
+	for _, d := range deliveries {
+		status := "delivered"
+		switch {
+		case d.Skipped:
+			status = "skipped"
+		case !d.Success:
+			status = "FAILED"
+		}
+		sb.WriteString(fmt.Sprintf("  - %s: %s", d.Action, status))
+		if d.Target != "" {
+			sb.WriteString(fmt.Sprintf(" → %s", d.Target))
+		}
+		if d.Attempts > 0 {
+			sb.WriteString(fmt.Sprintf(" (%d attempt", d.Attempts))
+			if d.Attempts != 1 {
+				sb.WriteString("s")
+			}
+			sb.WriteString(")")
+		}
+		if d.Error != "" {
+			sb.WriteString(": " + d.Error)
+		}
+		sb.WriteString(fmt.Sprintf(" at %s\n", d.At.Format(time.RFC3339)))
+	}
+	return sb.String()
+}
+
+// resolveSecret returns value, or the named environment variable if set.
+func resolveSecret(value, envName string, getenv func(string) string) string {
+	if envName != "" {
+		if v := getenv(envName); v != "" {
+			return v
+		}
+	}
+	return value
+}
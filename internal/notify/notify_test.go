@@ -0,0 +1,367 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func testNotification() *Notification {
+	return &Notification{
+		ID:       "hq-esc-1",
+		Severity: config.SeverityCritical,
+		Subject:  "Refinery is stuck",
+		Body:     "Merge queue has not moved in 2h.",
+		From:     "gastown/witness",
+		Time:     time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+// noSleep makes retries immediate and records the backoffs.
+func noSleep(d *Dispatcher) *[]time.Duration {
+	var waits []time.Duration
+	d.retry.sleep = func(_ context.Context, dur time.Duration) error {
+		waits = append(waits, dur)
+		return nil
+	}
+	return &waits
+}
+
+func TestDispatch_SlackWebhook(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = srv.URL
+	d := NewDispatcher(cfg)
+
+	deliveries := d.Dispatch(context.Background(), []string{"bead", "mail:mayor", "slack"}, testNotification())
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1 (non-external actions ignored)", len(deliveries))
+	}
+	if !deliveries[0].Success || deliveries[0].Attempts != 1 {
+		t.Errorf("delivery = %+v, want success on first attempt", deliveries[0])
+	}
+	text, _ := got["text"].(string)
+	if !strings.Contains(text, "[CRITICAL] Refinery is stuck (hq-esc-1)") {
+		t.Errorf("slack text = %q", text)
+	}
+}
+
+func TestDispatch_JSONTemplateWebhook(t *testing.T) {
+	var body []byte
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		auth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	cfg := config.NewEscalationConfig()
+	cfg.Webhooks = map[string]*config.EscalationWebhookConfig{
+		"pager": {
+			URL:      srv.URL,
+			Template: `{"summary": {{json .Subject}}, "sev": {{json .Severity}}}`,
+			Headers:  map[string]string{"Authorization": "Token abc"},
+		},
+	}
+	deliveries := NewDispatcher(cfg).Dispatch(context.Background(), []string{"webhook:pager"}, testNotification())
+	if !deliveries[0].Success {
+		t.Fatalf("delivery failed: %+v", deliveries[0])
+	}
+	if string(body) != `{"summary": "Refinery is stuck", "sev": "critical"}` {
+		t.Errorf("body = %s", body)
+	}
+	if auth != "Token abc" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
+
+func TestDispatch_RetriesWithBackoff(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.DiscordWebhook = srv.URL
+	cfg.Delivery = &config.EscalationDeliveryConfig{MaxAttempts: 4, InitialBackoff: "1s", MaxBackoff: "90s"}
+	d := NewDispatcher(cfg)
+	waits := noSleep(d)
+
+	deliveries := d.Dispatch(context.Background(), []string{"discord"}, testNotification())
+	if !deliveries[0].Success || deliveries[0].Attempts != 3 {
+		t.Errorf("delivery = %+v, want success on attempt 3", deliveries[0])
+	}
+	if len(*waits) != 2 || (*waits)[0] != time.Second || (*waits)[1] != 2*time.Second {
+		t.Errorf("backoffs = %v, want [1s 2s]", *waits)
+	}
+}
+
+func TestDispatch_PermanentErrorNotRetried(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "no such hook", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.SlackWebhook = srv.URL
+	d := NewDispatcher(cfg)
+	noSleep(d)
+
+	deliveries := d.Dispatch(context.Background(), []string{"slack"}, testNotification())
+	if deliveries[0].Success || calls != 1 {
+		t.Errorf("delivery = %+v after %d calls, want one failed attempt", deliveries[0], calls)
+	}
+	if !strings.Contains(deliveries[0].Error, "HTTP 404") {
+		t.Errorf("Error = %q", deliveries[0].Error)
+	}
+}
+
+func TestDispatch_UnconfiguredSkipped(t *testing.T) {
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.HumanEmail = "oncall@example.com"
+	deliveries := NewDispatcher(cfg).Dispatch(context.Background(),
+		[]string{"email:human", "sms:human", "webhook:missing"}, testNotification())
+
+	want := []string{"smtp.host not configured", "contacts.human_sms not configured", "webhooks.missing not configured"}
+	if len(deliveries) != len(want) {
+		t.Fatalf("got %d deliveries, want %d", len(deliveries), len(want))
+	}
+	for i, d := range deliveries {
+		if !d.Skipped || d.Error != want[i] {
+			t.Errorf("delivery[%d] = %+v, want skipped with %q", i, d, want[i])
+		}
+	}
+
+	comment := FormatDeliveries(deliveries)
+	if !strings.Contains(comment, "email:human: skipped") {
+		t.Errorf("FormatDeliveries = %q", comment)
+	}
+}
+
+type fakeSMS struct {
+	to, body string
+}
+
+func (f *fakeSMS) Name() string { return "fake" }
+
+func (f *fakeSMS) SendSMS(_ context.Context, to, body string) error {
+	f.to, f.body = to, body
+	return nil
+}
+
+func TestDispatch_SMSProvider(t *testing.T) {
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.HumanSMS = "+15550100"
+	d := NewDispatcher(cfg)
+	sms := &fakeSMS{}
+	d.SetSMSProvider(sms)
+
+	deliveries := d.Dispatch(context.Background(), []string{"sms:human"}, testNotification())
+	if !deliveries[0].Success || deliveries[0].Channel != "sms:fake" {
+		t.Fatalf("delivery = %+v", deliveries[0])
+	}
+	if sms.to != "+15550100" || !strings.HasPrefix(sms.body, "[CRITICAL] Refinery is stuck") {
+		t.Errorf("sent to=%q body=%q", sms.to, sms.body)
+	}
+}
+
+func TestTwilioProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if r.URL.Path != "/2010-04-01/Accounts/AC1/Messages.json" || user != "AC1" || pass != "secret" {
+			http.Error(w, "bad request", http.StatusUnauthorized)
+			return
+		}
+		_ = r.ParseForm()
+		if r.Form.Get("To") != "+15550100" || r.Form.Get("From") != "+15550199" {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	p, err := NewSMSProvider(&config.EscalationSMSConfig{
+		Provider:     "twilio",
+		AccountSID:   "AC1",
+		AuthTokenEnv: "TOKEN",
+		From:         "+15550199",
+		URL:          srv.URL,
+	}, nil, func(string) string { return "secret" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SendSMS(context.Background(), "+15550100", "hi"); err != nil {
+		t.Errorf("SendSMS: %v", err)
+	}
+}
+
+// smtpSink is a minimal SMTP server that records one message.
+type smtpSink struct {
+	ln   net.Listener
+	mu   sync.Mutex
+	from string
+	rcpt string
+	data string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpSink{ln: ln}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpSink) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpSink) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimSpace(line)
+		upper := strings.ToUpper(cmd)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			s.mu.Unlock()
+			reply("250 ok")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.mu.Lock()
+			s.rcpt = strings.Trim(cmd[len("RCPT TO:"):], "<> ")
+			s.mu.Unlock()
+			reply("250 ok")
+		case upper == "DATA":
+			reply("354 send data")
+			var sb strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				sb.WriteString(l)
+			}
+			s.mu.Lock()
+			s.data = sb.String()
+			s.mu.Unlock()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestDispatch_Email(t *testing.T) {
+	sink := newSMTPSink(t)
+
+	cfg := config.NewEscalationConfig()
+	cfg.Contacts.HumanEmail = "oncall@example.com"
+	cfg.SMTP = &config.EscalationSMTPConfig{
+		Host: "127.0.0.1",
+		Port: sink.port(),
+		From: "gastown@example.com",
+		TLS:  "none",
+	}
+
+	deliveries := NewDispatcher(cfg).Dispatch(context.Background(), []string{"email:human"}, testNotification())
+	if !deliveries[0].Success {
+		t.Fatalf("delivery failed: %+v", deliveries[0])
+	}
+
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.from != "gastown@example.com" || sink.rcpt != "oncall@example.com" {
+		t.Errorf("envelope from=%q rcpt=%q", sink.from, sink.rcpt)
+	}
+	for _, want := range []string{
+		"Subject: [CRITICAL] Refinery is stuck\r\n",
+		"X-Gastown-Escalation: hq-esc-1\r\n",
+		"Merge queue has not moved in 2h.",
+	} {
+		if !strings.Contains(sink.data, want) {
+			t.Errorf("message missing %q:\n%s", want, sink.data)
+		}
+	}
+}
+
+func TestSMTPNotifier_RefusesPlaintextCredentials(t *testing.T) {
+	sink := newSMTPSink(t)
+	n := NewSMTPNotifier(&config.EscalationSMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     sink.port(),
+		Username: "user",
+		Password: "pw",
+		From:     "gastown@example.com",
+	}, "oncall@example.com", func(string) string { return "" })
+
+	err := n.Send(context.Background(), testNotification())
+	if err == nil || !IsPermanent(err) || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("Send = %v, want permanent STARTTLS error", err)
+	}
+}
+
+func TestRetryPolicyFromConfig(t *testing.T) {
+	p := RetryPolicyFromConfig(nil)
+	if p.MaxAttempts != DefaultMaxAttempts || p.InitialBackoff != DefaultInitialBackoff {
+		t.Errorf("defaults = %+v", p)
+	}
+	p = RetryPolicyFromConfig(&config.EscalationDeliveryConfig{MaxAttempts: 5, Timeout: "3s"})
+	if p.MaxAttempts != 5 || p.Timeout != 3*time.Second || p.MaxBackoff != DefaultMaxBackoff {
+		t.Errorf("overrides = %+v", p)
+	}
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Default retry settings for external deliveries.
+const (
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 2 * time.Second
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultTimeout        = 10 * time.Second
+)
+
+// RetryPolicy retries failed deliveries with exponential backoff.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration // per attempt
+
+	// sleep waits between attempts; replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// RetryPolicyFromConfig builds a RetryPolicy, filling unset fields with defaults.
+func RetryPolicyFromConfig(c *config.EscalationDeliveryConfig) RetryPolicy {
+	p := RetryPolicy{
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+		Timeout:        DefaultTimeout,
+	}
+	if c == nil {
+		return p
+	}
+	if c.MaxAttempts > 0 {
+		p.MaxAttempts = c.MaxAttempts
+	}
+	if d, err := time.ParseDuration(c.InitialBackoff); err == nil && d > 0 {
+		p.InitialBackoff = d
+	}
+	if d, err := time.ParseDuration(c.MaxBackoff); err == nil && d > 0 {
+		p.MaxBackoff = d
+	}
+	if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+		p.Timeout = d
+	}
+	return p
+}
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so RetryPolicy.Do stops retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Do calls fn until it succeeds, returns a permanent error, the attempts are
+// exhausted, or ctx is done. It returns the number of attempts made and the
+// last error.
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) (int, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	sleep := p.sleep
+	if sleep == nil {
+		sleep = sleepCtx
+	}
+
+	backoff := p.InitialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		attemptCtx := ctx
+		cancel := func() {}
+		if p.Timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, p.Timeout)
+		}
+		err = fn(attemptCtx)
+		cancel()
+
+		if err == nil || IsPermanent(err) || attempt >= maxAttempts {
+			return attempt, err
+		}
+		if serr := sleep(ctx, backoff); serr != nil {
+			return attempt, err
+		}
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
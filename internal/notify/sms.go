@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// maxSMSLength keeps messages within a few SMS segments.
+const maxSMSLength = 480
+
+// SMSProvider sends a text message. Implement it to add a new SMS backend.
+type SMSProvider interface {
+	// Name identifies the provider in delivery records.
+	Name() string
+
+	// SendSMS sends body to the phone number to.
+	SendSMS(ctx context.Context, to, body string) error
+}
+
+// NewSMSProvider creates the SMS provider selected in config.
+func NewSMSProvider(cfg *config.EscalationSMSConfig, client *http.Client, getenv func(string) string) (SMSProvider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	switch cfg.Provider {
+	case "twilio":
+		token := resolveSecret(cfg.AuthToken, cfg.AuthTokenEnv, getenv)
+		if cfg.AccountSID == "" || token == "" || cfg.From == "" {
+			return nil, fmt.Errorf("sms: twilio requires account_sid, auth_token, and from")
+		}
+		baseURL := cfg.URL
+		if baseURL == "" {
+			baseURL = "https://api.twilio.com"
+		}
+		return &TwilioProvider{
+			AccountSID: cfg.AccountSID,
+			AuthToken:  token,
+			From:       cfg.From,
+			BaseURL:    baseURL,
+			client:     client,
+		}, nil
+	case "webhook":
+		return &WebhookSMSProvider{URL: cfg.URL, From: cfg.From, client: client}, nil
+	}
+	return nil, fmt.Errorf("sms: unknown provider %q", cfg.Provider)
+}
+
+// SMSNotifier delivers notifications as text messages.
+type SMSNotifier struct {
+	Provider SMSProvider
+	To       string
+}
+
+// Channel implements Notifier.
+func (s *SMSNotifier) Channel() string { return "sms:" + s.Provider.Name() }
+
+// Target implements Notifier.
+func (s *SMSNotifier) Target() string { return s.To }
+
+// Send implements Notifier.
+func (s *SMSNotifier) Send(ctx context.Context, n *Notification) error {
+	body := n.Text()
+	if len(body) > maxSMSLength {
+		body = body[:maxSMSLength-3] + "..."
+	}
+	return s.Provider.SendSMS(ctx, s.To, body)
+}
+
+// TwilioProvider sends SMS through the Twilio Messages API.
+type TwilioProvider struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+	BaseURL    string
+
+	client *http.Client
+}
+
+// Name implements SMSProvider.
+func (t *TwilioProvider) Name() string { return "twilio" }
+
+// SendSMS implements SMSProvider.
+func (t *TwilioProvider) SendSMS(ctx context.Context, to, body string) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json",
+		strings.TrimSuffix(t.BaseURL, "/"), url.PathEscape(t.AccountSID))
+	form := url.Values{"To": {to}, "From": {t.From}, "Body": {body}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Permanent(fmt.Errorf("building twilio request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.AccountSID, t.AuthToken)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending twilio request: %w", err)
+	}
+	defer resp.Body.Close()
+	return checkHTTPResponse(resp)
+}
+
+// WebhookSMSProvider POSTs {"to", "from", "body"} as JSON to a gateway URL.
+type WebhookSMSProvider struct {
+	URL  string
+	From string
+
+	client *http.Client
+}
+
+// Name implements SMSProvider.
+func (w *WebhookSMSProvider) Name() string { return "webhook" }
+
+// SendSMS implements SMSProvider.
+func (w *WebhookSMSProvider) SendSMS(ctx context.Context, to, body string) error {
+	data, err := json.Marshal(map[string]string{"to": to, "from": w.From, "body": body})
+	if err != nil {
+		return Permanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(data))
+	if err != nil {
+		return Permanent(fmt.Errorf("building sms request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending sms request: %w", err)
+	}
+	defer resp.Body.Close()
+	return checkHTTPResponse(resp)
+}
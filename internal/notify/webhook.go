@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"text/template"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Webhook payload formats.
+const (
+	FormatJSON    = "json"
+	FormatSlack   = "slack"
+	FormatDiscord = "discord"
+)
+
+// severityColors are attachment/embed colors per severity.
+var severityColors = map[string]int{
+	config.SeverityCritical: 0xD00000,
+	config.SeverityHigh:     0xFF8C00,
+	config.SeverityMedium:   0xFFD700,
+	config.SeverityLow:      0x808080,
+}
+
+// WebhookNotifier POSTs a notification to an HTTP endpoint.
+type WebhookNotifier struct {
+	name   string
+	cfg    *config.EscalationWebhookConfig
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a webhook notifier. A nil client uses
+// http.DefaultClient.
+func NewWebhookNotifier(name string, cfg *config.EscalationWebhookConfig, client *http.Client) *WebhookNotifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookNotifier{name: name, cfg: cfg, client: client}
+}
+
+// Channel implements Notifier.
+func (w *WebhookNotifier) Channel() string {
+	if w.cfg.Format == "" {
+		return FormatJSON
+	}
+	return w.cfg.Format
+}
+
+// Target implements Notifier. The URL is not reported since webhook URLs
+// usually embed a secret token.
+func (w *WebhookNotifier) Target() string {
+	return w.name
+}
+
+// Send implements Notifier.
+func (w *WebhookNotifier) Send(ctx context.Context, n *Notification) error {
+	body, err := w.payload(n)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(fmt.Errorf("building webhook request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	return checkHTTPResponse(resp)
+}
+
+// payload renders the request body for the configured format.
+func (w *WebhookNotifier) payload(n *Notification) ([]byte, error) {
+	switch w.cfg.Format {
+	case FormatSlack:
+		return json.Marshal(map[string]interface{}{
+			"text": n.Text(),
+			"attachments": []map[string]interface{}{{
+				"color": fmt.Sprintf("#%06X", severityColors[n.Severity]),
+				"fields": []map[string]interface{}{
+					{"title": "Severity", "value": n.Severity, "short": true},
+					{"title": "From", "value": n.From, "short": true},
+				},
+			}},
+		})
+
+	case FormatDiscord:
+		content := n.Text()
+		if len(content) > 2000 { // Discord message limit
+			content = content[:1997] + "..."
+		}
+		return json.Marshal(map[string]interface{}{
+			"content": content,
+			"embeds": []map[string]interface{}{{
+				"title": n.ID,
+				"color": severityColors[n.Severity],
+				"fields": []map[string]interface{}{
+					{"name": "Severity", "value": n.Severity, "inline": true},
+					{"name": "From", "value": n.From, "inline": true},
+				},
+			}},
+		})
+
+	default:
+		if w.cfg.Template == "" {
+			return json.Marshal(n)
+		}
+		tmpl, err := template.New(w.name).Funcs(template.FuncMap{
+			"json": func(v interface{}) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+		}).Parse(w.cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("parsing webhook template: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, n); err != nil {
+			return nil, fmt.Errorf("rendering webhook template: %w", err)
+		}
+		if !json.Valid(buf.Bytes()) {
+			return nil, fmt.Errorf("webhook template rendered invalid JSON (use {{json .Field}} to quote values)")
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// checkHTTPResponse maps a response status to a delivery error. Client
+// errors other than 408 and 429 are permanent.
+func checkHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err := fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	base := time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC) // Friday
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 9 * * *", time.Date(2025, 3, 15, 9, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 3, 14, 10, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * MON-FRI", time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)},
+		{"30 10 * * 7", time.Date(2025, 3, 16, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either may match (the 20th or a Monday).
+		{"0 0 20 * 1", time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * JAN-MAR/2 *", time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr, time.UTC)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(base); !got.Equal(tt.want) {
+			t.Errorf("ParseCron(%q).Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCron_Timezone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	base := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC) // 08:00 EDT
+
+	s, err := ParseCron("CRON_TZ=America/New_York 0 9 * * *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, 7, 1, 9, 0, 0, 0, ny)
+	if got := s.Next(base); !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+
+	// The default location applies without a prefix.
+	s, err = ParseCron("0 9 * * *", ny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(base); !got.Equal(want) {
+		t.Errorf("Next with default location = %v, want %v", got, want)
+	}
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@sometimes",
+		"CRON_TZ=Not/AZone 0 9 * * *",
+	} {
+		if _, err := ParseCron(expr, time.UTC); err == nil {
+			t.Errorf("ParseCron(%q): expected error", expr)
+		}
+	}
+}
+
+func TestParseCron_NeverFires(t *testing.T) {
+	s, err := ParseCron("0 0 30 2 *", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next = %v, want zero for impossible date", got)
+	}
+}
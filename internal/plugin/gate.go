@@ -0,0 +1,212 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// DefaultCooldown is used for cooldown gates without a duration.
+const DefaultCooldown = time.Hour
+
+// DefaultConditionTimeout bounds how long a condition gate's check may run.
+const DefaultConditionTimeout = 30 * time.Second
+
+// GateStatus is the result of evaluating a plugin's gate.
+type GateStatus struct {
+	// Open is true if the plugin is due to run now.
+	Open bool `json:"open"`
+
+	// Reason explains the decision (e.g., "cooldown: 40m remaining").
+	Reason string `json:"reason"`
+
+	// LastRun is when the plugin last ran, if known.
+	LastRun *time.Time `json:"last_run,omitempty"`
+
+	// NextRun is when the gate will next open. Nil for gates that are
+	// not time-based (condition, event, manual).
+	NextRun *time.Time `json:"next_run,omitempty"`
+}
+
+// LastRunSource provides the most recent run of a plugin.
+// Recorder implements it against the beads ledger.
+type LastRunSource interface {
+	GetLastRun(pluginName string) (*PluginRunBead, error)
+}
+
+// GateEvaluator decides whether plugins are due to run.
+type GateEvaluator struct {
+	runs LastRunSource
+
+	// Now returns the current time; replaced in tests.
+	Now func() time.Time
+
+	// RunCheck runs a condition gate's check command in dir and reports
+	// whether it exited 0; replaced in tests.
+	RunCheck func(ctx context.Context, dir, check string) (bool, error)
+
+	// SkipConditions leaves condition gates closed without running their
+	// check commands, for read-only listings.
+	SkipConditions bool
+}
+
+// NewGateEvaluator creates an evaluator that reads run history from runs.
+func NewGateEvaluator(runs LastRunSource) *GateEvaluator {
+	return &GateEvaluator{
+		runs:     runs,
+		Now:      time.Now,
+		RunCheck: runConditionCheck,
+	}
+}
+
+// Evaluate checks a plugin's gate. Plugins without a gate are manual.
+func (e *GateEvaluator) Evaluate(p *Plugin) GateStatus {
+	gate := p.Gate
+	if gate == nil || gate.Type == "" || gate.Type == GateManual {
+		return GateStatus{Reason: "manual: run with gt plugin run"}
+	}
+
+	now := e.Now()
+	switch gate.Type {
+	case GateCooldown:
+		return e.evaluateCooldown(p, now)
+	case GateCron:
+		return e.evaluateCron(p, now)
+	case GateCondition:
+		return e.evaluateCondition(p)
+	case GateEvent:
+		return GateStatus{Reason: fmt.Sprintf("event: runs on %q", gate.On)}
+	}
+	return GateStatus{Reason: fmt.Sprintf("unknown gate type %q", gate.Type)}
+}
+
+// lastRun returns the time of the plugin's most recent run, or nil if it
+// has never run.
+func (e *GateEvaluator) lastRun(p *Plugin) (*time.Time, error) {
+	run, err := e.runs.GetLastRun(p.Name)
+	if err != nil {
+		return nil, err
+	}
+	if run == nil || run.CreatedAt.IsZero() {
+		return nil, nil
+	}
+	t := run.CreatedAt
+	return &t, nil
+}
+
+func (e *GateEvaluator) evaluateCooldown(p *Plugin, now time.Time) GateStatus {
+	cooldown := DefaultCooldown
+	if p.Gate.Duration != "" {
+		d, err := time.ParseDuration(p.Gate.Duration)
+		if err != nil {
+			return GateStatus{Reason: fmt.Sprintf("invalid cooldown duration %q: %v", p.Gate.Duration, err)}
+		}
+		cooldown = d
+	}
+
+	last, err := e.lastRun(p)
+	if err != nil {
+		return GateStatus{Reason: fmt.Sprintf("checking last run: %v", err)}
+	}
+	if last == nil {
+		return GateStatus{Open: true, Reason: "cooldown: never run", NextRun: &now}
+	}
+
+	next := last.Add(cooldown)
+	status := GateStatus{LastRun: last, NextRun: &next}
+	if !now.Before(next) {
+		status.Open = true
+		status.Reason = fmt.Sprintf("cooldown: %s elapsed", cooldown)
+	} else {
+		status.Reason = fmt.Sprintf("cooldown: %s remaining", next.Sub(now).Round(time.Minute))
+	}
+	return status
+}
+
+// evaluateCron opens the gate once per scheduled firing: the plugin is due
+// when a scheduled time has passed since its last run. A plugin that has
+// never run is due immediately.
+func (e *GateEvaluator) evaluateCron(p *Plugin, now time.Time) GateStatus {
+	sched, err := p.Gate.CronSchedule()
+	if err != nil {
+		return GateStatus{Reason: err.Error()}
+	}
+
+	last, err := e.lastRun(p)
+	if err != nil {
+		return GateStatus{Reason: fmt.Sprintf("checking last run: %v", err)}
+	}
+	if last == nil {
+		return GateStatus{Open: true, Reason: "cron: never run", NextRun: &now}
+	}
+
+	status := GateStatus{LastRun: last}
+	due := sched.Next(*last)
+	if due.IsZero() {
+		status.Reason = fmt.Sprintf("cron: schedule %q never fires", sched)
+		return status
+	}
+	if !now.Before(due) {
+		status.Open = true
+		status.NextRun = &due
+		status.Reason = fmt.Sprintf("cron: scheduled at %s", due.Format(time.RFC3339))
+		return status
+	}
+	status.NextRun = &due
+	status.Reason = fmt.Sprintf("cron: next at %s", due.Format(time.RFC3339))
+	return status
+}
+
+func (e *GateEvaluator) evaluateCondition(p *Plugin) GateStatus {
+	if p.Gate.Check == "" {
+		return GateStatus{Reason: "condition: no check command"}
+	}
+	if e.SkipConditions {
+		return GateStatus{Reason: "condition: checked at patrol time"}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultConditionTimeout)
+	defer cancel()
+
+	ok, err := e.RunCheck(ctx, p.Path, p.Gate.Check)
+	if err != nil {
+		return GateStatus{Reason: fmt.Sprintf("condition: check failed: %v", err)}
+	}
+	if !ok {
+		return GateStatus{Reason: "condition: check not met"}
+	}
+	return GateStatus{Open: true, Reason: "condition: check passed"}
+}
+
+// CronSchedule parses the gate's cron schedule in its configured time zone.
+func (g *Gate) CronSchedule() (*CronSchedule, error) {
+	if g.Schedule == "" {
+		return nil, fmt.Errorf("cron gate has no schedule")
+	}
+	loc := time.Local
+	if g.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(g.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("cron gate timezone %q: %w", g.Timezone, err)
+		}
+	}
+	return ParseCron(g.Schedule, loc)
+}
+
+// runConditionCheck runs check with sh in dir. Exit 0 means the condition is
+// met; any other exit status means it is not.
+func runConditionCheck(ctx context.Context, dir, check string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", check) //nolint:gosec // G204: check comes from a trusted plugin.md
+	cmd.Dir = dir
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return false, fmt.Errorf("timed out after %s", DefaultConditionTimeout)
+		}
+		if _, ok := err.(*exec.ExitError); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
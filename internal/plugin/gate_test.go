@@ -0,0 +1,136 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeRuns is a LastRunSource backed by a map of plugin name to last run time.
+type fakeRuns map[string]time.Time
+
+func (f fakeRuns) GetLastRun(name string) (*PluginRunBead, error) {
+	t, ok := f[name]
+	if !ok {
+		return nil, nil
+	}
+	return &PluginRunBead{ID: "run-" + name, CreatedAt: t, Result: ResultSuccess}, nil
+}
+
+type failingRuns struct{}
+
+func (failingRuns) GetLastRun(string) (*PluginRunBead, error) {
+	return nil, errors.New("bd unavailable")
+}
+
+func TestGateEvaluator_Cooldown(t *testing.T) {
+	now := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	runs := fakeRuns{
+		"recent": now.Add(-30 * time.Minute),
+		"old":    now.Add(-2 * time.Hour),
+	}
+	e := NewGateEvaluator(runs)
+	e.Now = func() time.Time { return now }
+
+	gate := &Gate{Type: GateCooldown, Duration: "1h"}
+
+	status := e.Evaluate(&Plugin{Name: "recent", Gate: gate})
+	if status.Open {
+		t.Errorf("recent: gate open, want closed: %s", status.Reason)
+	}
+	if status.NextRun == nil || !status.NextRun.Equal(now.Add(30*time.Minute)) {
+		t.Errorf("recent: NextRun = %v, want %v", status.NextRun, now.Add(30*time.Minute))
+	}
+
+	if status := e.Evaluate(&Plugin{Name: "old", Gate: gate}); !status.Open {
+		t.Errorf("old: gate closed, want open: %s", status.Reason)
+	}
+	if status := e.Evaluate(&Plugin{Name: "never", Gate: gate}); !status.Open {
+		t.Errorf("never: gate closed, want open: %s", status.Reason)
+	}
+}
+
+func TestGateEvaluator_Cron(t *testing.T) {
+	now := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	runs := fakeRuns{
+		"ran-today":     time.Date(2025, 3, 14, 9, 0, 30, 0, time.UTC),
+		"ran-yesterday": time.Date(2025, 3, 13, 9, 0, 30, 0, time.UTC),
+	}
+	e := NewGateEvaluator(runs)
+	e.Now = func() time.Time { return now }
+
+	gate := &Gate{Type: GateCron, Schedule: "0 9 * * *", Timezone: "UTC"}
+
+	status := e.Evaluate(&Plugin{Name: "ran-today", Gate: gate})
+	if status.Open {
+		t.Errorf("ran-today: gate open, want closed: %s", status.Reason)
+	}
+	wantNext := time.Date(2025, 3, 15, 9, 0, 0, 0, time.UTC)
+	if status.NextRun == nil || !status.NextRun.Equal(wantNext) {
+		t.Errorf("ran-today: NextRun = %v, want %v", status.NextRun, wantNext)
+	}
+	if status.LastRun == nil || !status.LastRun.Equal(runs["ran-today"]) {
+		t.Errorf("ran-today: LastRun = %v", status.LastRun)
+	}
+
+	if status := e.Evaluate(&Plugin{Name: "ran-yesterday", Gate: gate}); !status.Open {
+		t.Errorf("ran-yesterday: gate closed, want open (missed 09:00 today): %s", status.Reason)
+	}
+	if status := e.Evaluate(&Plugin{Name: "never", Gate: gate}); !status.Open {
+		t.Errorf("never: gate closed, want open: %s", status.Reason)
+	}
+
+	bad := &Gate{Type: GateCron, Schedule: "not a cron"}
+	if status := e.Evaluate(&Plugin{Name: "never", Gate: bad}); status.Open {
+		t.Error("invalid schedule: gate open, want closed")
+	}
+}
+
+func TestGateEvaluator_Condition(t *testing.T) {
+	e := NewGateEvaluator(fakeRuns{})
+	var gotDir, gotCheck string
+	e.RunCheck = func(_ context.Context, dir, check string) (bool, error) {
+		gotDir, gotCheck = dir, check
+		return check == "true", nil
+	}
+
+	p := &Plugin{Name: "cond", Path: "/plugins/cond", Gate: &Gate{Type: GateCondition, Check: "true"}}
+	if status := e.Evaluate(p); !status.Open {
+		t.Errorf("condition met: gate closed: %s", status.Reason)
+	}
+	if gotDir != "/plugins/cond" || gotCheck != "true" {
+		t.Errorf("RunCheck(%q, %q)", gotDir, gotCheck)
+	}
+
+	p.Gate.Check = "false"
+	if status := e.Evaluate(p); status.Open {
+		t.Error("condition not met: gate open")
+	}
+
+	e.SkipConditions = true
+	p.Gate.Check = "true"
+	if status := e.Evaluate(p); status.Open {
+		t.Error("SkipConditions: gate open, want closed without running check")
+	}
+}
+
+func TestGateEvaluator_ManualAndErrors(t *testing.T) {
+	e := NewGateEvaluator(failingRuns{})
+	if status := e.Evaluate(&Plugin{Name: "m"}); status.Open {
+		t.Error("manual plugin: gate open")
+	}
+	if status := e.Evaluate(&Plugin{Name: "c", Gate: &Gate{Type: GateCooldown}}); status.Open {
+		t.Error("cooldown with failing history: gate open, want closed")
+	}
+}
+
+func TestRunConditionCheck(t *testing.T) {
+	dir := t.TempDir()
+	if ok, err := runConditionCheck(context.Background(), dir, "exit 0"); err != nil || !ok {
+		t.Errorf("exit 0 = %v, %v; want true, nil", ok, err)
+	}
+	if ok, err := runConditionCheck(context.Background(), dir, "exit 3"); err != nil || ok {
+		t.Errorf("exit 3 = %v, %v; want false, nil", ok, err)
+	}
+}
@@ -173,6 +173,11 @@ func parsePluginMD(content []byte, pluginDir string, location Location, rigName
 	if fm.Name == "" {
 		return nil, fmt.Errorf("missing required field: name")
 	}
+	if fm.Gate != nil && fm.Gate.Type == GateCron {
+		if _, err := fm.Gate.CronSchedule(); err != nil {
+			return nil, err
+		}
+	}
 
 	plugin := &Plugin{
 		Name:         fm.Name,
@@ -64,9 +64,13 @@ type Gate struct {
 	// Duration is for cooldown gates (e.g., "1h", "24h").
 	Duration string `json:"duration,omitempty" toml:"duration,omitempty"`
 
-	// Schedule is for cron gates (e.g., "0 9 * * *").
+	// Schedule is for cron gates (e.g., "0 9 * * *", "@daily").
 	Schedule string `json:"schedule,omitempty" toml:"schedule,omitempty"`
 
+	// Timezone is the IANA zone for cron gates (e.g., "America/New_York").
+	// Empty uses the local time zone.
+	Timezone string `json:"timezone,omitempty" toml:"timezone,omitempty"`
+
 	// Check is for condition gates (command that returns exit 0 to run).
 	Check string `json:"check,omitempty" toml:"check,omitempty"`
 
@@ -133,6 +137,9 @@ type PluginSummary struct {
 	RigName     string   `json:"rig_name,omitempty"`
 	GateType    GateType `json:"gate_type,omitempty"`
 	Path        string   `json:"path"`
+
+	// Status is the evaluated gate, when the caller has checked it.
+	Status *GateStatus `json:"status,omitempty"`
 }
 
 // Summary returns a PluginSummary for this plugin.
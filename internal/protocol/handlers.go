@@ -29,7 +29,7 @@ func (r *HandlerRegistry) Register(msgType MessageType, handler Handler) {
 // Handle dispatches a message to the appropriate handler.
 // Returns an error if no handler is registered for the message type.
 func (r *HandlerRegistry) Handle(msg *mail.Message) error {
-	msgType := ParseMessageType(msg.Subject)
+	msgType := MessageTypeOf(msg)
 	if msgType == "" {
 		return fmt.Errorf("unknown message type for subject: %s", msg.Subject)
 	}
@@ -44,9 +44,9 @@ func (r *HandlerRegistry) Handle(msg *mail.Message) error {
 
 // CanHandle returns true if a handler is registered for the message's type.
 func (r *HandlerRegistry) CanHandle(msg *mail.Message) bool {
-	msgType := ParseMessageType(msg.Subject)
+	msgType := MessageTypeOf(msg)
 	if msgType == "" {
-		return false
+		return false // not a protocol message
 	}
 
 	_, ok := r.handlers[msgType]
@@ -111,10 +111,6 @@ func WrapRefineryHandlers(h RefineryHandler) *HandlerRegistry {
 // It returns (true, nil) if the message was handled successfully,
 // (true, error) if handling failed, or (false, nil) if not a protocol message.
 func (r *HandlerRegistry) ProcessProtocolMessage(msg *mail.Message) (bool, error) {
-	if !IsProtocolMessage(msg.Subject) {
-		return false, nil
-	}
-
 	if !r.CanHandle(msg) {
 		return false, nil
 	}
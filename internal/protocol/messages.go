@@ -20,7 +20,7 @@ func NewMergeReadyMessage(rig, polecat, branch, issue string) *mail.Message {
 		Timestamp: time.Now(),
 	}
 
-	body := formatMergeReadyBody(payload)
+	body := encodeBody(TypeMergeReady, payload, formatMergeReadyBody(payload))
 
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/witness", rig),
@@ -60,7 +60,7 @@ func NewMergedMessage(rig, polecat, branch, issue, targetBranch, mergeCommit str
 		TargetBranch: targetBranch,
 	}
 
-	body := formatMergedBody(payload)
+	body := encodeBody(TypeMerged, payload, formatMergedBody(payload))
 
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/refinery", rig),
@@ -92,7 +92,7 @@ func formatMergedBody(p MergedPayload) string {
 // NewMergeFailedMessage creates a MERGE_FAILED protocol message.
 // Sent by Refinery to Witness when merge fails (tests, build, etc.).
 func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureType, errorMsg string) *mail.Message {
-	payload := MergeFailedPayload{
+	return NewMergeFailedMessageFromPayload(MergeFailedPayload{
 		Branch:       branch,
 		Issue:        issue,
 		Polecat:      polecat,
@@ -101,14 +101,22 @@ func NewMergeFailedMessage(rig, polecat, branch, issue, targetBranch, failureTyp
 		FailureType:  failureType,
 		Error:        errorMsg,
 		TargetBranch: targetBranch,
+	})
+}
+
+// NewMergeFailedMessageFromPayload creates a MERGE_FAILED protocol message
+// from a fully populated payload, including test failure details.
+func NewMergeFailedMessageFromPayload(payload MergeFailedPayload) *mail.Message {
+	if payload.FailedAt.IsZero() {
+		payload.FailedAt = time.Now()
 	}
 
-	body := formatMergeFailedBody(payload)
+	body := encodeBody(TypeMergeFailed, payload, formatMergeFailedBody(payload))
 
 	msg := mail.NewMessage(
-		fmt.Sprintf("%s/refinery", rig),
-		fmt.Sprintf("%s/witness", rig),
-		fmt.Sprintf("MERGE_FAILED %s", polecat),
+		fmt.Sprintf("%s/refinery", payload.Rig),
+		fmt.Sprintf("%s/witness", payload.Rig),
+		fmt.Sprintf("MERGE_FAILED %s", payload.Polecat),
 		body,
 	)
 	msg.Priority = mail.PriorityHigh
@@ -128,6 +136,18 @@ func formatMergeFailedBody(p MergeFailedPayload) string {
 	sb.WriteString(fmt.Sprintf("Failed-At: %s\n", p.FailedAt.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Failure-Type: %s\n", p.FailureType))
 	sb.WriteString(fmt.Sprintf("Error: %s\n", p.Error))
+	if p.MR != "" {
+		sb.WriteString(fmt.Sprintf("MR: %s\n", p.MR))
+	}
+	if len(p.FailedTests) > 0 {
+		sb.WriteString(fmt.Sprintf("Failed-Tests: %s\n", strings.Join(p.FailedTests, ", ")))
+	}
+	if len(p.FlakyTests) > 0 {
+		sb.WriteString(fmt.Sprintf("Flaky-Tests: %s\n", strings.Join(p.FlakyTests, ", ")))
+	}
+	if p.MR != "" && (len(p.FailedTests) > 0 || p.FailureType == "tests") {
+		sb.WriteString(fmt.Sprintf("Test-Log: bd show %s (see comments)\n", p.MR))
+	}
 	return sb.String()
 }
 
@@ -145,7 +165,7 @@ func NewReworkRequestMessage(rig, polecat, branch, issue, targetBranch string, c
 		Instructions:  formatRebaseInstructions(targetBranch),
 	}
 
-	body := formatReworkRequestBody(payload)
+	body := encodeBody(TypeReworkRequest, payload, formatReworkRequestBody(payload))
 
 	msg := mail.NewMessage(
 		fmt.Sprintf("%s/refinery", rig),
@@ -191,8 +211,25 @@ func formatRebaseInstructions(targetBranch string) string {
 The Refinery will retry the merge after rebase is complete.`, targetBranch, targetBranch)
 }
 
+// encodeBody writes the typed envelope for payload ahead of the
+// human-readable text. Handlers read the envelope; the text is for agents
+// and humans reading the mail. If the payload cannot be encoded the text is
+// sent alone and receivers fall back to parsing it.
+func encodeBody(msgType MessageType, payload interface{}, text string) string {
+	body, err := mail.EncodeEnvelope(string(msgType), payload, text)
+	if err != nil {
+		return text
+	}
+	return body
+}
+
 // ParseMergeReadyPayload parses a MERGE_READY message body into a payload.
+// The envelope is preferred; bodies without one are parsed as legacy text.
 func ParseMergeReadyPayload(body string) *MergeReadyPayload {
+	var payload MergeReadyPayload
+	if mail.DecodeEnvelope(body, string(TypeMergeReady), &payload) {
+		return &payload
+	}
 	return &MergeReadyPayload{
 		Branch:    parseField(body, "Branch"),
 		Issue:     parseField(body, "Issue"),
@@ -204,7 +241,13 @@ func ParseMergeReadyPayload(body string) *MergeReadyPayload {
 }
 
 // ParseMergedPayload parses a MERGED message body into a payload.
+// The envelope is preferred; bodies without one are parsed as legacy text.
 func ParseMergedPayload(body string) *MergedPayload {
+	var decoded MergedPayload
+	if mail.DecodeEnvelope(body, string(TypeMerged), &decoded) {
+		return &decoded
+	}
+
 	payload := &MergedPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
@@ -225,7 +268,13 @@ func ParseMergedPayload(body string) *MergedPayload {
 }
 
 // ParseMergeFailedPayload parses a MERGE_FAILED message body into a payload.
+// The envelope is preferred; bodies without one are parsed as legacy text.
 func ParseMergeFailedPayload(body string) *MergeFailedPayload {
+	var decoded MergeFailedPayload
+	if mail.DecodeEnvelope(body, string(TypeMergeFailed), &decoded) {
+		return &decoded
+	}
+
 	payload := &MergeFailedPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
@@ -234,6 +283,14 @@ func ParseMergeFailedPayload(body string) *MergeFailedPayload {
 		TargetBranch: parseField(body, "Target"),
 		FailureType:  parseField(body, "Failure-Type"),
 		Error:        parseField(body, "Error"),
+		MR:           parseField(body, "MR"),
+	}
+
+	if tests := parseField(body, "Failed-Tests"); tests != "" {
+		payload.FailedTests = strings.Split(tests, ", ")
+	}
+	if tests := parseField(body, "Flaky-Tests"); tests != "" {
+		payload.FlakyTests = strings.Split(tests, ", ")
 	}
 
 	// Parse timestamp
@@ -247,7 +304,13 @@ func ParseMergeFailedPayload(body string) *MergeFailedPayload {
 }
 
 // ParseReworkRequestPayload parses a REWORK_REQUEST message body into a payload.
+// The envelope is preferred; bodies without one are parsed as legacy text.
 func ParseReworkRequestPayload(body string) *ReworkRequestPayload {
+	var decoded ReworkRequestPayload
+	if mail.DecodeEnvelope(body, string(TypeReworkRequest), &decoded) {
+		return &decoded
+	}
+
 	payload := &ReworkRequestPayload{
 		Branch:       parseField(body, "Branch"),
 		Issue:        parseField(body, "Issue"),
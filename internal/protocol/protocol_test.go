@@ -376,3 +376,96 @@ func (m *mockRefineryHandler) HandleMergeReady(payload *MergeReadyPayload) error
 	m.readyCalled = true
 	return nil
 }
+
+func TestMergeFailedPayload_TestDetailsRoundTrip(t *testing.T) {
+	msg := NewMergeFailedMessageFromPayload(MergeFailedPayload{
+		Branch:       "polecat/nux/gt-abc",
+		Issue:        "gt-abc",
+		Polecat:      "nux",
+		Rig:          "gastown",
+		FailureType:  "tests",
+		Error:        "tests failed after 2 attempts: exit status 1",
+		TargetBranch: "main",
+		MR:           "gt-mr1",
+		FailedTests:  []string{"pkg.TestA", "pkg.TestB"},
+		FlakyTests:   []string{"pkg.TestC"},
+	})
+
+	if msg.Subject != "MERGE_FAILED nux" {
+		t.Errorf("Subject = %q, want %q", msg.Subject, "MERGE_FAILED nux")
+	}
+	if !strings.Contains(msg.Body, "Test-Log: bd show gt-mr1") {
+		t.Errorf("Body missing test log reference: %s", msg.Body)
+	}
+
+	payload := ParseMergeFailedPayload(msg.Body)
+	if payload.MR != "gt-mr1" {
+		t.Errorf("MR = %q, want %q", payload.MR, "gt-mr1")
+	}
+	if len(payload.FailedTests) != 2 || payload.FailedTests[1] != "pkg.TestB" {
+		t.Errorf("FailedTests = %v", payload.FailedTests)
+	}
+	if len(payload.FlakyTests) != 1 || payload.FlakyTests[0] != "pkg.TestC" {
+		t.Errorf("FlakyTests = %v", payload.FlakyTests)
+	}
+}
+
+func TestProtocolEnvelope(t *testing.T) {
+	msg := NewMergedMessage("gastown", "nux", "polecat/nux/gt-abc", "gt-abc", "main", "abc123")
+
+	env, ok := mail.ParseEnvelope(msg.Body)
+	if !ok {
+		t.Fatalf("MERGED body has no envelope: %s", msg.Body)
+	}
+	if env.ProtocolVersion != mail.ProtocolVersion || env.Type != string(TypeMerged) {
+		t.Errorf("envelope = v%d %q", env.ProtocolVersion, env.Type)
+	}
+
+	// Rewording the human-readable text must not affect the handshake.
+	text := mail.StripEnvelope(msg.Body)
+	reworded := strings.Replace(msg.Body, text, "Merged nux's branch into main. 🎉\n", 1)
+	payload := ParseMergedPayload(reworded)
+	if payload.Polecat != "nux" || payload.MergeCommit != "abc123" || payload.TargetBranch != "main" {
+		t.Errorf("ParseMergedPayload(reworded) = %+v", payload)
+	}
+	if payload.MergedAt.IsZero() {
+		t.Error("MergedAt not decoded from envelope")
+	}
+
+	// The envelope type wins over the subject.
+	renamed := &mail.Message{Subject: "merge done for nux", Body: reworded}
+	if got := MessageTypeOf(renamed); got != TypeMerged {
+		t.Errorf("MessageTypeOf = %q, want %q", got, TypeMerged)
+	}
+}
+
+func TestProtocolEnvelope_LegacyFallback(t *testing.T) {
+	// A future envelope version is ignored in favor of the text.
+	body := "```gt-protocol\n{\"protocol_version\":99,\"type\":\"MERGE_FAILED\",\"payload\":{\"polecat\":\"other\"}}\n```\n\n" +
+		"Branch: polecat/nux/gt-abc\nPolecat: nux\nFailure-Type: build\nError: boom\n"
+	payload := ParseMergeFailedPayload(body)
+	if payload.Polecat != "nux" || payload.FailureType != "build" || payload.Error != "boom" {
+		t.Errorf("ParseMergeFailedPayload = %+v", payload)
+	}
+
+	// An envelope of the wrong type is ignored too.
+	rework := NewReworkRequestMessage("gastown", "nux", "b", "gt-abc", "main", nil)
+	if got := ParseMergeReadyPayload(rework.Body); got.Branch != "b" || got.Polecat != "nux" {
+		t.Errorf("ParseMergeReadyPayload(rework) = %+v", got)
+	}
+}
+
+func TestWrapWitnessHandlers_Envelope(t *testing.T) {
+	handler := &mockWitnessHandler{}
+	registry := WrapWitnessHandlers(handler)
+
+	msg := NewMergeFailedMessage("gastown", "nux", "b", "gt-abc", "main", "tests", "failed")
+	msg.Subject = "Merge failed: nux" // subject no longer matches a prefix
+	handled, err := registry.ProcessProtocolMessage(msg)
+	if !handled || err != nil {
+		t.Fatalf("ProcessProtocolMessage = %v, %v", handled, err)
+	}
+	if !handler.failedCalled {
+		t.Error("HandleMergeFailed not called")
+	}
+}
@@ -13,6 +13,8 @@ package protocol
 import (
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
 )
 
 // MessageType identifies the protocol message type.
@@ -62,6 +64,19 @@ func ParseMessageType(subject string) MessageType {
 	return ""
 }
 
+// MessageTypeOf returns the protocol type of a message. The body's envelope
+// is authoritative; the subject prefix is used for messages without one.
+// Returns empty string if the message is not a known protocol message.
+func MessageTypeOf(msg *mail.Message) MessageType {
+	if env, ok := mail.ParseEnvelope(msg.Body); ok {
+		switch t := MessageType(env.Type); t {
+		case TypeMergeReady, TypeMerged, TypeMergeFailed, TypeReworkRequest:
+			return t
+		}
+	}
+	return ParseMessageType(msg.Subject)
+}
+
 // MergeReadyPayload contains the data for a MERGE_READY message.
 // Sent by Witness after verifying polecat work is complete.
 type MergeReadyPayload struct {
@@ -135,6 +150,16 @@ type MergeFailedPayload struct {
 
 	// TargetBranch is the branch we tried to merge into.
 	TargetBranch string `json:"target_branch"`
+
+	// MR is the merge-request bead ID. When tests fail, the tail of the
+	// test log is attached to this bead as a comment.
+	MR string `json:"mr,omitempty"`
+
+	// FailedTests lists the tests that failed on every attempt.
+	FailedTests []string `json:"failed_tests,omitempty"`
+
+	// FlakyTests lists tests that failed on some attempts but not others.
+	FlakyTests []string `json:"flaky_tests,omitempty"`
 }
 
 // ReworkRequestPayload contains the data for a REWORK_REQUEST message.
@@ -0,0 +1,323 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains batch ("train") merging with bisection.
+
+package refinery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// trainBranch is the local scratch branch used to stack a batch of MRs.
+const trainBranch = "refinery/train"
+
+// BatchEntry pairs an MR with the outcome of a batch merge.
+type BatchEntry struct {
+	MR     *MRInfo
+	Result ProcessResult
+}
+
+// SelectBatch picks the next merge train from the ready queue.
+// MRs are ordered by ScoreMR (highest first) and the top MaxConcurrent MRs
+// that share the highest-scored MR's target branch are returned.
+func (e *Engineer) SelectBatch(mrs []*MRInfo, now time.Time) []*MRInfo {
+	if len(mrs) == 0 {
+		return nil
+	}
+	size := e.config.MaxConcurrent
+	if size < 1 {
+		size = 1
+	}
+
+	scoring := DefaultScoreConfig()
+	if e.scoring != nil {
+		scoring = *e.scoring
+	}
+	sorted := make([]*MRInfo, len(mrs))
+	copy(sorted, mrs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ScoreWith(now, scoring) > sorted[j].ScoreWith(now, scoring)
+	})
+
+	target := sorted[0].Target
+	var batch []*MRInfo
+	for _, mr := range sorted {
+		if mr.Target != target {
+			continue
+		}
+		batch = append(batch, mr)
+		if len(batch) == size {
+			break
+		}
+	}
+	return batch
+}
+
+// ProcessBatch merges a train of MRs that share a target branch.
+//
+// The MRs are squash-merged in order onto a speculative branch and the
+// test suite runs once against the tip. If tests pass, the whole train
+// lands with a single push. If they fail, the train is bisected to find the
+// first MR whose addition breaks the tests; the MRs before it are kept, the
+// culprit is reported as TestsFailed, and the MRs after it are re-stacked
+// and tested again. MRs that conflict with the train are reported as
+// Conflict and skipped without stopping the train.
+//
+// A batch of one MR is processed exactly like ProcessMRInfo.
+// Results are returned in input order; callers should dispatch each one to
+// HandleMRInfoSuccess or HandleMRInfoFailure.
+func (e *Engineer) ProcessBatch(ctx context.Context, mrs []*MRInfo) []BatchEntry {
+	if len(mrs) == 0 {
+		return nil
+	}
+	if len(mrs) == 1 {
+		return []BatchEntry{{MR: mrs[0], Result: e.ProcessMRInfo(ctx, mrs[0])}}
+	}
+
+	target := mrs[0].Target
+	results := make(map[string]ProcessResult, len(mrs))
+	entries := func() []BatchEntry {
+		out := make([]BatchEntry, 0, len(mrs))
+		for _, mr := range mrs {
+			out = append(out, BatchEntry{MR: mr, Result: results[mr.ID]})
+		}
+		return out
+	}
+	failAll := func(pending []*MRInfo, msg string) []BatchEntry {
+		for _, mr := range pending {
+			if _, done := results[mr.ID]; !done {
+				results[mr.ID] = ProcessResult{Success: false, Error: msg}
+			}
+		}
+		return entries()
+	}
+
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Processing merge train of %d MRs into %s\n", len(mrs), target)
+
+	// Validate branches up front so one missing branch doesn't sink the train.
+	var candidates []*MRInfo
+	for _, mr := range mrs {
+		if mr.Target != target {
+			results[mr.ID] = ProcessResult{Success: false, Error: fmt.Sprintf("target %s does not match train target %s", mr.Target, target)}
+			continue
+		}
+		exists, err := e.git.BranchExists(mr.Branch)
+		if err != nil {
+			results[mr.ID] = ProcessResult{Success: false, Error: fmt.Sprintf("failed to check branch %s: %v", mr.Branch, err)}
+			continue
+		}
+		if !exists {
+			results[mr.ID] = ProcessResult{Success: false, Error: fmt.Sprintf("branch %s not found locally", mr.Branch)}
+			continue
+		}
+		candidates = append(candidates, mr)
+	}
+	if len(candidates) == 0 {
+		return entries()
+	}
+
+	if err := e.git.Checkout(target); err != nil {
+		return failAll(candidates, fmt.Sprintf("failed to checkout target %s: %v", target, err))
+	}
+	if err := e.git.Pull("origin", target); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: pull from origin/%s: %v (continuing)\n", target, err)
+	}
+	base, err := e.git.Rev("HEAD")
+	if err != nil {
+		return failAll(candidates, fmt.Sprintf("failed to resolve %s: %v", target, err))
+	}
+	defer func() {
+		_ = e.git.Checkout(target)
+		_ = e.git.DeleteBranch(trainBranch, true)
+	}()
+
+	// landed maps MR ID -> squash commit on the accepted chain.
+	landed := make(map[string]string)
+	for len(candidates) > 0 {
+		if ctx.Err() != nil {
+			return failAll(candidates, "merge train canceled")
+		}
+
+		stacked, commits, err := e.buildTrain(base, candidates, results)
+		if err != nil {
+			return failAll(candidates, err.Error())
+		}
+		if len(stacked) == 0 {
+			break
+		}
+
+		tip := commits[len(commits)-1]
+		if !e.shouldRunTests() {
+			e.acceptPrefix(stacked, commits, len(stacked), landed)
+			base = tip
+			break
+		}
+
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Testing train tip (%d MRs): %s\n", len(stacked), e.config.TestCommand)
+		tipResult, err := e.testCommit(ctx, tip)
+		if err != nil {
+			return failAll(stacked, err.Error())
+		}
+		if tipResult.Success {
+			e.acceptPrefix(stacked, commits, len(stacked), landed)
+			base = tip
+			break
+		}
+		if ctx.Err() != nil {
+			return failAll(stacked, "test run canceled")
+		}
+
+		// Bisect for the first failing prefix. Invariant: prefix of length
+		// lo passes (length 0 is the current base), prefix of length hi fails.
+		lo, hi := 0, len(stacked)
+		culpritResult := tipResult
+		for hi-lo > 1 {
+			mid := (lo + hi) / 2
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Bisecting train: testing first %d of %d MRs\n", mid, len(stacked))
+			r, err := e.testCommit(ctx, commits[mid-1])
+			if err != nil {
+				return failAll(stacked, err.Error())
+			}
+			if ctx.Err() != nil {
+				return failAll(stacked, "test run canceled")
+			}
+			if r.Success {
+				lo = mid
+			} else {
+				hi = mid
+				culpritResult = r
+			}
+		}
+
+		culprit := stacked[hi-1]
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Bisect found culprit: %s (%s)\n", culprit.ID, culprit.Branch)
+		culpritResult.TestsFailed = true
+		culpritResult.Error = fmt.Sprintf("%s (isolated by merge train bisection)", culpritResult.Error)
+		results[culprit.ID] = culpritResult
+
+		e.acceptPrefix(stacked, commits, lo, landed)
+		if lo > 0 {
+			base = commits[lo-1]
+		}
+		candidates = stacked[hi:]
+	}
+
+	if len(landed) == 0 {
+		return entries()
+	}
+
+	// Fast-forward target to the accepted chain and push once.
+	if err := e.git.Checkout(trainBranch); err != nil {
+		return e.failLanded(mrs, landed, results, fmt.Sprintf("failed to checkout train: %v", err))
+	}
+	if err := e.git.ResetBranch(target, base); err != nil {
+		return e.failLanded(mrs, landed, results, fmt.Sprintf("failed to advance %s: %v", target, err))
+	}
+	if err := e.git.Checkout(target); err != nil {
+		return e.failLanded(mrs, landed, results, fmt.Sprintf("failed to checkout target %s: %v", target, err))
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Pushing train (%d MRs) to origin/%s...\n", len(landed), target)
+	if err := e.git.Push("origin", target, false); err != nil {
+		return e.failLanded(mrs, landed, results, fmt.Sprintf("failed to push to origin: %v", err))
+	}
+
+	for id, commit := range landed {
+		results[id] = ProcessResult{Success: true, MergeCommit: commit}
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged train: %d landed, %d failed\n", len(landed), len(mrs)-len(landed))
+	return entries()
+}
+
+// shouldRunTests reports whether the merge queue is configured to gate on tests.
+func (e *Engineer) shouldRunTests() bool {
+	return e.config.RunTests && e.config.TestCommand != ""
+}
+
+// buildTrain resets the train branch to base and squash-merges each
+// candidate onto it. Candidates that conflict are recorded in results and
+// left out. It returns the MRs that were stacked and the commit after each.
+func (e *Engineer) buildTrain(base string, candidates []*MRInfo, results map[string]ProcessResult) ([]*MRInfo, []string, error) {
+	if err := e.git.Checkout(base); err != nil {
+		return nil, nil, fmt.Errorf("failed to checkout train base: %v", err)
+	}
+	if err := e.git.ResetBranch(trainBranch, base); err != nil {
+		return nil, nil, fmt.Errorf("failed to create train branch: %v", err)
+	}
+
+	var stacked []*MRInfo
+	var commits []string
+	for _, mr := range candidates {
+		conflicts, err := e.git.CheckConflicts(mr.Branch, trainBranch)
+		if err != nil {
+			results[mr.ID] = ProcessResult{Success: false, Conflict: true, Error: fmt.Sprintf("conflict check failed: %v", err)}
+			continue
+		}
+		if len(conflicts) > 0 {
+			results[mr.ID] = ProcessResult{Success: false, Conflict: true, Error: fmt.Sprintf("merge conflicts in: %v", conflicts)}
+			continue
+		}
+
+		msg, err := e.git.GetBranchCommitMessage(mr.Branch)
+		if err != nil {
+			msg = fmt.Sprintf("Squash merge %s into %s", mr.Branch, mr.Target)
+			if mr.SourceIssue != "" {
+				msg = fmt.Sprintf("Squash merge %s into %s (%s)", mr.Branch, mr.Target, mr.SourceIssue)
+			}
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Stacking %s: %s\n", mr.ID, strings.TrimSpace(msg))
+		if err := e.git.MergeSquash(mr.Branch, msg); err != nil {
+			_ = e.git.AbortMerge()
+			results[mr.ID] = ProcessResult{Success: false, Conflict: true, Error: "merge conflict during actual merge"}
+			continue
+		}
+		commit, err := e.git.Rev("HEAD")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get train commit SHA: %v", err)
+		}
+		stacked = append(stacked, mr)
+		commits = append(commits, commit)
+	}
+	return stacked, commits, nil
+}
+
+// testCommit checks out a train commit (detached) and runs the test suite there.
+func (e *Engineer) testCommit(ctx context.Context, commit string) (ProcessResult, error) {
+	if err := e.git.Checkout(commit); err != nil {
+		return ProcessResult{}, fmt.Errorf("failed to checkout %s for testing: %v", commit, err)
+	}
+	return e.runTests(ctx), nil
+}
+
+// acceptPrefix records the first n stacked MRs as landed at their train commits.
+func (e *Engineer) acceptPrefix(stacked []*MRInfo, commits []string, n int, landed map[string]string) {
+	for i := 0; i < n; i++ {
+		landed[stacked[i].ID] = commits[i]
+	}
+}
+
+// failLanded marks every MR that was about to land as failed (nothing was pushed).
+func (e *Engineer) failLanded(mrs []*MRInfo, landed map[string]string, results map[string]ProcessResult, msg string) []BatchEntry {
+	for id := range landed {
+		results[id] = ProcessResult{Success: false, Error: msg}
+	}
+	out := make([]BatchEntry, 0, len(mrs))
+	for _, mr := range mrs {
+		out = append(out, BatchEntry{MR: mr, Result: results[mr.ID]})
+	}
+	return out
+}
+
+// HandleBatchResults dispatches each batch entry to HandleMRInfoSuccess or
+// HandleMRInfoFailure.
+func (e *Engineer) HandleBatchResults(entries []BatchEntry) {
+	for _, entry := range entries {
+		if entry.Result.Success {
+			e.HandleMRInfoSuccess(entry.MR, entry.Result)
+		} else {
+			e.HandleMRInfoFailure(entry.MR, entry.Result)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// setupTrainRepo creates a bare origin and a working clone with one branch
+// per file name. Each branch adds its file on top of main.
+func setupTrainRepo(t *testing.T, files ...string) (work, origin string) {
+	t.Helper()
+	tmp := t.TempDir()
+	origin = filepath.Join(tmp, "origin.git")
+	work = filepath.Join(tmp, "work")
+
+	run := func(dir string, args ...string) { gitRun(t, dir, args...) }
+
+	run(tmp, "init", "--bare", "-b", "main", origin)
+	run(tmp, "clone", origin, work)
+	run(work, "config", "user.email", "test@test.com")
+	run(work, "config", "user.name", "Test User")
+	run(work, "checkout", "-b", "main")
+	if err := os.WriteFile(filepath.Join(work, "README.md"), []byte("# Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(work, "add", ".")
+	run(work, "commit", "-m", "initial")
+	run(work, "push", "origin", "main")
+
+	for _, f := range files {
+		run(work, "checkout", "-b", "polecat/"+f, "main")
+		if err := os.WriteFile(filepath.Join(work, f), []byte(f+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run(work, "add", ".")
+		run(work, "commit", "-m", "feat: add "+f)
+	}
+	run(work, "checkout", "main")
+	return work, origin
+}
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func newTrainEngineer(work, testCommand string) *Engineer {
+	cfg := DefaultMergeQueueConfig()
+	cfg.TestCommand = testCommand
+	cfg.MaxConcurrent = 4
+	return &Engineer{
+		git:     git.NewGit(work),
+		config:  cfg,
+		workDir: work,
+		output:  io.Discard,
+	}
+}
+
+func trainMRs(files ...string) []*MRInfo {
+	var mrs []*MRInfo
+	for i, f := range files {
+		mrs = append(mrs, &MRInfo{
+			ID:     "mr-" + string(rune('a'+i)),
+			Branch: "polecat/" + f,
+			Target: "main",
+		})
+	}
+	return mrs
+}
+
+func originFiles(t *testing.T, origin string) string {
+	t.Helper()
+	out, err := exec.Command("git", "--git-dir", origin, "ls-tree", "--name-only", "main").Output()
+	if err != nil {
+		t.Fatalf("ls-tree: %v", err)
+	}
+	return strings.Join(strings.Fields(string(out)), ",")
+}
+
+func TestProcessBatch_AllPass(t *testing.T) {
+	work, origin := setupTrainRepo(t, "a.txt", "b.txt", "c.txt")
+	e := newTrainEngineer(work, "true")
+
+	entries := e.ProcessBatch(context.Background(), trainMRs("a.txt", "b.txt", "c.txt"))
+
+	for _, entry := range entries {
+		if !entry.Result.Success {
+			t.Errorf("%s: expected success, got %q", entry.MR.ID, entry.Result.Error)
+		}
+		if entry.Result.MergeCommit == "" {
+			t.Errorf("%s: expected merge commit", entry.MR.ID)
+		}
+	}
+	if got := originFiles(t, origin); got != "README.md,a.txt,b.txt,c.txt" {
+		t.Errorf("origin/main files = %s", got)
+	}
+}
+
+func TestProcessBatch_BisectsCulprit(t *testing.T) {
+	work, origin := setupTrainRepo(t, "a.txt", "bad.txt", "c.txt", "d.txt")
+	e := newTrainEngineer(work, "test ! -e bad.txt")
+
+	entries := e.ProcessBatch(context.Background(), trainMRs("a.txt", "bad.txt", "c.txt", "d.txt"))
+
+	for _, entry := range entries {
+		if entry.MR.Branch == "polecat/bad.txt" {
+			if entry.Result.Success || !entry.Result.TestsFailed {
+				t.Errorf("culprit %s: expected TestsFailed, got %+v", entry.MR.ID, entry.Result)
+			}
+			continue
+		}
+		if !entry.Result.Success {
+			t.Errorf("%s: expected success, got %q", entry.MR.ID, entry.Result.Error)
+		}
+	}
+	if got := originFiles(t, origin); got != "README.md,a.txt,c.txt,d.txt" {
+		t.Errorf("origin/main files = %s, want culprit excluded", got)
+	}
+}
+
+func TestProcessBatch_ConflictSkipped(t *testing.T) {
+	work, origin := setupTrainRepo(t, "a.txt", "b.txt")
+	// A second branch that also adds a.txt with different content conflicts with the first.
+	gitRun(t, work, "checkout", "-b", "polecat/dup", "main")
+	if err := os.WriteFile(filepath.Join(work, "a.txt"), []byte("different\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, work, "add", ".")
+	gitRun(t, work, "commit", "-m", "feat: dup")
+	gitRun(t, work, "checkout", "main")
+
+	e := newTrainEngineer(work, "true")
+	mrs := trainMRs("a.txt", "b.txt")
+	mrs = append(mrs, &MRInfo{ID: "mr-dup", Branch: "polecat/dup", Target: "main"})
+
+	entries := e.ProcessBatch(context.Background(), mrs)
+	for _, entry := range entries {
+		if entry.MR.ID == "mr-dup" {
+			if !entry.Result.Conflict {
+				t.Errorf("mr-dup: expected Conflict, got %+v", entry.Result)
+			}
+			continue
+		}
+		if !entry.Result.Success {
+			t.Errorf("%s: expected success, got %q", entry.MR.ID, entry.Result.Error)
+		}
+	}
+	if got := originFiles(t, origin); got != "README.md,a.txt,b.txt" {
+		t.Errorf("origin/main files = %s", got)
+	}
+}
+
+func TestSelectBatch(t *testing.T) {
+	now := time.Now()
+	e := &Engineer{config: &MergeQueueConfig{MaxConcurrent: 2}}
+	mrs := []*MRInfo{
+		{ID: "low", Target: "main", Priority: 4, CreatedAt: now},
+		{ID: "high", Target: "main", Priority: 0, CreatedAt: now},
+		{ID: "other", Target: "develop", Priority: 1, CreatedAt: now},
+		{ID: "mid", Target: "main", Priority: 2, CreatedAt: now},
+	}
+
+	batch := e.SelectBatch(mrs, now)
+	if len(batch) != 2 || batch[0].ID != "high" || batch[1].ID != "mid" {
+		var ids []string
+		for _, mr := range batch {
+			ids = append(ids, mr.ID)
+		}
+		t.Errorf("SelectBatch = %v, want [high mid]", ids)
+	}
+}
@@ -51,6 +51,8 @@ type MergeQueueConfig struct {
 	PollInterval time.Duration `json:"poll_interval"`
 
 	// MaxConcurrent is the maximum number of MRs to process concurrently.
+	// Values above 1 enable batch ("train") merging: the top-scored MRs are
+	// stacked and tested together (see ProcessBatch).
 	MaxConcurrent int `json:"max_concurrent"`
 }
 
@@ -87,6 +89,7 @@ type MRInfo struct {
 	ConvoyCreatedAt *time.Time // Convoy creation time
 	CreatedAt       time.Time  // MR creation time
 	BlockedBy       string     // Task ID blocking this MR
+	Labels          []string   // Labels on the MR bead (for label boosts)
 }
 
 // Engineer is the merge queue processor that polls for ready merge-requests
@@ -96,6 +99,7 @@ type Engineer struct {
 	beads   *beads.Beads
 	git     *git.Git
 	config  *MergeQueueConfig
+	scoring *ScoreConfig // MR scoring weights; nil means DefaultScoreConfig
 	workDir string
 	output  io.Writer    // Output destination for user-facing messages
 	router  *mail.Router // Mail router for sending protocol messages
@@ -110,19 +114,15 @@ func NewEngineer(r *rig.Rig) *Engineer {
 	// Override target branch with rig's configured default branch
 	cfg.TargetBranch = r.DefaultBranch()
 
-	// Determine the git working directory for refinery operations.
-	// Prefer refinery/rig worktree, fall back to mayor/rig (legacy architecture).
-	// Using rig.Path directly would find town's .git with rig-named remotes instead of "origin".
-	gitDir := filepath.Join(r.Path, "refinery", "rig")
-	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
-		gitDir = filepath.Join(r.Path, "mayor", "rig")
-	}
+	gitDir := refineryGitDir(r)
+	scoring := LoadScoreConfig(r.Path)
 
 	return &Engineer{
 		rig:     r,
 		beads:   beads.New(r.Path),
 		git:     git.NewGit(gitDir),
 		config:  cfg,
+		scoring: &scoring,
 		workDir: gitDir,
 		output:  os.Stdout,
 		router:  mail.NewRouter(r.Path),
@@ -130,6 +130,17 @@ func NewEngineer(r *rig.Rig) *Engineer {
 	}
 }
 
+// refineryGitDir determines the git working directory for refinery operations.
+// Prefer refinery/rig worktree, fall back to mayor/rig (legacy architecture).
+// Using rig.Path directly would find town's .git with rig-named remotes instead of "origin".
+func refineryGitDir(r *rig.Rig) string {
+	gitDir := filepath.Join(r.Path, "refinery", "rig")
+	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
+		gitDir = filepath.Join(r.Path, "mayor", "rig")
+	}
+	return gitDir
+}
+
 // SetOutput sets the output writer for user-facing messages.
 // This is useful for testing or redirecting output.
 func (e *Engineer) SetOutput(w io.Writer) {
@@ -231,6 +242,10 @@ type ProcessResult struct {
 	Error       string
 	Conflict    bool
 	TestsFailed bool
+
+	// TestReport holds captured test output and parsed failures when
+	// TestsFailed is set. Nil otherwise.
+	TestReport *TestReport
 }
 
 // ProcessMR processes a single merge request from a beads issue.
@@ -314,6 +329,7 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 				Success:     false,
 				TestsFailed: true,
 				Error:       result.Error,
+				TestReport:  result.TestReport,
 			}
 		}
 		_, _ = fmt.Fprintln(e.output, "[Engineer] Tests passed")
@@ -388,6 +404,9 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 	}
 
 	var lastErr error
+	var lastOutput []byte
+	var attempts [][]TestFailure
+	format := TestFormatText
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		if attempt > 1 {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Retrying tests (attempt %d/%d)...\n", attempt, maxRetries)
@@ -397,15 +416,24 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		// not from PR branches. Shell execution is intentional for flexibility (pipes, etc).
 		cmd := exec.CommandContext(ctx, "sh", "-c", e.config.TestCommand) //nolint:gosec // G204: TestCommand is from trusted rig config
 		cmd.Dir = e.workDir
-		var stdout, stderr bytes.Buffer
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
 
 		err := cmd.Run()
 		if err == nil {
+			attempts = append(attempts, nil)
+			if flaky := flakyTests(attempts); len(flaky) > 0 {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Tests passed on attempt %d; flaky: %s\n", attempt, strings.Join(flaky, ", "))
+			}
 			return ProcessResult{Success: true}
 		}
 		lastErr = err
+		lastOutput = output.Bytes()
+
+		var failures []TestFailure
+		format, failures = ParseTestOutput(lastOutput)
+		attempts = append(attempts, failures)
 
 		// Check if context was canceled
 		if ctx.Err() != nil {
@@ -416,13 +444,35 @@ func (e *Engineer) runTests(ctx context.Context) ProcessResult {
 		}
 	}
 
+	report := &TestReport{
+		Format:     format,
+		Attempts:   len(attempts),
+		Failures:   attempts[len(attempts)-1],
+		Flaky:      flakyTests(attempts),
+		OutputTail: tailOutput(lastOutput),
+	}
+
+	errMsg := fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr)
+	if ids := report.FailedTestIDs(); len(ids) > 0 {
+		errMsg = fmt.Sprintf("%s (failing: %s)", errMsg, summarizeIDs(ids, 5))
+	}
+
 	return ProcessResult{
 		Success:     false,
 		TestsFailed: true,
-		Error:       fmt.Sprintf("tests failed after %d attempts: %v", maxRetries, lastErr),
+		Error:       errMsg,
+		TestReport:  report,
 	}
 }
 
+// summarizeIDs joins up to max IDs, noting how many were omitted.
+func summarizeIDs(ids []string, max int) string {
+	if len(ids) <= max {
+		return strings.Join(ids, ", ")
+	}
+	return fmt.Sprintf("%s, +%d more", strings.Join(ids[:max], ", "), len(ids)-max)
+}
+
 // handleSuccess handles a successful merge completion.
 // Steps:
 // 1. Update MR with merge_commit SHA
@@ -612,7 +662,31 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	} else if result.TestsFailed {
 		failureType = "tests"
 	}
-	msg := protocol.NewMergeFailedMessage(e.rig.Name, mr.Worker, mr.Branch, mr.SourceIssue, mr.Target, failureType, result.Error)
+	payload := protocol.MergeFailedPayload{
+		Branch:       mr.Branch,
+		Issue:        mr.SourceIssue,
+		Polecat:      mr.Worker,
+		Rig:          e.rig.Name,
+		FailureType:  failureType,
+		Error:        result.Error,
+		TargetBranch: mr.Target,
+		MR:           mr.ID,
+	}
+
+	// Attach the test log to the MR bead so the polecat can see which test broke
+	if result.TestReport != nil {
+		payload.FailedTests = result.TestReport.FailedTestIDs()
+		payload.FlakyTests = result.TestReport.Flaky
+		if mr.ID != "" {
+			if err := e.beads.AddComment(mr.ID, result.TestReport.FormatArtifact(e.config.TestCommand)); err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to attach test log to MR %s: %v\n", mr.ID, err)
+			} else {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Attached test log to MR %s\n", mr.ID)
+			}
+		}
+	}
+
+	msg := protocol.NewMergeFailedMessageFromPayload(payload)
 	if err := e.router.Send(msg); err != nil {
 		fmt.Fprintf(e.output, "[Engineer] Warning: failed to send MERGE_FAILED to witness: %v\n", err)
 	} else {
@@ -835,6 +909,7 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			ConvoyID:        fields.ConvoyID,
 			ConvoyCreatedAt: convoyCreatedAt,
 			CreatedAt:       createdAt,
+			Labels:          issue.Labels,
 		}
 		mrs = append(mrs, mr)
 	}
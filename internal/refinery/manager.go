@@ -34,6 +34,7 @@ type Manager struct {
 	rig     *rig.Rig
 	workDir string
 	output  io.Writer // Output destination for user-facing messages
+	scorer  *Scorer   // Lazily created on first Queue() call
 }
 
 // NewManager creates a new refinery manager for a rig.
@@ -262,34 +263,10 @@ func (m *Manager) Queue() ([]QueueItem, error) {
 // calculateIssueScore computes the priority score for an MR issue.
 // Higher scores mean higher priority (process first).
 func (m *Manager) calculateIssueScore(issue *beads.Issue, now time.Time) float64 {
-	fields := beads.ParseMRFields(issue)
-
-	// Parse MR creation time
-	mrCreatedAt := parseTime(issue.CreatedAt)
-	if mrCreatedAt.IsZero() {
-		mrCreatedAt = now // Fallback
-	}
-
-	// Build score input
-	input := ScoreInput{
-		Priority:    issue.Priority,
-		MRCreatedAt: mrCreatedAt,
-		Now:         now,
-	}
-
-	// Add fields from MR metadata if available
-	if fields != nil {
-		input.RetryCount = fields.RetryCount
-
-		// Parse convoy created at if available
-		if fields.ConvoyCreatedAt != "" {
-			if convoyTime := parseTime(fields.ConvoyCreatedAt); !convoyTime.IsZero() {
-				input.ConvoyCreatedAt = &convoyTime
-			}
-		}
+	if m.scorer == nil {
+		m.scorer = NewScorer(m.rig)
 	}
-
-	return ScoreMRWithDefaults(input)
+	return m.scorer.Score(issue, now)
 }
 
 // issueToMR converts a beads issue to a MergeRequest.
@@ -4,7 +4,16 @@
 package refinery
 
 import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
 )
 
 // ScoreConfig contains tunable weights for MR priority scoring.
@@ -37,6 +46,83 @@ type ScoreConfig struct {
 	// MaxRetryPenalty caps the total retry penalty to prevent permanent deprioritization.
 	// Default: 300.0 (after 6 retries, penalty is capped)
 	MaxRetryPenalty float64
+
+	// DiffSizeWeight is points subtracted per 100 changed lines (small first).
+	// Default: 0 (disabled)
+	DiffSizeWeight float64
+
+	// MaxDiffSizePenalty caps the total diff size penalty.
+	// Zero means uncapped.
+	MaxDiffSizePenalty float64
+
+	// HotPaths are glob patterns matched against changed file paths.
+	// A pattern without a slash matches the file's base name; a pattern
+	// ending in "/**" matches everything under a directory.
+	HotPaths []string
+
+	// HotPathWeight is points added per changed file matching HotPaths.
+	// Default: 0 (disabled)
+	HotPathWeight float64
+
+	// DependentWeight is points added per open issue blocked on the MR's
+	// source issue. Default: 0 (disabled)
+	DependentWeight float64
+
+	// LabelBoosts adds points for each matching label on the MR bead.
+	LabelBoosts map[string]float64
+}
+
+// NeedsDiffStats reports whether scoring uses diff size or changed files,
+// which require a git lookup per MR.
+func (c ScoreConfig) NeedsDiffStats() bool {
+	return c.DiffSizeWeight != 0 || (c.HotPathWeight != 0 && len(c.HotPaths) > 0)
+}
+
+// NeedsDependents reports whether scoring uses the blocking-dependents count,
+// which requires a beads lookup per MR.
+func (c ScoreConfig) NeedsDependents() bool {
+	return c.DependentWeight != 0
+}
+
+// ScoreConfigFromSettings applies rig settings overrides on top of the defaults.
+func ScoreConfigFromSettings(s *config.MergeQueueScoringConfig) ScoreConfig {
+	cfg := DefaultScoreConfig()
+	if s == nil {
+		return cfg
+	}
+	set := func(dst *float64, src *float64) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	set(&cfg.BaseScore, s.BaseScore)
+	set(&cfg.ConvoyAgeWeight, s.ConvoyAgeWeight)
+	set(&cfg.PriorityWeight, s.PriorityWeight)
+	set(&cfg.RetryPenalty, s.RetryPenalty)
+	set(&cfg.MaxRetryPenalty, s.MaxRetryPenalty)
+	set(&cfg.MRAgeWeight, s.MRAgeWeight)
+	set(&cfg.DiffSizeWeight, s.DiffSizeWeight)
+	set(&cfg.MaxDiffSizePenalty, s.MaxDiffSizePenalty)
+	set(&cfg.HotPathWeight, s.HotPathWeight)
+	set(&cfg.DependentWeight, s.DependentWeight)
+	cfg.HotPaths = append([]string(nil), s.HotPaths...)
+	if len(s.LabelBoosts) > 0 {
+		cfg.LabelBoosts = make(map[string]float64, len(s.LabelBoosts))
+		for k, v := range s.LabelBoosts {
+			cfg.LabelBoosts[k] = v
+		}
+	}
+	return cfg
+}
+
+// LoadScoreConfig loads the scoring config from a rig's settings/config.json.
+// Missing or invalid settings fall back to DefaultScoreConfig.
+func LoadScoreConfig(rigPath string) ScoreConfig {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil || settings.MergeQueue == nil {
+		return DefaultScoreConfig()
+	}
+	return ScoreConfigFromSettings(settings.MergeQueue.Scoring)
 }
 
 // DefaultScoreConfig returns sensible defaults for MR scoring.
@@ -69,11 +155,43 @@ type ScoreInput struct {
 	// 0 = first attempt.
 	RetryCount int
 
+	// DiffLines is the number of added plus deleted lines.
+	DiffLines int
+
+	// ChangedFiles are the paths touched by the MR (for hot-path matching).
+	ChangedFiles []string
+
+	// BlockingDependents is how many open issues are blocked on the MR's
+	// source issue.
+	BlockingDependents int
+
+	// Labels are the labels on the MR bead.
+	Labels []string
+
 	// Now is the current time (for deterministic testing).
 	// If zero, time.Now() is used.
 	Now time.Time
 }
 
+// ScoreTerm is one additive term of an MR's score.
+type ScoreTerm struct {
+	Name   string  `json:"name"`
+	Detail string  `json:"detail"`
+	Points float64 `json:"points"`
+}
+
+// ScoreBreakdown is an MR's score split into its terms.
+type ScoreBreakdown struct {
+	Terms []ScoreTerm `json:"terms"`
+	Total float64     `json:"total"`
+}
+
+// add appends a term and accumulates the total.
+func (b *ScoreBreakdown) add(name, detail string, points float64) {
+	b.Terms = append(b.Terms, ScoreTerm{Name: name, Detail: detail, Points: points})
+	b.Total += points
+}
+
 // ScoreMR calculates the priority score for a merge request.
 // Higher scores mean higher priority (process first).
 //
@@ -84,20 +202,33 @@ type ScoreInput struct {
 //	      + PriorityWeight * (4 - priority)          // P0=+400, P4=+0
 //	      - min(RetryPenalty * retryCount, MaxRetryPenalty)  // Prevent thrashing
 //	      + MRAgeWeight * hoursOld(MR)               // FIFO tiebreaker
+//	      - min(DiffSizeWeight * lines/100, MaxDiffSizePenalty)  // Small diffs first
+//	      + HotPathWeight * hotFiles                 // Hot path changes
+//	      + DependentWeight * blockingDependents     // Unblock others first
+//	      + sum(LabelBoosts[label])                  // Per-label boosts
+//
+// The optional factors default to zero, so they only apply when configured.
 func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
+	return ExplainMR(input, config).Total
+}
+
+// ExplainMR calculates the priority score for a merge request term by term.
+// Factors that contribute nothing and are not configured are omitted.
+func ExplainMR(input ScoreInput, config ScoreConfig) ScoreBreakdown {
 	now := input.Now
 	if now.IsZero() {
 		now = time.Now()
 	}
 
-	score := config.BaseScore
+	var b ScoreBreakdown
+	b.add("base", "base score", config.BaseScore)
 
 	// Convoy age factor: prevent starvation of old convoys
 	if input.ConvoyCreatedAt != nil {
 		convoyAge := now.Sub(*input.ConvoyCreatedAt)
 		convoyHours := convoyAge.Hours()
 		if convoyHours > 0 {
-			score += config.ConvoyAgeWeight * convoyHours
+			b.add("convoy_age", fmt.Sprintf("%.1fh × %g", convoyHours, config.ConvoyAgeWeight), config.ConvoyAgeWeight*convoyHours)
 		}
 	}
 
@@ -109,23 +240,85 @@ func ScoreMR(input ScoreInput, config ScoreConfig) float64 {
 	if priorityBonus > 4 {
 		priorityBonus = 4 // Clamp for invalid priorities < 0
 	}
-	score += config.PriorityWeight * float64(priorityBonus)
+	b.add("priority", fmt.Sprintf("P%d: %d × %g", input.Priority, priorityBonus, config.PriorityWeight), config.PriorityWeight*float64(priorityBonus))
 
 	// Retry penalty: prevent thrashing on repeatedly failing MRs
 	retryPenalty := config.RetryPenalty * float64(input.RetryCount)
 	if retryPenalty > config.MaxRetryPenalty {
 		retryPenalty = config.MaxRetryPenalty
 	}
-	score -= retryPenalty
+	if input.RetryCount > 0 {
+		b.add("retry_penalty", fmt.Sprintf("%d retries × %g (cap %g)", input.RetryCount, config.RetryPenalty, config.MaxRetryPenalty), -retryPenalty)
+	}
 
 	// MR age factor: FIFO ordering as tiebreaker
 	mrAge := now.Sub(input.MRCreatedAt)
 	mrHours := mrAge.Hours()
 	if mrHours > 0 {
-		score += config.MRAgeWeight * mrHours
+		b.add("mr_age", fmt.Sprintf("%.1fh × %g", mrHours, config.MRAgeWeight), config.MRAgeWeight*mrHours)
+	}
+
+	// Diff size factor: small diffs first
+	if config.DiffSizeWeight != 0 {
+		penalty := config.DiffSizeWeight * float64(input.DiffLines) / 100
+		detail := fmt.Sprintf("%d lines × %g/100", input.DiffLines, config.DiffSizeWeight)
+		if config.MaxDiffSizePenalty > 0 && penalty > config.MaxDiffSizePenalty {
+			penalty = config.MaxDiffSizePenalty
+			detail += fmt.Sprintf(" (cap %g)", config.MaxDiffSizePenalty)
+		}
+		b.add("diff_size", detail, -penalty)
+	}
+
+	// Hot path factor: files touching configured hot paths
+	if config.HotPathWeight != 0 && len(config.HotPaths) > 0 {
+		hot := countHotFiles(input.ChangedFiles, config.HotPaths)
+		b.add("hot_paths", fmt.Sprintf("%d files × %g", hot, config.HotPathWeight), config.HotPathWeight*float64(hot))
+	}
+
+	// Dependents factor: unblock others first
+	if config.DependentWeight != 0 {
+		b.add("dependents", fmt.Sprintf("%d blocked × %g", input.BlockingDependents, config.DependentWeight), config.DependentWeight*float64(input.BlockingDependents))
+	}
+
+	// Label boosts, in sorted order for stable output
+	if len(config.LabelBoosts) > 0 {
+		labels := append([]string(nil), input.Labels...)
+		sort.Strings(labels)
+		for _, label := range labels {
+			if boost, ok := config.LabelBoosts[label]; ok {
+				b.add("label", label, boost)
+			}
+		}
+	}
+
+	return b
+}
+
+// countHotFiles counts changed files matching any hot path pattern.
+func countHotFiles(files, patterns []string) int {
+	n := 0
+	for _, f := range files {
+		for _, p := range patterns {
+			if matchHotPath(p, f) {
+				n++
+				break
+			}
+		}
 	}
+	return n
+}
 
-	return score
+// matchHotPath matches a file path against a hot path pattern.
+func matchHotPath(pattern, file string) bool {
+	if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return file == dir || strings.HasPrefix(file, dir+"/")
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(file))
+		return ok
+	}
+	ok, _ := path.Match(pattern, file)
+	return ok
 }
 
 // ScoreMRWithDefaults is a convenience wrapper using default config.
@@ -150,3 +343,117 @@ func (mr *MRInfo) ScoreAt(now time.Time) float64 {
 	}
 	return ScoreMRWithDefaults(input)
 }
+
+// ScoreWith calculates the priority score at a specific time using the given config.
+func (mr *MRInfo) ScoreWith(now time.Time, config ScoreConfig) float64 {
+	input := ScoreInput{
+		Priority:        mr.Priority,
+		MRCreatedAt:     mr.CreatedAt,
+		ConvoyCreatedAt: mr.ConvoyCreatedAt,
+		RetryCount:      mr.RetryCount,
+		Labels:          mr.Labels,
+		Now:             now,
+	}
+	return ScoreMR(input, config)
+}
+
+// Scorer scores merge-request beads for a rig using the rig's configured
+// ScoreConfig. It gathers the optional inputs (diff stats, dependents)
+// only when the config gives them a non-zero weight.
+type Scorer struct {
+	Config ScoreConfig
+
+	git           *git.Git
+	beads         *beads.Beads
+	defaultBranch string
+}
+
+// NewScorer creates a Scorer for a rig, loading scoring weights from the
+// rig's settings.
+func NewScorer(r *rig.Rig) *Scorer {
+	return &Scorer{
+		Config:        LoadScoreConfig(r.Path),
+		git:           git.NewGit(refineryGitDir(r)),
+		beads:         beads.New(r.BeadsPath()),
+		defaultBranch: r.DefaultBranch(),
+	}
+}
+
+// Input builds the ScoreInput for an MR bead.
+func (s *Scorer) Input(issue *beads.Issue, now time.Time) ScoreInput {
+	mrCreatedAt := parseTime(issue.CreatedAt)
+	if mrCreatedAt.IsZero() {
+		mrCreatedAt = now // Fallback
+	}
+
+	input := ScoreInput{
+		Priority:    issue.Priority,
+		MRCreatedAt: mrCreatedAt,
+		Labels:      issue.Labels,
+		Now:         now,
+	}
+
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return input
+	}
+
+	input.RetryCount = fields.RetryCount
+	if fields.ConvoyCreatedAt != "" {
+		if convoyTime := parseTime(fields.ConvoyCreatedAt); !convoyTime.IsZero() {
+			input.ConvoyCreatedAt = &convoyTime
+		}
+	}
+
+	if s.Config.NeedsDiffStats() && s.git != nil && fields.Branch != "" {
+		target := fields.Target
+		if target == "" {
+			target = s.defaultBranch
+		}
+		stats, err := s.git.DiffStat(target, fields.Branch)
+		if err != nil {
+			// Refinery clone may only have remote-tracking refs
+			stats, _ = s.git.DiffStat("origin/"+target, "origin/"+fields.Branch)
+		}
+		for _, st := range stats {
+			input.DiffLines += st.Added + st.Deleted
+			input.ChangedFiles = append(input.ChangedFiles, st.Path)
+		}
+	}
+
+	if s.Config.NeedsDependents() && s.beads != nil && fields.SourceIssue != "" {
+		if src, err := s.beads.Show(fields.SourceIssue); err == nil {
+			input.BlockingDependents = countOpenDependents(src)
+		}
+	}
+
+	return input
+}
+
+// Score returns the priority score for an MR bead.
+func (s *Scorer) Score(issue *beads.Issue, now time.Time) float64 {
+	return ScoreMR(s.Input(issue, now), s.Config)
+}
+
+// Explain returns the term-by-term score breakdown for an MR bead.
+func (s *Scorer) Explain(issue *beads.Issue, now time.Time) ScoreBreakdown {
+	return ExplainMR(s.Input(issue, now), s.Config)
+}
+
+// countOpenDependents counts issues blocked on the given issue that are still open.
+func countOpenDependents(issue *beads.Issue) int {
+	if len(issue.Dependents) == 0 {
+		return issue.DependentCount
+	}
+	n := 0
+	for _, dep := range issue.Dependents {
+		if dep.Status == "closed" {
+			continue
+		}
+		if dep.DependencyType != "" && dep.DependencyType != "blocks" {
+			continue
+		}
+		n++
+	}
+	return n
+}
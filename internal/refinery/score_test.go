@@ -0,0 +1,128 @@
+package refinery
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestScoreMR_DefaultsUnchanged(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	convoy := now.Add(-10 * time.Hour)
+	input := ScoreInput{
+		Priority:        1,
+		MRCreatedAt:     now.Add(-2 * time.Hour),
+		ConvoyCreatedAt: &convoy,
+		RetryCount:      2,
+		DiffLines:       5000,
+		ChangedFiles:    []string{"internal/config/types.go"},
+		Labels:          []string{"urgent"},
+		Now:             now,
+	}
+
+	// base 1000 + convoy 10*10 + priority 3*100 - retry 2*50 + age 2*1
+	want := 1000.0 + 100 + 300 - 100 + 2
+	if got := ScoreMRWithDefaults(input); math.Abs(got-want) > 1e-9 {
+		t.Errorf("ScoreMRWithDefaults = %v, want %v (optional factors must default off)", got, want)
+	}
+}
+
+func TestExplainMR_OptionalFactors(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cfg := DefaultScoreConfig()
+	cfg.DiffSizeWeight = 10
+	cfg.MaxDiffSizePenalty = 50
+	cfg.HotPaths = []string{"internal/config/*", "docs/**"}
+	cfg.HotPathWeight = 25
+	cfg.DependentWeight = 40
+	cfg.LabelBoosts = map[string]float64{"urgent": 200, "docs": -50}
+
+	input := ScoreInput{
+		Priority:           4,
+		MRCreatedAt:        now,
+		DiffLines:          1000,
+		ChangedFiles:       []string{"internal/config/types.go", "docs/guide.md", "main.go"},
+		BlockingDependents: 3,
+		Labels:             []string{"urgent", "docs", "other"},
+		Now:                now,
+	}
+
+	b := ExplainMR(input, cfg)
+	terms := make(map[string]float64)
+	for _, term := range b.Terms {
+		terms[term.Name] += term.Points
+	}
+	checks := map[string]float64{
+		"base":       1000,
+		"priority":   0,
+		"diff_size":  -50, // 10 * 1000/100 = 100, capped at 50
+		"hot_paths":  50,  // two hot files
+		"dependents": 120,
+		"label":      150,
+	}
+	for name, want := range checks {
+		if got := terms[name]; got != want {
+			t.Errorf("term %s = %v, want %v", name, got, want)
+		}
+	}
+	if _, ok := terms["retry_penalty"]; ok {
+		t.Error("retry_penalty term present with zero retries")
+	}
+
+	sum := 0.0
+	for _, term := range b.Terms {
+		sum += term.Points
+	}
+	if b.Total != sum || ScoreMR(input, cfg) != b.Total {
+		t.Errorf("Total = %v, sum of terms = %v, ScoreMR = %v", b.Total, sum, ScoreMR(input, cfg))
+	}
+}
+
+func TestScoreConfigFromSettings(t *testing.T) {
+	if got := ScoreConfigFromSettings(nil); got.BaseScore != DefaultScoreConfig().BaseScore {
+		t.Errorf("nil settings should yield defaults, got %+v", got)
+	}
+
+	zero := 0.0
+	weight := 500.0
+	got := ScoreConfigFromSettings(&config.MergeQueueScoringConfig{
+		PriorityWeight: &weight,
+		MRAgeWeight:    &zero,
+		HotPaths:       []string{"cmd/*"},
+	})
+	if got.PriorityWeight != 500 {
+		t.Errorf("PriorityWeight = %v, want 500", got.PriorityWeight)
+	}
+	if got.MRAgeWeight != 0 {
+		t.Errorf("MRAgeWeight = %v, want explicit 0", got.MRAgeWeight)
+	}
+	if got.RetryPenalty != DefaultScoreConfig().RetryPenalty {
+		t.Errorf("RetryPenalty = %v, want default", got.RetryPenalty)
+	}
+	if got.NeedsDiffStats() {
+		t.Error("NeedsDiffStats = true, want false when hot paths have no weight")
+	}
+}
+
+func TestMatchHotPath(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"internal/config/*", "internal/config/types.go", true},
+		{"internal/config/*", "internal/config/sub/x.go", false},
+		{"docs/**", "docs/a/b.md", true},
+		{"docs/**", "docsx/a.md", false},
+		{"*.proto", "api/v1/service.proto", true},
+		{"go.mod", "go.mod", true},
+		{"go.mod", "tools/go.mod", true},
+		{"cmd/*", "internal/cmd/x.go", false},
+	}
+	for _, tt := range tests {
+		if got := matchHotPath(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("matchHotPath(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
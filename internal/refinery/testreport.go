@@ -0,0 +1,366 @@
+// Package refinery provides the merge queue processing agent.
+// This file contains test output capture and parsing for failed merges.
+
+package refinery
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// testLogTailLines is how many trailing lines of test output are kept.
+	testLogTailLines = 200
+
+	// testLogTailBytes caps the kept tail so bead comments stay small.
+	testLogTailBytes = 16 * 1024
+
+	// maxFailureOutputBytes caps per-test output kept from structured reports.
+	maxFailureOutputBytes = 2048
+)
+
+// Test output formats recognized by ParseTestOutput.
+const (
+	TestFormatGoJSON = "go-test-json"
+	TestFormatJUnit  = "junit"
+	TestFormatText   = "text"
+)
+
+// TestFailure is a single failing test extracted from test output.
+type TestFailure struct {
+	// Package is the Go package or JUnit classname (may be empty).
+	Package string `json:"package,omitempty"`
+
+	// Name is the test name. Empty means the package itself failed
+	// (e.g., a build error) without a specific test.
+	Name string `json:"name,omitempty"`
+
+	// Output is the captured output for this test, if available.
+	Output string `json:"output,omitempty"`
+}
+
+// ID returns a stable identifier for the failure, used to compare
+// failures across retry attempts.
+func (f TestFailure) ID() string {
+	switch {
+	case f.Package != "" && f.Name != "":
+		return f.Package + "." + f.Name
+	case f.Name != "":
+		return f.Name
+	default:
+		return f.Package
+	}
+}
+
+// TestReport summarizes a test run for a merge request.
+type TestReport struct {
+	// Format is the detected output format (go-test-json, junit, text).
+	Format string `json:"format"`
+
+	// Attempts is how many times the test command ran.
+	Attempts int `json:"attempts"`
+
+	// Failures are the tests that failed on the final attempt.
+	Failures []TestFailure `json:"failures,omitempty"`
+
+	// Flaky lists test IDs that failed on some attempts but not all.
+	Flaky []string `json:"flaky,omitempty"`
+
+	// OutputTail is the tail of the final attempt's combined output.
+	OutputTail string `json:"output_tail,omitempty"`
+}
+
+// FailedTestIDs returns the IDs of failing tests that are not flaky.
+func (r *TestReport) FailedTestIDs() []string {
+	if r == nil {
+		return nil
+	}
+	flaky := make(map[string]bool, len(r.Flaky))
+	for _, id := range r.Flaky {
+		flaky[id] = true
+	}
+	var ids []string
+	for _, f := range r.Failures {
+		if id := f.ID(); id != "" && !flaky[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// FormatArtifact renders the report as a bead comment: a summary of
+// failing tests followed by the tail of the test log.
+func (r *TestReport) FormatArtifact(testCommand string) string {
+	var sb strings.Builder
+	sb.WriteString("Refinery test log")
+	if testCommand != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", testCommand))
+	}
+	sb.WriteString(fmt.Sprintf("\nFormat: %s, attempts: %d\n", r.Format, r.Attempts))
+	if ids := r.FailedTestIDs(); len(ids) > 0 {
+		sb.WriteString("\nFailing tests:\n")
+		for _, id := range ids {
+			sb.WriteString("  - " + id + "\n")
+		}
+	}
+	if len(r.Flaky) > 0 {
+		sb.WriteString("\nFlaky tests (inconsistent across attempts):\n")
+		for _, id := range r.Flaky {
+			sb.WriteString("  - " + id + "\n")
+		}
+	}
+	if r.OutputTail != "" {
+		sb.WriteString("\n--- output tail ---\n")
+		sb.WriteString(r.OutputTail)
+		if !strings.HasSuffix(r.OutputTail, "\n") {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// ParseTestOutput detects the format of test output and extracts failing
+// tests. `go test -json` and JUnit XML are parsed structurally; anything
+// else falls back to scanning for "--- FAIL:" lines.
+func ParseTestOutput(output []byte) (string, []TestFailure) {
+	if failures, ok := parseGoTestJSON(output); ok {
+		return TestFormatGoJSON, failures
+	}
+	if failures, ok := parseJUnitXML(output); ok {
+		return TestFormatJUnit, failures
+	}
+	return TestFormatText, parseTextFailures(output)
+}
+
+// goTestEvent is one line of `go test -json` output.
+type goTestEvent struct {
+	Action  string `json:"Action"`
+	Package string `json:"Package"`
+	Test    string `json:"Test"`
+	Output  string `json:"Output"`
+}
+
+// parseGoTestJSON parses `go test -json` output. It returns ok=false if the
+// output does not look like a test2json stream. Non-JSON lines (e.g., build
+// errors printed by other tools) are tolerated.
+func parseGoTestJSON(output []byte) ([]TestFailure, bool) {
+	type key struct{ pkg, test string }
+	outputs := make(map[key]*strings.Builder)
+	var failed []key
+	events := 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal(line, &ev); err != nil || ev.Action == "" {
+			continue
+		}
+		events++
+		k := key{ev.Package, ev.Test}
+		switch ev.Action {
+		case "output":
+			b := outputs[k]
+			if b == nil {
+				b = &strings.Builder{}
+				outputs[k] = b
+			}
+			if b.Len() < maxFailureOutputBytes {
+				b.WriteString(ev.Output)
+			}
+		case "fail":
+			failed = append(failed, k)
+		}
+	}
+	if events == 0 {
+		return nil, false
+	}
+
+	// Drop parents whose failure is explained by a failing subtest, and
+	// packages whose failure is explained by a failing test.
+	explained := make(map[key]bool)
+	for _, k := range failed {
+		if k.test == "" {
+			continue
+		}
+		explained[key{k.pkg, ""}] = true
+		for i := strings.LastIndex(k.test, "/"); i > 0; i = strings.LastIndex(k.test[:i], "/") {
+			explained[key{k.pkg, k.test[:i]}] = true
+		}
+	}
+
+	var failures []TestFailure
+	for _, k := range failed {
+		if explained[k] {
+			continue
+		}
+		f := TestFailure{Package: k.pkg, Name: k.test}
+		if b := outputs[k]; b != nil {
+			f.Output = truncateOutput(b.String())
+		}
+		failures = append(failures, f)
+	}
+	return failures, true
+}
+
+// junitTestSuites is the root of a JUnit XML report with multiple suites.
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite is a single JUnit test suite.
+type junitTestSuite struct {
+	Name   string           `xml:"name,attr"`
+	Cases  []junitTestCase  `xml:"testcase"`
+	Suites []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestCase is a single JUnit test case.
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure"`
+	Error     *junitProblem `xml:"error"`
+}
+
+// junitProblem is a JUnit <failure> or <error> element.
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// parseJUnitXML parses a JUnit XML report (<testsuites> or <testsuite> root).
+// Leading non-XML text (such as runner output) is skipped.
+func parseJUnitXML(output []byte) ([]TestFailure, bool) {
+	start := bytes.Index(output, []byte("<testsuites"))
+	if start < 0 {
+		start = bytes.Index(output, []byte("<testsuite"))
+	}
+	if start < 0 {
+		return nil, false
+	}
+	data := output[start:]
+
+	var suites []junitTestSuite
+	var multi junitTestSuites
+	if err := xml.Unmarshal(data, &multi); err == nil {
+		suites = multi.Suites
+	} else {
+		var single junitTestSuite
+		if err := xml.Unmarshal(data, &single); err != nil {
+			return nil, false
+		}
+		suites = []junitTestSuite{single}
+	}
+
+	var failures []TestFailure
+	var walk func([]junitTestSuite)
+	walk = func(ss []junitTestSuite) {
+		for _, s := range ss {
+			for _, c := range s.Cases {
+				p := c.Failure
+				if p == nil {
+					p = c.Error
+				}
+				if p == nil {
+					continue
+				}
+				pkg := c.Classname
+				if pkg == "" {
+					pkg = s.Name
+				}
+				out := strings.TrimSpace(p.Body)
+				if out == "" {
+					out = p.Message
+				}
+				failures = append(failures, TestFailure{Package: pkg, Name: c.Name, Output: truncateOutput(out)})
+			}
+			walk(s.Suites)
+		}
+	}
+	walk(suites)
+	return failures, true
+}
+
+// goTextFailRe matches plain `go test` failure lines like "--- FAIL: TestFoo (0.01s)".
+var goTextFailRe = regexp.MustCompile(`^\s*--- FAIL: (\S+)`)
+
+// parseTextFailures extracts failing test names from plain-text output.
+func parseTextFailures(output []byte) []TestFailure {
+	var failures []TestFailure
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		m := goTextFailRe.FindStringSubmatch(scanner.Text())
+		if m == nil || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		failures = append(failures, TestFailure{Name: m[1]})
+	}
+	return failures
+}
+
+// tailOutput returns the last testLogTailLines lines of output, capped at
+// testLogTailBytes.
+func tailOutput(output []byte) string {
+	s := strings.TrimRight(string(output), "\n")
+	lines := strings.Split(s, "\n")
+	if len(lines) > testLogTailLines {
+		lines = lines[len(lines)-testLogTailLines:]
+	}
+	s = strings.Join(lines, "\n")
+	if len(s) > testLogTailBytes {
+		s = s[len(s)-testLogTailBytes:]
+	}
+	return s
+}
+
+// truncateOutput caps per-test output at maxFailureOutputBytes.
+func truncateOutput(s string) string {
+	if len(s) <= maxFailureOutputBytes {
+		return s
+	}
+	return s[:maxFailureOutputBytes] + "\n... (truncated)"
+}
+
+// flakyTests compares failing test IDs across attempts and returns those
+// that failed on some attempts but not on all of them. A passing attempt
+// counts as an attempt with no failures.
+func flakyTests(attempts [][]TestFailure) []string {
+	if len(attempts) < 2 {
+		return nil
+	}
+	counts := make(map[string]int)
+	for _, failures := range attempts {
+		seen := make(map[string]bool)
+		for _, f := range failures {
+			id := f.ID()
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			counts[id]++
+		}
+	}
+	var flaky []string
+	for id, n := range counts {
+		if n < len(attempts) {
+			flaky = append(flaky, id)
+		}
+	}
+	sort.Strings(flaky)
+	return flaky
+}
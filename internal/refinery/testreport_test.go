@@ -0,0 +1,172 @@
+package refinery
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTestOutput_GoJSON(t *testing.T) {
+	output := `{"Action":"run","Package":"example.com/foo","Test":"TestA"}
+{"Action":"output","Package":"example.com/foo","Test":"TestA","Output":"    a_test.go:10: boom\n"}
+{"Action":"fail","Package":"example.com/foo","Test":"TestA"}
+{"Action":"run","Package":"example.com/foo","Test":"TestB/sub"}
+{"Action":"fail","Package":"example.com/foo","Test":"TestB/sub"}
+{"Action":"fail","Package":"example.com/foo","Test":"TestB"}
+{"Action":"pass","Package":"example.com/foo","Test":"TestC"}
+{"Action":"fail","Package":"example.com/foo"}
+{"Action":"fail","Package":"example.com/broken"}
+`
+	format, failures := ParseTestOutput([]byte(output))
+	if format != TestFormatGoJSON {
+		t.Fatalf("format = %q, want %q", format, TestFormatGoJSON)
+	}
+
+	var ids []string
+	for _, f := range failures {
+		ids = append(ids, f.ID())
+	}
+	want := []string{"example.com/foo.TestA", "example.com/foo.TestB/sub", "example.com/broken"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("failures = %v, want %v", ids, want)
+	}
+	if !strings.Contains(failures[0].Output, "boom") {
+		t.Errorf("TestA output = %q, want captured output", failures[0].Output)
+	}
+}
+
+func TestParseTestOutput_JUnit(t *testing.T) {
+	output := `Running suite...
+<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="api">
+    <testcase classname="api.Users" name="test_create"/>
+    <testcase classname="api.Users" name="test_delete">
+      <failure message="expected 204">AssertionError: expected 204, got 500</failure>
+    </testcase>
+    <testcase classname="api.Auth" name="test_login">
+      <error message="timeout"/>
+    </testcase>
+  </testsuite>
+</testsuites>`
+	format, failures := ParseTestOutput([]byte(output))
+	if format != TestFormatJUnit {
+		t.Fatalf("format = %q, want %q", format, TestFormatJUnit)
+	}
+	if len(failures) != 2 {
+		t.Fatalf("got %d failures, want 2: %+v", len(failures), failures)
+	}
+	if failures[0].ID() != "api.Users.test_delete" || !strings.Contains(failures[0].Output, "got 500") {
+		t.Errorf("failures[0] = %+v", failures[0])
+	}
+	if failures[1].ID() != "api.Auth.test_login" || failures[1].Output != "timeout" {
+		t.Errorf("failures[1] = %+v", failures[1])
+	}
+}
+
+func TestParseTestOutput_Text(t *testing.T) {
+	output := `=== RUN   TestFoo
+--- FAIL: TestFoo (0.00s)
+    --- FAIL: TestFoo/case_1 (0.00s)
+FAIL
+FAIL	example.com/foo	0.012s
+`
+	format, failures := ParseTestOutput([]byte(output))
+	if format != TestFormatText {
+		t.Fatalf("format = %q, want %q", format, TestFormatText)
+	}
+	if len(failures) != 2 || failures[0].Name != "TestFoo" || failures[1].Name != "TestFoo/case_1" {
+		t.Errorf("failures = %+v", failures)
+	}
+}
+
+func TestFlakyTests(t *testing.T) {
+	attempts := [][]TestFailure{
+		{{Name: "TestA"}, {Name: "TestB"}},
+		{{Name: "TestA"}},
+	}
+	if got := flakyTests(attempts); !reflect.DeepEqual(got, []string{"TestB"}) {
+		t.Errorf("flakyTests = %v, want [TestB]", got)
+	}
+
+	// A passing attempt makes every earlier failure flaky.
+	attempts = append(attempts, nil)
+	if got := flakyTests(attempts); !reflect.DeepEqual(got, []string{"TestA", "TestB"}) {
+		t.Errorf("flakyTests with pass = %v, want [TestA TestB]", got)
+	}
+
+	if got := flakyTests(attempts[:1]); got != nil {
+		t.Errorf("flakyTests single attempt = %v, want nil", got)
+	}
+}
+
+func TestTailOutput(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < testLogTailLines+50; i++ {
+		sb.WriteString("line\n")
+	}
+	sb.WriteString("last line\n")
+	tail := tailOutput([]byte(sb.String()))
+	if got := strings.Count(tail, "\n") + 1; got != testLogTailLines {
+		t.Errorf("tail has %d lines, want %d", got, testLogTailLines)
+	}
+	if !strings.HasSuffix(tail, "last line") {
+		t.Errorf("tail should end with last line, got %q", tail[len(tail)-20:])
+	}
+}
+
+func TestRunTests_CapturesReport(t *testing.T) {
+	dir := t.TempDir()
+	// The script fails TestStable every time and TestFlaky only on the first run.
+	script := `#!/bin/sh
+echo '{"Action":"fail","Package":"p","Test":"TestStable"}'
+if [ ! -f ran ]; then
+	touch ran
+	echo '{"Action":"fail","Package":"p","Test":"TestFlaky"}'
+fi
+exit 1
+`
+	if err := os.WriteFile(filepath.Join(dir, "test.sh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultMergeQueueConfig()
+	cfg.TestCommand = "./test.sh"
+	cfg.RetryFlakyTests = 2
+	e := &Engineer{config: cfg, workDir: dir, output: io.Discard}
+
+	result := e.runTests(context.Background())
+	if result.Success || !result.TestsFailed {
+		t.Fatalf("expected test failure, got %+v", result)
+	}
+	report := result.TestReport
+	if report == nil {
+		t.Fatal("expected TestReport")
+	}
+	if report.Format != TestFormatGoJSON || report.Attempts != 2 {
+		t.Errorf("report format/attempts = %s/%d", report.Format, report.Attempts)
+	}
+	if got := report.FailedTestIDs(); !reflect.DeepEqual(got, []string{"p.TestStable"}) {
+		t.Errorf("FailedTestIDs = %v, want [p.TestStable]", got)
+	}
+	if !reflect.DeepEqual(report.Flaky, []string{"p.TestFlaky"}) {
+		t.Errorf("Flaky = %v, want [p.TestFlaky]", report.Flaky)
+	}
+	if !strings.Contains(result.Error, "p.TestStable") {
+		t.Errorf("Error = %q, want failing test name", result.Error)
+	}
+	if !strings.Contains(report.OutputTail, "TestStable") {
+		t.Errorf("OutputTail = %q, want captured output", report.OutputTail)
+	}
+
+	artifact := report.FormatArtifact(cfg.TestCommand)
+	for _, want := range []string{"Failing tests:", "p.TestStable", "Flaky tests", "--- output tail ---"} {
+		if !strings.Contains(artifact, want) {
+			t.Errorf("artifact missing %q:\n%s", want, artifact)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"os"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Session backends, as named in town settings (session_backend) and
+// GT_SESSION_BACKEND.
+const (
+	BackendTmux     = "tmux"
+	BackendHeadless = "headless"
+)
+
+// Backend runs agent sessions and drives them: starting, killing, sending
+// input, and reading output. *tmux.Tmux is the default backend;
+// *headless.Client runs agents under PTYs supervised by the daemon.
+type Backend interface {
+	// IsAvailable reports whether the backend can run sessions.
+	IsAvailable() bool
+
+	HasSession(name string) (bool, error)
+	ListSessions() ([]string, error)
+	GetSessionInfo(name string) (*tmux.SessionInfo, error)
+	NewSessionWithCommand(name, workDir, command string) error
+	EnsureSessionFresh(name, workDir string) error
+	KillSession(name string) error
+	KillSessionWithProcesses(name string) error
+	AttachSession(session string) error
+
+	SendKeys(session, keys string) error
+	SendKeysDebounced(session, keys string, debounceMs int) error
+	SendKeysRaw(session, keys string) error
+	NudgeSession(session, message string) error
+	AcceptBypassPermissionsWarning(session string) error
+	CapturePane(session string, lines int) (string, error)
+
+	SetEnvironment(session, key, value string) error
+	IsAgentRunning(session string, expectedPaneCommands ...string) bool
+	IsAgentAlive(session string) bool
+	WaitForCommand(session string, excludeCommands []string, timeout time.Duration) error
+
+	// ConfigureGasTownSession and SetPaneDiedHook decorate a new session;
+	// backends without a status bar may treat theming as a no-op.
+	ConfigureGasTownSession(session string, theme tmux.Theme, rig, worker, role string) error
+	SetPaneDiedHook(session, agentID string) error
+}
+
+var (
+	_ Backend = (*tmux.Tmux)(nil)
+	_ Backend = (*headless.Client)(nil)
+)
+
+// BackendName returns the session backend configured for a town:
+// GT_SESSION_BACKEND if set, else session_backend from town settings,
+// else tmux.
+func BackendName(townRoot string) string {
+	name := os.Getenv("GT_SESSION_BACKEND")
+	if name == "" && townRoot != "" {
+		if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+			name = settings.SessionBackend
+		}
+	}
+	if name == BackendHeadless {
+		return BackendHeadless
+	}
+	return BackendTmux
+}
+
+// NewBackend returns the session backend configured for a town.
+func NewBackend(townRoot string) Backend {
+	if BackendName(townRoot) == BackendHeadless {
+		return headless.NewClient(townRoot)
+	}
+	return tmux.NewTmux()
+}
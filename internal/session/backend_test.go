@@ -0,0 +1,38 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/headless"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestBackendSelection(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv("GT_SESSION_BACKEND", "")
+
+	if got := BackendName(townRoot); got != BackendTmux {
+		t.Errorf("default backend = %q, want tmux", got)
+	}
+	if _, ok := NewBackend(townRoot).(*tmux.Tmux); !ok {
+		t.Error("default backend should be *tmux.Tmux")
+	}
+
+	settings := config.NewTownSettings()
+	settings.SessionBackend = BackendHeadless
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	if got := BackendName(townRoot); got != BackendHeadless {
+		t.Errorf("backend from settings = %q, want headless", got)
+	}
+	if _, ok := NewBackend(townRoot).(*headless.Client); !ok {
+		t.Error("headless backend should be *headless.Client")
+	}
+
+	t.Setenv("GT_SESSION_BACKEND", BackendTmux)
+	if got := BackendName(townRoot); got != BackendTmux {
+		t.Errorf("GT_SESSION_BACKEND should override settings, got %q", got)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
 )
 
 // EventSource represents a source of events
@@ -214,7 +215,7 @@ func parseBeadContext(beadID string) (actor, rig, role string) {
 
 // GtEventsSource reads events from ~/gt/.events.jsonl (gt activity log)
 type GtEventsSource struct {
-	file   *os.File
+	tail   *events.Tailer
 	events chan Event
 	cancel context.CancelFunc
 }
@@ -229,35 +230,32 @@ type GtEvent struct {
 	Visibility string                 `json:"visibility"`
 }
 
-// NewGtEventsSource creates a source that tails ~/gt/.events.jsonl
+// NewGtEventsSource creates a source that tails ~/gt/.events.jsonl,
+// following it across rotations
 func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
-	eventsPath := filepath.Join(townRoot, ".events.jsonl")
-	file, err := os.Open(eventsPath)
-	if err != nil {
+	eventsPath := events.ActivePath(townRoot)
+	if _, err := os.Stat(eventsPath); err != nil {
 		return nil, err
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
 	source := &GtEventsSource{
-		file:   file,
+		tail:   events.NewTailer(townRoot),
 		events: make(chan Event, 100),
 		cancel: cancel,
 	}
 
-	go source.tail(ctx)
+	go source.run(ctx)
 
 	return source, nil
 }
 
-// tail follows the file and sends events
-func (s *GtEventsSource) tail(ctx context.Context) {
+// run follows the file and sends events
+func (s *GtEventsSource) run(ctx context.Context) {
 	defer close(s.events)
+	defer s.tail.Close()
 
-	// Seek to end for live tailing
-	_, _ = s.file.Seek(0, 2)
-
-	scanner := bufio.NewScanner(s.file)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -266,8 +264,7 @@ func (s *GtEventsSource) tail(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			for scanner.Scan() {
-				line := scanner.Text()
+			for _, line := range s.tail.Lines() {
 				if event := parseGtEventLine(line); event != nil {
 					select {
 					case s.events <- *event:
@@ -287,7 +284,7 @@ func (s *GtEventsSource) Events() <-chan Event {
 // Close stops the source
 func (s *GtEventsSource) Close() error {
 	s.cancel()
-	return s.file.Close()
+	return nil
 }
 
 // parseGtEventLine parses a line from .events.jsonl
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 const (
@@ -49,6 +51,9 @@ type APIHandler struct {
 	gtPath string
 	// workDir is the working directory for command execution.
 	workDir string
+	// backend serves town data directly from the Go packages.
+	// Nil when the dashboard is not running inside a town.
+	backend APIBackend
 }
 
 // NewAPIHandler creates a new API handler.
@@ -60,10 +65,14 @@ func NewAPIHandler() *APIHandler {
 	}
 	// Capture the current working directory for command execution
 	workDir, _ := os.Getwd()
-	return &APIHandler{
+	h := &APIHandler{
 		gtPath:  gtPath,
 		workDir: workDir,
 	}
+	if townRoot, err := workspace.Find(workDir); err == nil && townRoot != "" {
+		h.backend = NewTownBackend(townRoot)
+	}
+	return h
 }
 
 // ServeHTTP routes API requests to the appropriate handler.
@@ -79,6 +88,15 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	path := strings.TrimPrefix(r.URL.Path, "/api")
+	if strings.HasPrefix(path, "/"+APIVersion+"/") {
+		if h.backend == nil {
+			writeJSON(w, http.StatusServiceUnavailable, APIError{Error: "not in a Gas Town workspace"})
+			return
+		}
+		newAPIV1(h.backend).ServeHTTP(w, r)
+		return
+	}
+
 	switch {
 	case path == "/run" && r.Method == http.MethodPost:
 		h.handleRun(w, r)
@@ -109,6 +127,8 @@ func (h *APIHandler) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	auditDetail(r, "command", req.Command)
+
 	// Validate command against whitelist
 	meta, err := ValidateCommand(req.Command)
 	if err != nil {
@@ -116,6 +136,12 @@ func (h *APIHandler) handleRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Read-only users may only run safe commands
+	if !meta.Safe && !PrincipalFrom(r.Context()).CanOperate() {
+		h.sendError(w, "Command blocked: read-only access", http.StatusForbidden)
+		return
+	}
+
 	// Determine timeout
 	timeout := DefaultCommandTimeout
 	if req.Timeout > 0 {
@@ -154,11 +180,7 @@ func (h *APIHandler) handleRun(w http.ResponseWriter, r *http.Request) {
 		resp.Output = output
 	}
 
-	// Log command execution (but not for safe read-only commands to reduce noise)
-	if !meta.Safe || !resp.Success {
-		// Could add structured logging here
-		_ = meta // silence unused warning for now
-	}
+	auditDetail(r, "success", resp.Success)
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
@@ -231,117 +253,103 @@ type MailMessage struct {
 	Timestamp string `json:"timestamp"`
 	Read      bool   `json:"read"`
 	Priority  string `json:"priority,omitempty"`
+	Type      string `json:"type,omitempty"`
+	ThreadID  string `json:"thread_id,omitempty"`
+	ReplyTo   string `json:"reply_to,omitempty"`
 }
 
-// MailInboxResponse is the response for /api/mail/inbox.
+// MailInboxResponse is the response for /api/v1/mail/inbox.
 type MailInboxResponse struct {
 	Messages    []MailMessage `json:"messages"`
 	UnreadCount int           `json:"unread_count"`
 	Total       int           `json:"total"`
 }
 
-// handleMailInbox returns the user's inbox.
+// MailSearchHit is a ranked message in /api/v1/mail/search responses.
+type MailSearchHit struct {
+	MailMessage
+	Archived bool    `json:"archived,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// MailSearchResponse is the response for /api/v1/mail/search.
+type MailSearchResponse struct {
+	Query   string          `json:"query"`
+	Results []MailSearchHit `json:"results"`
+}
+
+// MailSendRequest is the request body for /api/v1/mail/send.
+type MailSendRequest struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	ReplyTo string `json:"reply_to,omitempty"`
+	// From is the sender address (default "overseer").
+	From string `json:"from,omitempty"`
+}
+
+// The unversioned mail and issue endpoints predate /api/v1 and are kept
+// for existing clients. They are served from the same backend.
+
+// handleMailInbox returns the overseer's inbox.
+// Deprecated: use GET /api/v1/mail/inbox.
 func (h *APIHandler) handleMailInbox(w http.ResponseWriter, r *http.Request) {
-	output, err := h.runGtCommand(r.Context(), 10*time.Second, []string{"mail", "inbox", "--json"})
-	if err != nil {
-		// Try without --json flag
-		output, err = h.runGtCommand(r.Context(), 10*time.Second, []string{"mail", "inbox"})
-		if err != nil {
-			h.sendError(w, "Failed to fetch inbox: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-		// Parse text output
-		messages := parseMailInboxText(output)
-		unread := 0
-		for _, m := range messages {
-			if !m.Read {
-				unread++
-			}
-		}
-		w.Header().Set("Content-Type", "application/json")
-		_ = json.NewEncoder(w).Encode(MailInboxResponse{
-			Messages:    messages,
-			UnreadCount: unread,
-			Total:       len(messages),
-		})
+	if h.backend == nil {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusServiceUnavailable)
 		return
 	}
-
-	// Parse JSON output
-	var messages []MailMessage
-	if err := json.Unmarshal([]byte(output), &messages); err != nil {
-		h.sendError(w, "Failed to parse inbox: "+err.Error(), http.StatusInternalServerError)
+	resp, err := newAPIV1(h.backend).inbox(mailAddress(r))
+	if err != nil {
+		h.sendError(w, "Failed to fetch inbox: "+err.Error(), apiErrorStatus(err))
 		return
 	}
-
-	unread := 0
-	for _, m := range messages {
-		if !m.Read {
-			unread++
-		}
-	}
-
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(MailInboxResponse{
-		Messages:    messages,
-		UnreadCount: unread,
-		Total:       len(messages),
-	})
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
-// handleMailRead reads a specific message by ID.
+// handleMailRead returns a message by ID and marks it read.
+// Deprecated: use GET /api/v1/mail/messages/{id}.
 func (h *APIHandler) handleMailRead(w http.ResponseWriter, r *http.Request) {
 	msgID := r.URL.Query().Get("id")
 	if msgID == "" {
 		h.sendError(w, "Missing message ID", http.StatusBadRequest)
 		return
 	}
+	if h.backend == nil {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusServiceUnavailable)
+		return
+	}
 
-	output, err := h.runGtCommand(r.Context(), 10*time.Second, []string{"mail", "read", msgID})
+	address := mailAddress(r)
+	msg, err := h.backend.Message(address, msgID)
 	if err != nil {
-		h.sendError(w, "Failed to read message: "+err.Error(), http.StatusInternalServerError)
+		h.sendError(w, "Failed to read message: "+err.Error(), apiErrorStatus(err))
 		return
 	}
-
-	// Parse the message output
-	msg := parseMailReadOutput(output, msgID)
+	_ = h.backend.MarkRead(address, msgID)
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(msg)
-}
-
-// MailSendRequest is the request body for /api/mail/send.
-type MailSendRequest struct {
-	To      string `json:"to"`
-	Subject string `json:"subject"`
-	Body    string `json:"body"`
-	ReplyTo string `json:"reply_to,omitempty"`
+	_ = json.NewEncoder(w).Encode(toMailMessage(msg, true))
 }
 
 // handleMailSend sends a new message.
+// Deprecated: use POST /api/v1/mail/send.
 func (h *APIHandler) handleMailSend(w http.ResponseWriter, r *http.Request) {
 	var req MailSendRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.sendError(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
-
-	if req.To == "" || req.Subject == "" {
-		h.sendError(w, "Missing required fields (to, subject)", http.StatusBadRequest)
+	if h.backend == nil {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusServiceUnavailable)
 		return
 	}
 
-	args := []string{"mail", "send", req.To, "-s", req.Subject}
-	if req.Body != "" {
-		args = append(args, "-m", req.Body)
-	}
-	if req.ReplyTo != "" {
-		args = append(args, "--reply-to", req.ReplyTo)
-	}
-
-	output, err := h.runGtCommand(r.Context(), 30*time.Second, args)
+	auditDetail(r, "to", req.To)
+	auditDetail(r, "subject", req.Subject)
+	msg, err := newAPIV1(h.backend).send(req)
 	if err != nil {
-		h.sendError(w, "Failed to send message: "+err.Error()+"\n"+output, http.StatusInternalServerError)
+		h.sendError(w, "Failed to send message: "+err.Error(), apiErrorStatus(err))
 		return
 	}
 
@@ -349,89 +357,10 @@ func (h *APIHandler) handleMailSend(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"success": true,
 		"message": "Message sent",
-		"output":  output,
+		"id":      msg.ID,
 	})
 }
 
-// parseMailInboxText parses text output from "gt mail inbox".
-func parseMailInboxText(output string) []MailMessage {
-	var messages []MailMessage
-	lines := strings.Split(output, "\n")
-
-	// Format: "  1. ● subject" or "  1. subject" (● = unread)
-	// followed by "      id from sender"
-	// followed by "      timestamp"
-	var current *MailMessage
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" || strings.HasPrefix(trimmed, "📬") || strings.HasPrefix(trimmed, "(no messages)") {
-			continue
-		}
-
-		// Check for numbered message line
-		if len(trimmed) > 2 && trimmed[0] >= '1' && trimmed[0] <= '9' && trimmed[1] == '.' {
-			// Save previous message
-			if current != nil {
-				messages = append(messages, *current)
-			}
-			current = &MailMessage{}
-			// Parse "1. ● subject" or "1. subject"
-			rest := strings.TrimSpace(trimmed[2:])
-			if strings.HasPrefix(rest, "●") {
-				current.Read = false
-				current.Subject = strings.TrimSpace(rest[len("●"):])
-			} else {
-				current.Read = true
-				current.Subject = rest
-			}
-		} else if current != nil && current.ID == "" && strings.Contains(trimmed, " from ") {
-			// Parse "id from sender"
-			parts := strings.SplitN(trimmed, " from ", 2)
-			if len(parts) == 2 {
-				current.ID = strings.TrimSpace(parts[0])
-				current.From = strings.TrimSpace(parts[1])
-			}
-		} else if current != nil && current.Timestamp == "" && (strings.Contains(trimmed, "-") || strings.Contains(trimmed, ":")) {
-			current.Timestamp = trimmed
-		}
-	}
-	// Don't forget the last one
-	if current != nil && current.ID != "" {
-		messages = append(messages, *current)
-	}
-
-	return messages
-}
-
-// parseMailReadOutput parses the output from "gt mail read <id>".
-func parseMailReadOutput(output string, msgID string) MailMessage {
-	msg := MailMessage{ID: msgID}
-	lines := strings.Split(output, "\n")
-
-	inBody := false
-	var bodyLines []string
-
-	for _, line := range lines {
-		if strings.HasPrefix(line, "📬 ") || strings.HasPrefix(line, "Subject: ") {
-			msg.Subject = strings.TrimPrefix(strings.TrimPrefix(line, "📬 "), "Subject: ")
-			msg.Subject = strings.TrimSpace(msg.Subject)
-		} else if strings.HasPrefix(line, "From: ") {
-			msg.From = strings.TrimPrefix(line, "From: ")
-		} else if strings.HasPrefix(line, "To: ") {
-			msg.To = strings.TrimPrefix(line, "To: ")
-		} else if strings.HasPrefix(line, "ID: ") {
-			msg.ID = strings.TrimPrefix(line, "ID: ")
-		} else if line == "" && msg.From != "" && !inBody {
-			inBody = true
-		} else if inBody {
-			bodyLines = append(bodyLines, line)
-		}
-	}
-
-	msg.Body = strings.TrimSpace(strings.Join(bodyLines, "\n"))
-	return msg
-}
-
 // OptionItem represents an option with name and status.
 type OptionItem struct {
 	Name    string `json:"name"`
@@ -459,17 +388,35 @@ func (h *APIHandler) handleOptions(w http.ResponseWriter, r *http.Request) {
 	var mu sync.Mutex
 
 	// Run all fetches in parallel
-	wg.Add(7)
-
-	// Fetch rigs (parse text output - no JSON support)
-	go func() {
-		defer wg.Done()
-		if output, err := h.runGtCommand(r.Context(), 5*time.Second, []string{"rig", "list"}); err == nil {
-			mu.Lock()
-			resp.Rigs = parseRigListOutput(output)
-			mu.Unlock()
-		}
-	}()
+	wg.Add(5)
+
+	// Fetch rigs, crew, and mail directly from the town
+	if h.backend != nil {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if rigs, err := h.backend.Rigs(); err == nil {
+				mu.Lock()
+				for _, rg := range rigs {
+					resp.Rigs = append(resp.Rigs, rg.Name)
+					for _, name := range rg.Crew {
+						resp.Crew = append(resp.Crew, rg.Name+"/"+name)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if messages, err := h.backend.Inbox(mailAddress(r)); err == nil {
+				mu.Lock()
+				for _, m := range messages {
+					resp.Messages = append(resp.Messages, m.ID)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
 
 	// Fetch polecats (has JSON support)
 	go func() {
@@ -501,26 +448,6 @@ func (h *APIHandler) handleOptions(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	// Fetch mail messages (parse text output)
-	go func() {
-		defer wg.Done()
-		if output, err := h.runGtCommand(r.Context(), 5*time.Second, []string{"mail", "inbox"}); err == nil {
-			mu.Lock()
-			resp.Messages = parseMailInboxOutput(output)
-			mu.Unlock()
-		}
-	}()
-
-	// Fetch crew members (parse text output)
-	go func() {
-		defer wg.Done()
-		if output, err := h.runGtCommand(r.Context(), 5*time.Second, []string{"crew", "list", "--all"}); err == nil {
-			mu.Lock()
-			resp.Crew = parseCrewListOutput(output)
-			mu.Unlock()
-		}
-	}()
-
 	// Fetch agents with status from status --json (needs longer timeout - can take 20+ seconds)
 	go func() {
 		defer wg.Done()
@@ -538,31 +465,6 @@ func (h *APIHandler) handleOptions(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-// parseRigListOutput extracts rig names from the text output of "gt rig list".
-// Example output:
-//
-//	Rigs in /Users/foo/gt:
-//	  claycantrell
-//	    Polecats: 1  Crew: 2
-//	  gastown
-//	    Polecats: 1  Crew: 1
-func parseRigListOutput(output string) []string {
-	var rigs []string
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		// Rig names are indented with 2 spaces and no colon
-		trimmed := strings.TrimPrefix(line, "  ")
-		if trimmed != line && !strings.Contains(trimmed, ":") && strings.TrimSpace(trimmed) != "" {
-			// This is a rig name line
-			name := strings.TrimSpace(trimmed)
-			if name != "" && !strings.HasPrefix(name, "Rigs") {
-				rigs = append(rigs, name)
-			}
-		}
-	}
-	return rigs
-}
-
 // parseConvoyListOutput extracts convoy IDs from text output.
 func parseConvoyListOutput(output string) []string {
 	var convoys []string
@@ -598,53 +500,6 @@ func parseHooksListOutput(output string) []string {
 	return hooks
 }
 
-// parseMailInboxOutput extracts message IDs from mail inbox output.
-func parseMailInboxOutput(output string) []string {
-	var messages []string
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		// Skip header lines and empty lines
-		if trimmed != "" && !strings.HasPrefix(trimmed, "Mail") && !strings.HasPrefix(trimmed, "No ") && !strings.HasPrefix(trimmed, "ID") && !strings.HasPrefix(trimmed, "---") {
-			parts := strings.Fields(trimmed)
-			if len(parts) > 0 {
-				messages = append(messages, parts[0])
-			}
-		}
-	}
-	return messages
-}
-
-// parseCrewListOutput extracts crew member names (rig/name format) from crew list output.
-func parseCrewListOutput(output string) []string {
-	var crew []string
-	lines := strings.Split(output, "\n")
-	currentRig := ""
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == "" {
-			continue
-		}
-		// Check if this is a rig header (ends with :)
-		if strings.HasSuffix(trimmed, ":") && !strings.Contains(trimmed, " ") {
-			currentRig = strings.TrimSuffix(trimmed, ":")
-			continue
-		}
-		// Skip non-crew lines
-		if strings.HasPrefix(trimmed, "Crew") || strings.HasPrefix(trimmed, "No ") {
-			continue
-		}
-		// This should be a crew member name
-		if currentRig != "" {
-			parts := strings.Fields(trimmed)
-			if len(parts) > 0 {
-				crew = append(crew, currentRig+"/"+parts[0])
-			}
-		}
-	}
-	return crew
-}
-
 // parseAgentsFromStatus extracts agents with status from "gt status --json" output.
 func parseAgentsFromStatus(jsonStr string) []OptionItem {
 	var status struct {
@@ -739,164 +594,41 @@ type IssueShowResponse struct {
 	Updated     string   `json:"updated,omitempty"`
 	DependsOn   []string `json:"depends_on,omitempty"`
 	Blocks      []string `json:"blocks,omitempty"`
-	RawOutput   string   `json:"raw_output"`
 }
 
 // handleIssueShow returns details for a specific issue/bead.
+// Deprecated: use GET /api/v1/issues/{id}.
 func (h *APIHandler) handleIssueShow(w http.ResponseWriter, r *http.Request) {
 	issueID := r.URL.Query().Get("id")
 	if issueID == "" {
 		h.sendError(w, "Missing issue ID", http.StatusBadRequest)
 		return
 	}
-
-	// Run bd show to get issue details
-	output, err := h.runBdCommand(r.Context(), 10*time.Second, []string{"show", issueID})
-	if err != nil {
-		h.sendError(w, "Failed to fetch issue: "+err.Error(), http.StatusInternalServerError)
+	if h.backend == nil {
+		h.sendError(w, "Not in a Gas Town workspace", http.StatusServiceUnavailable)
 		return
 	}
 
-	// Parse the output
-	resp := parseIssueShowOutput(output, issueID)
-
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(resp)
-}
-
-// runBdCommand executes a bd command with the given args.
-func (h *APIHandler) runBdCommand(ctx context.Context, timeout time.Duration, args []string) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "bd", args...)
-	if h.workDir != "" {
-		cmd.Dir = h.workDir
-	}
-	cmd.Stdin = nil
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	output := stdout.String()
-	if stderr.Len() > 0 {
-		if output != "" {
-			output += "\n"
-		}
-		output += stderr.String()
-	}
-
-	if ctx.Err() == context.DeadlineExceeded {
-		return output, fmt.Errorf("command timed out after %v", timeout)
-	}
-
+	issue, err := h.backend.Issue(issueID)
 	if err != nil {
-		return output, fmt.Errorf("command failed: %v", err)
-	}
-
-	return output, nil
-}
-
-// parseIssueShowOutput parses the output from "bd show <id>".
-func parseIssueShowOutput(output string, issueID string) IssueShowResponse {
-	resp := IssueShowResponse{
-		ID:        issueID,
-		RawOutput: output,
-	}
-
-	lines := strings.Split(output, "\n")
-	inDescription := false
-	parsedFirstLine := false
-	var descLines []string
-	var dependsOn []string
-	var blocks []string
-
-	for _, line := range lines {
-		// First non-empty line usually has the format: "○ id · title   [● P2 · OPEN]"
-		if !parsedFirstLine && (strings.HasPrefix(line, "○") || strings.HasPrefix(line, "●")) {
-			parsedFirstLine = true
-			// Parse the first line for title and status
-			// Format: "○ id · title   [● P2 · OPEN]"
-			// Find the bracket first to isolate the status
-			if bracketIdx := strings.Index(line, "["); bracketIdx > 0 {
-				beforeBracket := line[:bracketIdx]
-				statusPart := line[bracketIdx:]
-
-				// Extract priority and status from [● P2 · OPEN]
-				statusPart = strings.Trim(statusPart, "[]●○ ")
-				statusParts := strings.Split(statusPart, "·")
-				if len(statusParts) >= 1 {
-					resp.Priority = strings.TrimSpace(statusParts[0])
-				}
-				if len(statusParts) >= 2 {
-					resp.Status = strings.TrimSpace(statusParts[1])
-				}
-
-				// Now parse the title from before the bracket
-				// Format: "○ id · title"
-				// Find the second · which separates id from title
-				if firstDot := strings.Index(beforeBracket, "·"); firstDot > 0 {
-					afterFirstDot := beforeBracket[firstDot+len("·"):]
-					if secondDot := strings.Index(afterFirstDot, "·"); secondDot > 0 {
-						resp.Title = strings.TrimSpace(afterFirstDot[secondDot+len("·"):])
-					} else {
-						// Only one dot - id is embedded in icon part
-						resp.Title = strings.TrimSpace(afterFirstDot)
-					}
-				}
-			}
-			continue
-		}
-
-		if strings.HasPrefix(line, "Type:") {
-			resp.Type = strings.TrimSpace(strings.TrimPrefix(line, "Type:"))
-		} else if strings.HasPrefix(line, "Created:") {
-			parts := strings.Split(line, "·")
-			resp.Created = strings.TrimSpace(strings.TrimPrefix(parts[0], "Created:"))
-			if len(parts) > 1 {
-				resp.Updated = strings.TrimSpace(strings.TrimPrefix(parts[1], "Updated:"))
-			}
-		} else if line == "DESCRIPTION" {
-			inDescription = true
-		} else if line == "DEPENDS ON" || line == "BLOCKS" {
-			inDescription = false
-		} else if inDescription && strings.TrimSpace(line) != "" {
-			descLines = append(descLines, line)
-		} else if strings.HasPrefix(strings.TrimSpace(line), "→") {
-			// Dependency line
-			depLine := strings.TrimSpace(line)
-			depLine = strings.TrimPrefix(depLine, "→")
-			depLine = strings.TrimSpace(depLine)
-			// Extract just the bead ID
-			if colonIdx := strings.Index(depLine, ":"); colonIdx > 0 {
-				parts := strings.Fields(depLine[:colonIdx])
-				if len(parts) >= 2 {
-					dependsOn = append(dependsOn, parts[1])
-				}
-			}
-		} else if strings.HasPrefix(strings.TrimSpace(line), "←") {
-			// Blocks line
-			blockLine := strings.TrimSpace(line)
-			blockLine = strings.TrimPrefix(blockLine, "←")
-			blockLine = strings.TrimSpace(blockLine)
-			// Extract just the bead ID
-			if colonIdx := strings.Index(blockLine, ":"); colonIdx > 0 {
-				parts := strings.Fields(blockLine[:colonIdx])
-				if len(parts) >= 2 {
-					blocks = append(blocks, parts[1])
-				}
-			}
-		}
+		h.sendError(w, "Failed to fetch issue: "+err.Error(), apiErrorStatus(err))
+		return
 	}
+	v := toAPIIssue(issue)
 
-	resp.Description = strings.TrimSpace(strings.Join(descLines, "\n"))
-	resp.DependsOn = dependsOn
-	resp.Blocks = blocks
-
-	return resp
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(IssueShowResponse{
+		ID:          v.ID,
+		Title:       v.Title,
+		Type:        v.Type,
+		Status:      v.Status,
+		Priority:    fmt.Sprintf("P%d", v.Priority),
+		Description: v.Description,
+		Created:     v.CreatedAt,
+		Updated:     v.UpdatedAt,
+		DependsOn:   v.DependsOn,
+		Blocks:      v.Blocks,
+	})
 }
 
 // PRShowResponse is the response for /api/pr/show.
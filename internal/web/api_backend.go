@@ -0,0 +1,137 @@
+package web
+
+import (
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// APIBackend provides the town data served by /api/v1.
+// Lookups of missing items return the owning package's sentinel error
+// (rig.ErrRigNotFound, mail.ErrMessageNotFound, beads.ErrNotFound).
+type APIBackend interface {
+	// Rigs returns every rig registered in the town, sorted by name.
+	Rigs() ([]*rig.Rig, error)
+
+	// Rig returns a single rig by name.
+	Rig(name string) (*rig.Rig, error)
+
+	// MergeQueue returns the rig's open merge requests in processing order.
+	MergeQueue(rigName string) ([]refinery.QueueItem, error)
+
+	// Inbox returns the messages in address's mailbox.
+	Inbox(address string) ([]*mail.Message, error)
+
+	// Message returns a single message from address's mailbox.
+	Message(address, id string) (*mail.Message, error)
+
+	// SearchMail runs a query against the town's mail index.
+	SearchMail(query string, opts mail.IndexSearchOptions) ([]mail.SearchResult, error)
+
+	// MarkRead marks a message as read without archiving it.
+	MarkRead(address, id string) error
+
+	// SendMail routes a message to its recipient.
+	SendMail(msg *mail.Message) error
+
+	// Issue returns a bead from the town beads.
+	Issue(id string) (*beads.Issue, error)
+}
+
+// TownBackend is the APIBackend for a town on disk, backed directly by the
+// rig, mail, beads, and refinery packages.
+type TownBackend struct {
+	townRoot string
+}
+
+// NewTownBackend creates a backend for the town at townRoot.
+func NewTownBackend(townRoot string) *TownBackend {
+	return &TownBackend{townRoot: townRoot}
+}
+
+// rigManager loads the rigs config fresh so newly added rigs show up
+// without restarting the dashboard.
+func (b *TownBackend) rigManager() *rig.Manager {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(b.townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+	return rig.NewManager(b.townRoot, rigsConfig, git.NewGit(b.townRoot))
+}
+
+// Rigs implements APIBackend.
+func (b *TownBackend) Rigs() ([]*rig.Rig, error) {
+	rigs, err := b.rigManager().DiscoverRigs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+	return rigs, nil
+}
+
+// Rig implements APIBackend.
+func (b *TownBackend) Rig(name string) (*rig.Rig, error) {
+	return b.rigManager().GetRig(name)
+}
+
+// MergeQueue implements APIBackend.
+func (b *TownBackend) MergeQueue(rigName string) ([]refinery.QueueItem, error) {
+	r, err := b.Rig(rigName)
+	if err != nil {
+		return nil, err
+	}
+	return refinery.NewManager(r).Queue()
+}
+
+// mailbox returns the mailbox for address. All mail lives in town beads.
+func (b *TownBackend) mailbox(address string) (*mail.Mailbox, error) {
+	return mail.NewRouter(b.townRoot).GetMailbox(address)
+}
+
+// Inbox implements APIBackend.
+func (b *TownBackend) Inbox(address string) ([]*mail.Message, error) {
+	mailbox, err := b.mailbox(address)
+	if err != nil {
+		return nil, err
+	}
+	return mailbox.List()
+}
+
+// Message implements APIBackend.
+func (b *TownBackend) Message(address, id string) (*mail.Message, error) {
+	mailbox, err := b.mailbox(address)
+	if err != nil {
+		return nil, err
+	}
+	return mailbox.Get(id)
+}
+
+// SearchMail implements APIBackend.
+func (b *TownBackend) SearchMail(query string, opts mail.IndexSearchOptions) ([]mail.SearchResult, error) {
+	return mail.NewRouterWithTownRoot(b.townRoot, b.townRoot).SearchMail(query, opts, false)
+}
+
+// MarkRead implements APIBackend.
+func (b *TownBackend) MarkRead(address, id string) error {
+	mailbox, err := b.mailbox(address)
+	if err != nil {
+		return err
+	}
+	return mailbox.MarkReadOnly(id)
+}
+
+// SendMail implements APIBackend.
+func (b *TownBackend) SendMail(msg *mail.Message) error {
+	return mail.NewRouter(b.townRoot).Send(msg)
+}
+
+// Issue implements APIBackend.
+func (b *TownBackend) Issue(id string) (*beads.Issue, error) {
+	return beads.New(b.townRoot).Show(id)
+}
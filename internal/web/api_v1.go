@@ -0,0 +1,412 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// APIVersion is the current version of the dashboard JSON API,
+// served under /api/v1.
+const APIVersion = "v1"
+
+// defaultMailAddress is the mailbox the dashboard reads and sends as
+// when the request does not name one. The dashboard is the overseer's view.
+const defaultMailAddress = "overseer"
+
+// APIError is the body of every /api/v1 error response.
+type APIError struct {
+	Error string `json:"error"`
+}
+
+// APIRig is a rig in /api/v1 responses.
+type APIRig struct {
+	Name        string   `json:"name"`
+	Path        string   `json:"path"`
+	GitURL      string   `json:"git_url"`
+	Polecats    []string `json:"polecats"`
+	Crew        []string `json:"crew"`
+	HasWitness  bool     `json:"has_witness"`
+	HasRefinery bool     `json:"has_refinery"`
+	HasMayor    bool     `json:"has_mayor"`
+}
+
+// RigsResponse is the response for GET /api/v1/rigs.
+type RigsResponse struct {
+	Rigs []APIRig `json:"rigs"`
+}
+
+// MergeQueueItem is an open merge request in /api/v1 responses.
+type MergeQueueItem struct {
+	Position     int       `json:"position"`
+	ID           string    `json:"id"`
+	Branch       string    `json:"branch"`
+	Worker       string    `json:"worker,omitempty"`
+	IssueID      string    `json:"issue_id,omitempty"`
+	TargetBranch string    `json:"target_branch"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	Age          string    `json:"age"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// MergeQueueResponse is the response for GET /api/v1/rigs/{name}/merge-queue.
+type MergeQueueResponse struct {
+	Rig   string           `json:"rig"`
+	Items []MergeQueueItem `json:"items"`
+}
+
+// MailSendResponse is the response for POST /api/v1/mail/send.
+type MailSendResponse struct {
+	ID       string `json:"id"`
+	ThreadID string `json:"thread_id"`
+}
+
+// APIIssue is a bead in /api/v1 responses.
+type APIIssue struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Type        string   `json:"type"`
+	Status      string   `json:"status"`
+	Priority    int      `json:"priority"`
+	Description string   `json:"description"`
+	Assignee    string   `json:"assignee,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+	ClosedAt    string   `json:"closed_at,omitempty"`
+	DependsOn   []string `json:"depends_on"`
+	Blocks      []string `json:"blocks"`
+}
+
+// apiV1 serves the versioned JSON API from an APIBackend.
+type apiV1 struct {
+	backend APIBackend
+	mux     *http.ServeMux
+}
+
+func newAPIV1(backend APIBackend) *apiV1 {
+	v := &apiV1{backend: backend, mux: http.NewServeMux()}
+	v.mux.HandleFunc("GET /api/v1/rigs", v.handleRigs)
+	v.mux.HandleFunc("GET /api/v1/rigs/{name}", v.handleRig)
+	v.mux.HandleFunc("GET /api/v1/rigs/{name}/merge-queue", v.handleMergeQueue)
+	v.mux.HandleFunc("GET /api/v1/mail/inbox", v.handleInbox)
+	v.mux.HandleFunc("GET /api/v1/mail/search", v.handleMailSearch)
+	v.mux.HandleFunc("GET /api/v1/mail/messages/{id}", v.handleMessage)
+	v.mux.HandleFunc("POST /api/v1/mail/messages/{id}/read", v.handleMarkRead)
+	v.mux.HandleFunc("POST /api/v1/mail/send", v.handleSend)
+	v.mux.HandleFunc("GET /api/v1/issues/{id}", v.handleIssue)
+	return v
+}
+
+func (v *apiV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mux.ServeHTTP(w, r)
+}
+
+func (v *apiV1) handleRigs(w http.ResponseWriter, _ *http.Request) {
+	rigs, err := v.backend.Rigs()
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	resp := RigsResponse{Rigs: make([]APIRig, 0, len(rigs))}
+	for _, r := range rigs {
+		resp.Rigs = append(resp.Rigs, toAPIRig(r))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (v *apiV1) handleRig(w http.ResponseWriter, r *http.Request) {
+	rg, err := v.backend.Rig(r.PathValue("name"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIRig(rg))
+}
+
+func (v *apiV1) handleMergeQueue(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	queue, err := v.backend.MergeQueue(name)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	resp := MergeQueueResponse{Rig: name, Items: make([]MergeQueueItem, 0, len(queue))}
+	for _, item := range queue {
+		resp.Items = append(resp.Items, toMergeQueueItem(item))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (v *apiV1) handleInbox(w http.ResponseWriter, r *http.Request) {
+	resp, err := v.inbox(mailAddress(r))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// inbox lists a mailbox with its unread count.
+func (v *apiV1) inbox(address string) (*MailInboxResponse, error) {
+	messages, err := v.backend.Inbox(address)
+	if err != nil {
+		return nil, err
+	}
+	resp := &MailInboxResponse{Messages: make([]MailMessage, 0, len(messages))}
+	for _, m := range messages {
+		resp.Messages = append(resp.Messages, toMailMessage(m, false))
+		if !m.Read {
+			resp.UnreadCount++
+		}
+	}
+	resp.Total = len(resp.Messages)
+	return resp, nil
+}
+
+func (v *apiV1) handleMessage(w http.ResponseWriter, r *http.Request) {
+	msg, err := v.backend.Message(mailAddress(r), r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toMailMessage(msg, true))
+}
+
+func (v *apiV1) handleMarkRead(w http.ResponseWriter, r *http.Request) {
+	if err := v.backend.MarkRead(mailAddress(r), r.PathValue("id")); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (v *apiV1) handleSend(w http.ResponseWriter, r *http.Request) {
+	var req MailSendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, APIError{Error: "invalid request body"})
+		return
+	}
+	auditDetail(r, "to", req.To)
+	auditDetail(r, "subject", req.Subject)
+	msg, err := v.send(req)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, MailSendResponse{ID: msg.ID, ThreadID: msg.ThreadID})
+}
+
+// errBadRequest marks errors caused by the request rather than the town.
+var errBadRequest = errors.New("bad request")
+
+// send builds and routes a message. Replies join the original's thread.
+func (v *apiV1) send(req MailSendRequest) (*mail.Message, error) {
+	if req.To == "" || req.Subject == "" {
+		return nil, fmt.Errorf("%w: missing required fields (to, subject)", errBadRequest)
+	}
+	from := req.From
+	if from == "" {
+		from = defaultMailAddress
+	}
+
+	var msg *mail.Message
+	if req.ReplyTo != "" {
+		original, err := v.backend.Message(from, req.ReplyTo)
+		if err != nil {
+			return nil, err
+		}
+		msg = mail.NewReplyMessage(from, req.To, req.Subject, req.Body, original)
+	} else {
+		msg = mail.NewMessage(from, req.To, req.Subject, req.Body)
+	}
+	if err := v.backend.SendMail(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (v *apiV1) handleIssue(w http.ResponseWriter, r *http.Request) {
+	issue, err := v.backend.Issue(r.PathValue("id"))
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toAPIIssue(issue))
+}
+
+// defaultMailSearchLimit caps /api/v1/mail/search results unless the
+// request sets limit.
+const defaultMailSearchLimit = 50
+
+// handleMailSearch searches mail with the query language of gt mail search.
+// The search covers the address mailbox unless all=true is set.
+func (v *apiV1) handleMailSearch(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	opts := mail.IndexSearchOptions{
+		IncludeArchived: params.Get("archive") == "true",
+		Limit:           defaultMailSearchLimit,
+	}
+	if params.Get("all") != "true" {
+		opts.Identity = mailAddress(r)
+	}
+	if limit := params.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			writeAPIError(w, fmt.Errorf("%w: invalid limit %q", errBadRequest, limit))
+			return
+		}
+		opts.Limit = n
+	}
+
+	query := params.Get("q")
+	results, err := v.backend.SearchMail(query, opts)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	resp := MailSearchResponse{Query: query, Results: make([]MailSearchHit, 0, len(results))}
+	for _, res := range results {
+		resp.Results = append(resp.Results, MailSearchHit{
+			MailMessage: toMailMessage(res.Message, false),
+			Archived:    res.Archived,
+			Score:       res.Score,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// mailAddress returns the mailbox named by the address query parameter.
+func mailAddress(r *http.Request) string {
+	if address := r.URL.Query().Get("address"); address != "" {
+		return address
+	}
+	return defaultMailAddress
+}
+
+func toAPIRig(r *rig.Rig) APIRig {
+	return APIRig{
+		Name:        r.Name,
+		Path:        r.Path,
+		GitURL:      r.GitURL,
+		Polecats:    nonNil(r.Polecats),
+		Crew:        nonNil(r.Crew),
+		HasWitness:  r.HasWitness,
+		HasRefinery: r.HasRefinery,
+		HasMayor:    r.HasMayor,
+	}
+}
+
+func toMergeQueueItem(item refinery.QueueItem) MergeQueueItem {
+	mr := item.MR
+	return MergeQueueItem{
+		Position:     item.Position,
+		ID:           mr.ID,
+		Branch:       mr.Branch,
+		Worker:       mr.Worker,
+		IssueID:      mr.IssueID,
+		TargetBranch: mr.TargetBranch,
+		Status:       string(mr.Status),
+		CreatedAt:    mr.CreatedAt,
+		Age:          item.Age,
+		Error:        mr.Error,
+	}
+}
+
+// toMailMessage converts a message for the API. Listings omit bodies;
+// bodies have any protocol envelope stripped.
+func toMailMessage(m *mail.Message, withBody bool) MailMessage {
+	msg := MailMessage{
+		ID:        m.ID,
+		From:      m.From,
+		To:        m.To,
+		Subject:   m.Subject,
+		Timestamp: m.Timestamp.Format(time.RFC3339),
+		Read:      m.Read,
+		Priority:  string(m.Priority),
+		Type:      string(m.Type),
+		ThreadID:  m.ThreadID,
+		ReplyTo:   m.ReplyTo,
+	}
+	if withBody {
+		msg.Body = mail.StripEnvelope(m.Body)
+	}
+	return msg
+}
+
+func toAPIIssue(issue *beads.Issue) APIIssue {
+	return APIIssue{
+		ID:          issue.ID,
+		Title:       issue.Title,
+		Type:        issue.Type,
+		Status:      issue.Status,
+		Priority:    issue.Priority,
+		Description: issue.Description,
+		Assignee:    issue.Assignee,
+		Labels:      issue.Labels,
+		CreatedAt:   issue.CreatedAt,
+		UpdatedAt:   issue.UpdatedAt,
+		ClosedAt:    issue.ClosedAt,
+		DependsOn:   nonNil(issueDeps(issue.DependsOn, issue.Dependencies)),
+		Blocks:      nonNil(issueDeps(issue.Blocks, issue.Dependents)),
+	}
+}
+
+// issueDeps returns dependency IDs, preferring the plain ID list and
+// falling back to the detailed list bd show returns.
+func issueDeps(ids []string, detailed []beads.IssueDep) []string {
+	if len(ids) > 0 {
+		return ids
+	}
+	var out []string
+	for _, d := range detailed {
+		out = append(out, d.ID)
+	}
+	return out
+}
+
+// nonNil returns s, or an empty slice if s is nil, so it encodes as [].
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// apiErrorStatus maps backend errors to HTTP status codes.
+func apiErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, errBadRequest),
+		errors.Is(err, mail.ErrInvalidQuery),
+		errors.Is(err, mail.ErrUnknownList),
+		errors.Is(err, mail.ErrUnknownQueue),
+		errors.Is(err, mail.ErrUnknownAnnounce):
+		return http.StatusBadRequest
+	case errors.Is(err, rig.ErrRigNotFound),
+		errors.Is(err, mail.ErrMessageNotFound),
+		errors.Is(err, beads.ErrNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// writeAPIError writes err as an APIError with the matching status code.
+func writeAPIError(w http.ResponseWriter, err error) {
+	writeJSON(w, apiErrorStatus(err), APIError{Error: err.Error()})
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
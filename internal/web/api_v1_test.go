@@ -0,0 +1,266 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// fakeBackend is an in-memory APIBackend.
+type fakeBackend struct {
+	rigs   []*rig.Rig
+	queues map[string][]refinery.QueueItem
+	mail   map[string][]*mail.Message // by address
+	issues map[string]*beads.Issue
+	sent   []*mail.Message
+
+	searches []mail.IndexSearchOptions
+}
+
+func (f *fakeBackend) Rigs() ([]*rig.Rig, error) { return f.rigs, nil }
+
+func (f *fakeBackend) Rig(name string) (*rig.Rig, error) {
+	for _, r := range f.rigs {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return nil, rig.ErrRigNotFound
+}
+
+func (f *fakeBackend) MergeQueue(rigName string) ([]refinery.QueueItem, error) {
+	if _, err := f.Rig(rigName); err != nil {
+		return nil, err
+	}
+	return f.queues[rigName], nil
+}
+
+func (f *fakeBackend) Inbox(address string) ([]*mail.Message, error) {
+	return f.mail[address], nil
+}
+
+func (f *fakeBackend) Message(address, id string) (*mail.Message, error) {
+	for _, m := range f.mail[address] {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, mail.ErrMessageNotFound
+}
+
+// SearchMail validates the query and returns the mailbox in order; ranking
+// is covered by the mail package's tests.
+func (f *fakeBackend) SearchMail(query string, opts mail.IndexSearchOptions) ([]mail.SearchResult, error) {
+	if _, err := mail.ParseQuery(query, time.Now()); err != nil {
+		return nil, err
+	}
+	f.searches = append(f.searches, opts)
+	var results []mail.SearchResult
+	for _, m := range f.mail[opts.Identity] {
+		results = append(results, mail.SearchResult{Message: m, Score: 1})
+	}
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, nil
+}
+
+func (f *fakeBackend) MarkRead(address, id string) error {
+	m, err := f.Message(address, id)
+	if err != nil {
+		return err
+	}
+	m.Read = true
+	return nil
+}
+
+func (f *fakeBackend) SendMail(msg *mail.Message) error {
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func (f *fakeBackend) Issue(id string) (*beads.Issue, error) {
+	if issue, ok := f.issues[id]; ok {
+		return issue, nil
+	}
+	return nil, beads.ErrNotFound
+}
+
+func newTestAPIHandler() (*APIHandler, *fakeBackend) {
+	created := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	backend := &fakeBackend{
+		rigs: []*rig.Rig{
+			{Name: "gastown", Path: "/town/gastown", Polecats: []string{"Toast"}, Crew: []string{"joe"}, HasWitness: true, HasRefinery: true},
+		},
+		queues: map[string][]refinery.QueueItem{
+			"gastown": {{
+				Position: 1,
+				Age:      "5m",
+				MR: &refinery.MergeRequest{
+					ID: "gt-mr1", Branch: "polecat/Toast/gt-abc", Worker: "Toast", IssueID: "gt-abc",
+					TargetBranch: "main", Status: refinery.MROpen, CreatedAt: created,
+				},
+			}},
+		},
+		mail: map[string][]*mail.Message{
+			"overseer": {
+				{ID: "msg-1", From: "mayor/", To: "overseer", Subject: "Status", Body: "All quiet", Timestamp: created, Priority: mail.PriorityHigh, ThreadID: "thread-1"},
+				{ID: "msg-2", From: "gastown/witness", To: "overseer", Subject: "Done", Timestamp: created, Read: true},
+			},
+		},
+		issues: map[string]*beads.Issue{
+			"gt-abc": {ID: "gt-abc", Title: "Fix it", Type: "bug", Status: "open", Priority: 1, CreatedAt: "2025-03-14", DependsOn: []string{"gt-dep"}},
+		},
+	}
+	h := NewAPIHandler()
+	h.backend = backend
+	return h, backend
+}
+
+func serveAPI(t *testing.T, h http.Handler, method, path, body string, want int) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != want {
+		t.Fatalf("%s %s status = %d, want %d: %s", method, path, w.Code, want, w.Body.String())
+	}
+	return w
+}
+
+func TestAPIv1_Rigs(t *testing.T) {
+	h, _ := newTestAPIHandler()
+
+	var rigs RigsResponse
+	w := serveAPI(t, h, http.MethodGet, "/api/v1/rigs", "", http.StatusOK)
+	if err := json.NewDecoder(w.Body).Decode(&rigs); err != nil {
+		t.Fatal(err)
+	}
+	if len(rigs.Rigs) != 1 || rigs.Rigs[0].Name != "gastown" || rigs.Rigs[0].Crew[0] != "joe" {
+		t.Errorf("rigs = %+v", rigs)
+	}
+
+	var queue MergeQueueResponse
+	w = serveAPI(t, h, http.MethodGet, "/api/v1/rigs/gastown/merge-queue", "", http.StatusOK)
+	if err := json.NewDecoder(w.Body).Decode(&queue); err != nil {
+		t.Fatal(err)
+	}
+	if len(queue.Items) != 1 || queue.Items[0].Branch != "polecat/Toast/gt-abc" || queue.Items[0].Status != "open" {
+		t.Errorf("merge queue = %+v", queue)
+	}
+
+	w = serveAPI(t, h, http.MethodGet, "/api/v1/rigs/nope", "", http.StatusNotFound)
+	var apiErr APIError
+	if err := json.NewDecoder(w.Body).Decode(&apiErr); err != nil || apiErr.Error == "" {
+		t.Errorf("error body = %q, %v", w.Body.String(), err)
+	}
+	serveAPI(t, h, http.MethodGet, "/api/v1/rigs/nope/merge-queue", "", http.StatusNotFound)
+}
+
+func TestAPIv1_Mail(t *testing.T) {
+	h, backend := newTestAPIHandler()
+
+	var inbox MailInboxResponse
+	w := serveAPI(t, h, http.MethodGet, "/api/v1/mail/inbox", "", http.StatusOK)
+	if err := json.NewDecoder(w.Body).Decode(&inbox); err != nil {
+		t.Fatal(err)
+	}
+	if inbox.Total != 2 || inbox.UnreadCount != 1 {
+		t.Errorf("inbox total=%d unread=%d, want 2/1", inbox.Total, inbox.UnreadCount)
+	}
+	if inbox.Messages[0].Body != "" {
+		t.Error("inbox listing should omit bodies")
+	}
+
+	var msg MailMessage
+	w = serveAPI(t, h, http.MethodGet, "/api/v1/mail/messages/msg-1", "", http.StatusOK)
+	if err := json.NewDecoder(w.Body).Decode(&msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Body != "All quiet" || msg.Priority != "high" || msg.Timestamp != "2025-03-14T10:00:00Z" {
+		t.Errorf("message = %+v", msg)
+	}
+	serveAPI(t, h, http.MethodGet, "/api/v1/mail/messages/missing", "", http.StatusNotFound)
+
+	serveAPI(t, h, http.MethodPost, "/api/v1/mail/messages/msg-1/read", "", http.StatusNoContent)
+	if !backend.mail["overseer"][0].Read {
+		t.Error("msg-1 not marked read")
+	}
+
+	serveAPI(t, h, http.MethodPost, "/api/v1/mail/send", `{"to":"mayor/","subject":"Re: Status","body":"ok","reply_to":"msg-1"}`, http.StatusCreated)
+	if len(backend.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(backend.sent))
+	}
+	sent := backend.sent[0]
+	if sent.From != "overseer" || sent.ThreadID != "thread-1" || sent.ReplyTo != "msg-1" || sent.Type != mail.TypeReply {
+		t.Errorf("reply = %+v", sent)
+	}
+
+	serveAPI(t, h, http.MethodPost, "/api/v1/mail/send", `{"to":"mayor/"}`, http.StatusBadRequest)
+	serveAPI(t, h, http.MethodPost, "/api/v1/mail/send", `{"to":"mayor/","subject":"x","reply_to":"missing"}`, http.StatusNotFound)
+}
+
+func TestAPIv1_MailSearch(t *testing.T) {
+	h, backend := newTestAPIHandler()
+
+	var resp MailSearchResponse
+	w := serveAPI(t, h, http.MethodGet, "/api/v1/mail/search?q=from:mayor+is:unread&limit=1", "", http.StatusOK)
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Query != "from:mayor is:unread" || len(resp.Results) != 1 || resp.Results[0].ID != "msg-1" || resp.Results[0].Score != 1 {
+		t.Errorf("search = %+v", resp)
+	}
+	if resp.Results[0].Body != "" {
+		t.Error("search results should omit bodies")
+	}
+	if opts := backend.searches[0]; opts.Identity != "overseer" || opts.Limit != 1 || opts.IncludeArchived {
+		t.Errorf("search options = %+v", opts)
+	}
+
+	serveAPI(t, h, http.MethodGet, "/api/v1/mail/search?all=true&archive=true", "", http.StatusOK)
+	if opts := backend.searches[1]; opts.Identity != "" || opts.Limit != defaultMailSearchLimit || !opts.IncludeArchived {
+		t.Errorf("town-wide search options = %+v", opts)
+	}
+
+	serveAPI(t, h, http.MethodGet, "/api/v1/mail/search?q=is:starred", "", http.StatusBadRequest)
+	serveAPI(t, h, http.MethodGet, "/api/v1/mail/search?limit=-1", "", http.StatusBadRequest)
+}
+
+func TestAPIv1_Issue(t *testing.T) {
+	h, _ := newTestAPIHandler()
+
+	var issue APIIssue
+	w := serveAPI(t, h, http.MethodGet, "/api/v1/issues/gt-abc", "", http.StatusOK)
+	if err := json.NewDecoder(w.Body).Decode(&issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.Priority != 1 || issue.Type != "bug" || len(issue.DependsOn) != 1 || issue.Blocks == nil {
+		t.Errorf("issue = %+v", issue)
+	}
+	serveAPI(t, h, http.MethodGet, "/api/v1/issues/gt-missing", "", http.StatusNotFound)
+
+	// The legacy endpoint keeps its string priority.
+	var legacy IssueShowResponse
+	w = serveAPI(t, h, http.MethodGet, "/api/issues/show?id=gt-abc", "", http.StatusOK)
+	if err := json.NewDecoder(w.Body).Decode(&legacy); err != nil {
+		t.Fatal(err)
+	}
+	if legacy.Priority != "P1" || legacy.Title != "Fix it" {
+		t.Errorf("legacy issue = %+v", legacy)
+	}
+}
+
+func TestAPIv1_NoWorkspace(t *testing.T) {
+	h := NewAPIHandler()
+	h.backend = nil
+	serveAPI(t, h, http.MethodGet, "/api/v1/rigs", "", http.StatusServiceUnavailable)
+}
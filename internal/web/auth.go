@@ -0,0 +1,349 @@
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Cookie and header names used by dashboard authentication.
+const (
+	// sessionCookie holds a dashboard token after a browser opens the
+	// dashboard with ?token=.
+	sessionCookie = "gt_dashboard_token"
+
+	// csrfCookie holds the CSRF token. The dashboard's scripts echo it in
+	// csrfHeader on every unsafe request (double-submit).
+	csrfCookie = "gt_csrf"
+	csrfHeader = "X-CSRF-Token"
+)
+
+// Principal is the authenticated user of a dashboard request.
+type Principal struct {
+	// Name identifies the credential ("local" when auth is disabled).
+	Name string
+
+	// Scope is config.DashboardScopeReadOnly or config.DashboardScopeOperator.
+	Scope string
+}
+
+// CanOperate reports whether the principal may make changes.
+func (p *Principal) CanOperate() bool {
+	return p != nil && p.Scope == config.DashboardScopeOperator
+}
+
+// localPrincipal is used for every request when authentication is disabled.
+var localPrincipal = &Principal{Name: "local", Scope: config.DashboardScopeOperator}
+
+type principalKey struct{}
+
+func withPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal attached to a request by Auth.
+// Requests that did not pass through Auth are treated as local.
+func PrincipalFrom(ctx context.Context) *Principal {
+	if p, ok := ctx.Value(principalKey{}).(*Principal); ok {
+		return p
+	}
+	return localPrincipal
+}
+
+// credential is a resolved DashboardCredential.
+type credential struct {
+	name   string
+	secret string
+	scope  string
+}
+
+// Auth is the dashboard's authentication, authorization, CSRF, and audit
+// middleware.
+//
+//   - Requests authenticate with a bearer token, HTTP basic auth, or the
+//     session cookie set by opening the dashboard with ?token=.
+//   - Read-only principals may not use unsafe methods. /api/run is left to
+//     the handler, which lets them run safe commands.
+//   - Unsafe requests must echo the CSRF cookie in the X-CSRF-Token header
+//     unless they carry a bearer token, which browsers never send on
+//     their own.
+//   - Every unsafe request is recorded in the town's events log as a
+//     dashboard audit event, including denied ones.
+type Auth struct {
+	tokens []credential
+	users  []credential
+
+	// Audit records an audit event; replaced in tests.
+	Audit func(actor string, payload map[string]interface{})
+}
+
+// NewAuth creates middleware from a dashboard config, resolving secrets
+// from the environment. A config without tokens or users disables
+// authentication; CSRF checks and auditing still apply.
+func NewAuth(cfg *config.DashboardConfig) (*Auth, error) {
+	a := &Auth{Audit: logAuditEvent}
+	if cfg == nil {
+		return a, nil
+	}
+	var err error
+	if a.tokens, err = resolveCredentials("token", cfg.Tokens); err != nil {
+		return nil, err
+	}
+	if a.users, err = resolveCredentials("user", cfg.Users); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func resolveCredentials(kind string, creds []config.DashboardCredential) ([]credential, error) {
+	var out []credential
+	for _, c := range creds {
+		secret := c.Secret
+		if c.SecretEnv != "" {
+			secret = os.Getenv(c.SecretEnv)
+			if secret == "" {
+				return nil, fmt.Errorf("dashboard %s %q: $%s is not set", kind, c.Name, c.SecretEnv)
+			}
+		}
+		scope := c.Scope
+		if scope == "" {
+			scope = config.DashboardScopeReadOnly
+		}
+		out = append(out, credential{name: c.Name, secret: secret, scope: scope})
+	}
+	return out, nil
+}
+
+// Enabled reports whether requests must authenticate.
+func (a *Auth) Enabled() bool {
+	return len(a.tokens) > 0 || len(a.users) > 0
+}
+
+// Describe summarizes the configured authentication for startup output.
+func (a *Auth) Describe() string {
+	if !a.Enabled() {
+		return "disabled"
+	}
+	var parts []string
+	if n := len(a.tokens); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d token(s)", n))
+	}
+	if n := len(a.users); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d user(s)", n))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Wrap returns next guarded by the middleware.
+func (a *Auth) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Static assets and CORS preflights carry nothing sensitive.
+		if strings.HasPrefix(r.URL.Path, "/static/") || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if a.Enabled() && r.Method == http.MethodGet && r.URL.Query().Get("token") != "" {
+			a.exchangeToken(w, r)
+			return
+		}
+
+		ensureCSRFCookie(w, r)
+
+		if isSafeMethod(r.Method) {
+			p, _ := a.authenticate(r)
+			if p == nil {
+				a.challenge(w)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), p)))
+			return
+		}
+
+		rec := &auditRecord{payload: map[string]interface{}{
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"remote": r.RemoteAddr,
+		}}
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			rec.payload["status"] = sw.status
+			a.Audit(rec.actor, rec.payload)
+		}()
+
+		p, bearer := a.authenticate(r)
+		if p == nil {
+			rec.actor = "dashboard:anonymous"
+			a.challenge(sw)
+			return
+		}
+		rec.actor = "dashboard:" + p.Name
+		rec.payload["scope"] = p.Scope
+
+		if !bearer {
+			if err := checkCSRF(r); err != nil {
+				rec.payload["denied"] = err.Error()
+				http.Error(sw, "Forbidden: "+err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+		if !p.CanOperate() && r.URL.Path != "/api/run" {
+			rec.payload["denied"] = "read-only scope"
+			http.Error(sw, "Forbidden: read-only access", http.StatusForbidden)
+			return
+		}
+
+		ctx := withPrincipal(r.Context(), p)
+		ctx = context.WithValue(ctx, auditKey{}, rec)
+		next.ServeHTTP(sw, r.WithContext(ctx))
+	})
+}
+
+// authenticate identifies the request's principal. It reports whether the
+// credential came from an Authorization: Bearer header.
+func (a *Auth) authenticate(r *http.Request) (p *Principal, bearer bool) {
+	if !a.Enabled() {
+		return localPrincipal, false
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return a.matchToken(strings.TrimPrefix(auth, "Bearer ")), true
+	}
+	if name, password, ok := r.BasicAuth(); ok {
+		for _, u := range a.users {
+			if u.name == name && secretEqual(u.secret, password) {
+				return &Principal{Name: u.name, Scope: u.scope}, false
+			}
+		}
+		return nil, false
+	}
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		return a.matchToken(c.Value), false
+	}
+	return nil, false
+}
+
+func (a *Auth) matchToken(token string) *Principal {
+	for _, t := range a.tokens {
+		if secretEqual(t.secret, token) {
+			return &Principal{Name: t.name, Scope: t.scope}
+		}
+	}
+	return nil
+}
+
+// exchangeToken turns ?token= into a session cookie and redirects to the
+// same URL without the token, so it does not linger in history or logs.
+func (a *Auth) exchangeToken(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if a.matchToken(token) == nil {
+		a.challenge(w)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	u := *r.URL
+	q := u.Query()
+	q.Del("token")
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.RequestURI(), http.StatusSeeOther)
+}
+
+// challenge responds 401, prompting browsers for basic auth when users
+// are configured.
+func (a *Auth) challenge(w http.ResponseWriter) {
+	if len(a.users) > 0 {
+		w.Header().Set("WWW-Authenticate", `Basic realm="Gas Town", charset="UTF-8"`)
+	} else {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="Gas Town"`)
+	}
+	http.Error(w, "Unauthorized: open the dashboard with ?token=<token> or send Authorization: Bearer <token>", http.StatusUnauthorized)
+}
+
+// ensureCSRFCookie issues a CSRF token to browsers that lack one.
+func ensureCSRFCookie(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(csrfCookie); err == nil && c.Value != "" {
+		return
+	}
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read only fails on broken system
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookie,
+		Value:    hex.EncodeToString(b),
+		Path:     "/",
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// checkCSRF verifies that an unsafe request came from the dashboard itself.
+func checkCSRF(r *http.Request) error {
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return fmt.Errorf("cross-origin request from %s", origin)
+		}
+	}
+	c, err := r.Cookie(csrfCookie)
+	if err != nil || c.Value == "" || !secretEqual(c.Value, r.Header.Get(csrfHeader)) {
+		return fmt.Errorf("missing or invalid CSRF token")
+	}
+	return nil
+}
+
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func secretEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// auditRecord accumulates the audit event for one request.
+type auditRecord struct {
+	actor   string
+	payload map[string]interface{}
+}
+
+type auditKey struct{}
+
+// auditDetail adds a field to the request's audit event, if it has one.
+func auditDetail(r *http.Request, key string, value interface{}) {
+	if rec, ok := r.Context().Value(auditKey{}).(*auditRecord); ok {
+		rec.payload[key] = value
+	}
+}
+
+// logAuditEvent writes a dashboard audit event to the town's events log.
+func logAuditEvent(actor string, payload map[string]interface{}) {
+	_ = events.LogAudit(events.TypeDashboardRequest, actor, payload)
+}
+
+// statusWriter records the status code written by a handler.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,252 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+type auditEntry struct {
+	actor   string
+	payload map[string]interface{}
+}
+
+// newTestAuth wraps a handler that records the principal it saw.
+func newTestAuth(t *testing.T, cfg *config.DashboardConfig) (http.Handler, *[]auditEntry, **Principal) {
+	t.Helper()
+	auth, err := NewAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var audits []auditEntry
+	auth.Audit = func(actor string, payload map[string]interface{}) {
+		audits = append(audits, auditEntry{actor, payload})
+	}
+	var seen *Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = PrincipalFrom(r.Context())
+		auditDetail(r, "command", "status")
+		w.WriteHeader(http.StatusOK)
+	})
+	return auth.Wrap(next), &audits, &seen
+}
+
+func testDashboardConfig() *config.DashboardConfig {
+	return &config.DashboardConfig{
+		Tokens: []config.DashboardCredential{
+			{Name: "viewer", Secret: "view-token"},
+			{Name: "ops", Secret: "ops-token", Scope: config.DashboardScopeOperator},
+		},
+		Users: []config.DashboardCredential{
+			{Name: "alice", Secret: "hunter2", Scope: config.DashboardScopeOperator},
+		},
+	}
+}
+
+// csrfPost builds a POST carrying a matching CSRF cookie and header.
+func csrfPost(path string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString("{}"))
+	req.AddCookie(&http.Cookie{Name: csrfCookie, Value: "csrf-value"})
+	req.Header.Set(csrfHeader, "csrf-value")
+	return req
+}
+
+func serve(h http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuth_Authenticate(t *testing.T) {
+	h, _, seen := newTestAuth(t, testDashboardConfig())
+
+	if w := serve(h, httptest.NewRequest(http.MethodGet, "/", nil)); w.Code != http.StatusUnauthorized {
+		t.Errorf("no credentials: status %d, want 401", w.Code)
+	} else if !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), "Basic") {
+		t.Errorf("WWW-Authenticate = %q, want Basic challenge", w.Header().Get("WWW-Authenticate"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer view-token")
+	if w := serve(h, req); w.Code != http.StatusOK || (*seen).Name != "viewer" || (*seen).CanOperate() {
+		t.Errorf("bearer: status %d, principal %+v", w.Code, *seen)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	if w := serve(h, req); w.Code != http.StatusUnauthorized {
+		t.Errorf("bad bearer: status %d, want 401", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("alice", "hunter2")
+	if w := serve(h, req); w.Code != http.StatusOK || (*seen).Name != "alice" || !(*seen).CanOperate() {
+		t.Errorf("basic: status %d, principal %+v", w.Code, *seen)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth("alice", "wrong")
+	if w := serve(h, req); w.Code != http.StatusUnauthorized {
+		t.Errorf("bad basic: status %d, want 401", w.Code)
+	}
+
+	// Static assets need no credentials.
+	if w := serve(h, httptest.NewRequest(http.MethodGet, "/static/dashboard.js", nil)); w.Code != http.StatusOK {
+		t.Errorf("static: status %d, want 200", w.Code)
+	}
+}
+
+func TestAuth_TokenExchange(t *testing.T) {
+	h, _, seen := newTestAuth(t, testDashboardConfig())
+
+	w := serve(h, httptest.NewRequest(http.MethodGet, "/?token=ops-token&x=1", nil))
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("status %d, want 303", w.Code)
+	}
+	if loc := w.Header().Get("Location"); loc != "/?x=1" {
+		t.Errorf("Location = %q, want token stripped", loc)
+	}
+	var session *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == sessionCookie {
+			session = c
+		}
+	}
+	if session == nil || !session.HttpOnly {
+		t.Fatalf("session cookie = %+v", session)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(session)
+	if w := serve(h, req); w.Code != http.StatusOK || (*seen).Name != "ops" {
+		t.Errorf("cookie: status %d, principal %+v", w.Code, *seen)
+	}
+
+	if w := serve(h, httptest.NewRequest(http.MethodGet, "/?token=nope", nil)); w.Code != http.StatusUnauthorized {
+		t.Errorf("bad token: status %d, want 401", w.Code)
+	}
+}
+
+func TestAuth_ScopesAndCSRF(t *testing.T) {
+	h, audits, _ := newTestAuth(t, testDashboardConfig())
+
+	// Operator with CSRF token may post.
+	req := csrfPost("/api/v1/mail/send")
+	req.SetBasicAuth("alice", "hunter2")
+	if w := serve(h, req); w.Code != http.StatusOK {
+		t.Errorf("operator post: status %d, want 200", w.Code)
+	}
+
+	// Without the CSRF header, browser credentials are not enough.
+	req = csrfPost("/api/v1/mail/send")
+	req.Header.Del(csrfHeader)
+	req.SetBasicAuth("alice", "hunter2")
+	if w := serve(h, req); w.Code != http.StatusForbidden {
+		t.Errorf("missing CSRF: status %d, want 403", w.Code)
+	}
+
+	// Cross-origin posts are rejected even with a token.
+	req = csrfPost("/api/v1/mail/send")
+	req.Header.Set("Origin", "http://evil.example")
+	req.SetBasicAuth("alice", "hunter2")
+	if w := serve(h, req); w.Code != http.StatusForbidden {
+		t.Errorf("cross-origin: status %d, want 403", w.Code)
+	}
+
+	// Bearer tokens are not ambient, so they skip the CSRF check.
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/mail/send", nil)
+	req.Header.Set("Authorization", "Bearer ops-token")
+	if w := serve(h, req); w.Code != http.StatusOK {
+		t.Errorf("bearer post: status %d, want 200", w.Code)
+	}
+
+	// Read-only principals may not post, except to /api/run.
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/mail/send", nil)
+	req.Header.Set("Authorization", "Bearer view-token")
+	if w := serve(h, req); w.Code != http.StatusForbidden {
+		t.Errorf("read-only post: status %d, want 403", w.Code)
+	}
+	req = httptest.NewRequest(http.MethodPost, "/api/run", nil)
+	req.Header.Set("Authorization", "Bearer view-token")
+	if w := serve(h, req); w.Code != http.StatusOK {
+		t.Errorf("read-only run: status %d, want 200", w.Code)
+	}
+
+	if len(*audits) != 6 {
+		t.Fatalf("recorded %d audit events, want 6", len(*audits))
+	}
+	first := (*audits)[0]
+	if first.actor != "dashboard:alice" || first.payload["status"] != http.StatusOK || first.payload["command"] != "status" {
+		t.Errorf("audit = %+v", first)
+	}
+	denied := (*audits)[4]
+	if denied.actor != "dashboard:viewer" || denied.payload["status"] != http.StatusForbidden || denied.payload["denied"] == nil {
+		t.Errorf("denied audit = %+v", denied)
+	}
+}
+
+func TestAuth_Disabled(t *testing.T) {
+	h, audits, seen := newTestAuth(t, config.NewDashboardConfig())
+
+	w := serve(h, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK || (*seen).Name != "local" {
+		t.Errorf("open GET: status %d, principal %+v", w.Code, *seen)
+	}
+	var csrf *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == csrfCookie {
+			csrf = c
+		}
+	}
+	if csrf == nil || csrf.Value == "" || csrf.HttpOnly {
+		t.Errorf("csrf cookie = %+v, want script-readable token", csrf)
+	}
+
+	// CSRF still protects an open dashboard from drive-by posts.
+	if w := serve(h, httptest.NewRequest(http.MethodPost, "/api/run", nil)); w.Code != http.StatusForbidden {
+		t.Errorf("open POST without CSRF: status %d, want 403", w.Code)
+	}
+	if w := serve(h, csrfPost("/api/run")); w.Code != http.StatusOK {
+		t.Errorf("open POST with CSRF: status %d, want 200", w.Code)
+	}
+	if len(*audits) != 2 || (*audits)[1].actor != "dashboard:local" {
+		t.Errorf("audits = %+v", *audits)
+	}
+}
+
+func TestAPIHandler_Run_ReadOnlyBlocksActions(t *testing.T) {
+	handler := NewAPIHandler()
+
+	body := `{"command": "mail send mayor/ -s hi"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/run", bytes.NewBufferString(body))
+	req = req.WithContext(withPrincipal(req.Context(), &Principal{Name: "viewer", Scope: config.DashboardScopeReadOnly}))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("read-only action command status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestNewAuth_SecretEnv(t *testing.T) {
+	cfg := &config.DashboardConfig{Tokens: []config.DashboardCredential{{Name: "ci", SecretEnv: "GT_TEST_DASHBOARD_TOKEN"}}}
+
+	t.Setenv("GT_TEST_DASHBOARD_TOKEN", "")
+	if _, err := NewAuth(cfg); err == nil {
+		t.Error("expected error for unset secret_env")
+	}
+
+	t.Setenv("GT_TEST_DASHBOARD_TOKEN", "from-env")
+	auth, err := NewAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := auth.matchToken("from-env"); p == nil || p.Scope != config.DashboardScopeReadOnly {
+		t.Errorf("matchToken = %+v, want read-only ci", p)
+	}
+}
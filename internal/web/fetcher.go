@@ -14,6 +14,8 @@ import (
 
 	"github.com/steveyegge/gastown/internal/activity"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -87,6 +89,11 @@ func NewLiveConvoyFetcher() (*LiveConvoyFetcher, error) {
 	}, nil
 }
 
+// TownRoot returns the root of the town being displayed.
+func (f *LiveConvoyFetcher) TownRoot() string {
+	return f.townRoot
+}
+
 // FetchConvoys fetches all open convoys with their activity data.
 func (f *LiveConvoyFetcher) FetchConvoys() ([]ConvoyRow, error) {
 	// List all open convoy-type issues
@@ -915,89 +922,47 @@ func parseActivityTimestamp(s string) (int64, bool) {
 	return unix, true
 }
 
-// FetchMail fetches recent mail messages from the beads database.
+// FetchMail fetches recent mail messages from the town mail index.
 func (f *LiveConvoyFetcher) FetchMail() ([]MailRow, error) {
-	// List all message-type issues (mail)
-	stdout, err := runBdCmd(f.townBeads, "list", "--type=message", "--json", "--limit=50")
+	// The 50 most recent messages in the town, from the mail index
+	results, err := mail.NewRouterWithTownRoot(f.townRoot, f.townRoot).
+		SearchMail("-is:scheduled", mail.IndexSearchOptions{Limit: 50}, false)
 	if err != nil {
 		return nil, fmt.Errorf("listing mail: %w", err)
 	}
+	return mailRows(results), nil
+}
 
-	var messages []struct {
-		ID        string   `json:"id"`
-		Title     string   `json:"title"`
-		Status    string   `json:"status"`
-		CreatedAt string   `json:"created_at"`
-		Priority  int      `json:"priority"`
-		Assignee  string   `json:"assignee"`   // "to" address stored here
-		CreatedBy string   `json:"created_by"` // "from" address
-		Labels    []string `json:"labels"`
-	}
-	if err := json.Unmarshal(stdout.Bytes(), &messages); err != nil {
-		return nil, fmt.Errorf("parsing mail list: %w", err)
-	}
-
-	rows := make([]MailRow, 0, len(messages))
-	for _, m := range messages {
-		// Parse timestamp
-		var timestamp time.Time
+// mailRows converts mail search results into dashboard rows, keeping the
+// order of the results.
+func mailRows(results []mail.SearchResult) []MailRow {
+	rows := make([]MailRow, 0, len(results))
+	for _, m := range results {
 		var age string
-		var sortKey int64
-		if m.CreatedAt != "" {
-			if t, err := time.Parse(time.RFC3339, m.CreatedAt); err == nil {
-				timestamp = t
-				age = formatMailAge(time.Since(t))
-				sortKey = t.Unix()
-			}
+		if !m.Timestamp.IsZero() {
+			age = formatMailAge(time.Since(m.Timestamp))
 		}
 
-		// Determine priority string
-		priorityStr := "normal"
-		switch m.Priority {
-		case 0:
-			priorityStr = "urgent"
-		case 1:
-			priorityStr = "high"
-		case 2:
-			priorityStr = "normal"
-		case 3, 4:
-			priorityStr = "low"
-		}
-
-		// Determine message type from labels
-		msgType := "notification"
-		for _, label := range m.Labels {
-			if label == "task" || label == "reply" || label == "scavenge" {
-				msgType = label
-				break
-			}
+		msgType := string(m.Type)
+		if msgType == "" {
+			msgType = string(mail.TypeNotification)
 		}
 
-		// Format from/to addresses for display
-		from := formatAgentAddress(m.CreatedBy)
-		to := formatAgentAddress(m.Assignee)
-
 		rows = append(rows, MailRow{
 			ID:        m.ID,
-			From:      from,
-			FromRaw:   m.CreatedBy,
-			To:        to,
-			Subject:   m.Title,
-			Timestamp: timestamp.Format("15:04"),
+			From:      formatAgentAddress(m.From),
+			FromRaw:   m.From,
+			To:        formatAgentAddress(m.To),
+			Subject:   m.Subject,
+			Timestamp: m.Timestamp.Format("15:04"),
 			Age:       age,
-			Priority:  priorityStr,
+			Priority:  string(m.Priority),
 			Type:      msgType,
-			Read:      m.Status == "closed",
-			SortKey:   sortKey,
+			Read:      m.Read,
+			SortKey:   m.Timestamp.Unix(),
 		})
 	}
-
-	// Sort by timestamp, newest first
-	sort.Slice(rows, func(i, j int) bool {
-		return rows[i].SortKey > rows[j].SortKey
-	})
-
-	return rows, nil
+	return rows
 }
 
 // formatMailAge returns a human-readable age string.
@@ -1570,42 +1535,16 @@ func (f *LiveConvoyFetcher) FetchIssues() ([]IssueRow, error) {
 
 // FetchActivity returns recent activity from the event log.
 func (f *LiveConvoyFetcher) FetchActivity() ([]ActivityRow, error) {
-	eventsPath := filepath.Join(f.townRoot, ".events.jsonl")
-
-	// Read events file
-	data, err := os.ReadFile(eventsPath)
-	if err != nil {
-		return nil, nil // No events file
-	}
-
-	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
-	if len(lines) == 0 {
-		return nil, nil
-	}
-
-	// Take last 20 events (most recent)
-	start := 0
-	if len(lines) > 20 {
-		start = len(lines) - 20
+	// Take last 20 events (most recent), reaching into rotated segments
+	// if the active file has fewer
+	recent, err := events.Tail(f.townRoot, 20)
+	if err != nil || len(recent) == 0 {
+		return nil, nil // No events yet
 	}
 
 	var rows []ActivityRow
-	for i := len(lines) - 1; i >= start; i-- {
-		line := lines[i]
-		if line == "" {
-			continue
-		}
-
-		var event struct {
-			Timestamp  string                 `json:"ts"`
-			Type       string                 `json:"type"`
-			Actor      string                 `json:"actor"`
-			Payload    map[string]interface{} `json:"payload"`
-			Visibility string                 `json:"visibility"`
-		}
-		if err := json.Unmarshal([]byte(line), &event); err != nil {
-			continue
-		}
+	for i := len(recent) - 1; i >= 0; i-- {
+		event := recent[i]
 
 		// Skip audit-only events
 		if event.Visibility == "audit" {
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 //go:embed static
@@ -298,7 +300,15 @@ func computeSummary(workers []WorkerRow, hooks []HookRow, issues []IssueRow,
 	return summary
 }
 
+// townRooted is implemented by fetchers backed by a town on disk.
+// The dashboard serves live updates (/events) only for such fetchers.
+type townRooted interface {
+	TownRoot() string
+}
+
 // NewDashboardMux creates an HTTP handler that serves both the dashboard and API.
+// For fetchers backed by a town, access is controlled by the town's
+// settings/dashboard.json (see Auth).
 func NewDashboardMux(fetcher ConvoyFetcher) (http.Handler, error) {
 	convoyHandler, err := NewConvoyHandler(fetcher)
 	if err != nil {
@@ -314,10 +324,26 @@ func NewDashboardMux(fetcher ConvoyFetcher) (http.Handler, error) {
 	}
 	staticHandler := http.FileServer(http.FS(staticFS))
 
+	dashboardConfig := config.NewDashboardConfig()
 	mux := http.NewServeMux()
+	if tr, ok := fetcher.(townRooted); ok {
+		dashboardConfig, err = config.LoadOrCreateDashboardConfig(config.DashboardConfigPath(tr.TownRoot()))
+		if err != nil {
+			return nil, err
+		}
+		live, err := NewLiveUpdates(fetcher, tr.TownRoot())
+		if err != nil {
+			return nil, err
+		}
+		mux.Handle("/events", live)
+	}
+	auth, err := NewAuth(dashboardConfig)
+	if err != nil {
+		return nil, err
+	}
 	mux.Handle("/api/", apiHandler)
 	mux.Handle("/static/", http.StripPrefix("/static/", staticHandler))
 	mux.Handle("/", convoyHandler)
 
-	return mux, nil
+	return auth.Wrap(mux), nil
 }
@@ -0,0 +1,389 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/feed"
+)
+
+// Dashboard panels that receive live updates over /events.
+const (
+	livePanelConvoys    = "convoys"
+	livePanelWorkers    = "workers"
+	livePanelMergeQueue = "merge_queue"
+	livePanelActivity   = "activity"
+	livePanelMail       = "mail"
+)
+
+// Live update timing defaults.
+const (
+	livePollInterval = 250 * time.Millisecond
+	liveDebounce     = 500 * time.Millisecond
+	liveHeartbeat    = 15 * time.Second
+)
+
+// liveEventPanels maps raw event types to the panels they change.
+// Every feed-visible event also refreshes the activity panel.
+var liveEventPanels = map[string][]string{
+	events.TypeSling:          {livePanelWorkers, livePanelConvoys},
+	events.TypeHook:           {livePanelWorkers},
+	events.TypeUnhook:         {livePanelWorkers},
+	events.TypeHandoff:        {livePanelWorkers},
+	events.TypeDone:           {livePanelWorkers, livePanelConvoys, livePanelMergeQueue},
+	events.TypeSpawn:          {livePanelWorkers},
+	events.TypeKill:           {livePanelWorkers},
+	events.TypeSessionStart:   {livePanelWorkers},
+	events.TypeSessionEnd:     {livePanelWorkers},
+	events.TypeSessionDeath:   {livePanelWorkers},
+	events.TypeMassDeath:      {livePanelWorkers},
+	events.TypePolecatChecked: {livePanelWorkers},
+	events.TypePolecatNudged:  {livePanelWorkers},
+	events.TypeMergeStarted:   {livePanelMergeQueue},
+	events.TypeMerged:         {livePanelMergeQueue, livePanelConvoys},
+	events.TypeMergeFailed:    {livePanelMergeQueue},
+	events.TypeMergeSkipped:   {livePanelMergeQueue},
+	events.TypeMail:           {livePanelMail},
+}
+
+// PanelUpdate is the payload of a "panel" server-sent event. The browser
+// replaces the contents of the element with data-live-panel=Panel by HTML
+// and sets the panel's header count. Mail updates carry no HTML; the
+// browser reloads the inbox from /api/v1/mail/inbox.
+type PanelUpdate struct {
+	Panel string `json:"panel"`
+	Count int    `json:"count"`
+	HTML  string `json:"html,omitempty"`
+}
+
+// LiveUpdates serves /events, a Server-Sent Events stream of dashboard
+// panel updates. It tails the raw events log and the curated feed, and
+// when events arrive re-fetches only the panels they affect. A burst of
+// events is coalesced into one fetch per panel, shared by every connected
+// browser, so open dashboards do not multiply load on bd, tmux, or gh.
+//
+// The logs are only tailed while at least one browser is connected.
+type LiveUpdates struct {
+	fetcher    ConvoyFetcher
+	template   *template.Template
+	eventsPath string
+	feedPath   string
+
+	// PollInterval is how often the logs are checked for new lines.
+	PollInterval time.Duration
+
+	// Debounce is how long to wait after an event for more to arrive
+	// before refreshing panels.
+	Debounce time.Duration
+
+	// Heartbeat is the interval between keep-alive comments.
+	Heartbeat time.Duration
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+	stop    context.CancelFunc // stops the tailer; nil when not running
+}
+
+// NewLiveUpdates creates a live update stream for the town at townRoot.
+func NewLiveUpdates(fetcher ConvoyFetcher, townRoot string) (*LiveUpdates, error) {
+	tmpl, err := LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+	return &LiveUpdates{
+		fetcher:      fetcher,
+		template:     tmpl,
+		eventsPath:   filepath.Join(townRoot, events.EventsFile),
+		feedPath:     filepath.Join(townRoot, feed.FeedFile),
+		PollInterval: livePollInterval,
+		Debounce:     liveDebounce,
+		Heartbeat:    liveHeartbeat,
+		clients:      make(map[chan []byte]struct{}),
+	}, nil
+}
+
+// ServeHTTP streams panel updates until the client disconnects.
+func (l *LiveUpdates) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// The stream outlives the server's WriteTimeout; clear the deadline.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	ch := l.subscribe()
+	defer l.unsubscribe(ch)
+
+	// Tell the browser how long to wait before reconnecting.
+	_, _ = io.WriteString(w, "retry: 5000\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(l.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg := <-ch:
+			if _, err := w.Write(msg); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// subscribe registers a client and starts the tailer if it is the first.
+func (l *LiveUpdates) subscribe() chan []byte {
+	ch := make(chan []byte, 16)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clients[ch] = struct{}{}
+	if l.stop == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		l.stop = cancel
+		go l.run(ctx)
+	}
+	return ch
+}
+
+// unsubscribe removes a client and stops the tailer if it was the last.
+func (l *LiveUpdates) unsubscribe(ch chan []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.clients, ch)
+	if len(l.clients) == 0 && l.stop != nil {
+		l.stop()
+		l.stop = nil
+	}
+}
+
+// broadcast sends msg to every client. A client whose buffer is full
+// misses the message; updates are full panel snapshots, so the next one
+// brings it up to date.
+func (l *LiveUpdates) broadcast(msg []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for ch := range l.clients {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// run tails the events and feed logs, refreshing affected panels after
+// each burst of events.
+func (l *LiveUpdates) run(ctx context.Context) {
+	eventsTail := newLogTail(l.eventsPath)
+	feedTail := newLogTail(l.feedPath)
+
+	ticker := time.NewTicker(l.PollInterval)
+	defer ticker.Stop()
+
+	dirty := make(map[string]bool)
+	var flush <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			for _, line := range eventsTail.readLines() {
+				var e events.Event
+				if err := json.Unmarshal(line, &e); err != nil {
+					continue
+				}
+				for _, panel := range liveEventPanels[e.Type] {
+					dirty[panel] = true
+				}
+				if e.Visibility != events.VisibilityAudit {
+					dirty[livePanelActivity] = true
+				}
+			}
+			if len(feedTail.readLines()) > 0 {
+				dirty[livePanelActivity] = true
+			}
+			if len(dirty) > 0 && flush == nil {
+				flush = time.After(l.Debounce)
+			}
+
+		case <-flush:
+			flush = nil
+			panels := make([]string, 0, len(dirty))
+			for panel := range dirty {
+				panels = append(panels, panel)
+			}
+			dirty = make(map[string]bool)
+			l.refresh(panels)
+		}
+	}
+}
+
+// refresh fetches and renders panels in parallel and broadcasts each.
+func (l *LiveUpdates) refresh(panels []string) {
+	var wg sync.WaitGroup
+	for _, panel := range panels {
+		wg.Add(1)
+		go func(panel string) {
+			defer wg.Done()
+			update, err := l.renderPanel(panel)
+			if err != nil {
+				log.Printf("dashboard: live update for %s failed: %v", panel, err)
+				return
+			}
+			msg, err := formatSSE("panel", update)
+			if err != nil {
+				log.Printf("dashboard: encoding live update for %s: %v", panel, err)
+				return
+			}
+			l.broadcast(msg)
+		}(panel)
+	}
+	wg.Wait()
+}
+
+// renderPanel fetches the data for one panel and renders its body.
+func (l *LiveUpdates) renderPanel(panel string) (*PanelUpdate, error) {
+	var (
+		data  ConvoyData
+		count int
+		err   error
+	)
+	switch panel {
+	case livePanelConvoys:
+		data.Convoys, err = l.fetcher.FetchConvoys()
+		count = len(data.Convoys)
+	case livePanelWorkers:
+		data.Workers, err = l.fetcher.FetchWorkers()
+		count = len(data.Workers)
+	case livePanelMergeQueue:
+		data.MergeQueue, err = l.fetcher.FetchMergeQueue()
+		count = len(data.MergeQueue)
+	case livePanelActivity:
+		data.Activity, err = l.fetcher.FetchActivity()
+		count = len(data.Activity)
+	case livePanelMail:
+		return &PanelUpdate{Panel: panel}, nil
+	default:
+		return nil, fmt.Errorf("unknown panel %q", panel)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := l.template.ExecuteTemplate(&buf, "live-"+panel, data); err != nil {
+		return nil, fmt.Errorf("rendering: %w", err)
+	}
+	return &PanelUpdate{Panel: panel, Count: count, HTML: buf.String()}, nil
+}
+
+// formatSSE encodes v as a single-line JSON server-sent event.
+func formatSSE(event string, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "event: %s\ndata: %s\n\n", event, data)
+	return buf.Bytes(), nil
+}
+
+// logTail reads lines appended to a JSONL log since the last read.
+// It starts at the end of the file, tolerates the file not existing yet,
+// and restarts from the beginning if the file is truncated or replaced
+// (as when the events log is rotated).
+type logTail struct {
+	path    string
+	offset  int64
+	file    os.FileInfo // the file offset refers to
+	partial []byte      // an incomplete trailing line, held until its newline arrives
+}
+
+func newLogTail(path string) *logTail {
+	t := &logTail{path: path}
+	if info, err := os.Stat(path); err == nil {
+		t.offset = info.Size()
+		t.file = info
+	}
+	return t
+}
+
+// readLines returns the complete lines appended since the last call.
+func (t *logTail) readLines() [][]byte {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil
+	}
+	if info.Size() < t.offset || (t.file != nil && !os.SameFile(info, t.file)) {
+		t.offset = 0
+		t.partial = nil
+	}
+	t.file = info
+	if info.Size() == t.offset {
+		return nil
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return nil
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil
+	}
+	t.offset += int64(len(data))
+
+	data = append(t.partial, data...)
+	t.partial = nil
+
+	var lines [][]byte
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		if line := bytes.TrimSpace(data[:i]); len(line) > 0 {
+			lines = append(lines, line)
+		}
+		data = data[i+1:]
+	}
+	if len(data) > 0 {
+		t.partial = append([]byte(nil), data...)
+	}
+	return lines
+}
@@ -0,0 +1,157 @@
+package web
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestLogTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.jsonl")
+	if err := os.WriteFile(path, []byte("old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tail := newLogTail(path)
+	if lines := tail.readLines(); len(lines) != 0 {
+		t.Fatalf("existing lines returned: %q", lines)
+	}
+
+	appendFile(t, path, "one\ntw")
+	if lines := tail.readLines(); len(lines) != 1 || string(lines[0]) != "one" {
+		t.Fatalf("readLines = %q, want [one]", lines)
+	}
+	appendFile(t, path, "o\n")
+	if lines := tail.readLines(); len(lines) != 1 || string(lines[0]) != "two" {
+		t.Fatalf("readLines = %q, want [two] after partial line completed", lines)
+	}
+
+	// Truncation restarts from the beginning.
+	if err := os.WriteFile(path, []byte("new\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if lines := tail.readLines(); len(lines) != 1 || string(lines[0]) != "new" {
+		t.Fatalf("readLines after truncate = %q, want [new]", lines)
+	}
+
+	// A log that does not exist yet is picked up once created.
+	missing := newLogTail(filepath.Join(t.TempDir(), "later.jsonl"))
+	if lines := missing.readLines(); lines != nil {
+		t.Fatalf("missing file: readLines = %q", lines)
+	}
+	appendFile(t, missing.path, "first\n")
+	if lines := missing.readLines(); len(lines) != 1 {
+		t.Fatalf("created file: readLines = %q", lines)
+	}
+}
+
+func TestLiveUpdates_PushesAffectedPanels(t *testing.T) {
+	townRoot := t.TempDir()
+	fetcher := &MockConvoyFetcher{
+		Workers: []WorkerRow{{Name: "furiosa", Rig: "roxas", WorkStatus: "working"}},
+		Convoys: []ConvoyRow{{ID: "hq-cv-1"}},
+	}
+	live, err := NewLiveUpdates(fetcher, townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live.PollInterval = 10 * time.Millisecond
+	live.Debounce = 20 * time.Millisecond
+
+	srv := httptest.NewServer(live)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	if line, _ := reader.ReadString('\n'); !strings.HasPrefix(line, "retry:") {
+		t.Fatalf("first line = %q, want retry", line)
+	}
+
+	// A sling touches workers and convoys, and is feed-visible.
+	ev, _ := json.Marshal(events.Event{Type: events.TypeSling, Actor: "mayor", Visibility: events.VisibilityFeed})
+	appendFile(t, filepath.Join(townRoot, events.EventsFile), string(ev)+"\n")
+
+	got := make(map[string]PanelUpdate)
+	deadline := time.AfterFunc(5*time.Second, func() { resp.Body.Close() })
+	defer deadline.Stop()
+	for len(got) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream (got %v): %v", got, err)
+		}
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
+		}
+		var u PanelUpdate
+		if err := json.Unmarshal([]byte(data), &u); err != nil {
+			t.Fatalf("bad update %q: %v", data, err)
+		}
+		got[u.Panel] = u
+	}
+
+	if u := got[livePanelWorkers]; u.Count != 1 || !strings.Contains(u.HTML, "furiosa") {
+		t.Errorf("workers update = %+v", u)
+	}
+	if u := got[livePanelConvoys]; u.Count != 1 || !strings.Contains(u.HTML, "hq-cv-1") {
+		t.Errorf("convoys update = %+v", u)
+	}
+	if _, ok := got[livePanelActivity]; !ok {
+		t.Error("no activity update for feed-visible event")
+	}
+	if _, ok := got[livePanelMergeQueue]; ok {
+		t.Error("merge queue refreshed for a sling")
+	}
+}
+
+func TestLiveUpdates_StopsTailingWithoutClients(t *testing.T) {
+	live, err := NewLiveUpdates(&MockConvoyFetcher{}, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := live.subscribe(), live.subscribe()
+	live.unsubscribe(a)
+	live.mu.Lock()
+	running := live.stop != nil
+	live.mu.Unlock()
+	if !running {
+		t.Fatal("tailer stopped with a client still connected")
+	}
+
+	live.unsubscribe(b)
+	live.mu.Lock()
+	running = live.stop != nil
+	live.mu.Unlock()
+	if running {
+		t.Error("tailer still running after last client left")
+	}
+}
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,28 @@
 (function() {
     'use strict';
 
+    // ============================================
+    // CSRF PROTECTION
+    // ============================================
+    // The server requires unsafe requests to echo the gt_csrf cookie in
+    // the X-CSRF-Token header. Wrap fetch so every POST carries it.
+    function csrfToken() {
+        var m = document.cookie.match(/(?:^|;\s*)gt_csrf=([^;]+)/);
+        return m ? decodeURIComponent(m[1]) : '';
+    }
+
+    var nativeFetch = window.fetch.bind(window);
+    window.fetch = function(input, init) {
+        init = init || {};
+        var method = (init.method || 'GET').toUpperCase();
+        if (method !== 'GET' && method !== 'HEAD') {
+            var headers = new Headers(init.headers || {});
+            headers.set('X-CSRF-Token', csrfToken());
+            init.headers = headers;
+        }
+        return nativeFetch(input, init);
+    };
+
     // ============================================
     // EXPAND BUTTON HANDLER
     // ============================================
@@ -548,56 +570,73 @@
             // Hide detail/compose views
             mailDetail.style.display = 'none';
             mailCompose.style.display = 'none';
+
+            // Search results follow the tab's scope
+            if (mailSearchInput && mailSearchInput.value.trim()) {
+                refreshMail();
+            }
         });
     });
 
+    // Render messages into the inbox table. Returns false if there are none.
+    function renderMailRows(messages) {
+        var loading = document.getElementById('mail-loading');
+        var table = document.getElementById('mail-table');
+        var tbody = document.getElementById('mail-tbody');
+        var empty = document.getElementById('mail-empty');
+
+        loading.style.display = 'none';
+        if (!messages || messages.length === 0) {
+            table.style.display = 'none';
+            empty.style.display = 'block';
+            return false;
+        }
+
+        table.style.display = 'table';
+        empty.style.display = 'none';
+        tbody.innerHTML = '';
+
+        messages.forEach(function(msg) {
+            var tr = document.createElement('tr');
+            tr.className = 'mail-row' + (msg.read ? '' : ' mail-unread');
+            tr.setAttribute('data-msg-id', msg.id);
+            tr.setAttribute('data-from', msg.from);
+
+            var priorityIcon = '';
+            if (msg.priority === 'urgent') priorityIcon = '<span class="priority-urgent">⚡</span> ';
+            else if (msg.priority === 'high') priorityIcon = '<span class="priority-high">!</span> ';
+
+            tr.innerHTML =
+                '<td class="mail-from">' + escapeHtml(msg.from) + '</td>' +
+                '<td>' + priorityIcon + '<span class="mail-subject">' + escapeHtml(msg.subject) + '</span></td>' +
+                '<td class="mail-time">' + formatMailTime(msg.timestamp) + '</td>';
+            tbody.appendChild(tr);
+        });
+        return true;
+    }
+
     // Load mail inbox on page load
     function loadMailInbox() {
         var loading = document.getElementById('mail-loading');
-        var table = document.getElementById('mail-table');
         var tbody = document.getElementById('mail-tbody');
         var empty = document.getElementById('mail-empty');
         var count = document.getElementById('mail-count');
 
-        if (!loading || !table || !tbody) return;
+        if (!loading || !tbody) return;
+        empty.querySelector('p').textContent = 'No mail in inbox';
 
-        fetch('/api/mail/inbox')
+        fetch('/api/v1/mail/inbox')
             .then(function(r) { return r.json(); })
             .then(function(data) {
-                loading.style.display = 'none';
-
-                if (data.messages && data.messages.length > 0) {
-                    table.style.display = 'table';
-                    empty.style.display = 'none';
-                    tbody.innerHTML = '';
-
-                    data.messages.forEach(function(msg) {
-                        var tr = document.createElement('tr');
-                        tr.className = 'mail-row' + (msg.read ? '' : ' mail-unread');
-                        tr.setAttribute('data-msg-id', msg.id);
-                        tr.setAttribute('data-from', msg.from);
-
-                        var priorityIcon = '';
-                        if (msg.priority === 'urgent') priorityIcon = '<span class="priority-urgent">⚡</span> ';
-                        else if (msg.priority === 'high') priorityIcon = '<span class="priority-high">!</span> ';
-
-                        tr.innerHTML =
-                            '<td class="mail-from">' + escapeHtml(msg.from) + '</td>' +
-                            '<td>' + priorityIcon + '<span class="mail-subject">' + escapeHtml(msg.subject) + '</span></td>' +
-                            '<td class="mail-time">' + formatMailTime(msg.timestamp) + '</td>';
-                        tbody.appendChild(tr);
-                    });
-
+                if (renderMailRows(data.messages)) {
                     // Update count
                     if (count) {
                         var unread = data.unread_count || 0;
                         count.textContent = unread > 0 ? unread + ' unread' : data.total;
                         if (unread > 0) count.classList.add('has-unread');
                     }
-                } else {
-                    table.style.display = 'none';
-                    empty.style.display = 'block';
-                    if (count) count.textContent = '0';
+                } else if (count) {
+                    count.textContent = '0';
                 }
             })
             .catch(function(err) {
@@ -606,6 +645,72 @@
             });
     }
 
+    // Search mail through the mail index. The Inbox tab searches the
+    // overseer's mailbox; All Traffic searches every message in the town.
+    var mailSearchInput = document.getElementById('mail-search-input');
+    var mailSearchTimer = null;
+
+    function searchMail(query) {
+        var empty = document.getElementById('mail-empty');
+        var count = document.getElementById('mail-count');
+
+        mailList.style.display = 'block';
+        if (mailAll) mailAll.style.display = 'none';
+
+        var url = '/api/v1/mail/search?q=' + encodeURIComponent(query);
+        if (currentMailTab === 'all') url += '&all=true';
+
+        fetch(url)
+            .then(function(r) {
+                return r.json().then(function(data) { return { ok: r.ok, data: data }; });
+            })
+            .then(function(res) {
+                if (!res.ok) {
+                    renderMailRows([]);
+                    empty.querySelector('p').textContent = res.data.error || 'Search failed';
+                    return;
+                }
+                var results = res.data.results || [];
+                if (!renderMailRows(results)) {
+                    empty.querySelector('p').textContent = 'No matching mail';
+                }
+                if (count) {
+                    count.classList.remove('has-unread');
+                    count.textContent = results.length + ' found';
+                }
+            })
+            .catch(function(err) {
+                console.error('Mail search error:', err);
+            });
+    }
+
+    // Reload whichever mail view is showing: search results or the inbox.
+    function refreshMail() {
+        var query = mailSearchInput ? mailSearchInput.value.trim() : '';
+        if (query) {
+            searchMail(query);
+            return;
+        }
+        if (currentMailTab === 'all' && mailAll) {
+            mailList.style.display = 'none';
+            mailAll.style.display = 'block';
+        }
+        loadMailInbox();
+    }
+
+    if (mailSearchInput) {
+        mailSearchInput.addEventListener('input', function() {
+            clearTimeout(mailSearchTimer);
+            mailSearchTimer = setTimeout(refreshMail, 300);
+        });
+        mailSearchInput.addEventListener('keydown', function(e) {
+            if (e.key === 'Escape') {
+                mailSearchInput.value = '';
+                refreshMail();
+            }
+        });
+    }
+
     function formatMailTime(timestamp) {
         if (!timestamp) return '';
         var d = new Date(timestamp);
@@ -655,9 +760,13 @@
         mailDetail.style.display = 'block';
 
         // Fetch message content
-        fetch('/api/mail/read?id=' + encodeURIComponent(msgId))
+        fetch('/api/v1/mail/messages/' + encodeURIComponent(msgId))
             .then(function(r) { return r.json(); })
             .then(function(msg) {
+                if (msg.error) throw new Error(msg.error);
+                if (!msg.read) {
+                    fetch('/api/v1/mail/messages/' + encodeURIComponent(msgId) + '/read', { method: 'POST' });
+                }
                 document.getElementById('mail-detail-subject').textContent = msg.subject || '(no subject)';
                 document.getElementById('mail-detail-from').textContent = msg.from || from;
                 document.getElementById('mail-detail-body').textContent = msg.body || '(no content)';
@@ -761,7 +870,7 @@
         btn.textContent = 'Sending...';
         btn.disabled = true;
 
-        fetch('/api/mail/send', {
+        fetch('/api/v1/mail/send', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({
@@ -771,9 +880,12 @@
                 reply_to: replyTo || undefined
             })
         })
-        .then(function(r) { return r.json(); })
-        .then(function(data) {
-            if (data.success) {
+        .then(function(r) {
+            return r.json().then(function(data) { return { ok: r.ok, data: data }; });
+        })
+        .then(function(res) {
+            var data = res.data;
+            if (res.ok) {
                 showToast('success', 'Sent', 'Message sent to ' + to);
                 mailCompose.style.display = 'none';
                 mailList.style.display = 'block';
@@ -781,7 +893,7 @@
                 currentMessageFrom = null;
                 // Resume HTMX refresh and reload inbox
                 window.pauseRefresh = false;
-                loadMailInbox();
+                refreshMail();
             } else {
                 showToast('error', 'Failed', data.error || 'Failed to send message');
             }
@@ -892,7 +1004,7 @@
         issueDetail.style.display = 'block';
 
         // Fetch issue details
-        fetch('/api/issues/show?id=' + encodeURIComponent(issueId))
+        fetch('/api/v1/issues/' + encodeURIComponent(issueId))
             .then(function(r) { return r.json(); })
             .then(function(data) {
                 if (data.error) {
@@ -903,16 +1015,17 @@
 
                 document.getElementById('issue-detail-id').textContent = data.id || issueId;
                 document.getElementById('issue-detail-title-text').textContent = data.title || '(no title)';
-                document.getElementById('issue-detail-description').textContent = data.description || data.raw_output || '(no description)';
+                document.getElementById('issue-detail-description').textContent = data.description || '(no description)';
 
                 // Priority badge
                 var priorityEl = document.getElementById('issue-detail-priority');
-                if (data.priority) {
-                    priorityEl.textContent = data.priority;
+                if (typeof data.priority === 'number') {
+                    var priority = 'P' + data.priority;
+                    priorityEl.textContent = priority;
                     priorityEl.className = 'badge';
-                    if (data.priority === 'P1') priorityEl.classList.add('badge-red');
-                    else if (data.priority === 'P2') priorityEl.classList.add('badge-orange');
-                    else if (data.priority === 'P3') priorityEl.classList.add('badge-yellow');
+                    if (priority === 'P1') priorityEl.classList.add('badge-red');
+                    else if (priority === 'P2') priorityEl.classList.add('badge-orange');
+                    else if (priority === 'P3') priorityEl.classList.add('badge-yellow');
                     else priorityEl.classList.add('badge-muted');
                 }
 
@@ -927,8 +1040,8 @@
                 if (data.type) {
                     document.getElementById('issue-detail-type').textContent = 'Type: ' + data.type;
                 }
-                if (data.created) {
-                    document.getElementById('issue-detail-created').textContent = 'Created: ' + data.created;
+                if (data.created_at) {
+                    document.getElementById('issue-detail-created').textContent = 'Created: ' + data.created_at;
                 }
 
                 // Dependencies
@@ -1097,4 +1210,47 @@
         });
     }
 
+    // ============================================
+    // LIVE UPDATES (Server-Sent Events)
+    // ============================================
+    // /events pushes a panel's new body whenever the town's event log shows
+    // it changed. While connected, the full-page refresh slows to once a
+    // minute (see hx-trigger on #dashboard-main).
+    function applyPanelUpdate(update) {
+        // Like the full refresh, hold updates while a detail view is open.
+        if (window.pauseRefresh) return;
+
+        if (update.panel === 'mail') {
+            refreshMail();
+            return;
+        }
+
+        var body = document.querySelector('[data-live-panel="' + update.panel + '"]');
+        if (!body) return;
+        body.innerHTML = update.html;
+        if (window.htmx) htmx.process(body);
+
+        var panel = body.closest('.panel');
+        var count = panel && panel.querySelector('.panel-header .count');
+        if (count) count.textContent = update.count;
+    }
+
+    if (window.EventSource) {
+        var liveSource = new EventSource('/events');
+        liveSource.addEventListener('open', function() {
+            window.liveConnected = true;
+        });
+        liveSource.addEventListener('error', function() {
+            // The browser reconnects on its own; fall back to fast polling meanwhile.
+            window.liveConnected = false;
+        });
+        liveSource.addEventListener('panel', function(e) {
+            try {
+                applyPanelUpdate(JSON.parse(e.data));
+            } catch (err) {
+                console.error('Live update error:', err);
+            }
+        });
+    }
+
 })();
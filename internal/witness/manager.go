@@ -40,7 +40,7 @@ func NewManager(r *rig.Rig) *Manager {
 // IsRunning checks if the witness session is active.
 // ZFC: tmux session existence is the source of truth.
 func (m *Manager) IsRunning() (bool, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend(m.townRoot())
 	return t.HasSession(m.SessionName())
 }
 
@@ -52,7 +52,7 @@ func (m *Manager) SessionName() string {
 // Status returns information about the witness session.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
-	t := tmux.NewTmux()
+	t := session.NewBackend(m.townRoot())
 	sessionID := m.SessionName()
 
 	running, err := t.HasSession(sessionID)
@@ -89,7 +89,7 @@ func (m *Manager) witnessDir() string {
 // envOverrides are KEY=VALUE pairs that override all other env var sources.
 // ZFC-compliant: no state file, tmux session is source of truth.
 func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []string) error {
-	t := tmux.NewTmux()
+	t := session.NewBackend(m.townRoot())
 	sessionID := m.SessionName()
 
 	if foreground {
@@ -142,7 +142,7 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
 	if err := t.NewSessionWithCommand(sessionID, witnessDir, command); err != nil {
-		return fmt.Errorf("creating tmux session: %w", err)
+		return fmt.Errorf("creating session: %w", err)
 	}
 
 	// Set environment variables (non-fatal: session works without these)
@@ -237,7 +237,7 @@ func buildWitnessStartCommand(rigPath, rigName, townRoot, agentOverride string,
 // Stop stops the witness.
 // ZFC-compliant: tmux session is the source of truth.
 func (m *Manager) Stop() error {
-	t := tmux.NewTmux()
+	t := session.NewBackend(m.townRoot())
 	sessionID := m.SessionName()
 
 	// Check if tmux session exists
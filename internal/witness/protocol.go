@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
 )
 
 // Protocol message patterns for Witness inbox routing.
@@ -47,13 +49,14 @@ const (
 )
 
 // PolecatDonePayload contains parsed data from a POLECAT_DONE message.
+// It is also the envelope payload sent by gt done.
 type PolecatDonePayload struct {
-	PolecatName string
-	Exit        string // COMPLETED, ESCALATED, DEFERRED, PHASE_COMPLETE
-	IssueID     string
-	MRID        string
-	Branch      string
-	Gate        string // Gate ID when Exit is PHASE_COMPLETE
+	PolecatName string `json:"polecat"`
+	Exit        string `json:"exit"` // COMPLETED, ESCALATED, DEFERRED, PHASE_COMPLETE
+	IssueID     string `json:"issue,omitempty"`
+	MRID        string `json:"mr,omitempty"`
+	Branch      string `json:"branch,omitempty"`
+	Gate        string `json:"gate,omitempty"` // Gate ID when Exit is PHASE_COMPLETE
 }
 
 // HelpPayload contains parsed data from a HELP message.
@@ -67,21 +70,23 @@ type HelpPayload struct {
 }
 
 // MergedPayload contains parsed data from a MERGED message.
+// JSON tags match the refinery's envelope payload (protocol.MergedPayload).
 type MergedPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	MergedAt    time.Time
+	PolecatName string    `json:"polecat"`
+	Branch      string    `json:"branch"`
+	IssueID     string    `json:"issue"`
+	MergedAt    time.Time `json:"merged_at"`
 }
 
 // MergeFailedPayload contains parsed data from a MERGE_FAILED message.
+// JSON tags match the refinery's envelope payload (protocol.MergeFailedPayload).
 type MergeFailedPayload struct {
-	PolecatName string
-	Branch      string
-	IssueID     string
-	FailureType string // "build", "test", "lint", etc.
-	Error       string
-	FailedAt    time.Time
+	PolecatName string    `json:"polecat"`
+	Branch      string    `json:"branch"`
+	IssueID     string    `json:"issue"`
+	FailureType string    `json:"failure_type"` // "build", "test", "lint", etc.
+	Error       string    `json:"error"`
+	FailedAt    time.Time `json:"failed_at"`
 }
 
 // SwarmStartPayload contains parsed data from a SWARM_START message.
@@ -92,6 +97,32 @@ type SwarmStartPayload struct {
 	StartedAt time.Time
 }
 
+// Envelope types of the protocol messages the Witness receives.
+// See mail.Envelope; MERGED and MERGE_FAILED are written by the refinery.
+const (
+	EnvelopePolecatDone = "POLECAT_DONE"
+	EnvelopeMerged      = "MERGED"
+	EnvelopeMergeFailed = "MERGE_FAILED"
+)
+
+// envelopeTypes maps envelope message types to protocol types.
+var envelopeTypes = map[string]ProtocolType{
+	EnvelopePolecatDone: ProtoPolecatDone,
+	EnvelopeMerged:      ProtoMerged,
+	EnvelopeMergeFailed: ProtoMergeFailed,
+}
+
+// ClassifyMail determines the protocol type of a message, preferring the
+// body's envelope over the subject line.
+func ClassifyMail(subject, body string) ProtocolType {
+	if env, ok := mail.ParseEnvelope(body); ok {
+		if proto, known := envelopeTypes[env.Type]; known {
+			return proto
+		}
+	}
+	return ClassifyMessage(subject)
+}
+
 // ClassifyMessage determines the protocol type from a message subject.
 func ClassifyMessage(subject string) ProtocolType {
 	switch {
@@ -123,7 +154,14 @@ func ClassifyMessage(subject string) ProtocolType {
 //	MR: <mr-id>
 //	Gate: <gate-id>
 //	Branch: <branch>
+//
+// A body envelope, when present, takes precedence over both.
 func ParsePolecatDone(subject, body string) (*PolecatDonePayload, error) {
+	var decoded PolecatDonePayload
+	if mail.DecodeEnvelope(body, EnvelopePolecatDone, &decoded) && decoded.PolecatName != "" {
+		return &decoded, nil
+	}
+
 	matches := PatternPolecatDone.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid POLECAT_DONE subject: %s", subject)
@@ -195,7 +233,14 @@ func ParseHelp(subject, body string) (*HelpPayload, error) {
 //	Branch: <branch>
 //	Issue: <issue-id>
 //	Merged-At: <timestamp>
+//
+// A body envelope, when present, takes precedence over both.
 func ParseMerged(subject, body string) (*MergedPayload, error) {
+	var decoded MergedPayload
+	if mail.DecodeEnvelope(body, EnvelopeMerged, &decoded) && decoded.PolecatName != "" {
+		return &decoded, nil
+	}
+
 	matches := PatternMerged.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid MERGED subject: %s", subject)
@@ -229,9 +274,19 @@ func ParseMerged(subject, body string) (*MergedPayload, error) {
 //
 //	Branch: <branch>
 //	Issue: <issue-id>
-//	FailureType: <type>
+//	Failure-Type: <type>
 //	Error: <error-message>
+//
+// A body envelope, when present, takes precedence over both.
 func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
+	var decoded MergeFailedPayload
+	if mail.DecodeEnvelope(body, EnvelopeMergeFailed, &decoded) && decoded.PolecatName != "" {
+		if decoded.FailedAt.IsZero() {
+			decoded.FailedAt = time.Now()
+		}
+		return &decoded, nil
+	}
+
 	matches := PatternMergeFailed.FindStringSubmatch(subject)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid MERGE_FAILED subject: %s", subject)
@@ -250,7 +305,9 @@ func ParseMergeFailed(subject, body string) (*MergeFailedPayload, error) {
 			payload.Branch = strings.TrimSpace(strings.TrimPrefix(line, "Branch:"))
 		case strings.HasPrefix(line, "Issue:"):
 			payload.IssueID = strings.TrimSpace(strings.TrimPrefix(line, "Issue:"))
-		case strings.HasPrefix(line, "FailureType:"):
+		case strings.HasPrefix(line, "Failure-Type:"):
+			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "Failure-Type:"))
+		case strings.HasPrefix(line, "FailureType:"): // pre-envelope spelling
 			payload.FailureType = strings.TrimSpace(strings.TrimPrefix(line, "FailureType:"))
 		case strings.HasPrefix(line, "Error:"):
 			payload.Error = strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
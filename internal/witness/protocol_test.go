@@ -2,6 +2,9 @@ package witness
 
 import (
 	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
 )
 
 func TestClassifyMessage(t *testing.T) {
@@ -218,6 +221,66 @@ func TestParseMergeFailed_InvalidSubject(t *testing.T) {
 	}
 }
 
+func TestParseMergeFailed_RefineryFormat(t *testing.T) {
+	// The refinery writes Failure-Type:, not FailureType:.
+	payload, err := ParseMergeFailed("MERGE_FAILED nux", "Branch: b\nFailure-Type: tests\nError: boom")
+	if err != nil {
+		t.Fatalf("ParseMergeFailed() error = %v", err)
+	}
+	if payload.FailureType != "tests" {
+		t.Errorf("FailureType = %q, want %q", payload.FailureType, "tests")
+	}
+}
+
+func TestEnvelopePreferred(t *testing.T) {
+	done, err := mail.EncodeEnvelope(EnvelopePolecatDone, PolecatDonePayload{
+		PolecatName: "nux",
+		Exit:        "COMPLETED",
+		IssueID:     "gt-abc",
+		MRID:        "gt-mr1",
+		Branch:      "polecat/nux/gt-abc",
+	}, "Exit: ESCALATED\nIssue: gt-wrong\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ClassifyMail("work finished", done); got != ProtoPolecatDone {
+		t.Errorf("ClassifyMail = %v, want %v", got, ProtoPolecatDone)
+	}
+	if got := ClassifyMail("MERGED nux", "Branch: b"); got != ProtoMerged {
+		t.Errorf("ClassifyMail(legacy) = %v, want %v", got, ProtoMerged)
+	}
+
+	payload, err := ParsePolecatDone("work finished", done)
+	if err != nil {
+		t.Fatalf("ParsePolecatDone() error = %v", err)
+	}
+	if payload.PolecatName != "nux" || payload.Exit != "COMPLETED" || payload.IssueID != "gt-abc" || payload.MRID != "gt-mr1" {
+		t.Errorf("ParsePolecatDone() = %+v", payload)
+	}
+
+	// The refinery's MERGED payload uses the same JSON field names.
+	mergedAt := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	merged, err := mail.EncodeEnvelope(EnvelopeMerged, map[string]interface{}{
+		"branch":        "polecat/nux/gt-abc",
+		"issue":         "gt-abc",
+		"polecat":       "nux",
+		"rig":           "gastown",
+		"merged_at":     mergedAt,
+		"target_branch": "main",
+	}, "Merged!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mp, err := ParseMerged("MERGED nux", merged)
+	if err != nil {
+		t.Fatalf("ParseMerged() error = %v", err)
+	}
+	if mp.IssueID != "gt-abc" || !mp.MergedAt.Equal(mergedAt) {
+		t.Errorf("ParseMerged() = %+v", mp)
+	}
+}
+
 func TestCleanupWispLabels(t *testing.T) {
 	labels := CleanupWispLabels("nux", "pending")
 